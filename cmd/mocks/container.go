@@ -27,10 +27,21 @@ type MockContainer struct {
 
 	Controllers struct {
 		dig.In
-		MockController *controller.MockController
-		RuleController *controller.RuleController
-		LogController  *controller.LogController
+		MockController     *controller.MockController
+		RuleController     *controller.RuleController
+		LogController      *controller.LogController
+		AuthController     *controller.AuthController
+		ScenarioController *controller.ScenarioController
+		ResourceController *controller.ResourceController
+		ChaosController    *controller.ChaosController
+		WebhookController  *controller.WebhookController
+		BinController      *controller.BinController
 	}
+
+	// WebhookService is started by main, so that its workers send the queued webhooks.
+	WebhookService service.WebhookService
+	// RuleReloadService is started by main, so that the rules edited by hand are picked up.
+	RuleReloadService service.RuleReloadService
 }
 
 // BuildContainer initialize the dependency injection container.
@@ -47,18 +58,39 @@ func BuildContainer(cfg *configs.Config) MockContainer {
 
 		// Repositories
 		newRuleRepository,
+		newRuleRevisionRepository,
 		newLogRepository,
+		newAPIKeyRepository,
+		newScenarioRepository,
+		newResourceRepository,
+		newChaosRepository,
+		newWebhookJobRepository,
+		newBinRepository,
 
 		// Services
 		service.NewLogService,
 		service.NewRuleService,
+		service.NewRuleReloadService,
 		service.NewWebhookService,
 		service.NewMockService,
+		service.NewProxyService,
+		service.NewOpenAPIService,
+		service.NewAuthService,
+		service.NewScenarioService,
+		service.NewResourceService,
+		service.NewChaosService,
+		service.NewBinService,
 
 		// Controllers
 		controller.NewMockController,
 		controller.NewRuleController,
 		controller.NewLogController,
+		controller.NewAuthController,
+		controller.NewScenarioController,
+		controller.NewResourceController,
+		controller.NewChaosController,
+		controller.NewWebhookController,
+		controller.NewBinController,
 	}
 
 	for _, provider := range providers {
@@ -114,11 +146,27 @@ func provideDynamoClient(cfg *configs.Config) (*dynamodb.Client, error) {
 	return client, nil
 }
 
-// newRuleRepository contains the logic to select the appropriate repository implementation
-// based on the configuration.
+// newRuleRepository puts the routing index in front of the rule storage of the file datasource. The
+// index caches the rules in memory, so it is left out for the databases, whose rules can be changed by
+// the other instances that share them.
 func newRuleRepository(deps RepositoryDeps) (repository.RuleRepository, error) {
+	repo, err := newRuleStorageRepository(deps)
+	if err != nil {
+		return nil, err
+	}
+
+	if !deps.Config.IsFile() {
+		return repo, nil
+	}
+
+	return repository.NewIndexedRuleRepository(repo), nil
+}
+
+// newRuleStorageRepository contains the logic to select the appropriate repository implementation
+// based on the configuration.
+func newRuleStorageRepository(deps RepositoryDeps) (repository.RuleRepository, error) {
 	switch {
-	case deps.Config.DataSource == "file" || deps.Config.DataSource == "":
+	case deps.Config.IsFile():
 		repo, err := repository.NewRuleFileRepository(deps.Config)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create rule file repository: %w", err)
@@ -144,6 +192,33 @@ func newRuleRepository(deps RepositoryDeps) (repository.RuleRepository, error) {
 	return nil, errInvalidDataSource
 }
 
+// newRuleRevisionRepository selects where the revision history of the rules is stored.
+func newRuleRevisionRepository(deps RepositoryDeps) (repository.RuleRevisionRepository, error) {
+	switch {
+	case deps.Config.IsSQL():
+		if deps.DB == nil {
+			return nil, errDBNotInitialized
+		}
+
+		return repository.NewRuleRevisionSQLRepository(deps.DB), nil
+
+	case deps.Config.IsDynamo():
+		if deps.Dynamo == nil {
+			return nil, errDynamoNotInitialized
+		}
+
+		return repository.NewDynamoRuleRevisionRepository(deps.Dynamo, deps.Config), nil
+
+	default:
+		repo, err := repository.NewRuleRevisionFileRepository(deps.Config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create rule revision file repository: %w", err)
+		}
+
+		return repo, nil
+	}
+}
+
 // newLogRepository selects the appropriate log storage implementation.
 func newLogRepository(deps RepositoryDeps) (repository.LogRepository, error) {
 	switch {
@@ -166,3 +241,165 @@ func newLogRepository(deps RepositoryDeps) (repository.LogRepository, error) {
 		return repository.NewLogMemoryRepository(), nil
 	}
 }
+
+// newAPIKeyRepository selects where the API keys created through the admin API are stored.
+func newAPIKeyRepository(deps RepositoryDeps) (repository.APIKeyRepository, error) {
+	switch {
+	case deps.Config.IsSQL():
+		if deps.DB == nil {
+			return nil, errDBNotInitialized
+		}
+
+		return repository.NewAPIKeySQLRepository(deps.DB), nil
+
+	case deps.Config.IsDynamo():
+		if deps.Dynamo == nil {
+			return nil, errDynamoNotInitialized
+		}
+
+		return repository.NewDynamoAPIKeyRepository(deps.Dynamo, deps.Config), nil
+
+	default:
+		repo, err := repository.NewAPIKeyFileRepository(deps.Config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create api key file repository: %w", err)
+		}
+
+		return repo, nil
+	}
+}
+
+// newScenarioRepository selects where the state of the scenarios is stored.
+func newScenarioRepository(deps RepositoryDeps) (repository.ScenarioRepository, error) {
+	switch {
+	case deps.Config.IsSQL():
+		if deps.DB == nil {
+			return nil, errDBNotInitialized
+		}
+
+		return repository.NewScenarioSQLRepository(deps.DB), nil
+
+	case deps.Config.IsDynamo():
+		if deps.Dynamo == nil {
+			return nil, errDynamoNotInitialized
+		}
+
+		return repository.NewDynamoScenarioRepository(deps.Dynamo, deps.Config), nil
+
+	default:
+		repo, err := repository.NewScenarioFileRepository(deps.Config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create scenario file repository: %w", err)
+		}
+
+		return repo, nil
+	}
+}
+
+// newResourceRepository selects where the entities of the resource rules are stored.
+func newResourceRepository(deps RepositoryDeps) (repository.ResourceRepository, error) {
+	switch {
+	case deps.Config.IsSQL():
+		if deps.DB == nil {
+			return nil, errDBNotInitialized
+		}
+
+		return repository.NewResourceSQLRepository(deps.DB), nil
+
+	case deps.Config.IsDynamo():
+		if deps.Dynamo == nil {
+			return nil, errDynamoNotInitialized
+		}
+
+		return repository.NewDynamoResourceRepository(deps.Dynamo, deps.Config), nil
+
+	default:
+		repo, err := repository.NewResourceFileRepository(deps.Config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create resource file repository: %w", err)
+		}
+
+		return repo, nil
+	}
+}
+
+// newChaosRepository selects where the chaos settings are stored.
+func newChaosRepository(deps RepositoryDeps) (repository.ChaosRepository, error) {
+	switch {
+	case deps.Config.IsSQL():
+		if deps.DB == nil {
+			return nil, errDBNotInitialized
+		}
+
+		return repository.NewChaosSQLRepository(deps.DB), nil
+
+	case deps.Config.IsDynamo():
+		if deps.Dynamo == nil {
+			return nil, errDynamoNotInitialized
+		}
+
+		return repository.NewDynamoChaosRepository(deps.Dynamo, deps.Config), nil
+
+	default:
+		repo, err := repository.NewChaosFileRepository(deps.Config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create chaos file repository: %w", err)
+		}
+
+		return repo, nil
+	}
+}
+
+// newWebhookJobRepository selects where the queued webhooks are stored.
+func newWebhookJobRepository(deps RepositoryDeps) (repository.WebhookJobRepository, error) {
+	switch {
+	case deps.Config.IsSQL():
+		if deps.DB == nil {
+			return nil, errDBNotInitialized
+		}
+
+		return repository.NewWebhookJobSQLRepository(deps.DB), nil
+
+	case deps.Config.IsDynamo():
+		if deps.Dynamo == nil {
+			return nil, errDynamoNotInitialized
+		}
+
+		return repository.NewDynamoWebhookJobRepository(deps.Dynamo, deps.Config), nil
+
+	default:
+		repo, err := repository.NewWebhookJobFileRepository(deps.Config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create webhook job file repository: %w", err)
+		}
+
+		return repo, nil
+	}
+}
+
+// newBinRepository selects where the responses of the request bins are stored.
+func newBinRepository(deps RepositoryDeps) (repository.BinRepository, error) {
+	switch {
+	case deps.Config.IsSQL():
+		if deps.DB == nil {
+			return nil, errDBNotInitialized
+		}
+
+		return repository.NewBinSQLRepository(deps.DB), nil
+
+	case deps.Config.IsDynamo():
+		if deps.Dynamo == nil {
+			return nil, errDynamoNotInitialized
+		}
+
+		return repository.NewDynamoBinRepository(deps.Dynamo, deps.Config), nil
+
+	default:
+		repo, err := repository.NewBinFileRepository(deps.Config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create bin file repository: %w", err)
+		}
+
+		return repo, nil
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"os"
@@ -19,9 +20,18 @@ func main() {
 
 	mapRoutes(mux, api)
 
+	// Send the queued webhooks, including the ones left by a previous run
+	api.WebhookService.Start(context.Background())
+
+	// Reload the mocks file when it is edited by hand
+	api.RuleReloadService.Start(context.Background())
+
 	// Build handler chain
 	var handler http.Handler = mux
 
+	// Apply API key authentication, a no-op unless it is configured
+	handler = api.Controllers.AuthController.Middleware(handler)
+
 	// Apply Recovery middleware
 	handler = httputils.Recovery(handler)
 
@@ -15,8 +15,17 @@ func mapRoutes(mux *http.ServeMux, api MockContainer) {
 	mux.HandleFunc("DELETE /mock-service/rules/{key}", ruleController.Delete)
 	mux.HandleFunc("PUT /mock-service/rules/{key}", ruleController.Update)
 	mux.HandleFunc("PUT /mock-service/rules/{key}/status", ruleController.UpdateStatus)
+	mux.HandleFunc("GET /mock-service/rules/{key}/revisions", ruleController.Revisions)
+	mux.HandleFunc("GET /mock-service/rules/{key}/revisions/diff", ruleController.DiffRevisions)
+	mux.HandleFunc("GET /mock-service/rules/{key}/revisions/{n}", ruleController.Revision)
+	mux.HandleFunc("POST /mock-service/rules/{key}/revisions/{n}/restore", ruleController.RestoreRevision)
 	mux.HandleFunc("GET /mock-service/rules/export", ruleController.Export)
+	mux.HandleFunc("GET /mock-service/rules/candidates", ruleController.Candidates)
 	mux.HandleFunc("POST /mock-service/rules/import", ruleController.Import)
+	mux.HandleFunc("POST /mock-service/rules/import/openapi", ruleController.ImportOpenAPI)
+
+	resourceController := api.Controllers.ResourceController
+	mux.HandleFunc("POST /mock-service/rules/{key}/resource/reset", resourceController.Reset)
 
 	mockController := api.Controllers.MockController
 	// Any method wildcard route
@@ -25,6 +34,39 @@ func mapRoutes(mux *http.ServeMux, api MockContainer) {
 	logController := api.Controllers.LogController
 	mux.HandleFunc("GET /mock-service/logs", logController.GetLogs)
 	mux.HandleFunc("DELETE /mock-service/logs", logController.ClearLogs)
+	mux.HandleFunc("GET /mock-service/logs/stream", logController.Stream)
+	mux.HandleFunc("POST /mock-service/logs/verify", logController.Verify)
+	mux.HandleFunc("POST /mock-service/logs/{id}/webhooks/{n}/redeliver", logController.RedeliverWebhook)
+
+	webhookController := api.Controllers.WebhookController
+	mux.HandleFunc("GET /mock-service/webhooks/jobs", webhookController.ListJobs)
+	mux.HandleFunc("DELETE /mock-service/webhooks/jobs/{id}", webhookController.CancelJob)
+
+	binController := api.Controllers.BinController
+	// Any method wildcard route, the captured requests are listed through /mock-service/logs?bin={name}
+	mux.HandleFunc("/mock-service/bins/{name}/{path...}", binController.Receive)
+	mux.HandleFunc("GET /mock-service/bins", binController.List)
+	mux.HandleFunc("GET /mock-service/bins/{name}", binController.Get)
+	mux.HandleFunc("PUT /mock-service/bins/{name}", binController.Save)
+	mux.HandleFunc("DELETE /mock-service/bins/{name}", binController.Delete)
+
+	scenarioController := api.Controllers.ScenarioController
+	mux.HandleFunc("GET /mock-service/scenarios", scenarioController.List)
+	mux.HandleFunc("POST /mock-service/scenarios/reset", scenarioController.ResetAll)
+	mux.HandleFunc("GET /mock-service/scenarios/{name}", scenarioController.Get)
+	mux.HandleFunc("PUT /mock-service/scenarios/{name}/state", scenarioController.SetState)
+	mux.HandleFunc("POST /mock-service/scenarios/{name}/reset", scenarioController.Reset)
+
+	chaosController := api.Controllers.ChaosController
+	mux.HandleFunc("GET /mock-service/chaos", chaosController.Get)
+	mux.HandleFunc("PUT /mock-service/chaos", chaosController.Save)
+	mux.HandleFunc("PUT /mock-service/chaos/status", chaosController.SetStatus)
+
+	authController := api.Controllers.AuthController
+	mux.HandleFunc("GET /mock-service/auth/whoami", authController.WhoAmI)
+	mux.HandleFunc("GET /mock-service/auth/keys", authController.ListKeys)
+	mux.HandleFunc("POST /mock-service/auth/keys", authController.CreateKey)
+	mux.HandleFunc("DELETE /mock-service/auth/keys/{id}", authController.DeleteKey)
 
 	mux.HandleFunc("GET /ping", ping)
 }
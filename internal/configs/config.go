@@ -2,16 +2,45 @@ package configs
 
 import (
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
+)
+
+const (
+	defaultWebhookWorkers      = 4
+	defaultRulesReloadInterval = 2 * time.Second
 )
 
 type Config struct {
 	DataSource string
-	MocksFile  string
-	Database   DatabaseConfig
-	Dynamo     DynamoConfig
-	AWS        AWSConfig
-	IsLambda   bool
+	// MocksFile is the JSON or YAML file that keeps the rules when the file datasource is used. It can also be a
+	// directory, every *.json, *.yaml and *.yml file in it holds a rule or an array of rules.
+	MocksFile string
+	// RulesReloadInterval is how often MocksFile is checked for changes made outside the API, zero
+	// disables the reload.
+	RulesReloadInterval time.Duration
+	// ScenariosFile keeps the state of the scenarios when the file datasource is used.
+	ScenariosFile string
+	// ResourcesFile keeps the entities of the resource rules when the file datasource is used.
+	ResourcesFile string
+	// ChaosFile keeps the chaos settings when the file datasource is used.
+	ChaosFile string
+	// WebhookJobsFile keeps the queued webhooks when the file datasource is used.
+	WebhookJobsFile string
+	// BinsFile keeps the responses of the request bins when the file datasource is used.
+	BinsFile string
+	// RevisionsFile keeps the revision history of the rules when the file datasource is used.
+	RevisionsFile string
+	// WebhookWorkers is the number of webhooks that are sent at the same time.
+	WebhookWorkers int
+	Database       DatabaseConfig
+	Dynamo         DynamoConfig
+	AWS            AWSConfig
+	Proxy          ProxyConfig
+	Auth           AuthConfig
+	IsLambda       bool
 }
 
 type DatabaseConfig struct {
@@ -22,6 +51,8 @@ type DatabaseConfig struct {
 	Port     string
 	Name     string
 	SSLMode  string
+	// File is the database file of the sqlite datasource.
+	File string
 }
 
 type DynamoConfig struct {
@@ -33,10 +64,51 @@ type AWSConfig struct {
 	Region string
 }
 
+// ProxyConfig holds the upstreams that unmatched mock requests are forwarded to. Target is the global
+// upstream; Groups holds comma separated "group:/path/prefix=http://upstream" entries that take
+// precedence over it. When Record is set, every proxied response is saved as a new rule.
+type ProxyConfig struct {
+	Target string
+	Groups string
+	Record bool
+}
+
+// AuthConfig protects the admin API with API keys. Keys holds comma separated "name:role:secret" static
+// keys; more keys can be created through the API and are stored in the active datasource (KeysFile for
+// the file datasource). Authentication is enabled when Enabled is set or static keys are configured.
+// Mock execution stays open unless ProtectMocks is set.
+type AuthConfig struct {
+	Enabled      bool
+	Keys         string
+	KeysFile     string
+	ProtectMocks bool
+}
+
+// IsEnabled reports whether requests must be authenticated.
+func (c AuthConfig) IsEnabled() bool {
+	return c.Enabled || strings.TrimSpace(c.Keys) != ""
+}
+
 func New() *Config {
+	mocksFile := getEnv("MOCKS_FILE", "/tmp/mocks.json")
+
 	return &Config{
-		DataSource: getEnv("MOCKS_DATASOURCE", "file"),
-		MocksFile:  getEnv("MOCKS_FILE", "/tmp/mocks.json"),
+		DataSource:          getEnv("MOCKS_DATASOURCE", "file"),
+		MocksFile:           mocksFile,
+		RulesReloadInterval: getEnvDuration("MOCKS_RELOAD_INTERVAL", defaultRulesReloadInterval),
+		ScenariosFile: getEnv("MOCKS_SCENARIOS_FILE",
+			filepath.Join(filepath.Dir(mocksFile), "scenarios.json")),
+		ResourcesFile: getEnv("MOCKS_RESOURCES_FILE",
+			filepath.Join(filepath.Dir(mocksFile), "resources.json")),
+		ChaosFile: getEnv("MOCKS_CHAOS_FILE",
+			filepath.Join(filepath.Dir(mocksFile), "chaos.json")),
+		WebhookJobsFile: getEnv("MOCKS_WEBHOOK_JOBS_FILE",
+			filepath.Join(filepath.Dir(mocksFile), "webhook-jobs.json")),
+		BinsFile: getEnv("MOCKS_BINS_FILE",
+			filepath.Join(filepath.Dir(mocksFile), "bins.json")),
+		RevisionsFile: getEnv("MOCKS_REVISIONS_FILE",
+			filepath.Join(filepath.Dir(mocksFile), "revisions.json")),
+		WebhookWorkers: getEnvInt("MOCKS_WEBHOOK_WORKERS", defaultWebhookWorkers),
 		Database: DatabaseConfig{
 			URL:      getEnv("MYSQL_URL", getEnv("POSTGRES_URL", "")),
 			User:     getEnv("DB_USER", "root"),
@@ -45,6 +117,7 @@ func New() *Config {
 			Port:     getEnv("DB_PORT", "3306"),
 			Name:     getEnv("DB_NAME", "mockserver"),
 			SSLMode:  getEnv("DB_SSLMODE", "disable"),
+			File:     getEnv("MOCKS_SQLITE_FILE", filepath.Join(filepath.Dir(mocksFile), "mocks.db")),
 		},
 		Dynamo: DynamoConfig{
 			Endpoint:    os.Getenv("DYNAMO_ENDPOINT"),
@@ -53,6 +126,17 @@ func New() *Config {
 		AWS: AWSConfig{
 			Region: getEnv("AWS_REGION", "us-east-1"),
 		},
+		Proxy: ProxyConfig{
+			Target: os.Getenv("MOCKS_PROXY_TARGET"),
+			Groups: os.Getenv("MOCKS_PROXY_GROUPS"),
+			Record: strings.EqualFold(os.Getenv("MOCKS_PROXY_RECORD"), "true"),
+		},
+		Auth: AuthConfig{
+			Enabled:      strings.EqualFold(os.Getenv("MOCKS_AUTH_ENABLED"), "true"),
+			Keys:         os.Getenv("MOCKS_AUTH_KEYS"),
+			KeysFile:     getEnv("MOCKS_AUTH_KEYS_FILE", filepath.Join(filepath.Dir(mocksFile), "api-keys.json")),
+			ProtectMocks: strings.EqualFold(os.Getenv("MOCKS_AUTH_PROTECT_MOCKS"), "true"),
+		},
 		IsLambda: os.Getenv("AWS_LAMBDA_FUNCTION_NAME") != "",
 	}
 }
@@ -65,10 +149,36 @@ func getEnv(name, defaultValue string) string {
 	return defaultValue
 }
 
+func getEnvInt(name string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(name))
+	if err != nil || value < 1 {
+		return defaultValue
+	}
+
+	return value
+}
+
+// getEnvDuration parses a duration such as "500ms" or "5s". Zero is allowed, so that a feature can be
+// turned off.
+func getEnvDuration(name string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(name))
+	if err != nil || value < 0 {
+		return defaultValue
+	}
+
+	return value
+}
+
+func (c *Config) IsFile() bool {
+	ds := strings.ToLower(c.DataSource)
+
+	return ds == "file" || ds == ""
+}
+
 func (c *Config) IsSQL() bool {
 	ds := strings.ToLower(c.DataSource)
 
-	return ds == "mysql" || ds == "postgres"
+	return ds == "mysql" || ds == "postgres" || ds == "sqlite"
 }
 
 func (c *Config) IsDynamo() bool {
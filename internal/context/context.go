@@ -7,16 +7,43 @@ import (
 	"github.com/nicopozo/mockserver/internal/utils/log"
 )
 
-type loggerKey struct{}
+type (
+	loggerKey     struct{}
+	identityKey   struct{}
+	trackingIDKey struct{}
+)
 
 func New(request *http.Request) context.Context {
 	trackingID := request.Header.Get("x-tracking-id")
+	identity := Identity(request.Context())
 
 	if len(trackingID) == 0 {
-		return context.WithValue(request.Context(), loggerKey{}, log.DefaultLogger())
+		return context.WithValue(request.Context(), loggerKey{}, log.DefaultLogger().WithIdentity(identity))
 	}
 
-	return context.WithValue(request.Context(), loggerKey{}, log.NewLogger(trackingID))
+	ctx := context.WithValue(request.Context(), trackingIDKey{}, trackingID)
+
+	return context.WithValue(ctx, loggerKey{}, log.NewLogger(trackingID).WithIdentity(identity))
+}
+
+// WithIdentity returns a copy of ctx that carries the name of the authenticated caller, so that it is
+// included in the logs of the request.
+func WithIdentity(ctx context.Context, identity string) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// Identity returns the name of the authenticated caller, or an empty string.
+func Identity(ctx context.Context) string {
+	identity, _ := ctx.Value(identityKey{}).(string)
+
+	return identity
+}
+
+// TrackingID returns the x-tracking-id header of the request, or an empty string.
+func TrackingID(ctx context.Context) string {
+	trackingID, _ := ctx.Value(trackingIDKey{}).(string)
+
+	return trackingID
 }
 
 func Logger(ctx context.Context) log.ILogger {
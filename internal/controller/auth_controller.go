@@ -0,0 +1,231 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	mockscontext "github.com/nicopozo/mockserver/internal/context"
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
+	"github.com/nicopozo/mockserver/internal/model"
+	"github.com/nicopozo/mockserver/internal/service"
+	httputils "github.com/nicopozo/mockserver/internal/utils/http"
+)
+
+const (
+	apiKeyHeader = "X-API-Key"
+	bearerPrefix = "Bearer "
+)
+
+// AuthController protects the admin API and manages its API keys.
+type AuthController struct {
+	AuthService service.AuthService
+}
+
+func NewAuthController(authService service.AuthService) *AuthController {
+	return &AuthController{
+		AuthService: authService,
+	}
+}
+
+// Middleware rejects the requests that do not carry an API key with the role required by the route.
+// The identity of the key is added to the request context so that it shows up in the request logs.
+func (controller *AuthController) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if !controller.AuthService.Enabled() {
+			next.ServeHTTP(writer, request)
+
+			return
+		}
+
+		role, protected := controller.requiredRole(request)
+		if !protected {
+			next.ServeHTTP(writer, request)
+
+			return
+		}
+
+		reqContext := mockscontext.New(request)
+		logger := mockscontext.Logger(reqContext)
+
+		key, err := controller.AuthService.Authenticate(reqContext, apiKeyFromRequest(request))
+		if err != nil {
+			if errors.As(err, &mockserrors.UnauthorizedError{}) {
+				logger.Debug(controller, nil, "Rejected request to %s: %s", request.URL.Path, err.Error())
+				writer.Header().Set("WWW-Authenticate", `Bearer realm="mock-service"`)
+				httputils.WriteError(writer, model.Unauthorized, "%s", err.Error())
+
+				return
+			}
+
+			logger.Error(controller, nil, err, "Error authenticating request")
+			httputils.WriteError(writer, model.InternalError, "Error occurred when authenticating. %s", err.Error())
+
+			return
+		}
+
+		if !key.Allows(role) {
+			logger.Debug(controller, map[string]string{"identity": key.Name}, "Rejected request to %s, role %s required",
+				request.URL.Path, role)
+			httputils.WriteError(writer, model.Forbidden, "api key '%s' has role %s, %s is required", key.Name,
+				key.Role, role)
+
+			return
+		}
+
+		next.ServeHTTP(writer, request.WithContext(mockscontext.WithIdentity(request.Context(), key.Name)))
+	})
+}
+
+// requiredRole returns the role needed to call the route, and false for the routes that are open.
+func (controller *AuthController) requiredRole(request *http.Request) (string, bool) {
+	path := request.URL.Path
+
+	switch {
+	case request.Method == http.MethodOptions,
+		path == "/ping",
+		strings.HasPrefix(path, "/mock-service/admin/"):
+		return "", false
+	case strings.HasPrefix(path, "/mock-service/mock/"), isBinCapture(path):
+		return model.RoleReadOnly, controller.AuthService.ProtectMocks()
+	case path == "/mock-service/auth/whoami":
+		return model.RoleReadOnly, true
+	case strings.HasPrefix(path, "/mock-service/auth/"):
+		return model.RoleAdmin, true
+	case !strings.HasPrefix(path, "/mock-service/"):
+		return "", false
+	case request.Method == http.MethodGet || request.Method == http.MethodHead,
+		request.Method == http.MethodPost && path == "/mock-service/logs/verify":
+		return model.RoleReadOnly, true
+	}
+
+	return model.RoleEditor, true
+}
+
+// isBinCapture reports whether path sends a request to a bin, which is protected like the mocks, rather
+// than managing the bin.
+func isBinCapture(path string) bool {
+	name, found := strings.CutPrefix(path, "/mock-service/bins/")
+
+	return found && strings.Contains(name, "/")
+}
+
+func apiKeyFromRequest(request *http.Request) string {
+	if key := request.Header.Get(apiKeyHeader); key != "" {
+		return key
+	}
+
+	authorization := request.Header.Get("Authorization")
+	if len(authorization) > len(bearerPrefix) && strings.EqualFold(authorization[:len(bearerPrefix)], bearerPrefix) {
+		return authorization[len(bearerPrefix):]
+	}
+
+	return ""
+}
+
+// WhoAmI returns the API key used in the request.
+func (controller *AuthController) WhoAmI(writer http.ResponseWriter, request *http.Request) {
+	reqContext := mockscontext.New(request)
+	logger := mockscontext.Logger(reqContext)
+
+	logger.Debug(controller, nil, "Entering AuthController WhoAmI()")
+
+	if !controller.AuthService.Enabled() {
+		httputils.WriteJSON(writer, http.StatusOK, model.APIKey{Name: "anonymous", Role: model.RoleAdmin})
+
+		return
+	}
+
+	key, err := controller.AuthService.Authenticate(reqContext, apiKeyFromRequest(request))
+	if err != nil {
+		httputils.WriteError(writer, model.Unauthorized, "%s", err.Error())
+
+		return
+	}
+
+	key.SecretHash = ""
+
+	httputils.WriteJSON(writer, http.StatusOK, key)
+}
+
+// CreateKey creates an API key. The response holds the secret, which cannot be retrieved again.
+func (controller *AuthController) CreateKey(writer http.ResponseWriter, request *http.Request) {
+	reqContext := mockscontext.New(request)
+	logger := mockscontext.Logger(reqContext)
+
+	logger.Debug(controller, nil, "Entering AuthController CreateKey()")
+
+	key, err := model.UnmarshalAPIKey(request.Body)
+	if err != nil {
+		logger.Error(controller, nil, err, "Error unmarshalling API key JSON")
+		httputils.WriteError(writer, model.ValidationError, "Invalid JSON. %s", err.Error())
+
+		return
+	}
+
+	created, err := controller.AuthService.CreateKey(reqContext, *key)
+	if err != nil {
+		if errors.As(err, &mockserrors.InvalidRulesError{}) {
+			httputils.WriteError(writer, model.ValidationError, "%s", err.Error())
+
+			return
+		}
+
+		logger.Error(controller, nil, err, "Error occurred when creating API key")
+		httputils.WriteError(writer, model.InternalError, "Error occurred when creating API key. %s", err.Error())
+
+		return
+	}
+
+	logger.Info(controller, map[string]string{"key_id": created.ID}, "API key %s created with role %s",
+		created.Name, created.Role)
+
+	httputils.WriteJSON(writer, http.StatusCreated, created)
+}
+
+// ListKeys returns the API keys without their secrets.
+func (controller *AuthController) ListKeys(writer http.ResponseWriter, request *http.Request) {
+	reqContext := mockscontext.New(request)
+	logger := mockscontext.Logger(reqContext)
+
+	logger.Debug(controller, nil, "Entering AuthController ListKeys()")
+
+	keys, err := controller.AuthService.ListKeys(reqContext)
+	if err != nil {
+		logger.Error(controller, nil, err, "Error occurred when listing API keys")
+		httputils.WriteError(writer, model.InternalError, "Error occurred when listing API keys. %s", err.Error())
+
+		return
+	}
+
+	httputils.WriteJSON(writer, http.StatusOK, keys)
+}
+
+// DeleteKey revokes an API key.
+func (controller *AuthController) DeleteKey(writer http.ResponseWriter, request *http.Request) {
+	reqContext := mockscontext.New(request)
+	logger := mockscontext.Logger(reqContext)
+
+	logger.Debug(controller, nil, "Entering AuthController DeleteKey()")
+
+	id := request.PathValue("id")
+
+	err := controller.AuthService.DeleteKey(reqContext, id)
+	if err != nil {
+		switch {
+		case errors.As(err, &mockserrors.APIKeyNotFoundError{}):
+			httputils.WriteError(writer, model.ResourceNotFoundError, "%s", err.Error())
+		case errors.As(err, &mockserrors.InvalidRulesError{}):
+			httputils.WriteError(writer, model.ValidationError, "%s", err.Error())
+		default:
+			logger.Error(controller, nil, err, "Error occurred when deleting API key %s", id)
+			httputils.WriteError(writer, model.InternalError, "Error occurred when deleting API key. %s", err.Error())
+		}
+
+		return
+	}
+
+	logger.Info(controller, map[string]string{"key_id": id}, "API key revoked")
+
+	writer.WriteHeader(http.StatusNoContent)
+}
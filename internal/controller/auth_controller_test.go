@@ -0,0 +1,136 @@
+package controller_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/nicopozo/mockserver/internal/configs"
+	mockscontext "github.com/nicopozo/mockserver/internal/context"
+	"github.com/nicopozo/mockserver/internal/controller"
+	"github.com/nicopozo/mockserver/internal/repository"
+	"github.com/nicopozo/mockserver/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func newAuthMiddleware(t *testing.T, auth configs.AuthConfig) http.Handler {
+	t.Helper()
+
+	auth.KeysFile = filepath.Join(t.TempDir(), "api-keys.json")
+	cfg := &configs.Config{Auth: auth}
+
+	repo, err := repository.NewAPIKeyFileRepository(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authService, err := service.NewAuthService(cfg, repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	next := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("X-Identity", mockscontext.Identity(request.Context()))
+		writer.WriteHeader(http.StatusOK)
+	})
+
+	return controller.NewAuthController(authService).Middleware(next)
+}
+
+func TestAuthController_Middleware(t *testing.T) {
+	staticKeys := "viewer:read-only:viewer-secret,ci:editor:ci-secret,ops:admin:ops-secret"
+
+	tests := []struct {
+		name         string
+		auth         configs.AuthConfig
+		method       string
+		path         string
+		headers      map[string]string
+		wantStatus   int
+		wantIdentity string
+	}{
+		{
+			name: "Should not authenticate when disabled", method: http.MethodDelete, path: "/mock-service/rules/a1",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "Should reject missing key", auth: configs.AuthConfig{Keys: staticKeys},
+			method: http.MethodGet, path: "/mock-service/rules", wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "Should reject unknown key", auth: configs.AuthConfig{Keys: staticKeys},
+			method: http.MethodGet, path: "/mock-service/logs", headers: map[string]string{"X-API-Key": "nope"},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "Should let read-only keys read", auth: configs.AuthConfig{Keys: staticKeys},
+			method: http.MethodGet, path: "/mock-service/rules", headers: map[string]string{"X-API-Key": "viewer-secret"},
+			wantStatus: http.StatusOK, wantIdentity: "viewer",
+		},
+		{
+			name: "Should let read-only keys verify", auth: configs.AuthConfig{Keys: staticKeys},
+			method: http.MethodPost, path: "/mock-service/logs/verify",
+			headers:    map[string]string{"X-API-Key": "viewer-secret"},
+			wantStatus: http.StatusOK, wantIdentity: "viewer",
+		},
+		{
+			name: "Should forbid read-only keys to write", auth: configs.AuthConfig{Keys: staticKeys},
+			method: http.MethodDelete, path: "/mock-service/rules/a1",
+			headers: map[string]string{"X-API-Key": "viewer-secret"}, wantStatus: http.StatusForbidden,
+		},
+		{
+			name: "Should accept bearer tokens", auth: configs.AuthConfig{Keys: staticKeys},
+			method: http.MethodPost, path: "/mock-service/rules",
+			headers:    map[string]string{"Authorization": "Bearer ci-secret"},
+			wantStatus: http.StatusOK, wantIdentity: "ci",
+		},
+		{
+			name: "Should forbid editors to manage keys", auth: configs.AuthConfig{Keys: staticKeys},
+			method: http.MethodGet, path: "/mock-service/auth/keys", headers: map[string]string{"X-API-Key": "ci-secret"},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name: "Should let admins manage keys", auth: configs.AuthConfig{Keys: staticKeys},
+			method: http.MethodPost, path: "/mock-service/auth/keys", headers: map[string]string{"X-API-Key": "ops-secret"},
+			wantStatus: http.StatusOK, wantIdentity: "ops",
+		},
+		{
+			name: "Should keep mocks open by default", auth: configs.AuthConfig{Keys: staticKeys},
+			method: http.MethodPost, path: "/mock-service/mock/v1/payments", wantStatus: http.StatusOK,
+		},
+		{
+			name: "Should protect mocks when configured", auth: configs.AuthConfig{Keys: staticKeys, ProtectMocks: true},
+			method: http.MethodPost, path: "/mock-service/mock/v1/payments", wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "Should keep bins open like the mocks", auth: configs.AuthConfig{Keys: staticKeys},
+			method: http.MethodPost, path: "/mock-service/bins/callbacks/done", wantStatus: http.StatusOK,
+		},
+		{
+			name: "Should require an editor to configure bins", auth: configs.AuthConfig{Keys: staticKeys},
+			method: http.MethodPut, path: "/mock-service/bins/callbacks",
+			headers: map[string]string{"X-API-Key": "viewer-secret"}, wantStatus: http.StatusForbidden,
+		},
+		{
+			name: "Should keep the UI and ping open", auth: configs.AuthConfig{Enabled: true, Keys: staticKeys},
+			method: http.MethodGet, path: "/mock-service/admin/index.html", wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(tt.method, tt.path, nil)
+			for name, value := range tt.headers {
+				request.Header.Set(name, value)
+			}
+
+			response := httptest.NewRecorder()
+
+			newAuthMiddleware(t, tt.auth).ServeHTTP(response, request)
+
+			assert.Equal(t, tt.wantStatus, response.Code)
+			assert.Equal(t, tt.wantIdentity, response.Header().Get("X-Identity"))
+		})
+	}
+}
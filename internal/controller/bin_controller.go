@@ -0,0 +1,178 @@
+package controller
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	mockscontext "github.com/nicopozo/mockserver/internal/context"
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
+	"github.com/nicopozo/mockserver/internal/model"
+	"github.com/nicopozo/mockserver/internal/service"
+	httputils "github.com/nicopozo/mockserver/internal/utils/http"
+	"github.com/nicopozo/mockserver/internal/utils/log"
+)
+
+// BinController captures the requests sent to the request bins and manages the bin responses. The
+// captured requests are listed, verified and cleared through the log endpoints with ?bin=<name>.
+type BinController struct {
+	BinService service.BinService
+	LogService service.LogService
+}
+
+func NewBinController(binService service.BinService, logService service.LogService) *BinController {
+	return &BinController{
+		BinService: binService,
+		LogService: logService,
+	}
+}
+
+// Receive records a request sent to a bin and answers it with the response configured for the bin.
+func (controller *BinController) Receive(writer http.ResponseWriter, request *http.Request) {
+	reqContext := mockscontext.New(request)
+	logger := mockscontext.Logger(reqContext)
+
+	logger.Debug(controller, nil, "Entering BinController Receive()")
+
+	name := request.PathValue("name")
+
+	bin, err := controller.BinService.Get(reqContext, name)
+	if err != nil {
+		controller.writeError(writer, logger, name, err)
+
+		return
+	}
+
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		logger.Error(controller, nil, err, "Error reading the body sent to bin %s", name)
+		httputils.WriteError(writer, model.ValidationError, "Error reading body. %s", err.Error())
+
+		return
+	}
+
+	entry := newLogEntry(request, "/"+request.PathValue("path"), string(body))
+	entry.Bin = bin.Name
+
+	if bin.ContentType != "" {
+		writer.Header().Set("Content-Type", bin.ContentType)
+	}
+
+	for header, value := range bin.Headers {
+		writer.Header().Set(header, value)
+	}
+
+	time.Sleep(time.Duration(bin.Delay) * time.Millisecond)
+
+	writer.WriteHeader(bin.Status)
+
+	_, _ = writer.Write([]byte(bin.Body))
+
+	entry.ResponseStatus = bin.Status
+	entry.ResponseBody = bin.Body
+	entry.DurationMs = time.Since(entry.Timestamp).Milliseconds()
+	controller.LogService.Add(entry)
+}
+
+// List returns the bins with a configured response.
+func (controller *BinController) List(writer http.ResponseWriter, request *http.Request) {
+	reqContext := mockscontext.New(request)
+	logger := mockscontext.Logger(reqContext)
+
+	logger.Debug(controller, nil, "Entering BinController List()")
+
+	bins, err := controller.BinService.List(reqContext)
+	if err != nil {
+		logger.Error(controller, nil, err, "Error occurred when listing bins")
+		httputils.WriteError(writer, model.InternalError, "Error occurred when listing bins. %s", err.Error())
+
+		return
+	}
+
+	httputils.WriteJSON(writer, http.StatusOK, bins)
+}
+
+// Get returns the response of a bin, the default one when the bin has not been configured.
+func (controller *BinController) Get(writer http.ResponseWriter, request *http.Request) {
+	reqContext := mockscontext.New(request)
+	logger := mockscontext.Logger(reqContext)
+
+	logger.Debug(controller, nil, "Entering BinController Get()")
+
+	name := request.PathValue("name")
+
+	bin, err := controller.BinService.Get(reqContext, name)
+	if err != nil {
+		controller.writeError(writer, logger, name, err)
+
+		return
+	}
+
+	httputils.WriteJSON(writer, http.StatusOK, bin)
+}
+
+// Save configures the response of a bin.
+func (controller *BinController) Save(writer http.ResponseWriter, request *http.Request) {
+	reqContext := mockscontext.New(request)
+	logger := mockscontext.Logger(reqContext)
+
+	logger.Debug(controller, nil, "Entering BinController Save()")
+
+	bin, err := model.UnmarshalBin(request.Body)
+	if err != nil {
+		logger.Error(controller, nil, err, "Error unmarshalling bin JSON")
+		httputils.WriteError(writer, model.ValidationError, "Invalid JSON. %s", err.Error())
+
+		return
+	}
+
+	bin.Name = request.PathValue("name")
+
+	saved, err := controller.BinService.Save(reqContext, *bin)
+	if err != nil {
+		controller.writeError(writer, logger, bin.Name, err)
+
+		return
+	}
+
+	httputils.WriteJSON(writer, http.StatusOK, saved)
+}
+
+// Delete removes the response of a bin, which answers with the default response from then on. The
+// captured requests are kept.
+func (controller *BinController) Delete(writer http.ResponseWriter, request *http.Request) {
+	reqContext := mockscontext.New(request)
+	logger := mockscontext.Logger(reqContext)
+
+	logger.Debug(controller, nil, "Entering BinController Delete()")
+
+	name := request.PathValue("name")
+
+	if err := controller.BinService.Delete(reqContext, name); err != nil {
+		controller.writeError(writer, logger, name, err)
+
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+func (controller *BinController) writeError(writer http.ResponseWriter, logger log.ILogger, name string,
+	err error,
+) {
+	if errors.As(err, &mockserrors.InvalidRulesError{}) {
+		httputils.WriteError(writer, model.ValidationError, "%s", err.Error())
+
+		return
+	}
+
+	if errors.As(err, &mockserrors.BinNotFoundError{}) {
+		httputils.WriteError(writer, model.ResourceNotFoundError, "%s", err.Error())
+
+		return
+	}
+
+	logger.Error(controller, nil, err, "Error occurred when handling bin %s", name)
+	httputils.WriteError(writer, model.InternalError, "Error occurred when handling bin %s. %s", name, err.Error())
+}
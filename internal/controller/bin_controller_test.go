@@ -0,0 +1,113 @@
+package controller_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nicopozo/mockserver/internal/configs"
+	"github.com/nicopozo/mockserver/internal/controller"
+	"github.com/nicopozo/mockserver/internal/model"
+	"github.com/nicopozo/mockserver/internal/repository"
+	"github.com/nicopozo/mockserver/internal/service"
+	jsonutils "github.com/nicopozo/mockserver/internal/utils/json"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBinController_CaptureAndVerify(t *testing.T) {
+	binRepo, err := repository.NewBinFileRepository(&configs.Config{BinsFile: filepath.Join(t.TempDir(), "bins.json")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	binService, err := service.NewBinService(binRepo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logService := service.NewLogService(repository.NewLogMemoryRepository())
+	binController := controller.NewBinController(binService, logService)
+	logController := controller.NewLogController(logService, newWebhookService(t, logService))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/mock-service/bins/{name}/{path...}", binController.Receive)
+	mux.HandleFunc("PUT /mock-service/bins/{name}", binController.Save)
+	mux.HandleFunc("GET /mock-service/logs", logController.GetLogs)
+	mux.HandleFunc("DELETE /mock-service/logs", logController.ClearLogs)
+	mux.HandleFunc("POST /mock-service/logs/verify", logController.Verify)
+
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		response := httptest.NewRecorder()
+		mux.ServeHTTP(response, httptest.NewRequest(method, path, strings.NewReader(body)))
+
+		return response
+	}
+
+	response := serve(http.MethodPut, "/mock-service/bins/callbacks",
+		`{"status":202,"content_type":"application/json","body":"{\"received\":true}"}`)
+	if !assert.Equal(t, http.StatusOK, response.Code, response.Body.String()) {
+		return
+	}
+
+	response = serve(http.MethodPost, "/mock-service/bins/callbacks/payments/42?attempt=1", `{"status":"paid"}`)
+	assert.Equal(t, http.StatusAccepted, response.Code)
+	assert.Equal(t, "application/json", response.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"received":true}`, response.Body.String())
+
+	response = serve(http.MethodGet, "/mock-service/bins/other/", "")
+	assert.Equal(t, http.StatusOK, response.Code, "a bin captures requests without any setup")
+
+	response = serve(http.MethodGet, "/mock-service/logs?bin=callbacks", "")
+
+	var logs model.LogList
+	if !assert.NoError(t, jsonutils.Unmarshal(response.Body, &logs)) || !assert.Len(t, logs.Results, 1) {
+		return
+	}
+
+	assert.Equal(t, "callbacks", logs.Results[0].Bin)
+	assert.Equal(t, "/payments/42?attempt=1", logs.Results[0].URL)
+	assert.Equal(t, `{"status":"paid"}`, logs.Results[0].RequestBody)
+	assert.Equal(t, http.StatusAccepted, logs.Results[0].ResponseStatus)
+
+	response = serve(http.MethodGet, "/mock-service/logs", "")
+	assert.NoError(t, jsonutils.Unmarshal(response.Body, &logs))
+	assert.Empty(t, logs.Results, "bin requests are not listed with the mock requests")
+
+	tests := []struct {
+		name     string
+		body     string
+		wantPass bool
+	}{
+		{
+			name:     "Should verify the requests of the bin",
+			body:     `{"bin":"callbacks","method":"POST","path":"/payments/42","count":{"exactly":1}}`,
+			wantPass: true,
+		},
+		{
+			name:     "Should not verify the requests of another bin",
+			body:     `{"bin":"other","method":"POST","path":"/payments/42","count":{"exactly":1}}`,
+			wantPass: false,
+		},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			response := serve(http.MethodPost, "/mock-service/logs/verify", tt.body)
+			assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+			var result model.VerificationResult
+			if assert.NoError(t, jsonutils.Unmarshal(response.Body, &result)) {
+				assert.Equal(t, tt.wantPass, result.Passed)
+			}
+		})
+	}
+
+	response = serve(http.MethodDelete, "/mock-service/logs?bin=callbacks", "")
+	assert.Equal(t, http.StatusNoContent, response.Code)
+
+	response = serve(http.MethodGet, "/mock-service/logs?bin=callbacks", "")
+	assert.NoError(t, jsonutils.Unmarshal(response.Body, &logs))
+	assert.Empty(t, logs.Results)
+}
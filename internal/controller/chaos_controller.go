@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	mockscontext "github.com/nicopozo/mockserver/internal/context"
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
+	"github.com/nicopozo/mockserver/internal/model"
+	"github.com/nicopozo/mockserver/internal/service"
+	httputils "github.com/nicopozo/mockserver/internal/utils/http"
+	"github.com/nicopozo/mockserver/internal/utils/log"
+)
+
+// ChaosController manages the global chaos switch, the seed and the chaos policies of the groups.
+type ChaosController struct {
+	ChaosService service.ChaosService
+}
+
+func NewChaosController(chaosService service.ChaosService) *ChaosController {
+	return &ChaosController{
+		ChaosService: chaosService,
+	}
+}
+
+// Get returns the chaos settings.
+func (controller *ChaosController) Get(writer http.ResponseWriter, request *http.Request) {
+	reqContext := mockscontext.New(request)
+	logger := mockscontext.Logger(reqContext)
+
+	logger.Debug(controller, nil, "Entering ChaosController Get()")
+
+	settings, err := controller.ChaosService.Get(reqContext)
+	if err != nil {
+		logger.Error(controller, nil, err, "Error occurred when getting chaos settings")
+		httputils.WriteError(writer, model.InternalError, "Error occurred when getting chaos settings. %s",
+			err.Error())
+
+		return
+	}
+
+	httputils.WriteJSON(writer, http.StatusOK, settings)
+}
+
+// Save replaces the chaos settings and restarts the random decisions from the seed.
+func (controller *ChaosController) Save(writer http.ResponseWriter, request *http.Request) {
+	reqContext := mockscontext.New(request)
+	logger := mockscontext.Logger(reqContext)
+
+	logger.Debug(controller, nil, "Entering ChaosController Save()")
+
+	settings, err := model.UnmarshalChaosSettings(request.Body)
+	if err != nil {
+		logger.Error(controller, nil, err, "Error unmarshalling chaos settings JSON")
+		httputils.WriteError(writer, model.ValidationError, "Invalid JSON. %s", err.Error())
+
+		return
+	}
+
+	saved, err := controller.ChaosService.Save(reqContext, *settings)
+	controller.writeSettings(writer, logger, saved, err)
+}
+
+// SetStatus switches every chaos policy on or off.
+func (controller *ChaosController) SetStatus(writer http.ResponseWriter, request *http.Request) {
+	reqContext := mockscontext.New(request)
+	logger := mockscontext.Logger(reqContext)
+
+	logger.Debug(controller, nil, "Entering ChaosController SetStatus()")
+
+	status, err := model.UnmarshalChaosStatus(request.Body)
+	if err != nil {
+		logger.Error(controller, nil, err, "Error unmarshalling chaos status JSON")
+		httputils.WriteError(writer, model.ValidationError, "Invalid JSON. %s", err.Error())
+
+		return
+	}
+
+	saved, err := controller.ChaosService.SetStatus(reqContext, status.Status)
+	controller.writeSettings(writer, logger, saved, err)
+}
+
+func (controller *ChaosController) writeSettings(writer http.ResponseWriter, logger log.ILogger,
+	settings *model.ChaosSettings, err error,
+) {
+	if err != nil {
+		if errors.As(err, &mockserrors.InvalidRulesError{}) {
+			httputils.WriteError(writer, model.ValidationError, "%s", err.Error())
+
+			return
+		}
+
+		logger.Error(controller, nil, err, "Error occurred when saving chaos settings")
+		httputils.WriteError(writer, model.InternalError, "Error occurred when saving chaos settings. %s",
+			err.Error())
+
+		return
+	}
+
+	httputils.WriteJSON(writer, http.StatusOK, settings)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/nicopozo/mockserver/internal/model"
 )
@@ -65,3 +66,48 @@ func getParametersFromRequest(request *http.Request) map[string]any {
 
 	return params
 }
+
+func getLogFilterFromRequest(request *http.Request) (model.LogFilter, error) {
+	query := request.URL.Query()
+	filter := model.LogFilter{
+		Method:   query.Get("method"),
+		URL:      query.Get("url"),
+		URLRegex: query.Get("url_regex"),
+		RuleKey:  query.Get("rule_key"),
+		Bin:      query.Get("bin"),
+	}
+
+	var err error
+
+	for name, target := range map[string]*int{"status_from": &filter.StatusFrom, "status_to": &filter.StatusTo} {
+		if value := query.Get(name); value != "" {
+			if *target, err = strconv.Atoi(value); err != nil {
+				return model.LogFilter{}, fmt.Errorf("error parsing %s, %w", name, err)
+			}
+		}
+	}
+
+	for name, target := range map[string]*time.Time{"from": &filter.From, "to": &filter.To} {
+		if value := query.Get(name); value != "" {
+			if *target, err = time.Parse(time.RFC3339, value); err != nil {
+				return model.LogFilter{}, fmt.Errorf("error parsing %s, %w", name, err)
+			}
+		}
+	}
+
+	for name, target := range map[string]**bool{
+		"has_assertion_errors": &filter.HasAssertionErrors,
+		"has_webhook_failures": &filter.HasWebhookFailures,
+	} {
+		if value := query.Get(name); value != "" {
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				return model.LogFilter{}, fmt.Errorf("error parsing %s, %w", name, err)
+			}
+
+			*target = &parsed
+		}
+	}
+
+	return filter, nil
+}
@@ -1,26 +1,36 @@
 package controller
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	mockscontext "github.com/nicopozo/mockserver/internal/context"
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
 	"github.com/nicopozo/mockserver/internal/model"
 	"github.com/nicopozo/mockserver/internal/service"
 	httputils "github.com/nicopozo/mockserver/internal/utils/http"
+	jsonutils "github.com/nicopozo/mockserver/internal/utils/json"
 )
 
+const streamKeepAliveInterval = 15 * time.Second
+
 // LogController exposes the in-memory request/response logs.
 type LogController struct {
-	LogService service.LogService
+	LogService     service.LogService
+	WebhookService service.WebhookService
 }
 
-func NewLogController(logService service.LogService) *LogController {
+func NewLogController(logService service.LogService, webhookService service.WebhookService) *LogController {
 	return &LogController{
-		LogService: logService,
+		LogService:     logService,
+		WebhookService: webhookService,
 	}
 }
 
-// GetLogs returns captured log entries with pagination.
+// GetLogs returns captured log entries with pagination, optionally filtered.
 func (controller *LogController) GetLogs(writer http.ResponseWriter, request *http.Request) {
 	reqContext := mockscontext.New(request)
 	logger := mockscontext.Logger(reqContext)
@@ -35,18 +45,200 @@ func (controller *LogController) GetLogs(writer http.ResponseWriter, request *ht
 		return
 	}
 
-	logs := controller.LogService.GetAll(*paging)
+	filter, err := getLogFilterFromRequest(request)
+	if err != nil {
+		logger.Error(controller, nil, err, "Error parsing log filters")
+		httputils.WriteError(writer, model.ValidationError, "Error parsing log filters: %s", err.Error())
+
+		return
+	}
+
+	logs, err := controller.LogService.GetAll(filter, *paging)
+	if err != nil {
+		if errors.As(err, &mockserrors.InvalidRulesError{}) {
+			httputils.WriteError(writer, model.ValidationError, "Invalid log filters. %s", err.Error())
+
+			return
+		}
+
+		logger.Error(controller, nil, err, "Failed to get logs")
+		httputils.WriteError(writer, model.InternalError, "Error occurred when getting logs. %s", err.Error())
+
+		return
+	}
 
 	httputils.WriteJSON(writer, http.StatusOK, logs)
 }
 
-// ClearLogs deletes all captured log entries.
+// Verify checks whether the captured requests match the given criteria the expected number of times.
+func (controller *LogController) Verify(writer http.ResponseWriter, request *http.Request) {
+	reqContext := mockscontext.New(request)
+	logger := mockscontext.Logger(reqContext)
+
+	logger.Debug(controller, nil, "Entering LogController Verify()")
+
+	verification, err := model.UnmarshalVerificationRequest(request.Body)
+	if err != nil {
+		logger.Error(controller, nil, err, "Error unmarshalling verification JSON")
+		httputils.WriteError(writer, model.ValidationError, "Invalid JSON. %s", err.Error())
+
+		return
+	}
+
+	result, err := controller.LogService.Verify(*verification)
+	if err != nil {
+		if errors.As(err, &mockserrors.InvalidRulesError{}) {
+			httputils.WriteError(writer, model.ValidationError, "Invalid verification. %s", err.Error())
+
+			return
+		}
+
+		logger.Error(controller, nil, err, "Failed to verify requests")
+		httputils.WriteError(writer, model.InternalError, "Error occurred when verifying requests. %s", err.Error())
+
+		return
+	}
+
+	httputils.WriteJSON(writer, http.StatusOK, result)
+}
+
+// Stream pushes the new log entries and their updates as Server-Sent Events until the client disconnects.
+// It accepts the same filters as GetLogs.
+func (controller *LogController) Stream(writer http.ResponseWriter, request *http.Request) {
+	reqContext := mockscontext.New(request)
+	logger := mockscontext.Logger(reqContext)
+
+	logger.Debug(controller, nil, "Entering LogController Stream()")
+
+	filter, err := getLogFilterFromRequest(request)
+	if err != nil {
+		logger.Error(controller, nil, err, "Error parsing log filters")
+		httputils.WriteError(writer, model.ValidationError, "Error parsing log filters: %s", err.Error())
+
+		return
+	}
+
+	subscription, err := controller.LogService.Subscribe(filter)
+	if err != nil {
+		httputils.WriteError(writer, model.ValidationError, "Invalid log filters. %s", err.Error())
+
+		return
+	}
+	defer subscription.Close()
+
+	responseController := http.NewResponseController(writer)
+
+	writer.Header().Set("Content-Type", "text/event-stream")
+	writer.Header().Set("Cache-Control", "no-cache")
+	writer.Header().Set("Connection", "keep-alive")
+	writer.Header().Set("X-Accel-Buffering", "no")
+	writer.WriteHeader(http.StatusOK)
+
+	if err := responseController.Flush(); err != nil {
+		logger.Error(controller, nil, err, "Streaming is not supported by the response writer")
+
+		return
+	}
+
+	keepAlive := time.NewTicker(streamKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	var dropped uint64
+
+	for {
+		select {
+		case <-request.Context().Done():
+			return
+		case <-keepAlive.C:
+			_, err = fmt.Fprint(writer, ": keep-alive\n\n")
+		case event, ok := <-subscription.Events():
+			if !ok {
+				return
+			}
+
+			if current := subscription.Dropped(); current != dropped {
+				_, _ = fmt.Fprintf(writer, "event: dropped\ndata: {\"dropped\":%d}\n\n", current-dropped)
+				dropped = current
+			}
+
+			_, err = fmt.Fprintf(writer, "id: %s\nevent: %s\ndata: %s\n\n", event.Entry.ID, event.Type,
+				jsonutils.Marshal(event.Entry))
+		}
+
+		if err == nil {
+			err = responseController.Flush()
+		}
+
+		if err != nil {
+			logger.Debug(controller, nil, "Log stream closed: %s", err.Error())
+
+			return
+		}
+	}
+}
+
+// RedeliverWebhook sends again the webhook request recorded at position n of the webhook results of a
+// log entry. The outcome is added to the entry and returned.
+func (controller *LogController) RedeliverWebhook(writer http.ResponseWriter, request *http.Request) {
+	reqContext := mockscontext.New(request)
+	logger := mockscontext.Logger(reqContext)
+
+	logger.Debug(controller, nil, "Entering LogController RedeliverWebhook()")
+
+	logID := request.PathValue("id")
+
+	index, err := strconv.Atoi(request.PathValue("n"))
+	if err != nil || index < 0 {
+		httputils.WriteError(writer, model.ValidationError, "Invalid webhook position '%s'", request.PathValue("n"))
+
+		return
+	}
+
+	entry, err := controller.LogService.Get(logID)
+	if err != nil {
+		if errors.As(err, &mockserrors.LogEntryNotFoundError{}) {
+			httputils.WriteError(writer, model.ResourceNotFoundError, "%s", err.Error())
+
+			return
+		}
+
+		logger.Error(controller, nil, err, "Failed to get log entry")
+		httputils.WriteError(writer, model.InternalError, "Error occurred when getting log entry. %s", err.Error())
+
+		return
+	}
+
+	if index >= len(entry.WebhookResults) {
+		httputils.WriteError(writer, model.ResourceNotFoundError, "webhook %d not found in log entry %s", index,
+			logID)
+
+		return
+	}
+
+	result := controller.WebhookService.Redeliver(reqContext, entry.RuleKey, entry.WebhookResults[index])
+
+	err = controller.LogService.Update(logID, func(entry *model.LogEntry) {
+		entry.AddRedelivery(index, result)
+		result = entry.WebhookResults[len(entry.WebhookResults)-1]
+	})
+	if err != nil {
+		logger.Error(controller, nil, err, "Failed to record webhook redelivery")
+		httputils.WriteError(writer, model.InternalError, "Error occurred when recording webhook redelivery. %s",
+			err.Error())
+
+		return
+	}
+
+	httputils.WriteJSON(writer, http.StatusOK, result)
+}
+
+// ClearLogs deletes all captured log entries, or the requests captured by a bin with ?bin=.
 func (controller *LogController) ClearLogs(writer http.ResponseWriter, request *http.Request) {
 	reqContext := mockscontext.New(request)
 	logger := mockscontext.Logger(reqContext)
 
 	logger.Debug(controller, nil, "Entering LogController ClearLogs()")
 
-	controller.LogService.Clear()
+	controller.LogService.Clear(request.URL.Query().Get("bin"))
 	writer.WriteHeader(http.StatusNoContent)
 }
@@ -0,0 +1,156 @@
+package controller_test
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nicopozo/mockserver/internal/configs"
+	"github.com/nicopozo/mockserver/internal/controller"
+	"github.com/nicopozo/mockserver/internal/model"
+	"github.com/nicopozo/mockserver/internal/repository"
+	"github.com/nicopozo/mockserver/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+// newWebhookService creates a WebhookService that queues the webhooks in a temporary file.
+func newWebhookService(t *testing.T, logService service.LogService) service.WebhookService {
+	t.Helper()
+
+	dir := t.TempDir()
+	cfg := &configs.Config{
+		MocksFile:       filepath.Join(dir, "mocks.json"),
+		RevisionsFile:   filepath.Join(dir, "revisions.json"),
+		WebhookJobsFile: filepath.Join(dir, "webhook-jobs.json"),
+	}
+
+	repo, err := repository.NewWebhookJobFileRepository(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ruleRepo, err := repository.NewRuleFileRepository(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	revisionRepo, err := repository.NewRuleRevisionFileRepository(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ruleService, err := service.NewRuleService(ruleRepo, revisionRepo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return service.NewWebhookService(cfg, repo, logService, ruleService)
+}
+
+func TestLogController_Stream(t *testing.T) {
+	logService := service.NewLogService(repository.NewLogMemoryRepository())
+	logController := controller.NewLogController(logService, newWebhookService(t, logService))
+	server := httptest.NewServer(http.HandlerFunc(logController.Stream))
+
+	defer server.Close()
+
+	request, err := http.NewRequestWithContext(t.Context(), http.MethodGet, server.URL+"?method=post", nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	defer response.Body.Close()
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "text/event-stream", response.Header.Get("Content-Type"))
+
+	// The subscription is registered before the headers are sent, so these entries are streamed.
+	logService.Add(model.LogEntry{ID: "01", Method: http.MethodGet, URL: "/ignored"})
+	logService.Add(model.LogEntry{ID: "02", Method: http.MethodPost, URL: "/payments"})
+
+	reader := bufio.NewReader(response.Body)
+
+	var lines []string
+
+	for len(lines) < 3 {
+		line, err := reader.ReadString('\n')
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		lines = append(lines, strings.TrimSuffix(line, "\n"))
+	}
+
+	assert.Equal(t, "id: 02", lines[0])
+	assert.Equal(t, "event: log", lines[1])
+	assert.True(t, strings.HasPrefix(lines[2], `data: {"id":"02"`), lines[2])
+}
+
+func TestLogController_StreamInvalidFilter(t *testing.T) {
+	logService := service.NewLogService(repository.NewLogMemoryRepository())
+
+	request := httptest.NewRequest(http.MethodGet, "/mock-service/logs/stream?status_from=abc", nil)
+	response := httptest.NewRecorder()
+
+	controller.NewLogController(logService, newWebhookService(t, logService)).Stream(response, request)
+
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestLogController_RedeliverWebhook(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		writer.WriteHeader(http.StatusAccepted)
+	}))
+	defer receiver.Close()
+
+	logService := service.NewLogService(repository.NewLogMemoryRepository())
+	logService.Add(model.LogEntry{
+		ID: "01", Method: http.MethodPost, URL: "/payments",
+		WebhookResults: []model.WebhookResult{
+			{URL: receiver.URL, Method: http.MethodPost, Attempt: 1, StatusCode: http.StatusBadGateway, RequestBody: "{}"},
+		},
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /mock-service/logs/{id}/webhooks/{n}/redeliver",
+		controller.NewLogController(logService, newWebhookService(t, logService)).RedeliverWebhook)
+
+	tests := []struct {
+		name       string
+		id         string
+		position   string
+		wantStatus int
+	}{
+		{name: "Should fail with an unknown log entry", id: "99", position: "0", wantStatus: http.StatusNotFound},
+		{name: "Should fail with an unknown webhook", id: "01", position: "1", wantStatus: http.StatusNotFound},
+		{name: "Should fail with an invalid position", id: "01", position: "x", wantStatus: http.StatusBadRequest},
+		{name: "Should redeliver the webhook", id: "01", position: "0", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			path := "/mock-service/logs/" + tt.id + "/webhooks/" + tt.position + "/redeliver"
+			response := httptest.NewRecorder()
+			mux.ServeHTTP(response, httptest.NewRequest(http.MethodPost, path, nil))
+
+			assert.Equal(t, tt.wantStatus, response.Code, response.Body.String())
+		})
+	}
+
+	entry, err := logService.Get("01")
+	assert.NoError(t, err)
+	assert.Len(t, entry.WebhookResults, 2)
+	assert.True(t, entry.WebhookResults[0].Superseded)
+	assert.Equal(t, http.StatusAccepted, entry.WebhookResults[1].StatusCode)
+	assert.Equal(t, 2, entry.WebhookResults[1].Attempt)
+	assert.True(t, entry.WebhookResults[1].Redelivery)
+	assert.False(t, entry.HasWebhookFailures())
+}
@@ -17,14 +17,18 @@ import (
 )
 
 type MockController struct {
-	MockService service.MockService
-	LogService  service.LogService
+	MockService  service.MockService
+	LogService   service.LogService
+	ProxyService service.ProxyService
 }
 
-func NewMockController(mockService service.MockService, logService service.LogService) *MockController {
+func NewMockController(mockService service.MockService, logService service.LogService,
+	proxyService service.ProxyService,
+) *MockController {
 	return &MockController{
-		MockService: mockService,
-		LogService:  logService,
+		MockService:  mockService,
+		LogService:   logService,
+		ProxyService: proxyService,
 	}
 }
 
@@ -47,24 +51,21 @@ func (controller *MockController) Execute(writer http.ResponseWriter, request *h
 	reqBody := controller.extractExecutionBody(logger, request.Body)
 
 	// Build base log entry from the incoming request.
-	logEntry := controller.buildLogEntry(request, path, reqBody)
+	logEntry := newLogEntry(request, path, reqBody)
 
-	// Generate a log ID upfront so the webhook callback can reference it.
+	// Generate a log ID upfront so the webhook results can be recorded into the same log entry.
 	logID := ulid.Make().String()
 	logEntry.ID = logID
 
-	// Callback to record webhook result into the same log entry.
-	onWebhookResult := func(result model.WebhookResult) {
-		controller.LogService.Update(logID, func(entry *model.LogEntry) {
-			entry.WebhookResults = append(entry.WebhookResults, result)
-		})
-	}
-
-	response, assertionResult, err := controller.MockService.SearchResponseForRequest(
-		reqContext, request, path, reqBody, onWebhookResult)
+	response, result, err := controller.MockService.SearchResponseForRequest(
+		reqContext, request, path, reqBody, logID)
 
-	// Attach assertion errors to the log entry regardless of outcome.
-	logEntry.AssertionErrors = assertionResult.AssertionErrors
+	// Attach the matched rule and assertion errors to the log entry regardless of outcome.
+	logEntry.RuleKey = result.RuleKey
+	logEntry.RuleName = result.RuleName
+	logEntry.Scene = result.Scene
+	logEntry.Chaos = result.Chaos
+	logEntry.AssertionErrors = result.AssertionErrors
 
 	if err != nil {
 		controller.handleExecutionError(writer, request, logger, path, logEntry, err)
@@ -74,7 +75,22 @@ func (controller *MockController) Execute(writer http.ResponseWriter, request *h
 
 	writer.Header().Set("Content-Type", response.ContentType)
 
+	for name, value := range response.Headers {
+		writer.Header().Set(name, value)
+	}
+
+	for _, cookie := range response.Cookies {
+		http.SetCookie(writer, cookie.HTTPCookie())
+	}
+
 	time.Sleep(time.Duration(response.Delay) * time.Millisecond)
+
+	if response.Fault != "" {
+		controller.injectFault(writer, request, logger, response, logEntry)
+
+		return
+	}
+
 	writer.WriteHeader(response.HTTPStatus)
 
 	_, _ = writer.Write([]byte(response.Body))
@@ -94,6 +110,10 @@ func (controller *MockController) handleExecutionError(
 		logger.Debug(controller, nil, "No rule found for path: %v and method: %s",
 			path, request.Method)
 
+		if controller.proxy(writer, request, path, logEntry) {
+			return
+		}
+
 		errorResult := model.NewError(model.ResourceNotFoundError,
 			"No rule found for path: %v and method: %s. %v", path, request.Method, err.Error())
 
@@ -136,8 +156,59 @@ func (controller *MockController) handleExecutionError(
 	controller.recordLog(logEntry, http.StatusInternalServerError, errorResult.Message)
 }
 
-// buildLogEntry constructs a LogEntry from the incoming request.
-func (controller *MockController) buildLogEntry(request *http.Request, path, reqBody string) model.LogEntry {
+// injectFault breaks the exchange with the fault of the response and records it in the log entry.
+func (controller *MockController) injectFault(
+	writer http.ResponseWriter,
+	request *http.Request,
+	logger log.ILogger,
+	response model.Response,
+	logEntry model.LogEntry,
+) {
+	logger.Debug(controller, map[string]string{"fault": response.Fault}, "injecting response fault")
+
+	logEntry.Fault = response.Fault
+
+	status, err := writeFault(writer, request, response)
+	if errors.Is(err, errHijackNotSupported) {
+		logger.Error(controller, nil, err, "Failed to inject fault %s", response.Fault)
+
+		errorResult := model.NewError(model.InternalError, "Fault %s cannot be injected. %s", response.Fault,
+			err.Error())
+
+		httputils.WriteJSON(writer, http.StatusInternalServerError, errorResult)
+		controller.recordLog(logEntry, http.StatusInternalServerError, errorResult.Message)
+
+		return
+	}
+
+	if err != nil {
+		logger.Warn(controller, nil, "Error writing fault %s: %s", response.Fault, err.Error())
+	}
+
+	controller.recordLog(logEntry, status, "")
+}
+
+// proxy forwards an unmatched request to the configured upstream, if any, and records its response.
+func (controller *MockController) proxy(writer http.ResponseWriter, request *http.Request, path string,
+	logEntry model.LogEntry,
+) bool {
+	if controller.ProxyService == nil {
+		return false
+	}
+
+	result, forwarded := controller.ProxyService.Forward(mockscontext.New(request), writer, request, path,
+		logEntry.RequestBody)
+	if !forwarded {
+		return false
+	}
+
+	controller.recordLog(logEntry, result.StatusCode, result.Body)
+
+	return true
+}
+
+// newLogEntry constructs a LogEntry from the incoming request, recorded under path.
+func newLogEntry(request *http.Request, path, reqBody string) model.LogEntry {
 	headers := make(map[string]string, len(request.Header))
 
 	for key, values := range request.Header {
@@ -160,6 +231,7 @@ func (controller *MockController) buildLogEntry(request *http.Request, path, req
 	}
 
 	return model.LogEntry{
+		Timestamp:      time.Now(),
 		Method:         request.Method,
 		URL:            fullURL,
 		RequestBody:    reqBody,
@@ -176,6 +248,7 @@ func (controller *MockController) recordLog(entry model.LogEntry, responseStatus
 
 	entry.ResponseStatus = responseStatus
 	entry.ResponseBody = responseBody
+	entry.DurationMs = time.Since(entry.Timestamp).Milliseconds()
 	controller.LogService.Add(entry)
 }
 
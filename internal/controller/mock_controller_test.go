@@ -2,13 +2,18 @@ package controller_test
 
 import (
 	"errors"
+	"io"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/nicopozo/mockserver/internal/controller"
 	mockserrors "github.com/nicopozo/mockserver/internal/errors"
 	"github.com/nicopozo/mockserver/internal/model"
+	"github.com/nicopozo/mockserver/internal/repository"
+	"github.com/nicopozo/mockserver/internal/service"
 	testutils "github.com/nicopozo/mockserver/internal/utils/test"
 	"github.com/nicopozo/mockserver/internal/utils/test/mocks"
 	"github.com/stretchr/testify/assert"
@@ -25,6 +30,7 @@ func TestMockController_Execute(t *testing.T) {
 		serviceResponse  model.Response
 		serviceCallTimes int
 		rulePath         string
+		wantHeaders      map[string]string
 	}{
 		{
 			name:       "Create rule successfully",
@@ -134,6 +140,24 @@ func TestMockController_Execute(t *testing.T) {
 			serviceCallTimes: 1,
 			rulePath:         "test",
 		},
+		{
+			name:       "Should write custom headers and cookies",
+			want:       "{}",
+			wantStatus: http.StatusCreated,
+			serviceResponse: model.Response{
+				Body:        "{}",
+				ContentType: "application/json",
+				HTTPStatus:  http.StatusCreated,
+				Headers:     map[string]string{"Location": "/payments/1", "ETag": "\"abc\""},
+				Cookies:     []model.Cookie{{Name: "session", Value: "xyz", Path: "/", SameSite: "strict"}},
+			},
+			serviceCallTimes: 1,
+			wantHeaders: map[string]string{
+				"Location":   "/payments/1",
+				"Etag":       "\"abc\"",
+				"Set-Cookie": "session=xyz; Path=/; SameSite=Strict",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -155,7 +179,7 @@ func TestMockController_Execute(t *testing.T) {
 			}
 
 			mockServiceMock.EXPECT().SearchResponseForRequest(gomock.Any(), gomock.Any(), mockSearchPath, gomock.Any(), gomock.Any()).
-				Return(tt.serviceResponse, model.AssertionResult{}, tt.serviceErr).Times(tt.serviceCallTimes)
+				Return(tt.serviceResponse, model.ExecutionResult{}, tt.serviceErr).Times(tt.serviceCallTimes)
 
 			response, request := testutils.GetHTTPContext()
 			request.SetPathValue("rule", expectedPath)
@@ -180,6 +204,166 @@ func TestMockController_Execute(t *testing.T) {
 			res := response.Body.String()
 
 			assert.Equal(t, tt.want, res)
+
+			for name, value := range tt.wantHeaders {
+				assert.Equal(t, value, response.Header().Get(name))
+			}
+		})
+	}
+}
+
+func TestMockController_ExecuteProxiesUnmatchedRequests(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockServiceMock := mocks.NewMockMockService(mockCtrl)
+	proxyServiceMock := mocks.NewMockProxyService(mockCtrl)
+
+	mockServiceMock.EXPECT().SearchResponseForRequest(gomock.Any(), gomock.Any(), "/test", gomock.Any(), gomock.Any()).
+		Return(model.Response{}, model.ExecutionResult{}, mockserrors.RuleNotFoundError{Message: "no rule found"})
+	proxyServiceMock.EXPECT().Forward(gomock.Any(), gomock.Any(), gomock.Any(), "/test", gomock.Any()).
+		DoAndReturn(func(_ interface{}, writer http.ResponseWriter, _ *http.Request, _, _ string) (service.ProxyResult, bool) {
+			writer.WriteHeader(http.StatusAccepted)
+			_, _ = writer.Write([]byte("from upstream"))
+
+			return service.ProxyResult{StatusCode: http.StatusAccepted, Body: "from upstream"}, true
+		})
+
+	response, request := testutils.GetHTTPContext()
+	request.SetPathValue("rule", "/test")
+	request.Method = http.MethodGet
+
+	mc := controller.NewMockController(mockServiceMock, nil, proxyServiceMock)
+	mc.Execute(response, request)
+
+	assert.Equal(t, http.StatusAccepted, response.Code)
+	assert.Equal(t, "from upstream", response.Body.String())
+}
+
+func TestMockController_ExecuteRecordsMatchedRule(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockServiceMock := mocks.NewMockMockService(mockCtrl)
+	mockServiceMock.EXPECT().SearchResponseForRequest(gomock.Any(), gomock.Any(), "/test", gomock.Any(), gomock.Any()).
+		Return(model.Response{HTTPStatus: http.StatusOK, Body: "ok", Delay: 5},
+			model.ExecutionResult{RuleKey: "rule-1", RuleName: "payments", Scene: "approved"}, nil)
+
+	logService := service.NewLogService(repository.NewLogMemoryRepository())
+
+	response, request := testutils.GetHTTPContext()
+	request.SetPathValue("rule", "/test")
+	request.Method = http.MethodGet
+
+	mc := controller.NewMockController(mockServiceMock, logService, nil)
+	mc.Execute(response, request)
+
+	logs, err := logService.GetAll(model.LogFilter{}, model.Paging{Limit: 10})
+	assert.NoError(t, err)
+	if assert.Len(t, logs.Results, 1) {
+		assert.Equal(t, "rule-1", logs.Results[0].RuleKey)
+		assert.Equal(t, "payments", logs.Results[0].RuleName)
+		assert.Equal(t, "approved", logs.Results[0].Scene)
+		assert.GreaterOrEqual(t, logs.Results[0].DurationMs, int64(5))
+	}
+}
+
+func TestMockController_ExecuteInjectsFaults(t *testing.T) {
+	tests := []struct {
+		fault       string
+		wantDoErr   string
+		wantBody    string
+		wantReadErr string
+		wantStatus  int
+	}{
+		{fault: model.ResponseFaultEmptyReply, wantDoErr: "EOF"},
+		{fault: model.ResponseFaultConnectionReset, wantDoErr: "connection reset by peer"},
+		{fault: model.ResponseFaultRandomData, wantDoErr: "malformed HTTP"},
+		{
+			fault:       model.ResponseFaultTruncatedBody,
+			wantStatus:  http.StatusOK,
+			wantBody:    `{"balanc`,
+			wantReadErr: "unexpected EOF",
+		},
+		{
+			fault:       model.ResponseFaultMalformedChunk,
+			wantStatus:  http.StatusOK,
+			wantReadErr: "invalid byte in chunk length",
+		},
+		{
+			fault:       model.ResponseFaultStallAfterHeaders,
+			wantStatus:  http.StatusOK,
+			wantReadErr: "Client.Timeout",
+		},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		t.Run(tt.fault, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockServiceMock := mocks.NewMockMockService(mockCtrl)
+			mockServiceMock.EXPECT().SearchResponseForRequest(gomock.Any(), gomock.Any(), "/test", gomock.Any(),
+				gomock.Any()).
+				Return(model.Response{
+					HTTPStatus:  http.StatusOK,
+					ContentType: "application/json",
+					Body:        `{"balance":5000}`,
+					Fault:       tt.fault,
+				}, model.ExecutionResult{RuleKey: "rule-1"}, nil)
+
+			logService := service.NewLogService(repository.NewLogMemoryRepository())
+			mc := controller.NewMockController(mockServiceMock, logService, nil)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("/mock-service/mock/{rule...}", mc.Execute)
+
+			server := httptest.NewServer(mux)
+			defer server.Close()
+
+			client := &http.Client{
+				Timeout:   500 * time.Millisecond,
+				Transport: &http.Transport{DisableKeepAlives: true},
+			}
+
+			response, err := client.Get(server.URL + "/mock-service/mock/test")
+			if tt.wantDoErr != "" {
+				assert.ErrorContains(t, err, tt.wantDoErr)
+			} else if assert.NoError(t, err) {
+				body, err := io.ReadAll(response.Body)
+				_ = response.Body.Close()
+
+				assert.Equal(t, tt.wantStatus, response.StatusCode)
+				assert.Equal(t, tt.wantBody, string(body))
+				assert.ErrorContains(t, err, tt.wantReadErr)
+			}
+
+			assert.Eventually(t, func() bool {
+				logs, err := logService.GetAll(model.LogFilter{}, model.Paging{Limit: 10})
+
+				return err == nil && len(logs.Results) == 1 && logs.Results[0].Fault == tt.fault &&
+					logs.Results[0].ResponseStatus == tt.wantStatus
+			}, 2*time.Second, 10*time.Millisecond)
 		})
 	}
 }
+
+func TestMockController_ExecuteFaultWithoutHijacker(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockServiceMock := mocks.NewMockMockService(mockCtrl)
+	mockServiceMock.EXPECT().SearchResponseForRequest(gomock.Any(), gomock.Any(), "/test", gomock.Any(), gomock.Any()).
+		Return(model.Response{HTTPStatus: http.StatusOK, Fault: model.ResponseFaultEmptyReply},
+			model.ExecutionResult{}, nil)
+
+	response, request := testutils.GetHTTPContext()
+	request.SetPathValue("rule", "/test")
+	request.Method = http.MethodGet
+
+	mc := controller.NewMockController(mockServiceMock, nil, nil)
+	mc.Execute(response, request)
+
+	assert.Equal(t, http.StatusInternalServerError, response.Code)
+	assert.Contains(t, response.Body.String(), "cannot be hijacked")
+}
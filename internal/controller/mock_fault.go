@@ -0,0 +1,117 @@
+package controller
+
+import (
+	"bufio"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/nicopozo/mockserver/internal/model"
+)
+
+const (
+	// faultRandomDataSize is the number of random bytes written by the random_data fault.
+	faultRandomDataSize = 1024
+	// faultStallTimeout bounds how long the stall_after_headers fault keeps the connection open when the
+	// client does not give up first.
+	faultStallTimeout = 5 * time.Minute
+)
+
+var errHijackNotSupported = errors.New("the connection cannot be hijacked")
+
+// writeFault breaks the exchange as described by the fault of the response. The status, headers and
+// cookies must already be set in writer. It returns the status sent to the client, or 0 if none was.
+func writeFault(writer http.ResponseWriter, request *http.Request, response model.Response) (int, error) {
+	if response.Fault == model.ResponseFaultStallAfterHeaders {
+		return stallAfterHeaders(writer, request, response.HTTPStatus)
+	}
+
+	hijacker, ok := writer.(http.Hijacker)
+	if !ok {
+		return 0, errHijackNotSupported
+	}
+
+	header := writer.Header().Clone()
+
+	conn, buffer, err := hijacker.Hijack()
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", errHijackNotSupported, err)
+	}
+
+	defer func() { _ = conn.Close() }()
+
+	switch response.Fault {
+	case model.ResponseFaultConnectionReset:
+		// With a zero linger, closing the socket sends a RST instead of the usual FIN.
+		if tcpConn, ok := conn.(*net.TCPConn); ok {
+			_ = tcpConn.SetLinger(0)
+		}
+
+		return 0, nil
+
+	case model.ResponseFaultRandomData:
+		payload := make([]byte, faultRandomDataSize)
+		_, _ = rand.Read(payload)
+		_, _ = buffer.Write(payload)
+
+		return 0, flush(buffer)
+
+	case model.ResponseFaultTruncatedBody:
+		header.Set("Content-Length", strconv.Itoa(max(len(response.Body), 1)))
+		writeStatusAndHeaders(buffer, response.HTTPStatus, header)
+		_, _ = buffer.WriteString(response.Body[:len(response.Body)/2])
+
+		return response.HTTPStatus, flush(buffer)
+
+	case model.ResponseFaultMalformedChunk:
+		header.Del("Content-Length")
+		header.Set("Transfer-Encoding", "chunked")
+		writeStatusAndHeaders(buffer, response.HTTPStatus, header)
+		// "zz" is not a hexadecimal chunk size.
+		_, _ = fmt.Fprintf(buffer, "zz\r\n%s\r\n0\r\n\r\n", response.Body)
+
+		return response.HTTPStatus, flush(buffer)
+	}
+
+	// model.ResponseFaultEmptyReply: the connection is closed without writing anything.
+	return 0, nil
+}
+
+// stallAfterHeaders sends the status and headers, and then waits for the client to give up.
+func stallAfterHeaders(writer http.ResponseWriter, request *http.Request, status int) (int, error) {
+	writer.WriteHeader(status)
+
+	if err := http.NewResponseController(writer).Flush(); err != nil {
+		return status, fmt.Errorf("error flushing headers: %w", err)
+	}
+
+	timer := time.NewTimer(faultStallTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-request.Context().Done():
+	case <-timer.C:
+	}
+
+	return status, nil
+}
+
+func writeStatusAndHeaders(buffer *bufio.ReadWriter, status int, header http.Header) {
+	header.Set("Connection", "close")
+
+	_, _ = fmt.Fprintf(buffer, "HTTP/1.1 %d %s\r\n", status, http.StatusText(status))
+	_ = header.Write(buffer)
+	_, _ = buffer.WriteString("\r\n")
+}
+
+func flush(buffer *bufio.ReadWriter) error {
+	if err := buffer.Flush(); err != nil {
+		return fmt.Errorf("error writing fault: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	mockscontext "github.com/nicopozo/mockserver/internal/context"
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
+	"github.com/nicopozo/mockserver/internal/model"
+	"github.com/nicopozo/mockserver/internal/service"
+	httputils "github.com/nicopozo/mockserver/internal/utils/http"
+)
+
+// ResourceController manages the collections served by resource rules.
+type ResourceController struct {
+	ResourceService service.ResourceService
+}
+
+func NewResourceController(resourceService service.ResourceService) *ResourceController {
+	return &ResourceController{
+		ResourceService: resourceService,
+	}
+}
+
+// Reset replaces the entities of the collection of a resource rule with its seed entities.
+func (controller *ResourceController) Reset(writer http.ResponseWriter, request *http.Request) {
+	reqContext := mockscontext.New(request)
+	logger := mockscontext.Logger(reqContext)
+
+	logger.Debug(controller, nil, "Entering ResourceController Reset()")
+
+	key := request.PathValue("key")
+
+	if err := controller.ResourceService.Reset(reqContext, key); err != nil {
+		switch {
+		case errors.As(err, &mockserrors.RuleNotFoundError{}):
+			httputils.WriteError(writer, model.ResourceNotFoundError, "%s", err.Error())
+		case errors.As(err, &mockserrors.InvalidRulesError{}):
+			httputils.WriteError(writer, model.ValidationError, "%s", err.Error())
+		default:
+			logger.Error(controller, nil, err, "Error occurred when resetting resource %s", key)
+			httputils.WriteError(writer, model.InternalError, "Error occurred when resetting resource. %s",
+				err.Error())
+		}
+
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
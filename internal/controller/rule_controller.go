@@ -1,8 +1,11 @@
 package controller
 
 import (
+	"context"
 	"errors"
+	"io"
 	"net/http"
+	"strconv"
 
 	mockscontext "github.com/nicopozo/mockserver/internal/context"
 	ruleserrors "github.com/nicopozo/mockserver/internal/errors"
@@ -15,12 +18,14 @@ import (
 const exportLimit = 10000
 
 type RuleController struct {
-	RuleService service.RuleService
+	RuleService    service.RuleService
+	OpenAPIService service.OpenAPIService
 }
 
-func NewRuleController(ruleService service.RuleService) *RuleController {
+func NewRuleController(ruleService service.RuleService, openAPIService service.OpenAPIService) *RuleController {
 	return &RuleController{
-		RuleService: ruleService,
+		RuleService:    ruleService,
+		OpenAPIService: openAPIService,
 	}
 }
 
@@ -30,10 +35,10 @@ func (controller *RuleController) Create(writer http.ResponseWriter, request *ht
 	logger := mockscontext.Logger(reqContext)
 	logger.Debug(controller, nil, "Entering RuleController Save()")
 
-	rule, err := model.UnmarshalRule(request.Body)
+	rule, err := unmarshalRule(request)
 	if err != nil {
-		logger.Error(controller, nil, err, "Error unmarshalling Rule JSON")
-		httputils.WriteError(writer, model.ValidationError, "Invalid JSON. %s", err.Error())
+		logger.Error(controller, nil, err, "Error unmarshalling Rule %s", bodyFormat(request))
+		httputils.WriteError(writer, model.ValidationError, "Invalid %s. %s", bodyFormat(request), err.Error())
 
 		return
 	}
@@ -63,10 +68,10 @@ func (controller *RuleController) Update(writer http.ResponseWriter, request *ht
 
 	key := request.PathValue("key")
 
-	rule, err := model.UnmarshalRule(request.Body)
+	rule, err := unmarshalRule(request)
 	if err != nil {
-		logger.Error(controller, nil, err, "Error unmarshalling Rule JSON")
-		httputils.WriteError(writer, model.ValidationError, "Invalid JSON. %s", err.Error())
+		logger.Error(controller, nil, err, "Error unmarshalling Rule %s", bodyFormat(request))
+		httputils.WriteError(writer, model.ValidationError, "Invalid %s. %s", bodyFormat(request), err.Error())
 
 		return
 	}
@@ -192,6 +197,33 @@ func (controller *RuleController) Search(writer http.ResponseWriter, request *ht
 	httputils.WriteJSON(writer, http.StatusOK, ruleList)
 }
 
+// Candidates lists the enabled rules for a method and path in the order they are resolved.
+func (controller *RuleController) Candidates(writer http.ResponseWriter, request *http.Request) {
+	reqContext := mockscontext.New(request)
+	logger := mockscontext.Logger(reqContext)
+
+	logger.Debug(controller, nil, "Entering RuleController Candidates()")
+
+	method := request.URL.Query().Get("method")
+	path := request.URL.Query().Get("path")
+
+	ruleList, err := controller.RuleService.SearchCandidates(reqContext, method, path)
+	if err != nil {
+		if errors.As(err, &ruleserrors.InvalidRulesError{}) {
+			httputils.WriteError(writer, model.ValidationError, "Invalid parameters. %s", err.Error())
+
+			return
+		}
+
+		logger.Error(controller, nil, err, "Failed to search candidate rules")
+		httputils.WriteError(writer, model.InternalError, "Error occurred when searching rules. %s", err.Error())
+
+		return
+	}
+
+	httputils.WriteJSON(writer, http.StatusOK, ruleList)
+}
+
 // Delete Rule.
 func (controller *RuleController) Delete(writer http.ResponseWriter, request *http.Request) {
 	reqContext := mockscontext.New(request)
@@ -218,7 +250,7 @@ func (controller *RuleController) Delete(writer http.ResponseWriter, request *ht
 	writer.WriteHeader(http.StatusNoContent)
 }
 
-// Export all Rules.
+// Export all Rules, in YAML when it is asked for with the Accept header.
 func (controller *RuleController) Export(writer http.ResponseWriter, request *http.Request) {
 	reqContext := mockscontext.New(request)
 	logger := mockscontext.Logger(reqContext)
@@ -236,6 +268,12 @@ func (controller *RuleController) Export(writer http.ResponseWriter, request *ht
 		return
 	}
 
+	if httputils.IsYAML(request.Header.Get("Accept")) {
+		httputils.WriteYAML(writer, http.StatusOK, ruleList.Results)
+
+		return
+	}
+
 	httputils.WriteJSON(writer, http.StatusOK, ruleList.Results)
 }
 
@@ -246,10 +284,10 @@ func (controller *RuleController) Import(writer http.ResponseWriter, request *ht
 
 	logger.Debug(controller, nil, "Entering RuleController Import()")
 
-	rules, err := model.UnmarshalRules(request.Body)
+	rules, err := unmarshalRules(request)
 	if err != nil {
-		logger.Error(controller, nil, err, "Error unmarshalling Rules JSON array")
-		httputils.WriteError(writer, model.ValidationError, "Invalid JSON array. %s", err.Error())
+		logger.Error(controller, nil, err, "Error unmarshalling Rules %s array", bodyFormat(request))
+		httputils.WriteError(writer, model.ValidationError, "Invalid %s array. %s", bodyFormat(request), err.Error())
 
 		return
 	}
@@ -294,3 +332,178 @@ func (controller *RuleController) Import(writer http.ResponseWriter, request *ht
 
 	httputils.WriteJSON(writer, http.StatusOK, stats)
 }
+
+// ImportOpenAPI creates rules from an OpenAPI 3 or Swagger 2 specification in YAML or JSON.
+func (controller *RuleController) ImportOpenAPI(writer http.ResponseWriter, request *http.Request) {
+	reqContext := mockscontext.New(request)
+	logger := mockscontext.Logger(reqContext)
+
+	logger.Debug(controller, nil, "Entering RuleController ImportOpenAPI()")
+
+	spec, err := io.ReadAll(request.Body)
+	if err != nil {
+		logger.Error(controller, nil, err, "Error reading specification")
+		httputils.WriteError(writer, model.ValidationError, "Invalid specification. %s", err.Error())
+
+		return
+	}
+
+	report, err := controller.OpenAPIService.Import(reqContext, spec, request.URL.Query().Get("group"))
+	if err != nil {
+		if errors.As(err, &ruleserrors.InvalidRulesError{}) {
+			httputils.WriteError(writer, model.ValidationError, "Invalid specification. %s", err.Error())
+
+			return
+		}
+
+		logger.Error(controller, nil, err, "Failed to import specification")
+		httputils.WriteError(writer, model.InternalError, "Error occurred when importing specification. %s", err.Error())
+
+		return
+	}
+
+	httputils.WriteJSON(writer, http.StatusOK, report)
+}
+
+// Revisions lists the revision history of a Rule.
+func (controller *RuleController) Revisions(writer http.ResponseWriter, request *http.Request) {
+	reqContext := mockscontext.New(request)
+	logger := mockscontext.Logger(reqContext)
+
+	logger.Debug(controller, nil, "Entering RuleController Revisions()")
+
+	key := request.PathValue("key")
+
+	revisions, err := controller.RuleService.Revisions(reqContext, key)
+	if err != nil {
+		controller.writeRevisionError(reqContext, writer, err, "listing revisions")
+
+		return
+	}
+
+	httputils.WriteJSON(writer, http.StatusOK, revisions)
+}
+
+// Revision gets a revision of a Rule.
+func (controller *RuleController) Revision(writer http.ResponseWriter, request *http.Request) {
+	reqContext := mockscontext.New(request)
+	logger := mockscontext.Logger(reqContext)
+
+	logger.Debug(controller, nil, "Entering RuleController Revision()")
+
+	revision, ok := revisionNumber(writer, request.PathValue("n"))
+	if !ok {
+		return
+	}
+
+	ruleRevision, err := controller.RuleService.Revision(reqContext, request.PathValue("key"), revision)
+	if err != nil {
+		controller.writeRevisionError(reqContext, writer, err, "getting revision")
+
+		return
+	}
+
+	httputils.WriteJSON(writer, http.StatusOK, ruleRevision)
+}
+
+// DiffRevisions lists the changes between the revisions ?from= and ?to= of a Rule.
+func (controller *RuleController) DiffRevisions(writer http.ResponseWriter, request *http.Request) {
+	reqContext := mockscontext.New(request)
+	logger := mockscontext.Logger(reqContext)
+
+	logger.Debug(controller, nil, "Entering RuleController DiffRevisions()")
+
+	from, ok := revisionNumber(writer, request.URL.Query().Get("from"))
+	if !ok {
+		return
+	}
+
+	to, ok := revisionNumber(writer, request.URL.Query().Get("to"))
+	if !ok {
+		return
+	}
+
+	diff, err := controller.RuleService.DiffRevisions(reqContext, request.PathValue("key"), from, to)
+	if err != nil {
+		controller.writeRevisionError(reqContext, writer, err, "comparing revisions")
+
+		return
+	}
+
+	httputils.WriteJSON(writer, http.StatusOK, diff)
+}
+
+// RestoreRevision saves a Rule as it was in one of its revisions.
+func (controller *RuleController) RestoreRevision(writer http.ResponseWriter, request *http.Request) {
+	reqContext := mockscontext.New(request)
+	logger := mockscontext.Logger(reqContext)
+
+	logger.Debug(controller, nil, "Entering RuleController RestoreRevision()")
+
+	revision, ok := revisionNumber(writer, request.PathValue("n"))
+	if !ok {
+		return
+	}
+
+	rule, err := controller.RuleService.RestoreRevision(reqContext, request.PathValue("key"), revision)
+	if err != nil {
+		controller.writeRevisionError(reqContext, writer, err, "restoring revision")
+
+		return
+	}
+
+	httputils.WriteJSON(writer, http.StatusOK, rule)
+}
+
+// revisionNumber parses a revision number, writing a validation error when it is not valid.
+func revisionNumber(writer http.ResponseWriter, value string) (int, bool) {
+	revision, err := strconv.Atoi(value)
+	if err != nil || revision < 1 {
+		httputils.WriteError(writer, model.ValidationError, "Invalid revision '%s'", value)
+
+		return 0, false
+	}
+
+	return revision, true
+}
+
+func (controller *RuleController) writeRevisionError(ctx context.Context, writer http.ResponseWriter, err error,
+	action string,
+) {
+	switch {
+	case errors.As(err, &ruleserrors.RuleNotFoundError{}), errors.As(err, &ruleserrors.RuleRevisionNotFoundError{}):
+		httputils.WriteError(writer, model.ResourceNotFoundError, "%s", err.Error())
+	case errors.As(err, &ruleserrors.InvalidRulesError{}):
+		httputils.WriteError(writer, model.ValidationError, "%s", err.Error())
+	default:
+		mockscontext.Logger(ctx).Error(controller, nil, err, "Error occurred when %s", action)
+		httputils.WriteError(writer, model.InternalError, "Error occurred when %s. %s", action, err.Error())
+	}
+}
+
+// unmarshalRule reads the rule in the body of request, in YAML when it is sent with a YAML content type
+// and in JSON otherwise.
+func unmarshalRule(request *http.Request) (*model.Rule, error) {
+	if httputils.IsYAML(request.Header.Get("Content-Type")) {
+		return model.UnmarshalRuleYAML(request.Body) //nolint:wrapcheck
+	}
+
+	return model.UnmarshalRule(request.Body) //nolint:wrapcheck
+}
+
+// unmarshalRules reads the array of rules in the body of request, like unmarshalRule.
+func unmarshalRules(request *http.Request) ([]model.Rule, error) {
+	if httputils.IsYAML(request.Header.Get("Content-Type")) {
+		return model.UnmarshalRulesYAML(request.Body) //nolint:wrapcheck
+	}
+
+	return model.UnmarshalRules(request.Body) //nolint:wrapcheck
+}
+
+func bodyFormat(request *http.Request) string {
+	if httputils.IsYAML(request.Header.Get("Content-Type")) {
+		return "YAML"
+	}
+
+	return "JSON"
+}
@@ -5,13 +5,21 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"reflect"
+	"sort"
 	"strings"
 	"testing"
 
+	"github.com/nicopozo/mockserver/internal/configs"
 	"github.com/nicopozo/mockserver/internal/controller"
 	mockserrors "github.com/nicopozo/mockserver/internal/errors"
 	"github.com/nicopozo/mockserver/internal/model"
+	"github.com/nicopozo/mockserver/internal/repository"
+	"github.com/nicopozo/mockserver/internal/service"
+	httputils "github.com/nicopozo/mockserver/internal/utils/http"
 	stringutils "github.com/nicopozo/mockserver/internal/utils/string"
 	testutils "github.com/nicopozo/mockserver/internal/utils/test"
 	"github.com/nicopozo/mockserver/internal/utils/test/mocks"
@@ -585,3 +593,308 @@ func TestRuleController_Delete(t *testing.T) {
 		})
 	}
 }
+
+func TestRuleController_ImportOpenAPI(t *testing.T) {
+	tests := []struct {
+		name       string
+		report     model.ImportReport
+		serviceErr error
+		wantStatus int
+		want       string
+	}{
+		{
+			name: "Should return the import report",
+			report: model.ImportReport{
+				Created: []model.ImportedRule{{Key: "k1", Name: "getPayment", Method: "GET", Path: "/v1/payments/{id}"}},
+				Updated: []model.ImportedRule{},
+				Skipped: []model.SkippedOperation{{Method: "DELETE", Path: "/v1/payments", Reason: "no documented status codes"}},
+			},
+			wantStatus: http.StatusOK,
+			want: `{"created":[{"key":"k1","name":"getPayment","method":"GET","path":"/v1/payments/{id}"}],"updated":[],` +
+				`"skipped":[{"method":"DELETE","path":"/v1/payments","reason":"no documented status codes"}]}`,
+		},
+		{
+			name:       "Should return bad request when specification is invalid",
+			serviceErr: mockserrors.InvalidRulesError{Message: "specification does not have any paths"},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Should return internal error when import fails",
+			serviceErr: errors.New("disk full"),
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			openAPIServiceMock := mocks.NewMockOpenAPIService(mockCtrl)
+			openAPIServiceMock.EXPECT().Import(gomock.Any(), []byte("openapi: 3.0.0"), "payments").
+				Return(tt.report, tt.serviceErr)
+
+			response, _ := testutils.GetHTTPContext()
+			request, _ := http.NewRequestWithContext(context.Background(), http.MethodPost,
+				"/mock-service/rules/import/openapi?group=payments", strings.NewReader("openapi: 3.0.0"))
+
+			rc := controller.NewRuleController(nil, openAPIServiceMock)
+			rc.ImportOpenAPI(response, request)
+
+			assert.Equal(t, tt.wantStatus, response.Code)
+
+			if tt.want != "" {
+				assert.JSONEq(t, tt.want, response.Body.String())
+			}
+		})
+	}
+}
+
+//nolint:paralleltest
+func TestRuleController_YAMLRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	sourceFile := filepath.Join(dir, "rules.yaml")
+
+	err := os.WriteFile(sourceFile, []byte(`
+- key: k1
+  group: payments
+  name: get payment
+  path: /v1/payments/{id}
+  strategy: normal
+  method: GET
+  status: enabled
+  responses:
+    - http_status: 200
+      content_type: application/json
+      body:
+        id: 5804214224
+        payer_id: 548390723
+        external_reference: X281924481
+      webhooks:
+        - url: http://localhost/callback
+          method: POST
+          enabled: true
+          body: {event: payment_created, id: "{id}"}
+- key: k2
+  group: payments
+  name: create payment
+  path: /v1/payments
+  strategy: normal
+  method: POST
+  status: enabled
+  responses:
+    - http_status: 201
+      content_type: text/plain
+      body: |
+        created
+        at: 2024-01-01
+      headers:
+        Location: /v1/payments/1
+`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The rules of a YAML file are exported the same in YAML and JSON.
+	source := newFileRuleController(t, sourceFile)
+
+	exportedJSON := serveRules(source.Export, http.MethodGet, "", "application/json", "")
+	exportedYAML := serveRules(source.Export, http.MethodGet, "", "application/yaml", "")
+
+	assert.Equal(t, httputils.ContentTypeYAML, exportedYAML.Header().Get("Content-Type"))
+	assert.Contains(t, exportedYAML.Body.String(), "body: |\n")
+
+	want := decodeRules(t, exportedJSON.Body.String())
+	if assert.Len(t, want, 2) {
+		assert.Equal(t, `{"id":5804214224,"payer_id":548390723,"external_reference":"X281924481"}`,
+			want[1].Responses[0].Body)
+		assert.Equal(t, `{"event":"payment_created","id":"{id}"}`, want[1].Responses[0].Webhooks[0].Body)
+		assert.Equal(t, "created\nat: 2024-01-01\n", want[0].Responses[0].Body)
+	}
+
+	yamlRules, err := model.UnmarshalRulesYAML(strings.NewReader(exportedYAML.Body.String()))
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, yamlRules)
+	}
+
+	// The export is a valid rules file.
+	exportedFile := filepath.Join(dir, "exported.yaml")
+	if err := os.WriteFile(exportedFile, exportedYAML.Body.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	fromFile := newFileRuleController(t, exportedFile)
+	assert.Equal(t, want,
+		decodeRules(t, serveRules(fromFile.Export, http.MethodGet, "", "application/json", "").Body.String()))
+
+	// And it can be imported, the rules keep their keys.
+	imported := newFileRuleController(t, filepath.Join(dir, "mocks.json"))
+
+	response := serveRules(imported.Import, http.MethodPost, "application/yaml", "", exportedYAML.Body.String())
+	if assert.Equal(t, http.StatusOK, response.Code, response.Body.String()) {
+		assert.JSONEq(t, `{"created":0,"updated":2,"failed":0}`, response.Body.String())
+	}
+
+	got := decodeRules(t, serveRules(imported.Export, http.MethodGet, "", "", "").Body.String())
+	assert.Equal(t, want, got)
+
+	// A single rule can be created in YAML.
+	response = serveRules(imported.Create, http.MethodPost, "application/yaml", "", `
+name: list payments
+path: /v1/payments
+method: get
+responses:
+  - http_status: 200
+    body: [{id: 1}]
+`)
+	if assert.Equal(t, http.StatusCreated, response.Code, response.Body.String()) {
+		rule, err := testutils.GetRuleFromResponse(response.Body.Bytes())
+		if assert.NoError(t, err) {
+			assert.Equal(t, `[{"id":1}]`, rule.Responses[0].Body)
+		}
+	}
+
+	response = serveRules(imported.Create, http.MethodPost, "application/yaml", "", "name: [")
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	assert.Contains(t, response.Body.String(), "Invalid YAML.")
+}
+
+func TestRuleController_Revisions(t *testing.T) {
+	ruleController := newFileRuleController(t, filepath.Join(t.TempDir(), "mocks.json"))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /mock-service/rules/{key}", ruleController.Update)
+	mux.HandleFunc("GET /mock-service/rules/{key}", ruleController.Get)
+	mux.HandleFunc("GET /mock-service/rules/{key}/revisions", ruleController.Revisions)
+	mux.HandleFunc("GET /mock-service/rules/{key}/revisions/diff", ruleController.DiffRevisions)
+	mux.HandleFunc("GET /mock-service/rules/{key}/revisions/{n}", ruleController.Revision)
+	mux.HandleFunc("POST /mock-service/rules/{key}/revisions/{n}/restore", ruleController.RestoreRevision)
+
+	for _, status := range []int{http.StatusOK, http.StatusNotFound} {
+		body := fmt.Sprintf(`{"name":"get payment","path":"/v1/payments","method":"GET",
+			"responses":[{"body":"{}","http_status":%d}]}`, status)
+
+		request := httptest.NewRequest(http.MethodPut, "/mock-service/rules/k1", strings.NewReader(body))
+		request.Header.Set("x-tracking-id", "tracking-1")
+
+		response := httptest.NewRecorder()
+		mux.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name: "Should list the revisions", method: http.MethodGet, path: "/mock-service/rules/k1/revisions",
+			wantStatus: http.StatusOK,
+			wantBody:   `"revision":2,"action":"update","author":"tracking-1"`,
+		},
+		{
+			name: "Should fail to list the revisions of an unknown rule", method: http.MethodGet,
+			path: "/mock-service/rules/k2/revisions", wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "Should get a revision", method: http.MethodGet, path: "/mock-service/rules/k1/revisions/1",
+			wantStatus: http.StatusOK, wantBody: `"revision":1,"action":"create"`,
+		},
+		{
+			name: "Should fail to get an unknown revision", method: http.MethodGet,
+			path: "/mock-service/rules/k1/revisions/3", wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "Should fail to get an invalid revision", method: http.MethodGet,
+			path: "/mock-service/rules/k1/revisions/0", wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Should diff two revisions", method: http.MethodGet,
+			path: "/mock-service/rules/k1/revisions/diff?from=1&to=2", wantStatus: http.StatusOK,
+			wantBody: `{"rule_key":"k1","from":1,"to":2,"changes":[` +
+				`{"op":"replace","path":"/responses/0/http_status","old_value":200,"value":404}]}`,
+		},
+		{
+			name: "Should fail to diff without revisions", method: http.MethodGet,
+			path: "/mock-service/rules/k1/revisions/diff?from=1", wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Should restore a revision", method: http.MethodPost,
+			path: "/mock-service/rules/k1/revisions/1/restore", wantStatus: http.StatusOK,
+			wantBody: `"http_status":200`,
+		},
+		{
+			name: "Should list the restore", method: http.MethodGet, path: "/mock-service/rules/k1/revisions/3",
+			wantStatus: http.StatusOK, wantBody: `"revision":3,"action":"restore"`,
+		},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			mux.ServeHTTP(response, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, tt.wantStatus, response.Code, response.Body.String())
+			assert.Contains(t, response.Body.String(), tt.wantBody)
+		})
+	}
+}
+
+func newFileRuleController(t *testing.T, mocksFile string) *controller.RuleController {
+	t.Helper()
+
+	cfg := &configs.Config{
+		MocksFile:     mocksFile,
+		RevisionsFile: filepath.Join(filepath.Dir(mocksFile), "revisions.json"),
+	}
+
+	ruleRepo, err := repository.NewRuleFileRepository(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	revisionRepo, err := repository.NewRuleRevisionFileRepository(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ruleService, err := service.NewRuleService(ruleRepo, revisionRepo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return controller.NewRuleController(ruleService, nil)
+}
+
+func serveRules(handler http.HandlerFunc, method, contentType, accept, body string) *httptest.ResponseRecorder {
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest(method, "/mock-service/rules", strings.NewReader(body))
+
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+
+	if accept != "" {
+		request.Header.Set("Accept", accept)
+	}
+
+	handler(response, request)
+
+	return response
+}
+
+// decodeRules reads the rules of an export, sorted by name.
+func decodeRules(t *testing.T, body string) []model.Rule {
+	t.Helper()
+
+	rules, err := model.UnmarshalRules(strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sort.Slice(rules, func(i, j int) bool { return rules[i].Name < rules[j].Name })
+
+	return rules
+}
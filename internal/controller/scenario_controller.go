@@ -0,0 +1,136 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	mockscontext "github.com/nicopozo/mockserver/internal/context"
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
+	"github.com/nicopozo/mockserver/internal/model"
+	"github.com/nicopozo/mockserver/internal/service"
+	httputils "github.com/nicopozo/mockserver/internal/utils/http"
+)
+
+// ScenarioController lists, inspects and resets the scenarios that rules take part in.
+type ScenarioController struct {
+	ScenarioService service.ScenarioService
+}
+
+func NewScenarioController(scenarioService service.ScenarioService) *ScenarioController {
+	return &ScenarioController{
+		ScenarioService: scenarioService,
+	}
+}
+
+// List returns every scenario with its current state.
+func (controller *ScenarioController) List(writer http.ResponseWriter, request *http.Request) {
+	reqContext := mockscontext.New(request)
+	logger := mockscontext.Logger(reqContext)
+
+	logger.Debug(controller, nil, "Entering ScenarioController List()")
+
+	scenarios, err := controller.ScenarioService.List(reqContext)
+	if err != nil {
+		logger.Error(controller, nil, err, "Error occurred when listing scenarios")
+		httputils.WriteError(writer, model.InternalError, "Error occurred when listing scenarios. %s", err.Error())
+
+		return
+	}
+
+	httputils.WriteJSON(writer, http.StatusOK, scenarios)
+}
+
+// Get returns a scenario with its current state, its known states and the rules that reference it.
+func (controller *ScenarioController) Get(writer http.ResponseWriter, request *http.Request) {
+	reqContext := mockscontext.New(request)
+	logger := mockscontext.Logger(reqContext)
+
+	logger.Debug(controller, nil, "Entering ScenarioController Get()")
+
+	scenario, err := controller.ScenarioService.Get(reqContext, request.PathValue("name"))
+	if err != nil {
+		if errors.As(err, &mockserrors.ScenarioNotFoundError{}) {
+			httputils.WriteError(writer, model.ResourceNotFoundError, "%s", err.Error())
+
+			return
+		}
+
+		logger.Error(controller, nil, err, "Error occurred when getting scenario")
+		httputils.WriteError(writer, model.InternalError, "Error occurred when getting scenario. %s", err.Error())
+
+		return
+	}
+
+	httputils.WriteJSON(writer, http.StatusOK, scenario)
+}
+
+// SetState moves a scenario to the given state.
+func (controller *ScenarioController) SetState(writer http.ResponseWriter, request *http.Request) {
+	reqContext := mockscontext.New(request)
+	logger := mockscontext.Logger(reqContext)
+
+	logger.Debug(controller, nil, "Entering ScenarioController SetState()")
+
+	state, err := model.UnmarshalScenarioState(request.Body)
+	if err != nil {
+		logger.Error(controller, nil, err, "Error unmarshalling scenario state JSON")
+		httputils.WriteError(writer, model.ValidationError, "Invalid JSON. %s", err.Error())
+
+		return
+	}
+
+	name := request.PathValue("name")
+
+	scenario, err := controller.ScenarioService.SetState(reqContext, name, state.State)
+	if err != nil {
+		if errors.As(err, &mockserrors.InvalidRulesError{}) {
+			httputils.WriteError(writer, model.ValidationError, "%s", err.Error())
+
+			return
+		}
+
+		logger.Error(controller, nil, err, "Error occurred when setting state of scenario %s", name)
+		httputils.WriteError(writer, model.InternalError, "Error occurred when setting scenario state. %s",
+			err.Error())
+
+		return
+	}
+
+	httputils.WriteJSON(writer, http.StatusOK, scenario)
+}
+
+// Reset moves a scenario back to its Started state.
+func (controller *ScenarioController) Reset(writer http.ResponseWriter, request *http.Request) {
+	reqContext := mockscontext.New(request)
+	logger := mockscontext.Logger(reqContext)
+
+	logger.Debug(controller, nil, "Entering ScenarioController Reset()")
+
+	name := request.PathValue("name")
+
+	if err := controller.ScenarioService.Reset(reqContext, name); err != nil {
+		logger.Error(controller, nil, err, "Error occurred when resetting scenario %s", name)
+		httputils.WriteError(writer, model.InternalError, "Error occurred when resetting scenario. %s", err.Error())
+
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+// ResetAll moves every scenario back to its Started state.
+func (controller *ScenarioController) ResetAll(writer http.ResponseWriter, request *http.Request) {
+	reqContext := mockscontext.New(request)
+	logger := mockscontext.Logger(reqContext)
+
+	logger.Debug(controller, nil, "Entering ScenarioController ResetAll()")
+
+	if err := controller.ScenarioService.ResetAll(reqContext); err != nil {
+		logger.Error(controller, nil, err, "Error occurred when resetting scenarios")
+		httputils.WriteError(writer, model.InternalError, "Error occurred when resetting scenarios. %s", err.Error())
+
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
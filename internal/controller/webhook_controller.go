@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	mockscontext "github.com/nicopozo/mockserver/internal/context"
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
+	"github.com/nicopozo/mockserver/internal/model"
+	"github.com/nicopozo/mockserver/internal/service"
+	httputils "github.com/nicopozo/mockserver/internal/utils/http"
+)
+
+// WebhookController lists and cancels the queued webhook deliveries.
+type WebhookController struct {
+	WebhookService service.WebhookService
+}
+
+func NewWebhookController(webhookService service.WebhookService) *WebhookController {
+	return &WebhookController{
+		WebhookService: webhookService,
+	}
+}
+
+// ListJobs returns the queued webhooks, optionally filtered by ?status=pending|failed.
+func (controller *WebhookController) ListJobs(writer http.ResponseWriter, request *http.Request) {
+	reqContext := mockscontext.New(request)
+	logger := mockscontext.Logger(reqContext)
+
+	logger.Debug(controller, nil, "Entering WebhookController ListJobs()")
+
+	jobs, err := controller.WebhookService.ListJobs(reqContext, request.URL.Query().Get("status"))
+	if err != nil {
+		if errors.As(err, &mockserrors.InvalidRulesError{}) {
+			httputils.WriteError(writer, model.ValidationError, "%s", err.Error())
+
+			return
+		}
+
+		logger.Error(controller, nil, err, "Error occurred when listing webhook jobs")
+		httputils.WriteError(writer, model.InternalError, "Error occurred when listing webhook jobs. %s", err.Error())
+
+		return
+	}
+
+	httputils.WriteJSON(writer, http.StatusOK, jobs)
+}
+
+// CancelJob removes a webhook job from the queue, so that its pending webhooks are not sent.
+func (controller *WebhookController) CancelJob(writer http.ResponseWriter, request *http.Request) {
+	reqContext := mockscontext.New(request)
+	logger := mockscontext.Logger(reqContext)
+
+	logger.Debug(controller, nil, "Entering WebhookController CancelJob()")
+
+	id := request.PathValue("id")
+
+	if err := controller.WebhookService.CancelJob(reqContext, id); err != nil {
+		if errors.As(err, &mockserrors.WebhookJobNotFoundError{}) {
+			httputils.WriteError(writer, model.ResourceNotFoundError, "%s", err.Error())
+
+			return
+		}
+
+		logger.Error(controller, nil, err, "Error occurred when cancelling webhook job %s", id)
+		httputils.WriteError(writer, model.InternalError, "Error occurred when cancelling webhook job. %s",
+			err.Error())
+
+		return
+	}
+
+	writer.WriteHeader(http.StatusNoContent)
+}
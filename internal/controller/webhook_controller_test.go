@@ -0,0 +1,69 @@
+package controller_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mockscontext "github.com/nicopozo/mockserver/internal/context"
+	"github.com/nicopozo/mockserver/internal/controller"
+	"github.com/nicopozo/mockserver/internal/model"
+	"github.com/nicopozo/mockserver/internal/repository"
+	"github.com/nicopozo/mockserver/internal/service"
+	jsonutils "github.com/nicopozo/mockserver/internal/utils/json"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookController_Jobs(t *testing.T) {
+	webhookService := newWebhookService(t, service.NewLogService(repository.NewLogMemoryRepository()))
+	webhookService.Fire(mockscontext.Background(), "01", []model.WebhookConfig{
+		{URL: "http://hooks.local", Method: http.MethodPost, Delay: 3600000, Enabled: true},
+	}, "", nil)
+
+	webhookController := controller.NewWebhookController(webhookService)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /mock-service/webhooks/jobs", webhookController.ListJobs)
+	mux.HandleFunc("DELETE /mock-service/webhooks/jobs/{id}", webhookController.CancelJob)
+
+	response := httptest.NewRecorder()
+	mux.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/mock-service/webhooks/jobs?status=pending", nil))
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	var jobs []model.WebhookJob
+	if !assert.NoError(t, jsonutils.Unmarshal(response.Body, &jobs)) || !assert.Len(t, jobs, 1) {
+		return
+	}
+
+	assert.Equal(t, "01", jobs[0].LogID)
+	assert.Equal(t, model.WebhookJobPending, jobs[0].Status)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+	}{
+		{
+			name: "Should fail with an unknown status", method: http.MethodGet,
+			path: "/mock-service/webhooks/jobs?status=done", wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Should cancel the job", method: http.MethodDelete,
+			path: "/mock-service/webhooks/jobs/" + jobs[0].ID, wantStatus: http.StatusNoContent,
+		},
+		{
+			name: "Should fail with an unknown job", method: http.MethodDelete,
+			path: "/mock-service/webhooks/jobs/" + jobs[0].ID, wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			mux.ServeHTTP(response, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, tt.wantStatus, response.Code, response.Body.String())
+		})
+	}
+}
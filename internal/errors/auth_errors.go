@@ -0,0 +1,17 @@
+package mockserrors
+
+type APIKeyNotFoundError struct {
+	Message string
+}
+
+func (e APIKeyNotFoundError) Error() string {
+	return e.Message
+}
+
+type UnauthorizedError struct {
+	Message string
+}
+
+func (e UnauthorizedError) Error() string {
+	return e.Message
+}
@@ -0,0 +1,11 @@
+package mockserrors
+
+import "fmt"
+
+type BinNotFoundError struct {
+	Name string
+}
+
+func (e BinNotFoundError) Error() string {
+	return fmt.Sprintf("bin not found: %s", e.Name)
+}
@@ -13,3 +13,11 @@ func (e LogEntryNotFoundError) Error() string {
 func NewLogEntryNotFoundError(id string) error {
 	return LogEntryNotFoundError{ID: id}
 }
+
+type WebhookJobNotFoundError struct {
+	ID string
+}
+
+func (e WebhookJobNotFoundError) Error() string {
+	return fmt.Sprintf("webhook job not found: %s", e.ID)
+}
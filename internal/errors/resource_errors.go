@@ -0,0 +1,17 @@
+package mockserrors
+
+type ResourceEntityNotFoundError struct {
+	Message string
+}
+
+func (e ResourceEntityNotFoundError) Error() string {
+	return e.Message
+}
+
+type ResourceEntityConflictError struct {
+	Message string
+}
+
+func (e ResourceEntityConflictError) Error() string {
+	return e.Message
+}
@@ -15,3 +15,11 @@ type InvalidRulesError struct {
 func (e InvalidRulesError) Error() string {
 	return e.Message
 }
+
+type RuleRevisionNotFoundError struct {
+	Message string
+}
+
+func (e RuleRevisionNotFoundError) Error() string {
+	return e.Message
+}
@@ -0,0 +1,9 @@
+package mockserrors
+
+type ScenarioNotFoundError struct {
+	Message string
+}
+
+func (e ScenarioNotFoundError) Error() string {
+	return e.Message
+}
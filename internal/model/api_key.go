@@ -0,0 +1,77 @@
+package model
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
+	jsonutils "github.com/nicopozo/mockserver/internal/utils/json"
+)
+
+const (
+	RoleReadOnly = "read-only"
+	RoleEditor   = "editor"
+	RoleAdmin    = "admin"
+)
+
+// APIKey identifies a client of the admin API. Only the SHA-256 hash of the secret is stored; the
+// secret itself is returned once, when the key is created.
+type APIKey struct {
+	ID         string    `json:"id" example:"01HZX3K2Q8V4N6B7C9D0E1F2G3"`
+	Name       string    `json:"name" example:"ci-pipeline"`
+	Role       string    `json:"role" example:"editor"`
+	Secret     string    `json:"secret,omitempty" example:"mks_3f1c..."`
+	SecretHash string    `json:"-"`
+	Static     bool      `json:"static,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func UnmarshalAPIKey(body io.Reader) (*APIKey, error) {
+	key := &APIKey{}
+
+	err := jsonutils.Unmarshal(body, key)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling body, %w", err)
+	}
+
+	return key, nil
+}
+
+func (key APIKey) Validate() error {
+	if strings.TrimSpace(key.Name) == "" {
+		return mockserrors.InvalidRulesError{Message: "name is required"}
+	}
+
+	if !IsValidRole(key.Role) {
+		return mockserrors.InvalidRulesError{
+			Message: fmt.Sprintf("invalid role '%s', must be one of %s, %s or %s", key.Role, RoleReadOnly,
+				RoleEditor, RoleAdmin),
+		}
+	}
+
+	return nil
+}
+
+// Allows reports whether the key role grants at least the permissions of required.
+func (key APIKey) Allows(required string) bool {
+	return roleLevel(key.Role) >= roleLevel(required)
+}
+
+func IsValidRole(role string) bool {
+	return roleLevel(role) > 0
+}
+
+func roleLevel(role string) int {
+	switch role {
+	case RoleReadOnly:
+		return 1
+	case RoleEditor:
+		return 2 //nolint:mnd
+	case RoleAdmin:
+		return 3 //nolint:mnd
+	}
+
+	return 0
+}
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"time"
+
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
+	jsonutils "github.com/nicopozo/mockserver/internal/utils/json"
+)
+
+var binNameRegex = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// Bin is a request bin: a sink that records every request sent under /mock-service/bins/{name}/ and
+// answers it with the configured response. Bins need no setup; one that has not been configured answers
+// with an empty 200. Delay is in milliseconds.
+type Bin struct {
+	Name        string            `json:"name" example:"payment-callbacks"`
+	Status      int               `json:"status" example:"202"`
+	ContentType string            `json:"content_type,omitempty" example:"application/json"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	Body        string            `json:"body,omitempty" example:"{\"received\":true}"`
+	Delay       int               `json:"delay,omitempty" example:"0"`
+	UpdatedAt   time.Time         `json:"updated_at,omitzero"`
+}
+
+// NewBin returns the response of a bin that has not been configured.
+func NewBin(name string) Bin {
+	return Bin{Name: name, Status: http.StatusOK}
+}
+
+func UnmarshalBin(body io.Reader) (*Bin, error) {
+	bin := &Bin{}
+
+	if err := jsonutils.Unmarshal(body, bin); err != nil {
+		return nil, fmt.Errorf("error unmarshalling body, %w", err)
+	}
+
+	return bin, nil
+}
+
+// ValidateBinName checks that name can be used in the URL of a bin.
+func ValidateBinName(name string) error {
+	if !binNameRegex.MatchString(name) {
+		return mockserrors.InvalidRulesError{
+			Message: fmt.Sprintf("'%s' is not a valid bin name, use up to 64 letters, digits, '.', '_' or '-'", name),
+		}
+	}
+
+	return nil
+}
+
+func (bin Bin) Validate() error {
+	if err := ValidateBinName(bin.Name); err != nil {
+		return err
+	}
+
+	if bin.Status < http.StatusContinue || bin.Status > 599 {
+		return mockserrors.InvalidRulesError{Message: fmt.Sprintf("%d is not a valid bin status", bin.Status)}
+	}
+
+	for name := range bin.Headers {
+		if !isValidHeaderName(name) {
+			return mockserrors.InvalidRulesError{Message: fmt.Sprintf("'%s' is not a valid header name", name)}
+		}
+	}
+
+	if bin.Delay < 0 {
+		return mockserrors.InvalidRulesError{Message: "bin delay cannot be negative"}
+	}
+
+	return nil
+}
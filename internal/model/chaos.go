@@ -0,0 +1,281 @@
+package model
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
+	jsonutils "github.com/nicopozo/mockserver/internal/utils/json"
+)
+
+const (
+	ChaosStatusEnabled  = "enabled"
+	ChaosStatusDisabled = "disabled"
+
+	ChaosSourceRule  = "rule"
+	ChaosSourceGroup = "group"
+
+	ChaosLatencyFixed     = "fixed"
+	ChaosLatencyUniform   = "uniform"
+	ChaosLatencyNormal    = "normal"
+	ChaosLatencyLogNormal = "lognormal"
+
+	// chaosP99ZScore is the 99th percentile of the standard normal distribution.
+	chaosP99ZScore = 2.3263478740408408
+)
+
+var chaosLatencyDistributions = []string{
+	ChaosLatencyFixed, ChaosLatencyUniform, ChaosLatencyNormal, ChaosLatencyLogNormal,
+}
+
+// ChaosPolicy disturbs a share of the requests answered by a rule or by the rules of a group. When the
+// policy fires, which happens with the given probability, the latency is added to the response and the
+// response is replaced by the error status or broken with the fault.
+type ChaosPolicy struct {
+	Probability float64       `json:"probability" example:"0.1"`
+	ErrorStatus int           `json:"error_status,omitempty" example:"503"`
+	ErrorBody   string        `json:"error_body,omitempty" example:"{\"message\":\"service unavailable\"}"`
+	Latency     *ChaosLatency `json:"latency,omitempty"`
+	Fault       string        `json:"fault,omitempty" example:"connection_reset"`
+}
+
+// ChaosLatency is a distribution of delays in milliseconds. Fixed uses Value, uniform uses Min and Max,
+// normal uses Mean and StdDev, and lognormal is fitted to the P50 and P99 percentiles.
+type ChaosLatency struct {
+	Distribution string  `json:"distribution" example:"lognormal"`
+	Value        float64 `json:"value,omitempty" example:"200"`
+	Min          float64 `json:"min,omitempty" example:"100"`
+	Max          float64 `json:"max,omitempty" example:"500"`
+	Mean         float64 `json:"mean,omitempty" example:"250"`
+	StdDev       float64 `json:"std_dev,omitempty" example:"50"`
+	P50          float64 `json:"p50,omitempty" example:"120"`
+	P99          float64 `json:"p99,omitempty" example:"900"`
+}
+
+// ChaosSettings holds the global chaos switch, the seed of the random decisions and the group policies.
+type ChaosSettings struct {
+	Status    string                 `json:"status" example:"enabled"`
+	Seed      *int64                 `json:"seed,omitempty" example:"42"`
+	Groups    map[string]ChaosPolicy `json:"groups,omitempty"`
+	UpdatedAt time.Time              `json:"updated_at,omitzero"`
+}
+
+// ChaosStatus is the body used to switch chaos on and off.
+type ChaosStatus struct {
+	Status string `json:"status" example:"disabled"`
+}
+
+// ChaosResult records the chaos applied to a request so that unexpected responses can be explained.
+type ChaosResult struct {
+	Source      string `json:"source" example:"group"`
+	Group       string `json:"group,omitempty" example:"payments"`
+	LatencyMs   int    `json:"latency_ms,omitempty" example:"340"`
+	ErrorStatus int    `json:"error_status,omitempty" example:"503"`
+	Fault       string `json:"fault,omitempty" example:"connection_reset"`
+	ErrorBody   string `json:"-"`
+}
+
+// Sampler returns pseudo-random numbers for the chaos decisions.
+type Sampler interface {
+	Float64() float64
+	NormFloat64() float64
+}
+
+func NewChaosSettings() *ChaosSettings {
+	return &ChaosSettings{Status: ChaosStatusEnabled}
+}
+
+func UnmarshalChaosSettings(body io.Reader) (*ChaosSettings, error) {
+	settings := &ChaosSettings{}
+
+	if err := jsonutils.Unmarshal(body, settings); err != nil {
+		return nil, fmt.Errorf("error unmarshalling body, %w", err)
+	}
+
+	return settings, nil
+}
+
+func UnmarshalChaosStatus(body io.Reader) (*ChaosStatus, error) {
+	status := &ChaosStatus{}
+
+	if err := jsonutils.Unmarshal(body, status); err != nil {
+		return nil, fmt.Errorf("error unmarshalling body, %w", err)
+	}
+
+	return status, nil
+}
+
+// Enabled reports whether the chaos policies are applied.
+func (settings ChaosSettings) Enabled() bool {
+	return settings.Status != ChaosStatusDisabled
+}
+
+func (settings ChaosSettings) Validate() error {
+	if err := (ChaosStatus{Status: settings.Status}).Validate(); err != nil {
+		return err
+	}
+
+	for group, policy := range settings.Groups {
+		if strings.TrimSpace(group) == "" {
+			return mockserrors.InvalidRulesError{Message: "chaos group name cannot be empty"}
+		}
+
+		if err := policy.Validate(); err != nil {
+			return mockserrors.InvalidRulesError{Message: fmt.Sprintf("group '%s': %s", group, err.Error())}
+		}
+	}
+
+	return nil
+}
+
+func (status ChaosStatus) Validate() error {
+	if status.Status != ChaosStatusEnabled && status.Status != ChaosStatusDisabled {
+		return mockserrors.InvalidRulesError{
+			Message: fmt.Sprintf("chaos status must be '%s' or '%s'", ChaosStatusEnabled, ChaosStatusDisabled),
+		}
+	}
+
+	return nil
+}
+
+func (policy ChaosPolicy) Validate() error {
+	if policy.Probability < 0 || policy.Probability > 1 {
+		return mockserrors.InvalidRulesError{Message: "chaos probability must be between 0 and 1"}
+	}
+
+	if policy.ErrorStatus == 0 && policy.Latency == nil && policy.Fault == "" {
+		return mockserrors.InvalidRulesError{Message: "chaos policy must set error_status, latency or fault"}
+	}
+
+	if policy.ErrorStatus != 0 && policy.Fault != "" {
+		return mockserrors.InvalidRulesError{Message: "chaos error_status and fault cannot be combined"}
+	}
+
+	if policy.ErrorStatus != 0 && (policy.ErrorStatus < http.StatusBadRequest || policy.ErrorStatus > 599) {
+		return mockserrors.InvalidRulesError{Message: "chaos error_status must be between 400 and 599"}
+	}
+
+	if err := validateFault(policy.Fault); err != nil {
+		return err
+	}
+
+	if policy.Latency != nil {
+		return policy.Latency.Validate()
+	}
+
+	return nil
+}
+
+func (latency ChaosLatency) Validate() error {
+	var valid bool
+
+	switch latency.Distribution {
+	case ChaosLatencyFixed:
+		valid = latency.Value >= 0
+	case ChaosLatencyUniform:
+		valid = latency.Min >= 0 && latency.Min <= latency.Max
+	case ChaosLatencyNormal:
+		valid = latency.Mean >= 0 && latency.StdDev >= 0
+	case ChaosLatencyLogNormal:
+		valid = latency.P50 > 0 && latency.P50 <= latency.P99
+	default:
+		return mockserrors.InvalidRulesError{
+			Message: fmt.Sprintf("chaos latency distribution must be one of: %s",
+				strings.Join(chaosLatencyDistributions, ", ")),
+		}
+	}
+
+	if !valid {
+		return mockserrors.InvalidRulesError{
+			Message: fmt.Sprintf("invalid parameters for the %s latency distribution", latency.Distribution),
+		}
+	}
+
+	return nil
+}
+
+// Sample draws a delay from the distribution. Negative draws of the normal distribution become 0.
+func (latency ChaosLatency) Sample(sampler Sampler) time.Duration {
+	var millis float64
+
+	switch latency.Distribution {
+	case ChaosLatencyFixed:
+		millis = latency.Value
+	case ChaosLatencyUniform:
+		millis = latency.Min + sampler.Float64()*(latency.Max-latency.Min)
+	case ChaosLatencyNormal:
+		millis = latency.Mean + sampler.NormFloat64()*latency.StdDev
+	case ChaosLatencyLogNormal:
+		mu := math.Log(latency.P50)
+		sigma := (math.Log(latency.P99) - mu) / chaosP99ZScore
+		millis = math.Exp(mu + sampler.NormFloat64()*sigma)
+	}
+
+	return time.Duration(max(millis, 0) * float64(time.Millisecond))
+}
+
+// Decide rolls the policy. It returns nil when the policy does not fire for the request.
+func (policy ChaosPolicy) Decide(sampler Sampler) *ChaosResult {
+	if sampler.Float64() >= policy.Probability {
+		return nil
+	}
+
+	result := &ChaosResult{
+		ErrorStatus: policy.ErrorStatus,
+		ErrorBody:   policy.ErrorBody,
+		Fault:       policy.Fault,
+	}
+
+	if policy.Latency != nil {
+		result.LatencyMs = int(policy.Latency.Sample(sampler).Milliseconds())
+	}
+
+	return result
+}
+
+// Interrupts reports whether the rule must not be executed because the request is answered with an error.
+func (result *ChaosResult) Interrupts() bool {
+	return result != nil && result.ErrorStatus != 0
+}
+
+// Apply adds the chaos to the response that the rule returned.
+func (result *ChaosResult) Apply(response Response) Response {
+	if result == nil {
+		return response
+	}
+
+	if result.ErrorStatus != 0 {
+		response = Response{HTTPStatus: result.ErrorStatus, ContentType: "application/json", Body: result.ErrorBody}
+
+		if response.Body == "" {
+			response.Body = jsonutils.Marshal(map[string]interface{}{
+				"message": "error injected by chaos policy",
+				"status":  result.ErrorStatus,
+			})
+		}
+	}
+
+	response.Delay += result.LatencyMs
+
+	if result.Fault != "" {
+		response.Fault = result.Fault
+	}
+
+	return response
+}
+
+func validateFault(fault string) error {
+	if fault == "" || slices.Contains(responseFaults, fault) {
+		return nil
+	}
+
+	return mockserrors.InvalidRulesError{
+		Message: fmt.Sprintf("'%s' is not a valid fault, valid values are: %s", fault,
+			strings.Join(responseFaults, ", ")),
+	}
+}
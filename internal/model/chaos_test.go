@@ -0,0 +1,133 @@
+package model_test
+
+import (
+	"math/rand"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/nicopozo/mockserver/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChaosLatency_Sample(t *testing.T) {
+	random := rand.New(rand.NewSource(7)) //nolint:gosec
+
+	fixed := model.ChaosLatency{Distribution: model.ChaosLatencyFixed, Value: 250}
+	assert.Equal(t, 250*time.Millisecond, fixed.Sample(random))
+
+	uniform := model.ChaosLatency{Distribution: model.ChaosLatencyUniform, Min: 100, Max: 200}
+	normal := model.ChaosLatency{Distribution: model.ChaosLatencyNormal, Mean: 10, StdDev: 50}
+	logNormal := model.ChaosLatency{Distribution: model.ChaosLatencyLogNormal, P50: 100, P99: 1000}
+
+	samples := make([]time.Duration, 0, 20000)
+
+	for range 20000 {
+		delay := uniform.Sample(random)
+		assert.True(t, delay >= 100*time.Millisecond && delay <= 200*time.Millisecond)
+		assert.GreaterOrEqual(t, normal.Sample(random), time.Duration(0))
+
+		samples = append(samples, logNormal.Sample(random))
+	}
+
+	slices.Sort(samples)
+
+	assert.InDelta(t, 100, samples[len(samples)/2].Milliseconds(), 10)
+	assert.InDelta(t, 1000, samples[len(samples)*99/100].Milliseconds(), 150)
+}
+
+func TestChaosPolicy_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  model.ChaosPolicy
+		wantErr string
+	}{
+		{
+			name:   "Should accept an error status",
+			policy: model.ChaosPolicy{Probability: 0.1, ErrorStatus: http.StatusServiceUnavailable},
+		},
+		{
+			name: "Should accept latency with a fault",
+			policy: model.ChaosPolicy{
+				Probability: 1,
+				Latency:     &model.ChaosLatency{Distribution: model.ChaosLatencyNormal, Mean: 200, StdDev: 20},
+				Fault:       model.ResponseFaultTruncatedBody,
+			},
+		},
+		{
+			name:    "Should fail with a probability above 1",
+			policy:  model.ChaosPolicy{Probability: 1.5, ErrorStatus: http.StatusServiceUnavailable},
+			wantErr: "chaos probability must be between 0 and 1",
+		},
+		{
+			name:    "Should fail without an effect",
+			policy:  model.ChaosPolicy{Probability: 0.5},
+			wantErr: "chaos policy must set error_status, latency or fault",
+		},
+		{
+			name: "Should fail with an error status and a fault",
+			policy: model.ChaosPolicy{
+				Probability: 0.5, ErrorStatus: http.StatusBadGateway, Fault: model.ResponseFaultEmptyReply,
+			},
+			wantErr: "chaos error_status and fault cannot be combined",
+		},
+		{
+			name:    "Should fail with a success error status",
+			policy:  model.ChaosPolicy{Probability: 0.5, ErrorStatus: http.StatusOK},
+			wantErr: "chaos error_status must be between 400 and 599",
+		},
+		{
+			name:    "Should fail with an unknown fault",
+			policy:  model.ChaosPolicy{Probability: 0.5, Fault: "explode"},
+			wantErr: "'explode' is not a valid fault",
+		},
+		{
+			name: "Should fail with an unknown distribution",
+			policy: model.ChaosPolicy{
+				Probability: 0.5, Latency: &model.ChaosLatency{Distribution: "pareto"},
+			},
+			wantErr: "chaos latency distribution must be one of: fixed, uniform, normal, lognormal",
+		},
+		{
+			name: "Should fail with a p99 below the p50",
+			policy: model.ChaosPolicy{
+				Probability: 0.5,
+				Latency:     &model.ChaosLatency{Distribution: model.ChaosLatencyLogNormal, P50: 500, P99: 100},
+			},
+			wantErr: "invalid parameters for the lognormal latency distribution",
+		},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestChaosResult_Apply(t *testing.T) {
+	response := model.Response{Body: "ok", ContentType: "text/plain", HTTPStatus: http.StatusOK, Delay: 10}
+
+	var none *model.ChaosResult
+	assert.Equal(t, response, none.Apply(response))
+
+	slow := &model.ChaosResult{LatencyMs: 90, Fault: model.ResponseFaultRandomData}
+	assert.Equal(t, model.Response{
+		Body: "ok", ContentType: "text/plain", HTTPStatus: http.StatusOK, Delay: 100,
+		Fault: model.ResponseFaultRandomData,
+	}, slow.Apply(response))
+
+	failed := &model.ChaosResult{ErrorStatus: http.StatusTooManyRequests, ErrorBody: `{"error":"slow down"}`}
+	assert.True(t, failed.Interrupts())
+	assert.Equal(t, model.Response{
+		Body: `{"error":"slow down"}`, ContentType: "application/json", HTTPStatus: http.StatusTooManyRequests,
+	}, failed.Apply(response))
+}
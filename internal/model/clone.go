@@ -0,0 +1,161 @@
+package model
+
+import (
+	"maps"
+	"slices"
+)
+
+// Clone returns a deep copy of the rule. Rules kept in memory by a repository are cloned before they are
+// handed out, since serving a request writes to them, like the values of their variables.
+func (rule Rule) Clone() Rule {
+	rule.Matchers = slices.Clone(rule.Matchers)
+	rule.Responses = cloneSlice(rule.Responses, Response.Clone)
+	rule.Variables = cloneVariables(rule.Variables)
+	rule.Scenario = clonePointer(rule.Scenario)
+	rule.Chaos = rule.Chaos.clone()
+	rule.Resource = rule.Resource.clone()
+
+	return rule
+}
+
+// Clone returns a deep copy of the response.
+func (response Response) Clone() Response {
+	response.Headers = maps.Clone(response.Headers)
+	response.Cookies = slices.Clone(response.Cookies)
+	response.Webhooks = cloneSlice(response.Webhooks, WebhookConfig.Clone)
+
+	if response.Webhook != nil {
+		webhook := response.Webhook.Clone()
+		response.Webhook = &webhook
+	}
+
+	return response
+}
+
+// Clone returns a deep copy of the webhook.
+func (webhook WebhookConfig) Clone() WebhookConfig {
+	webhook.Headers = maps.Clone(webhook.Headers)
+	webhook.Timeout = clonePointer(webhook.Timeout)
+	webhook.Signature = clonePointer(webhook.Signature)
+	webhook.Variables = cloneVariables(webhook.Variables)
+
+	if webhook.Retry != nil {
+		retry := *webhook.Retry
+		retry.RetryOn = slices.Clone(retry.RetryOn)
+		webhook.Retry = &retry
+	}
+
+	return webhook
+}
+
+// Clone returns a deep copy of the variable.
+func (variable Variable) Clone() Variable {
+	variable.Min = clonePointer(variable.Min)
+	variable.Max = clonePointer(variable.Max)
+	variable.Decimals = clonePointer(variable.Decimals)
+
+	if variable.Assertions != nil {
+		assertions := make([]*Assertion, len(variable.Assertions))
+		for index, assertion := range variable.Assertions {
+			assertions[index] = clonePointer(assertion)
+		}
+
+		variable.Assertions = assertions
+	}
+
+	return variable
+}
+
+func (policy *ChaosPolicy) clone() *ChaosPolicy {
+	if policy == nil {
+		return nil
+	}
+
+	cloned := *policy
+	cloned.Latency = clonePointer(policy.Latency)
+
+	return &cloned
+}
+
+func (config *ResourceConfig) clone() *ResourceConfig {
+	if config == nil {
+		return nil
+	}
+
+	cloned := *config
+	cloned.Schema = cloneJSONObject(config.Schema)
+
+	if config.Seed != nil {
+		cloned.Seed = make([]map[string]interface{}, len(config.Seed))
+		for index, entity := range config.Seed {
+			cloned.Seed[index] = cloneJSONObject(entity)
+		}
+	}
+
+	return &cloned
+}
+
+func cloneVariables(variables []*Variable) []*Variable {
+	if variables == nil {
+		return nil
+	}
+
+	cloned := make([]*Variable, len(variables))
+
+	for index, variable := range variables {
+		if variable != nil {
+			copied := variable.Clone()
+			cloned[index] = &copied
+		}
+	}
+
+	return cloned
+}
+
+func cloneSlice[T any](items []T, clone func(T) T) []T {
+	if items == nil {
+		return nil
+	}
+
+	cloned := make([]T, len(items))
+	for index, item := range items {
+		cloned[index] = clone(item)
+	}
+
+	return cloned
+}
+
+func clonePointer[T any](value *T) *T {
+	if value == nil {
+		return nil
+	}
+
+	cloned := *value
+
+	return &cloned
+}
+
+func cloneJSONObject(object map[string]interface{}) map[string]interface{} {
+	if object == nil {
+		return nil
+	}
+
+	cloned := make(map[string]interface{}, len(object))
+	for key, value := range object {
+		cloned[key] = cloneJSONValue(value)
+	}
+
+	return cloned
+}
+
+// cloneJSONValue copies the objects and arrays of a decoded JSON value, the other values are immutable.
+func cloneJSONValue(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		return cloneJSONObject(typed)
+	case []interface{}:
+		return cloneSlice(typed, cloneJSONValue)
+	default:
+		return value
+	}
+}
@@ -14,6 +14,8 @@ const (
 	ServiceUnavailableError   = 1021
 	ResourceNotFoundError     = 1030
 	NotImplementedError       = 1031
+	Unauthorized              = 1032
+	Forbidden                 = 1033
 )
 
 //nolint:gochecknoglobals
@@ -26,6 +28,8 @@ var causeMap = map[int]causeMapping{
 	ServiceUnavailableError:   {status: http.StatusBadGateway, message: "Service Unavailable"},
 	ResourceNotFoundError:     {status: http.StatusNotFound, message: "Resource Not Found"},
 	NotImplementedError:       {status: http.StatusNotImplemented, message: "Not Implemented"},
+	Unauthorized:              {status: http.StatusUnauthorized, message: "Unauthorized"},
+	Forbidden:                 {status: http.StatusForbidden, message: "Forbidden"},
 }
 
 type ErrorCause struct {
@@ -0,0 +1,11 @@
+package model
+
+// ExecutionResult describes how a mock request was resolved: the rule that matched it, the scene of
+// the selected response and the outcome of the rule assertions.
+type ExecutionResult struct {
+	AssertionResult
+	RuleKey  string
+	RuleName string
+	Scene    string
+	Chaos    *ChaosResult
+}
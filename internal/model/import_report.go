@@ -0,0 +1,23 @@
+package model
+
+// ImportReport summarizes the rules generated from an API specification.
+type ImportReport struct {
+	Created []ImportedRule     `json:"created"`
+	Updated []ImportedRule     `json:"updated"`
+	Skipped []SkippedOperation `json:"skipped"`
+}
+
+// ImportedRule identifies a rule created or updated by an import.
+type ImportedRule struct {
+	Key    string `json:"key" example:"01HZX3K2Q8V4N6B7C9D0E1F2G3"`
+	Name   string `json:"name" example:"getPayment"`
+	Method string `json:"method" example:"GET"`
+	Path   string `json:"path" example:"/v1/payments/{payment_id}"`
+}
+
+// SkippedOperation is an operation of the specification that could not be turned into a rule.
+type SkippedOperation struct {
+	Method string `json:"method" example:"GET"`
+	Path   string `json:"path" example:"/v1/payments/{payment_id}"`
+	Reason string `json:"reason" example:"no documented status codes"`
+}
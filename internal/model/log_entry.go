@@ -1,23 +1,40 @@
 package model
 
 import (
+	"net/http"
+	"net/url"
+	"strings"
 	"time"
 )
 
-// WebhookResult stores the outcome of a fired webhook.
+// WebhookResult stores the outcome of one attempt to deliver a webhook. Webhook is the position of the
+// webhook among the ones fired by the response. The request is kept so that the webhook can be
+// redelivered. An attempt is superseded when a later attempt sent the webhook again.
 type WebhookResult struct {
-	URL          string `json:"url"`
-	Method       string `json:"method"`
-	StatusCode   int    `json:"status_code"`
-	DurationMs   int64  `json:"duration_ms"`
-	Error        string `json:"error,omitempty"`
-	ResponseBody string `json:"response_body,omitempty"`
+	URL            string            `json:"url"`
+	Method         string            `json:"method"`
+	Webhook        int               `json:"webhook"`
+	Attempt        int               `json:"attempt,omitempty"`
+	Timestamp      time.Time         `json:"timestamp,omitzero"`
+	StatusCode     int               `json:"status_code"`
+	DurationMs     int64             `json:"duration_ms"`
+	Error          string            `json:"error,omitempty"`
+	ResponseBody   string            `json:"response_body,omitempty"`
+	RequestHeaders map[string]string `json:"request_headers,omitempty"`
+	RequestBody    string            `json:"request_body,omitempty"`
+	Redelivery     bool              `json:"redelivery,omitempty"`
+	Superseded     bool              `json:"superseded,omitempty"`
 }
 
-// LogEntry represents a captured request/response pair from the mock endpoint.
+// LogEntry represents a captured request/response pair from the mock endpoint. Requests received by a
+// request bin are stored the same way, with the name of the bin in Bin and their URL relative to it.
 type LogEntry struct {
 	ID              string            `json:"id"`
+	Bin             string            `json:"bin,omitempty"`
 	Timestamp       time.Time         `json:"timestamp"`
+	RuleKey         string            `json:"rule_key,omitempty"`
+	RuleName        string            `json:"rule_name,omitempty"`
+	Scene           string            `json:"scene,omitempty"`
 	Method          string            `json:"method"`
 	URL             string            `json:"url"`
 	RequestBody     string            `json:"request_body"`
@@ -25,12 +42,84 @@ type LogEntry struct {
 	QueryParams     map[string]string `json:"query_params"`
 	ResponseStatus  int               `json:"response_status"`
 	ResponseBody    string            `json:"response_body"`
+	DurationMs      int64             `json:"duration_ms"`
+	Fault           string            `json:"fault,omitempty"`
+	Chaos           *ChaosResult      `json:"chaos,omitempty"`
 	AssertionErrors []string          `json:"assertion_errors,omitempty"`
 	WebhookResults  []WebhookResult   `json:"webhook_results,omitempty"`
 }
 
+// Failed reports whether the webhook could not be delivered or was answered with an error status.
+func (result WebhookResult) Failed() bool {
+	return result.Error != "" || result.StatusCode >= http.StatusBadRequest
+}
+
+// HasAssertionErrors reports whether any rule assertion failed for the request.
+func (entry LogEntry) HasAssertionErrors() bool {
+	return len(entry.AssertionErrors) > 0
+}
+
+// HasWebhookFailures reports whether any webhook fired by the response failed. Failed attempts that
+// were superseded by a retry or a redelivery are not counted.
+func (entry LogEntry) HasWebhookFailures() bool {
+	for _, result := range entry.WebhookResults {
+		if result.Failed() && !result.Superseded {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AddRedelivery records result as a redelivery of the webhook result at index, which it supersedes.
+// Its attempt follows the last attempt made for the same webhook.
+func (entry *LogEntry) AddRedelivery(index int, result WebhookResult) {
+	source := &entry.WebhookResults[index]
+	source.Superseded = true
+
+	result.Webhook = source.Webhook
+	result.Attempt = source.Attempt + 1
+
+	for _, previous := range entry.WebhookResults {
+		if previous.Webhook == source.Webhook && previous.Attempt >= result.Attempt {
+			result.Attempt = previous.Attempt + 1
+		}
+	}
+
+	result.Redelivery = true
+
+	entry.WebhookResults = append(entry.WebhookResults, result)
+}
+
 // LogList wraps a slice of LogEntry for API responses.
 type LogList struct {
 	Paging  Paging     `json:"paging"`
 	Results []LogEntry `json:"results"`
 }
+
+// MatchRequest rebuilds the request parts that matchers inspect from a captured log entry.
+func (entry LogEntry) MatchRequest() *MatchRequest {
+	headers := make(http.Header, len(entry.RequestHeaders))
+	for name, value := range entry.RequestHeaders {
+		headers.Set(name, value)
+	}
+
+	query := make(url.Values, len(entry.QueryParams))
+	for name, value := range entry.QueryParams {
+		query.Set(name, value)
+	}
+
+	return &MatchRequest{
+		Headers: headers,
+		Query:   query,
+		Cookies: (&http.Request{Header: headers}).Cookies(),
+		Body:    entry.RequestBody,
+	}
+}
+
+// Path returns the request path of the entry, without its query string.
+func (entry LogEntry) Path() string {
+	path, _, _ := strings.Cut(entry.URL, "?")
+
+	return path
+}
@@ -0,0 +1,12 @@
+package model
+
+const (
+	LogEventCreated = "log"
+	LogEventUpdated = "update"
+)
+
+// LogEvent notifies subscribers that a log entry was captured or updated, e.g. with a webhook result.
+type LogEvent struct {
+	Type  string   `json:"type"`
+	Entry LogEntry `json:"entry"`
+}
@@ -0,0 +1,87 @@
+package model
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
+)
+
+// LogFilter narrows the log entries returned by a search. Empty fields are ignored, except Bin: an empty
+// Bin selects the requests received by the mocks, and a bin name the requests received by that bin.
+type LogFilter struct {
+	Bin                string
+	Method             string
+	URL                string
+	URLRegex           string
+	StatusFrom         int
+	StatusTo           int
+	From               time.Time
+	To                 time.Time
+	RuleKey            string
+	HasAssertionErrors *bool
+	HasWebhookFailures *bool
+}
+
+func (filter LogFilter) Validate() error {
+	if filter.URL != "" && filter.URLRegex != "" {
+		return mockserrors.InvalidRulesError{Message: "url and url_regex cannot be combined"}
+	}
+
+	if filter.URLRegex != "" {
+		if _, err := regexp.Compile(filter.URLRegex); err != nil {
+			return mockserrors.InvalidRulesError{Message: "url_regex is not a valid regular expression: " + err.Error()}
+		}
+	}
+
+	if filter.StatusFrom < 0 || filter.StatusTo < 0 {
+		return mockserrors.InvalidRulesError{Message: "status range cannot be negative"}
+	}
+
+	if filter.StatusTo != 0 && filter.StatusFrom > filter.StatusTo {
+		return mockserrors.InvalidRulesError{Message: "status_from must be lower than or equal to status_to"}
+	}
+
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.From.After(filter.To) {
+		return mockserrors.InvalidRulesError{Message: "from must be before to"}
+	}
+
+	return nil
+}
+
+// Matcher returns a function that reports whether an entry satisfies every criteria of the filter, with
+// url_regex compiled once for all the entries. It is meant for in-memory repositories; database backends
+// translate the filter into their own query language. An invalid url_regex, which Validate reports,
+// matches no entry.
+func (filter LogFilter) Matcher() func(entry LogEntry) bool {
+	var urlRegex *regexp.Regexp
+
+	if filter.URLRegex != "" {
+		regex, err := regexp.Compile(filter.URLRegex)
+		if err != nil {
+			return func(LogEntry) bool { return false }
+		}
+
+		urlRegex = regex
+	}
+
+	return func(entry LogEntry) bool {
+		switch {
+		case filter.Bin != entry.Bin,
+			filter.Method != "" && !strings.EqualFold(filter.Method, entry.Method),
+			filter.URL != "" && !strings.Contains(entry.URL, filter.URL),
+			urlRegex != nil && !urlRegex.MatchString(entry.URL),
+			filter.StatusFrom != 0 && entry.ResponseStatus < filter.StatusFrom,
+			filter.StatusTo != 0 && entry.ResponseStatus > filter.StatusTo,
+			!filter.From.IsZero() && entry.Timestamp.Before(filter.From),
+			!filter.To.IsZero() && entry.Timestamp.After(filter.To),
+			filter.RuleKey != "" && filter.RuleKey != entry.RuleKey,
+			filter.HasAssertionErrors != nil && *filter.HasAssertionErrors != entry.HasAssertionErrors(),
+			filter.HasWebhookFailures != nil && *filter.HasWebhookFailures != entry.HasWebhookFailures():
+			return false
+		}
+
+		return true
+	}
+}
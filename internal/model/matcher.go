@@ -0,0 +1,217 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/antchfx/xmlquery"
+	"github.com/antchfx/xpath"
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
+	jsonutils "github.com/nicopozo/mockserver/internal/utils/json"
+	"github.com/yalp/jsonpath"
+)
+
+const (
+	MatcherTypeHeader   = "header"
+	MatcherTypeQuery    = "query"
+	MatcherTypeCookie   = "cookie"
+	MatcherTypeJSONPath = "jsonpath"
+	MatcherTypeXPath    = "xpath"
+
+	MatcherOperatorEquals  = "equals"
+	MatcherOperatorRegex   = "regex"
+	MatcherOperatorPresent = "present"
+	MatcherOperatorAbsent  = "absent"
+)
+
+// Matcher is a predicate over the incoming request that must pass for a rule to be selected.
+type Matcher struct {
+	Type     string `json:"type" example:"header"`
+	Key      string `json:"key" example:"X-Country"`
+	Operator string `json:"operator" example:"equals"`
+	Value    string `json:"value,omitempty" example:"AR"`
+}
+
+// MatchRequest holds the parts of an incoming mock request that matchers can inspect.
+type MatchRequest struct {
+	Headers http.Header
+	Query   url.Values
+	Cookies []*http.Cookie
+	Body    string
+	// ScenarioState returns the current state of a scenario. When nil, every scenario is Started.
+	ScenarioState func(name string) string
+}
+
+func (request *MatchRequest) scenarioState(name string) string {
+	if request == nil || request.ScenarioState == nil {
+		return ScenarioStateStarted
+	}
+
+	return request.ScenarioState(name)
+}
+
+// NewMatchRequest builds a MatchRequest from the incoming request and its already read body.
+func NewMatchRequest(request *http.Request, body string) *MatchRequest {
+	if request == nil {
+		return &MatchRequest{Body: body}
+	}
+
+	matchRequest := &MatchRequest{
+		Headers: request.Header,
+		Cookies: request.Cookies(),
+		Body:    body,
+	}
+
+	if request.URL != nil {
+		matchRequest.Query = request.URL.Query()
+	}
+
+	return matchRequest
+}
+
+func (matcher Matcher) Validate() error {
+	switch matcher.Type {
+	case MatcherTypeHeader, MatcherTypeQuery, MatcherTypeCookie, MatcherTypeJSONPath, MatcherTypeXPath:
+	default:
+		return mockserrors.InvalidRulesError{
+			Message: fmt.Sprintf("matcher type must be '%s', '%s', '%s', '%s' or '%s'", MatcherTypeHeader,
+				MatcherTypeQuery, MatcherTypeCookie, MatcherTypeJSONPath, MatcherTypeXPath),
+		}
+	}
+
+	if strings.TrimSpace(matcher.Key) == "" {
+		return mockserrors.InvalidRulesError{Message: "matcher key cannot be empty"}
+	}
+
+	if err := matcher.validateKey(); err != nil {
+		return err
+	}
+
+	switch matcher.Operator {
+	case MatcherOperatorPresent, MatcherOperatorAbsent, MatcherOperatorEquals:
+		return nil
+	case MatcherOperatorRegex:
+		if _, err := regexp.Compile(matcher.Value); err != nil {
+			return mockserrors.InvalidRulesError{Message: fmt.Sprintf("invalid regex pattern: %s", matcher.Value)}
+		}
+
+		return nil
+	}
+
+	return mockserrors.InvalidRulesError{
+		Message: fmt.Sprintf("matcher operator must be '%s', '%s', '%s' or '%s'", MatcherOperatorEquals,
+			MatcherOperatorRegex, MatcherOperatorPresent, MatcherOperatorAbsent),
+	}
+}
+
+func (matcher Matcher) validateKey() error {
+	switch matcher.Type {
+	case MatcherTypeJSONPath:
+		if _, err := jsonpath.Prepare(matcher.Key); err != nil {
+			return mockserrors.InvalidRulesError{Message: fmt.Sprintf("invalid JSON path: %s", matcher.Key)}
+		}
+	case MatcherTypeXPath:
+		if _, err := xpath.Compile(matcher.Key); err != nil {
+			return mockserrors.InvalidRulesError{Message: fmt.Sprintf("invalid XPath: %s", matcher.Key)}
+		}
+	}
+
+	return nil
+}
+
+// Matches reports whether the request satisfies the matcher.
+func (matcher Matcher) Matches(request *MatchRequest) bool {
+	if request == nil {
+		request = &MatchRequest{}
+	}
+
+	value, found := matcher.extract(request)
+
+	switch matcher.Operator {
+	case MatcherOperatorPresent:
+		return found
+	case MatcherOperatorAbsent:
+		return !found
+	case MatcherOperatorEquals:
+		return found && value == matcher.Value
+	case MatcherOperatorRegex:
+		if !found {
+			return false
+		}
+
+		matched, err := regexp.MatchString(matcher.Value, value)
+
+		return err == nil && matched
+	}
+
+	return false
+}
+
+func (matcher Matcher) extract(request *MatchRequest) (string, bool) {
+	switch matcher.Type {
+	case MatcherTypeHeader:
+		values := request.Headers.Values(matcher.Key)
+		if len(values) == 0 {
+			return "", false
+		}
+
+		return values[0], true
+	case MatcherTypeQuery:
+		values, ok := request.Query[matcher.Key]
+		if !ok || len(values) == 0 {
+			return "", false
+		}
+
+		return values[0], true
+	case MatcherTypeCookie:
+		for _, cookie := range request.Cookies {
+			if cookie.Name == matcher.Key {
+				return cookie.Value, true
+			}
+		}
+
+		return "", false
+	case MatcherTypeJSONPath:
+		return extractJSONPath(matcher.Key, request.Body)
+	case MatcherTypeXPath:
+		return extractXPath(matcher.Key, request.Body)
+	}
+
+	return "", false
+}
+
+func extractJSONPath(key, body string) (string, bool) {
+	var document interface{}
+	if err := json.Unmarshal([]byte(body), &document); err != nil {
+		return "", false
+	}
+
+	value, err := jsonpath.Read(document, key)
+	if err != nil || value == nil {
+		return "", false
+	}
+
+	if s, ok := value.(string); ok {
+		return s, true
+	}
+
+	return jsonutils.Marshal(value), true
+}
+
+func extractXPath(key, body string) (string, bool) {
+	doc, err := xmlquery.Parse(strings.NewReader(body))
+	if err != nil {
+		return "", false
+	}
+
+	node, err := xmlquery.Query(doc, key)
+	if err != nil || node == nil {
+		return "", false
+	}
+
+	return node.InnerText(), true
+}
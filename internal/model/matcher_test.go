@@ -0,0 +1,158 @@
+package model_test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
+	"github.com/nicopozo/mockserver/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatcher_Matches(t *testing.T) {
+	request := &model.MatchRequest{
+		Headers: http.Header{"X-Country": {"AR"}},
+		Query:   url.Values{"type": {"refund"}},
+		Cookies: []*http.Cookie{{Name: "session", Value: "abc123"}},
+		Body:    `{"payment":{"amount":100,"currency":"ARS"}}`,
+	}
+
+	xmlRequest := &model.MatchRequest{
+		Body: `<payment><currency>USD</currency></payment>`,
+	}
+
+	tests := []struct {
+		name    string
+		matcher model.Matcher
+		request *model.MatchRequest
+		want    bool
+	}{
+		{
+			name:    "Should match header equals",
+			matcher: model.Matcher{Type: "header", Key: "x-country", Operator: "equals", Value: "AR"},
+			request: request,
+			want:    true,
+		},
+		{
+			name:    "Should not match header with different value",
+			matcher: model.Matcher{Type: "header", Key: "X-Country", Operator: "equals", Value: "BR"},
+			request: request,
+			want:    false,
+		},
+		{
+			name:    "Should match query equals",
+			matcher: model.Matcher{Type: "query", Key: "type", Operator: "equals", Value: "refund"},
+			request: request,
+			want:    true,
+		},
+		{
+			name:    "Should match absent query",
+			matcher: model.Matcher{Type: "query", Key: "status", Operator: "absent"},
+			request: request,
+			want:    true,
+		},
+		{
+			name:    "Should match cookie regex",
+			matcher: model.Matcher{Type: "cookie", Key: "session", Operator: "regex", Value: "^abc[0-9]+$"},
+			request: request,
+			want:    true,
+		},
+		{
+			name:    "Should match present JSON path",
+			matcher: model.Matcher{Type: "jsonpath", Key: "$.payment.amount", Operator: "present"},
+			request: request,
+			want:    true,
+		},
+		{
+			name:    "Should match JSON path equals on numbers",
+			matcher: model.Matcher{Type: "jsonpath", Key: "$.payment.amount", Operator: "equals", Value: "100"},
+			request: request,
+			want:    true,
+		},
+		{
+			name:    "Should match JSON path equals on strings",
+			matcher: model.Matcher{Type: "jsonpath", Key: "$.payment.currency", Operator: "equals", Value: "ARS"},
+			request: request,
+			want:    true,
+		},
+		{
+			name:    "Should not match missing JSON path",
+			matcher: model.Matcher{Type: "jsonpath", Key: "$.payment.status", Operator: "present"},
+			request: request,
+			want:    false,
+		},
+		{
+			name:    "Should match XPath equals",
+			matcher: model.Matcher{Type: "xpath", Key: "//currency", Operator: "equals", Value: "USD"},
+			request: xmlRequest,
+			want:    true,
+		},
+		{
+			name:    "Should not match XPath on invalid XML",
+			matcher: model.Matcher{Type: "xpath", Key: "//currency", Operator: "present"},
+			request: request,
+			want:    false,
+		},
+		{
+			name:    "Should match absent header when request is nil",
+			matcher: model.Matcher{Type: "header", Key: "X-Country", Operator: "absent"},
+			request: nil,
+			want:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.matcher.Matches(tt.request))
+		})
+	}
+}
+
+func TestMatcher_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		matcher   model.Matcher
+		wantedErr error
+	}{
+		{
+			name:    "Should validate successfully",
+			matcher: model.Matcher{Type: "header", Key: "X-Country", Operator: "equals", Value: "AR"},
+		},
+		{
+			name:    "Should fail when type is invalid",
+			matcher: model.Matcher{Type: "path", Key: "id", Operator: "equals"},
+			wantedErr: mockserrors.InvalidRulesError{
+				Message: "matcher type must be 'header', 'query', 'cookie', 'jsonpath' or 'xpath'",
+			},
+		},
+		{
+			name:      "Should fail when key is empty",
+			matcher:   model.Matcher{Type: "query", Operator: "present"},
+			wantedErr: mockserrors.InvalidRulesError{Message: "matcher key cannot be empty"},
+		},
+		{
+			name:      "Should fail when regex is invalid",
+			matcher:   model.Matcher{Type: "query", Key: "type", Operator: "regex", Value: "[a-"},
+			wantedErr: mockserrors.InvalidRulesError{Message: "invalid regex pattern: [a-"},
+		},
+		{
+			name:      "Should fail when XPath is invalid",
+			matcher:   model.Matcher{Type: "xpath", Key: "//[", Operator: "present"},
+			wantedErr: mockserrors.InvalidRulesError{Message: "invalid XPath: //["},
+		},
+		{
+			name:    "Should fail when operator is invalid",
+			matcher: model.Matcher{Type: "query", Key: "type", Operator: "contains"},
+			wantedErr: mockserrors.InvalidRulesError{
+				Message: "matcher operator must be 'equals', 'regex', 'present' or 'absent'",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantedErr, tt.matcher.Validate())
+		})
+	}
+}
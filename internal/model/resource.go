@@ -0,0 +1,180 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+const (
+	// RuleMethodAny is the method stored for resource rules, which answer to every CRUD method.
+	RuleMethodAny = "ANY"
+
+	ResourceDefaultIDField = "id"
+	ResourceDefaultLimit   = 30
+	ResourceMaxLimit       = 1000
+)
+
+// ResourceConfig turns a rule into a REST collection. The rule path is the collection path, and its items
+// are served under path/{id}. Seed holds the entities the collection starts with, and Schema an optional
+// JSON schema that created and updated entities must satisfy.
+type ResourceConfig struct {
+	IDField      string                   `json:"id_field,omitempty" example:"id"`
+	DefaultLimit int32                    `json:"default_limit,omitempty" example:"30"`
+	Schema       map[string]interface{}   `json:"schema,omitempty"`
+	Seed         []map[string]interface{} `json:"seed,omitempty"`
+}
+
+// ResourceEntity is an item of a resource collection. Data holds the entity as sent by the client, with
+// the id field always set.
+type ResourceEntity struct {
+	ID        string                 `json:"id"`
+	Data      map[string]interface{} `json:"data"`
+	CreatedAt time.Time              `json:"created_at"`
+	UpdatedAt time.Time              `json:"updated_at"`
+}
+
+type ResourceEntityList struct {
+	Paging  Paging           `json:"paging"`
+	Results []ResourceEntity `json:"results"`
+}
+
+// RuleRoute is a method and path pattern that a rule answers to.
+type RuleRoute struct {
+	Method string
+	Path   string
+}
+
+// IsResource reports whether the rule serves a REST collection instead of its responses.
+func (rule *Rule) IsResource() bool {
+	return rule.Strategy == RuleStrategyResource
+}
+
+// ResourceConfig returns the resource configuration of the rule with its defaults applied.
+func (rule *Rule) ResourceConfig() ResourceConfig {
+	config := ResourceConfig{}
+	if rule.Resource != nil {
+		config = *rule.Resource
+	}
+
+	if config.IDField == "" {
+		config.IDField = ResourceDefaultIDField
+	}
+
+	if config.DefaultLimit <= 0 {
+		config.DefaultLimit = ResourceDefaultLimit
+	}
+
+	return config
+}
+
+// ItemPath returns the path pattern of the items of a resource rule.
+func (rule *Rule) ItemPath() string {
+	return strings.TrimSuffix(rule.Path, "/") + "/{" + rule.ResourceConfig().IDField + "}"
+}
+
+// Routes returns the method and path patterns the rule answers to. Resource rules list and create
+// entities on their path, and read, replace, patch and delete them on their item path.
+func (rule *Rule) Routes() []RuleRoute {
+	if !rule.IsResource() {
+		return []RuleRoute{{Method: rule.Method, Path: rule.Path}}
+	}
+
+	itemPath := rule.ItemPath()
+
+	return []RuleRoute{
+		{Method: "GET", Path: rule.Path},
+		{Method: "POST", Path: rule.Path},
+		{Method: "GET", Path: itemPath},
+		{Method: "PUT", Path: itemPath},
+		{Method: "PATCH", Path: itemPath},
+		{Method: "DELETE", Path: itemPath},
+	}
+}
+
+func (config ResourceConfig) Validate() error {
+	if config.DefaultLimit < 0 || config.DefaultLimit > ResourceMaxLimit {
+		return mockserrors.InvalidRulesError{
+			Message: fmt.Sprintf("resource default_limit must be between 0 and %d", ResourceMaxLimit),
+		}
+	}
+
+	if config.Schema != nil {
+		if _, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(config.Schema)); err != nil {
+			return mockserrors.InvalidRulesError{Message: "invalid resource schema: " + err.Error()}
+		}
+	}
+
+	idField := config.IDField
+	if idField == "" {
+		idField = ResourceDefaultIDField
+	}
+
+	ids := make(map[string]bool, len(config.Seed))
+
+	for index, entity := range config.Seed {
+		if value, found := entity[idField]; found {
+			id, ok := EntityID(value)
+			if !ok {
+				return mockserrors.InvalidRulesError{
+					Message: fmt.Sprintf("seed entity %d: %s must be a string or a number", index, idField),
+				}
+			}
+
+			if ids[id] {
+				return mockserrors.InvalidRulesError{
+					Message: fmt.Sprintf("seed entity %d: duplicated %s '%s'", index, idField, id),
+				}
+			}
+
+			ids[id] = true
+		}
+
+		if errs := config.SchemaErrors(entity); len(errs) > 0 {
+			return mockserrors.InvalidRulesError{
+				Message: fmt.Sprintf("seed entity %d does not match the schema: %s", index, strings.Join(errs, "; ")),
+			}
+		}
+	}
+
+	return nil
+}
+
+// SchemaErrors validates entity against the schema of the resource, if any.
+func (config ResourceConfig) SchemaErrors(entity map[string]interface{}) []string {
+	if config.Schema == nil {
+		return nil
+	}
+
+	result, err := gojsonschema.Validate(gojsonschema.NewGoLoader(config.Schema), gojsonschema.NewGoLoader(entity))
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	errs := make([]string, 0, len(result.Errors()))
+	for _, resultErr := range result.Errors() {
+		errs = append(errs, resultErr.String())
+	}
+
+	return errs
+}
+
+// EntityID returns the string form of an id field value, which must be a string or a number.
+func EntityID(value interface{}) (string, bool) {
+	switch id := value.(type) {
+	case string:
+		return id, id != ""
+	case float64:
+		return strconv.FormatFloat(id, 'f', -1, 64), true
+	case int:
+		return strconv.Itoa(id), true
+	case int64:
+		return strconv.FormatInt(id, 10), true
+	default:
+		return "", false
+	}
+}
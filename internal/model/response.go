@@ -1,22 +1,227 @@
 package model
 
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
+)
+
+const (
+	CookieSameSiteLax    = "lax"
+	CookieSameSiteStrict = "strict"
+	CookieSameSiteNone   = "none"
+
+	// ResponseTemplateGo renders the response body, headers, cookies and webhook with text/template.
+	ResponseTemplateGo = "go"
+
+	// ResponseFaultEmptyReply closes the connection without writing anything.
+	ResponseFaultEmptyReply = "empty_reply"
+	// ResponseFaultConnectionReset aborts the connection with a TCP RST.
+	ResponseFaultConnectionReset = "connection_reset"
+	// ResponseFaultRandomData writes random bytes instead of an HTTP response and closes the connection.
+	ResponseFaultRandomData = "random_data"
+	// ResponseFaultTruncatedBody sends half of the body with the Content-Length of the whole body.
+	ResponseFaultTruncatedBody = "truncated_body"
+	// ResponseFaultMalformedChunk sends the body as a chunk with an invalid size.
+	ResponseFaultMalformedChunk = "malformed_chunk"
+	// ResponseFaultStallAfterHeaders sends the status and headers, and then never sends the body.
+	ResponseFaultStallAfterHeaders = "stall_after_headers"
+)
+
+var responseFaults = []string{
+	ResponseFaultEmptyReply, ResponseFaultConnectionReset, ResponseFaultRandomData, ResponseFaultTruncatedBody,
+	ResponseFaultMalformedChunk, ResponseFaultStallAfterHeaders,
+}
+
 // WebhookConfig represents the configuration for sending a webhook when a response is returned.
 type WebhookConfig struct {
-	URL     string            `json:"url" example:"https://hooks.example.com/callback"`
-	Method  string            `json:"method" example:"POST"`
-	Headers map[string]string `json:"headers" example:"{\"Authorization\":\"Bearer token\"}"`
-	Body    string            `json:"body" example:"{\"event\":\"payment_created\",\"id\":\"{payment_id}\"}"`
-	Enabled bool              `json:"enabled" example:"true"`
-	Delay   int               `json:"delay" example:"3000"`
-	Timeout *int              `json:"timeout,omitempty" example:"5000"`
+	URL       string            `json:"url" example:"https://hooks.example.com/callback"`
+	Method    string            `json:"method" example:"POST"`
+	Headers   map[string]string `json:"headers" example:"{\"Authorization\":\"Bearer token\"}"`
+	Body      string            `json:"body" example:"{\"event\":\"payment_created\",\"id\":\"{payment_id}\"}"`
+	Enabled   bool              `json:"enabled" example:"true"`
+	Delay     int               `json:"delay" example:"3000"`
+	Timeout   *int              `json:"timeout,omitempty" example:"5000"`
+	Retry     *WebhookRetry     `json:"retry,omitempty"`
+	Signature *WebhookSignature `json:"signature,omitempty"`
+	Variables []*Variable       `json:"variables,omitempty"`
+}
+
+// Cookie represents a cookie sent back in the mock response through a Set-Cookie header.
+type Cookie struct {
+	Name     string `json:"name" example:"session_id"`
+	Value    string `json:"value" example:"{session_id}"`
+	Path     string `json:"path,omitempty" example:"/"`
+	Domain   string `json:"domain,omitempty" example:"example.com"`
+	MaxAge   int    `json:"max_age,omitempty" example:"3600"`
+	Secure   bool   `json:"secure,omitempty" example:"true"`
+	HTTPOnly bool   `json:"http_only,omitempty" example:"true"`
+	SameSite string `json:"same_site,omitempty" example:"lax"`
 }
 
 type Response struct {
-	Body        string         `json:"body" example:"{\"id\":5804214224, \"payer_id\": 548390723, \"external_reference\": \"X281924481\"}"` //nolint:lll
-	ContentType string         `json:"content_type" example:"application/json"`
-	HTTPStatus  int            `json:"http_status" example:"200"`
-	Delay       int            `json:"delay" example:"0"`
-	Scene       string         `json:"scene" example:"normal"`
-	Description string         `json:"description" example:"success response"`
-	Webhook     *WebhookConfig `json:"webhook,omitempty"`
+	Body        string            `json:"body" example:"{\"id\":5804214224, \"payer_id\": 548390723, \"external_reference\": \"X281924481\"}"` //nolint:lll
+	ContentType string            `json:"content_type" example:"application/json"`
+	HTTPStatus  int               `json:"http_status" example:"200"`
+	Delay       int               `json:"delay" example:"0"`
+	Scene       string            `json:"scene" example:"normal"`
+	Description string            `json:"description" example:"success response"`
+	Template    string            `json:"template,omitempty" example:"go"`
+	Headers     map[string]string `json:"headers,omitempty" example:"{\"Location\":\"/v1/payments/{payment_id}\"}"`
+	Cookies     []Cookie          `json:"cookies,omitempty"`
+	Webhook     *WebhookConfig    `json:"webhook,omitempty"`
+	Webhooks    []WebhookConfig   `json:"webhooks,omitempty"`
+	WebhookMode string            `json:"webhook_mode,omitempty" example:"sequential"`
+	Fault       string            `json:"fault,omitempty" example:"connection_reset"`
+}
+
+// UnmarshalJSON reads a response whose body is a string or, so that rules are easy to read and review,
+// any other JSON value, like an object written as native YAML in a rules file.
+func (response *Response) UnmarshalJSON(data []byte) error {
+	type plainResponse Response
+
+	aux := struct {
+		*plainResponse
+		Body json.RawMessage `json:"body"`
+	}{plainResponse: (*plainResponse)(response)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	body, err := unmarshalBody(aux.Body)
+	if err != nil {
+		return err
+	}
+
+	response.Body = body
+
+	return nil
+}
+
+// UnmarshalJSON reads a webhook whose body is a string or any other JSON value, like Response.
+func (webhook *WebhookConfig) UnmarshalJSON(data []byte) error {
+	type plainWebhook WebhookConfig
+
+	aux := struct {
+		*plainWebhook
+		Body json.RawMessage `json:"body"`
+	}{plainWebhook: (*plainWebhook)(webhook)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	body, err := unmarshalBody(aux.Body)
+	if err != nil {
+		return err
+	}
+
+	webhook.Body = body
+
+	return nil
+}
+
+// unmarshalBody returns a body written as a string as is, and any other JSON value as compact JSON.
+func unmarshalBody(raw json.RawMessage) (string, error) {
+	raw = bytes.TrimSpace(raw)
+
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return "", nil
+	}
+
+	if raw[0] == '"' {
+		var body string
+		if err := json.Unmarshal(raw, &body); err != nil {
+			return "", fmt.Errorf("error unmarshalling body, %w", err)
+		}
+
+		return body, nil
+	}
+
+	var body bytes.Buffer
+	if err := json.Compact(&body, raw); err != nil {
+		return "", fmt.Errorf("error compacting body, %w", err)
+	}
+
+	return body.String(), nil
+}
+
+// ValidateFault checks that the fault, if any, is one of the supported response faults.
+func (response Response) ValidateFault() error {
+	return validateFault(response.Fault)
+}
+
+func (cookie Cookie) Validate() error {
+	if strings.TrimSpace(cookie.Name) == "" {
+		return mockserrors.InvalidRulesError{Message: "cookie name cannot be empty"}
+	}
+
+	if !isValidHeaderName(cookie.Name) {
+		return mockserrors.InvalidRulesError{Message: fmt.Sprintf("'%s' is not a valid cookie name", cookie.Name)}
+	}
+
+	switch strings.ToLower(cookie.SameSite) {
+	case "", CookieSameSiteLax, CookieSameSiteStrict, CookieSameSiteNone:
+		return nil
+	}
+
+	return mockserrors.InvalidRulesError{
+		Message: fmt.Sprintf("cookie same_site must be '%s', '%s' or '%s'",
+			CookieSameSiteLax, CookieSameSiteStrict, CookieSameSiteNone),
+	}
+}
+
+// HTTPCookie converts the cookie into its net/http representation.
+func (cookie Cookie) HTTPCookie() *http.Cookie {
+	httpCookie := &http.Cookie{
+		Name:     cookie.Name,
+		Value:    cookie.Value,
+		Path:     cookie.Path,
+		Domain:   cookie.Domain,
+		MaxAge:   cookie.MaxAge,
+		Secure:   cookie.Secure,
+		HttpOnly: cookie.HTTPOnly,
+	}
+
+	switch strings.ToLower(cookie.SameSite) {
+	case CookieSameSiteLax:
+		httpCookie.SameSite = http.SameSiteLaxMode
+	case CookieSameSiteStrict:
+		httpCookie.SameSite = http.SameSiteStrictMode
+	case CookieSameSiteNone:
+		httpCookie.SameSite = http.SameSiteNoneMode
+	}
+
+	return httpCookie
+}
+
+// ValidateHeaders checks that every custom response header has a valid name.
+func (response Response) ValidateHeaders() error {
+	for name := range response.Headers {
+		if !isValidHeaderName(name) {
+			return mockserrors.InvalidRulesError{Message: fmt.Sprintf("'%s' is not a valid header name", name)}
+		}
+	}
+
+	return nil
+}
+
+// isValidHeaderName reports whether name is a valid RFC 7230 token.
+func isValidHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for _, r := range name {
+		if r <= ' ' || r >= 0x7f || strings.ContainsRune("\"(),/:;<=>?@[\\]{}", r) {
+			return false
+		}
+	}
+
+	return true
 }
@@ -0,0 +1,74 @@
+package model_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nicopozo/mockserver/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponse_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    string
+		wantErr bool
+	}{
+		{name: "Should keep a string body", body: `"{\"id\": 5804214224}"`, want: `{"id": 5804214224}`},
+		{name: "Should compact an object body", body: `{"id": 5804214224, "payer": {"id": 1}}`, want: `{"id":5804214224,"payer":{"id":1}}`},
+		{name: "Should compact an array body", body: `[1, "two"]`, want: `[1,"two"]`},
+		{name: "Should keep a number body", body: `42`, want: `42`},
+		{name: "Should leave a null body empty", body: `null`, want: ``},
+		{name: "Should fail with an invalid body", body: `{"id":`, wantErr: true},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := model.UnmarshalRule(strings.NewReader(
+				`{"name":"rule","responses":[{"http_status":200,"body":` + tt.body + `,"webhooks":[{"body":` + tt.body + `}]}]}`))
+			if tt.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			if assert.NoError(t, err) {
+				assert.Equal(t, "rule", rule.Name)
+				assert.Equal(t, 200, rule.Responses[0].HTTPStatus, "the other fields are read as usual")
+				assert.Equal(t, tt.want, rule.Responses[0].Body)
+				assert.Equal(t, tt.want, rule.Responses[0].Webhooks[0].Body)
+			}
+		})
+	}
+}
+
+func TestUnmarshalRuleYAML(t *testing.T) {
+	rule, err := model.UnmarshalRuleYAML(strings.NewReader(`
+name: get payment
+path: /v1/payments/{id}
+method: GET
+responses:
+  - http_status: 200
+    content_type: application/json
+    body:
+      id: 5804214224
+      status: approved
+      payer_id: 548390723
+  - http_status: 404
+    scene: not found
+    body: |
+      {"message": "not found"}
+`))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, "/v1/payments/{id}", rule.Path)
+
+	if assert.Len(t, rule.Responses, 2) {
+		assert.Equal(t, `{"id":5804214224,"status":"approved","payer_id":548390723}`, rule.Responses[0].Body,
+			"the keys keep their order")
+		assert.Equal(t, "{\"message\": \"not found\"}\n", rule.Responses[1].Body)
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	mockserrors "github.com/nicopozo/mockserver/internal/errors"
 	jsonutils "github.com/nicopozo/mockserver/internal/utils/json"
+	yamlutils "github.com/nicopozo/mockserver/internal/utils/yaml"
 )
 
 const (
@@ -16,19 +17,25 @@ const (
 	RuleStrategySequential = "sequential"
 	RuleStrategyRandom     = "random"
 	RuleStrategyScene      = "scene"
+	RuleStrategyResource   = "resource"
 )
 
 type Rule struct {
-	Key               string      `json:"key" example:"payments_get_556032950"`
-	Group             string      `json:"group" example:"payments"`
-	Name              string      `json:"name" example:"get payment"`
-	Path              string      `json:"path" example:"/v1/payments/{payment_id}"`
-	Strategy          string      `json:"strategy" example:"normal"`
-	Method            string      `json:"method" example:"GET"`
-	Status            string      `json:"status" example:"enabled"`
-	Responses         []Response  `json:"responses"`
-	Variables         []*Variable `json:"variables"`
-	NextResponseIndex int         `json:"-"`
+	Key               string          `json:"key" example:"payments_get_556032950"`
+	Group             string          `json:"group" example:"payments"`
+	Name              string          `json:"name" example:"get payment"`
+	Path              string          `json:"path" example:"/v1/payments/{payment_id}"`
+	Strategy          string          `json:"strategy" example:"normal"`
+	Method            string          `json:"method" example:"GET"`
+	Status            string          `json:"status" example:"enabled"`
+	Priority          int             `json:"priority" example:"0"`
+	Matchers          []Matcher       `json:"matchers,omitempty"`
+	Responses         []Response      `json:"responses"`
+	Variables         []*Variable     `json:"variables"`
+	Scenario          *RuleScenario   `json:"scenario,omitempty"`
+	Resource          *ResourceConfig `json:"resource,omitempty"`
+	Chaos             *ChaosPolicy    `json:"chaos,omitempty"`
+	NextResponseIndex int             `json:"-"`
 }
 
 type RuleList struct {
@@ -62,6 +69,30 @@ func UnmarshalRules(body io.Reader) ([]Rule, error) {
 	return *rules, nil
 }
 
+// UnmarshalRuleYAML reads a rule written in YAML.
+func UnmarshalRuleYAML(body io.Reader) (*Rule, error) {
+	rule := &Rule{}
+
+	err := yamlutils.Unmarshal(body, rule)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling body, %w", err)
+	}
+
+	return rule, nil
+}
+
+// UnmarshalRulesYAML reads an array of rules written in YAML.
+func UnmarshalRulesYAML(body io.Reader) ([]Rule, error) {
+	rules := new([]Rule)
+
+	err := yamlutils.Unmarshal(body, rules)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling body, %w", err)
+	}
+
+	return *rules, nil
+}
+
 func UnmarshalRuleStatus(body io.Reader) (*RuleStatus, error) {
 	status := &RuleStatus{}
 
@@ -73,6 +104,24 @@ func UnmarshalRuleStatus(body io.Reader) (*RuleStatus, error) {
 	return status, nil
 }
 
+// MatchesRequest reports whether every matcher of the rule passes for the given request.
+// Rules without matchers match any request. Rules in a scenario also require the scenario to be in their
+// required state.
+func (rule *Rule) MatchesRequest(request *MatchRequest) bool {
+	if rule.Scenario != nil && rule.Scenario.RequiredState != "" &&
+		request.scenarioState(rule.Scenario.Name) != rule.Scenario.RequiredState {
+		return false
+	}
+
+	for _, matcher := range rule.Matchers {
+		if !matcher.Matches(request) {
+			return false
+		}
+	}
+
+	return true
+}
+
 func (status *RuleStatus) Validate() error {
 	if status.Status != RuleStatusEnabled && status.Status != RuleStatusDisabled {
 		return mockserrors.InvalidRulesError{
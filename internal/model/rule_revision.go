@@ -0,0 +1,161 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	RuleRevisionCreate  = "create"
+	RuleRevisionUpdate  = "update"
+	RuleRevisionDelete  = "delete"
+	RuleRevisionRestore = "restore"
+
+	RuleChangeAdd     = "add"
+	RuleChangeRemove  = "remove"
+	RuleChangeReplace = "replace"
+)
+
+// RuleRevision is a copy of a rule taken every time it is saved or deleted through the API. Revisions are
+// numbered from 1 for each rule and are never changed; the revision of a delete keeps the deleted rule.
+// Author is the authenticated caller, or the x-tracking-id of the request when auth is disabled.
+type RuleRevision struct {
+	RuleKey   string    `json:"rule_key" example:"payments_get_556032950"`
+	Revision  int       `json:"revision" example:"3"`
+	Action    string    `json:"action" example:"update"`
+	Author    string    `json:"author,omitempty" example:"ci-pipeline"`
+	CreatedAt time.Time `json:"created_at"`
+	Rule      Rule      `json:"rule"`
+}
+
+// RuleDiff lists the changes that turn the rule of revision From into the rule of revision To.
+type RuleDiff struct {
+	RuleKey string       `json:"rule_key" example:"payments_get_556032950"`
+	From    int          `json:"from" example:"2"`
+	To      int          `json:"to" example:"3"`
+	Changes []RuleChange `json:"changes"`
+}
+
+// RuleChange is a change of a single value of the rule. Path is a JSON pointer to the value in the JSON
+// representation of the rule, such as /responses/0/http_status.
+type RuleChange struct {
+	Op       string `json:"op" example:"replace"`
+	Path     string `json:"path" example:"/responses/0/http_status"`
+	OldValue any    `json:"old_value,omitempty"`
+	Value    any    `json:"value,omitempty"`
+}
+
+// DiffRules compares the JSON representation of two rules. Objects are compared field by field and
+// arrays item by item, so a change in a response is reported at the path of that response.
+func DiffRules(from, to Rule) ([]RuleChange, error) {
+	fromValue, err := toJSONValue(from)
+	if err != nil {
+		return nil, err
+	}
+
+	toValue, err := toJSONValue(to)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]RuleChange, 0)
+
+	return diffValues(changes, "", fromValue, toValue), nil
+}
+
+func toJSONValue(rule Rule) (any, error) {
+	content, err := json.Marshal(rule)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling rule, %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("error unmarshalling rule, %w", err)
+	}
+
+	return value, nil
+}
+
+func diffValues(changes []RuleChange, path string, from, to any) []RuleChange {
+	switch fromTyped := from.(type) {
+	case map[string]any:
+		if toTyped, ok := to.(map[string]any); ok {
+			return diffObjects(changes, path, fromTyped, toTyped)
+		}
+	case []any:
+		if toTyped, ok := to.([]any); ok {
+			return diffArrays(changes, path, fromTyped, toTyped)
+		}
+	}
+
+	if reflect.DeepEqual(from, to) {
+		return changes
+	}
+
+	return append(changes, RuleChange{Op: RuleChangeReplace, Path: path, OldValue: from, Value: to})
+}
+
+func diffObjects(changes []RuleChange, path string, from, to map[string]any) []RuleChange {
+	keys := make([]string, 0, len(from)+len(to))
+
+	for key := range from {
+		keys = append(keys, key)
+	}
+
+	for key := range to {
+		if _, found := from[key]; !found {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fieldPath := path + "/" + escapePointer(key)
+		fromField, inFrom := from[key]
+		toField, inTo := to[key]
+
+		switch {
+		case !inTo:
+			changes = append(changes, RuleChange{Op: RuleChangeRemove, Path: fieldPath, OldValue: fromField})
+		case !inFrom:
+			changes = append(changes, RuleChange{Op: RuleChangeAdd, Path: fieldPath, Value: toField})
+		default:
+			changes = diffValues(changes, fieldPath, fromField, toField)
+		}
+	}
+
+	return changes
+}
+
+func diffArrays(changes []RuleChange, path string, from, to []any) []RuleChange {
+	for index := 0; index < len(from) || index < len(to); index++ {
+		itemPath := path + "/" + strconv.Itoa(index)
+
+		switch {
+		case index >= len(to):
+			changes = append(changes, RuleChange{Op: RuleChangeRemove, Path: itemPath, OldValue: from[index]})
+		case index >= len(from):
+			changes = append(changes, RuleChange{Op: RuleChangeAdd, Path: itemPath, Value: to[index]})
+		default:
+			changes = diffValues(changes, itemPath, from[index], to[index])
+		}
+	}
+
+	return changes
+}
+
+// escapePointer escapes a key to be used in a JSON pointer, as defined by RFC 6901.
+func escapePointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,50 @@
+package model_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/nicopozo/mockserver/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffRules(t *testing.T) {
+	from := model.Rule{
+		Key: "k1", Name: "get payment", Path: "/v1/payments/{id}", Method: http.MethodGet,
+		Responses: []model.Response{
+			{Body: `{"id":1}`, HTTPStatus: http.StatusOK, Headers: map[string]string{"X-Env/Name": "dev"}},
+			{Body: `{"message":"not found"}`, HTTPStatus: http.StatusNotFound, Scene: "missing"},
+		},
+	}
+
+	to := from
+	to.Name = "get payment v2"
+	to.Responses = []model.Response{
+		{Body: `{"id":1}`, HTTPStatus: http.StatusCreated, Headers: map[string]string{"X-Env/Name": "prod"}},
+	}
+	to.Matchers = []model.Matcher{{Type: "header", Key: "x-env", Operator: "equals", Value: "prod"}}
+
+	changes, err := model.DiffRules(from, to)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	content, err := json.Marshal(changes)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.JSONEq(t, `[
+		{"op":"add","path":"/matchers","value":[{"type":"header","key":"x-env","operator":"equals","value":"prod"}]},
+		{"op":"replace","path":"/name","old_value":"get payment","value":"get payment v2"},
+		{"op":"replace","path":"/responses/0/headers/X-Env~1Name","old_value":"dev","value":"prod"},
+		{"op":"replace","path":"/responses/0/http_status","old_value":200,"value":201},
+		{"op":"remove","path":"/responses/1","old_value":{"body":"{\"message\":\"not found\"}",
+			"content_type":"","http_status":404,"delay":0,"description":"","scene":"missing"}}
+	]`, string(content))
+
+	changes, err = model.DiffRules(from, from)
+	assert.NoError(t, err)
+	assert.Empty(t, changes, "a rule has no changes with itself")
+}
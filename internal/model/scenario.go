@@ -0,0 +1,70 @@
+package model
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
+	jsonutils "github.com/nicopozo/mockserver/internal/utils/json"
+)
+
+// ScenarioStateStarted is the state every scenario is in until a rule moves it, and after it is reset.
+const ScenarioStateStarted = "Started"
+
+// RuleScenario makes a rule part of a named scenario. The rule is only selected while the scenario is in
+// RequiredState (any state when empty), and moves the scenario to NewState (if set) after responding, or
+// as soon as it is selected when it also sets RequiredState.
+type RuleScenario struct {
+	Name          string `json:"name" example:"checkout"`
+	RequiredState string `json:"required_state,omitempty" example:"Started"`
+	NewState      string `json:"new_state,omitempty" example:"paid"`
+}
+
+// Scenario is the current state of a scenario. States and Rules list the states and the keys of the
+// rules that reference the scenario, and are only filled in by the scenario service.
+type Scenario struct {
+	Name      string    `json:"name" example:"checkout"`
+	State     string    `json:"state" example:"paid"`
+	UpdatedAt time.Time `json:"updated_at,omitzero"`
+	States    []string  `json:"states,omitempty"`
+	Rules     []string  `json:"rules,omitempty"`
+}
+
+type ScenarioState struct {
+	State string `json:"state" example:"paid"`
+}
+
+func UnmarshalScenarioState(body io.Reader) (*ScenarioState, error) {
+	state := &ScenarioState{}
+
+	err := jsonutils.Unmarshal(body, state)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling body, %w", err)
+	}
+
+	return state, nil
+}
+
+func (scenario RuleScenario) Validate() error {
+	if strings.TrimSpace(scenario.Name) == "" {
+		return mockserrors.InvalidRulesError{Message: "scenario name cannot be empty"}
+	}
+
+	if scenario.RequiredState == "" && scenario.NewState == "" {
+		return mockserrors.InvalidRulesError{
+			Message: fmt.Sprintf("scenario '%s' must set required_state, new_state or both", scenario.Name),
+		}
+	}
+
+	return nil
+}
+
+func (state ScenarioState) Validate() error {
+	if strings.TrimSpace(state.State) == "" {
+		return mockserrors.InvalidRulesError{Message: "state cannot be empty"}
+	}
+
+	return nil
+}
@@ -16,6 +16,9 @@ const (
 	VariableTypeQuery         = "query"
 	VariableTypePath          = "path"
 	VariableTypeComposite     = "composite"
+	// VariableTypeWebhook takes a value from the response of a webhook. It can only be declared on a webhook,
+	// and its value is available to the webhooks sent after it.
+	VariableTypeWebhook = "webhook"
 )
 
 type Variable struct {
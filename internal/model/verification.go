@@ -0,0 +1,117 @@
+package model
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
+	jsonutils "github.com/nicopozo/mockserver/internal/utils/json"
+)
+
+// VerificationRequest describes the requests expected to have been received by the mock server.
+// Every field is optional; entries must satisfy all the given ones to match. Bin verifies the requests
+// received by a request bin instead of the ones received by the mocks.
+type VerificationRequest struct {
+	Bin      string            `json:"bin,omitempty" example:"payment-callbacks"`
+	Method   string            `json:"method,omitempty" example:"POST"`
+	Path     string            `json:"path,omitempty" example:"/v1/payments/{payment_id}"`
+	RuleKey  string            `json:"rule_key,omitempty" example:"01HZX3K2Q8V4N6B7C9D0E1F2G3"`
+	Matchers []Matcher         `json:"matchers,omitempty"`
+	Count    VerificationCount `json:"count"`
+}
+
+// VerificationCount is the expected number of matching requests. When it is empty, at least one
+// matching request is expected.
+type VerificationCount struct {
+	Exactly *int `json:"exactly,omitempty" example:"1"`
+	AtLeast *int `json:"at_least,omitempty" example:"1"`
+	AtMost  *int `json:"at_most,omitempty" example:"3"`
+}
+
+// VerificationResult is the outcome of a verification.
+type VerificationResult struct {
+	Passed  bool                `json:"passed"`
+	Message string              `json:"message"`
+	Count   int                 `json:"count"`
+	Matches []LogEntry          `json:"matches"`
+	Closest []VerificationMatch `json:"closest"`
+}
+
+// VerificationMatch is a non-matching log entry together with the criteria it failed.
+type VerificationMatch struct {
+	Entry      LogEntry `json:"entry"`
+	Mismatches []string `json:"mismatches"`
+}
+
+func UnmarshalVerificationRequest(body io.Reader) (*VerificationRequest, error) {
+	request := &VerificationRequest{}
+
+	err := jsonutils.Unmarshal(body, request)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling body, %w", err)
+	}
+
+	return request, nil
+}
+
+func (request VerificationRequest) Validate() error {
+	if request.Path != "" && !strings.HasPrefix(request.Path, "/") {
+		return mockserrors.InvalidRulesError{Message: "path must start with '/'"}
+	}
+
+	for _, matcher := range request.Matchers {
+		if err := matcher.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return request.Count.Validate()
+}
+
+func (count VerificationCount) Validate() error {
+	names := []string{"exactly", "at_least", "at_most"}
+
+	for index, value := range []*int{count.Exactly, count.AtLeast, count.AtMost} {
+		if value != nil && *value < 0 {
+			return mockserrors.InvalidRulesError{Message: fmt.Sprintf("count %s cannot be negative", names[index])}
+		}
+	}
+
+	if count.Exactly != nil && (count.AtLeast != nil || count.AtMost != nil) {
+		return mockserrors.InvalidRulesError{Message: "count exactly cannot be combined with at_least or at_most"}
+	}
+
+	if count.AtLeast != nil && count.AtMost != nil && *count.AtLeast > *count.AtMost {
+		return mockserrors.InvalidRulesError{Message: "count at_least must be lower than or equal to at_most"}
+	}
+
+	return nil
+}
+
+// Satisfied reports whether actual matching requests meet the expected count.
+func (count VerificationCount) Satisfied(actual int) bool {
+	switch {
+	case count.Exactly != nil:
+		return actual == *count.Exactly
+	case count.AtLeast == nil && count.AtMost == nil:
+		return actual >= 1
+	}
+
+	return (count.AtLeast == nil || actual >= *count.AtLeast) && (count.AtMost == nil || actual <= *count.AtMost)
+}
+
+func (count VerificationCount) String() string {
+	switch {
+	case count.Exactly != nil:
+		return fmt.Sprintf("exactly %d", *count.Exactly)
+	case count.AtLeast != nil && count.AtMost != nil:
+		return fmt.Sprintf("between %d and %d", *count.AtLeast, *count.AtMost)
+	case count.AtMost != nil:
+		return fmt.Sprintf("at most %d", *count.AtMost)
+	case count.AtLeast != nil:
+		return fmt.Sprintf("at least %d", *count.AtLeast)
+	}
+
+	return "at least 1"
+}
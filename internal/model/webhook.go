@@ -0,0 +1,300 @@
+package model
+
+import (
+	"crypto/hmac"
+	"crypto/sha1" //nolint:gosec
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
+)
+
+const (
+	// WebhookMaxAttempts bounds the attempts of a webhook, so that a misconfigured rule cannot keep a
+	// delivery alive for hours.
+	WebhookMaxAttempts = 10
+
+	defaultWebhookBackoff    = 500
+	defaultWebhookMaxBackoff = 30000
+
+	// WebhookModeSequential sends the webhooks of a response one after the other; the delay of each one
+	// starts when the previous one is done.
+	WebhookModeSequential = "sequential"
+	// WebhookModeParallel sends the webhooks of a response at once; their delays start with the response.
+	WebhookModeParallel = "parallel"
+
+	WebhookSignatureHMACSHA1   = "hmac-sha1"
+	WebhookSignatureHMACSHA256 = "hmac-sha256"
+	WebhookSignatureHMACSHA512 = "hmac-sha512"
+
+	WebhookSignatureHex    = "hex"
+	WebhookSignatureBase64 = "base64"
+
+	// defaultWebhookSignatureFormat puts the bare signature in the header.
+	defaultWebhookSignatureFormat = "{sig}"
+	// defaultWebhookSignaturePayload signs the body alone.
+	defaultWebhookSignaturePayload = "{body}"
+)
+
+var webhookSignatureHashes = map[string]func() hash.Hash{
+	WebhookSignatureHMACSHA1:   sha1.New,
+	WebhookSignatureHMACSHA256: sha256.New,
+	WebhookSignatureHMACSHA512: sha512.New,
+}
+
+// defaultWebhookRetryOn holds the statuses that are retried when the retry does not list its own.
+var defaultWebhookRetryOn = []int{
+	http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusInternalServerError,
+	http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout,
+}
+
+// WebhookRetry configures how a webhook is sent again when a delivery fails. Deliveries that do not
+// get a response are always retried; the ones that do are retried when their status is in RetryOn.
+// Backoff and MaxBackoff are in milliseconds.
+type WebhookRetry struct {
+	MaxAttempts int   `json:"max_attempts" example:"5"`
+	Backoff     int   `json:"backoff,omitempty" example:"500"`
+	MaxBackoff  int   `json:"max_backoff,omitempty" example:"30000"`
+	RetryOn     []int `json:"retry_on,omitempty" example:"429,503"`
+}
+
+// WebhookSignature signs the webhook body with an HMAC, the way payment providers and code hosts sign
+// their callbacks. Payload is the signed text and Format the header value; both are templates where
+// {body} is the body after variable substitution, {timestamp} the Unix time of the attempt and {sig} the
+// encoded HMAC. For example, Stripe signs "{timestamp}.{body}" into "t={timestamp},v1={sig}" and GitHub
+// signs "{body}" into "sha256={sig}".
+type WebhookSignature struct {
+	Algorithm string `json:"algorithm" example:"hmac-sha256"`
+	Secret    string `json:"secret" example:"whsec_test"`
+	Header    string `json:"header" example:"Stripe-Signature"`
+	Format    string `json:"format,omitempty" example:"t={timestamp},v1={sig}"`
+	Payload   string `json:"payload,omitempty" example:"{timestamp}.{body}"`
+	Encoding  string `json:"encoding,omitempty" example:"hex"`
+}
+
+// EnabledWebhooks returns the enabled webhooks of the response in the order they are sent: the single
+// webhook first and then the list.
+func (response Response) EnabledWebhooks() []WebhookConfig {
+	webhooks := make([]WebhookConfig, 0, len(response.Webhooks)+1)
+
+	if response.Webhook != nil && response.Webhook.Enabled {
+		webhooks = append(webhooks, *response.Webhook)
+	}
+
+	for _, webhook := range response.Webhooks {
+		if webhook.Enabled {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+
+	return webhooks
+}
+
+// ValidateWebhooks checks the webhook mode and every webhook of the response.
+func (response Response) ValidateWebhooks() error {
+	if response.WebhookMode != "" && response.WebhookMode != WebhookModeSequential &&
+		response.WebhookMode != WebhookModeParallel {
+		return mockserrors.InvalidRulesError{
+			Message: fmt.Sprintf("webhook_mode must be '%s' or '%s'", WebhookModeSequential, WebhookModeParallel),
+		}
+	}
+
+	webhooks := response.Webhooks
+	if response.Webhook != nil {
+		webhooks = append([]WebhookConfig{*response.Webhook}, webhooks...)
+	}
+
+	for _, webhook := range webhooks {
+		if err := webhook.Validate(); err != nil {
+			return err
+		}
+
+		if len(webhook.Variables) > 0 && response.WebhookMode == WebhookModeParallel {
+			return mockserrors.InvalidRulesError{
+				Message: fmt.Sprintf("webhook variables need the '%s' webhook_mode", WebhookModeSequential),
+			}
+		}
+	}
+
+	return nil
+}
+
+// Validate checks the retry, signature and variables of the webhook, if any.
+func (webhook WebhookConfig) Validate() error {
+	if webhook.Retry != nil {
+		if err := webhook.Retry.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if webhook.Signature != nil {
+		if err := webhook.Signature.Validate(); err != nil {
+			return err
+		}
+	}
+
+	for _, variable := range webhook.Variables {
+		if variable.Type != VariableTypeWebhook {
+			return mockserrors.InvalidRulesError{
+				Message: fmt.Sprintf("webhook variables must be of type '%s'", VariableTypeWebhook),
+			}
+		}
+
+		if variable.Name == "" || variable.Key == "" {
+			return mockserrors.InvalidRulesError{Message: "webhook variables must set name and key"}
+		}
+	}
+
+	return nil
+}
+
+func (retry WebhookRetry) Validate() error {
+	if retry.MaxAttempts < 1 || retry.MaxAttempts > WebhookMaxAttempts {
+		return mockserrors.InvalidRulesError{
+			Message: fmt.Sprintf("webhook max_attempts must be between 1 and %d", WebhookMaxAttempts),
+		}
+	}
+
+	if retry.Backoff < 0 || retry.MaxBackoff < 0 {
+		return mockserrors.InvalidRulesError{Message: "webhook backoff cannot be negative"}
+	}
+
+	if retry.MaxBackoff > 0 && retry.MaxBackoff < retry.Backoff {
+		return mockserrors.InvalidRulesError{Message: "webhook max_backoff cannot be lower than backoff"}
+	}
+
+	for _, status := range retry.RetryOn {
+		if status < http.StatusContinue || status > 599 {
+			return mockserrors.InvalidRulesError{
+				Message: fmt.Sprintf("%d is not a valid HTTP Status to retry a webhook on", status),
+			}
+		}
+	}
+
+	return nil
+}
+
+// Attempts returns how many times the webhook is sent at most. A nil retry sends it once.
+func (retry *WebhookRetry) Attempts() int {
+	if retry == nil {
+		return 1
+	}
+
+	return retry.MaxAttempts
+}
+
+// Retryable reports whether a delivery that got the given result must be sent again.
+func (retry *WebhookRetry) Retryable(result WebhookResult) bool {
+	if retry == nil {
+		return false
+	}
+
+	if result.StatusCode == 0 {
+		return true
+	}
+
+	retryOn := retry.RetryOn
+	if len(retryOn) == 0 {
+		retryOn = defaultWebhookRetryOn
+	}
+
+	return slices.Contains(retryOn, result.StatusCode)
+}
+
+// Delay returns how long to wait before the next attempt, after the given attempt failed. It doubles
+// the backoff on every attempt up to MaxBackoff, and picks a random point in its upper half so that the
+// retries of many webhooks do not hit the receiver at the same time. jitter must be in [0, 1).
+func (retry *WebhookRetry) Delay(attempt int, jitter float64) time.Duration {
+	backoff, maxBackoff := defaultWebhookBackoff, defaultWebhookMaxBackoff
+
+	if retry != nil && retry.Backoff > 0 {
+		backoff = retry.Backoff
+	}
+
+	if retry != nil && retry.MaxBackoff > 0 {
+		maxBackoff = retry.MaxBackoff
+	}
+
+	delay := float64(backoff)
+	for i := 1; i < attempt && delay < float64(maxBackoff); i++ {
+		delay *= 2
+	}
+
+	delay = min(delay, float64(maxBackoff))
+
+	return time.Duration((delay/2 + jitter*delay/2) * float64(time.Millisecond))
+}
+
+func (signature WebhookSignature) Validate() error {
+	if _, found := webhookSignatureHashes[signature.Algorithm]; !found {
+		return mockserrors.InvalidRulesError{
+			Message: fmt.Sprintf("webhook signature algorithm must be '%s', '%s' or '%s'", WebhookSignatureHMACSHA1,
+				WebhookSignatureHMACSHA256, WebhookSignatureHMACSHA512),
+		}
+	}
+
+	if signature.Secret == "" {
+		return mockserrors.InvalidRulesError{Message: "webhook signature secret cannot be empty"}
+	}
+
+	if !isValidHeaderName(signature.Header) {
+		return mockserrors.InvalidRulesError{
+			Message: fmt.Sprintf("'%s' is not a valid webhook signature header name", signature.Header),
+		}
+	}
+
+	if signature.Format != "" && !strings.Contains(signature.Format, "{sig}") {
+		return mockserrors.InvalidRulesError{Message: "webhook signature format must contain {sig}"}
+	}
+
+	if signature.Encoding != "" && signature.Encoding != WebhookSignatureHex &&
+		signature.Encoding != WebhookSignatureBase64 {
+		return mockserrors.InvalidRulesError{
+			Message: fmt.Sprintf("webhook signature encoding must be '%s' or '%s'", WebhookSignatureHex,
+				WebhookSignatureBase64),
+		}
+	}
+
+	return nil
+}
+
+// Sign returns the value of the signature header for body, sent at the given time.
+func (signature WebhookSignature) Sign(body string, sentAt time.Time) string {
+	timestamp := strconv.FormatInt(sentAt.Unix(), 10)
+
+	payload := signature.Payload
+	if payload == "" {
+		payload = defaultWebhookSignaturePayload
+	}
+
+	payload = strings.NewReplacer("{timestamp}", timestamp, "{body}", body).Replace(payload)
+
+	newHash, found := webhookSignatureHashes[signature.Algorithm]
+	if !found {
+		newHash = sha256.New
+	}
+
+	mac := hmac.New(newHash, []byte(signature.Secret))
+	_, _ = mac.Write([]byte(payload))
+
+	sig := hex.EncodeToString(mac.Sum(nil))
+	if signature.Encoding == WebhookSignatureBase64 {
+		sig = base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	}
+
+	format := signature.Format
+	if format == "" {
+		format = defaultWebhookSignatureFormat
+	}
+
+	return strings.NewReplacer("{timestamp}", timestamp, "{sig}", sig).Replace(format)
+}
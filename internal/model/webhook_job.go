@@ -0,0 +1,64 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
+)
+
+const (
+	// WebhookJobPending is a job waiting for its next attempt.
+	WebhookJobPending = "pending"
+	// WebhookJobFailed is a job whose webhook failed on its last attempt. It is kept until it is cancelled.
+	WebhookJobFailed = "failed"
+)
+
+// WebhookJob is a webhook delivery waiting in the queue. The webhooks of a sequential response travel in
+// a single job, which moves to the next webhook (Step) once the current one is done; a parallel response
+// queues one job per webhook. Index is the position of the first webhook of the job in the response.
+// A job is deleted once every webhook has been delivered.
+type WebhookJob struct {
+	ID        string               `json:"id" example:"01HZX3K8Q4T2B9W6JZ5N7R1C0M"`
+	LogID     string               `json:"log_id,omitempty"`
+	Webhooks  []WebhookConfig      `json:"webhooks"`
+	Index     int                  `json:"index"`
+	Step      int                  `json:"step"`
+	Attempt   int                  `json:"attempt"`
+	Variables []WebhookJobVariable `json:"variables,omitempty"`
+	Status    string               `json:"status" example:"pending"`
+	DueAt     time.Time            `json:"due_at"`
+	// LockedUntil is set while a worker is sending the webhook, so that no other worker picks the job. If
+	// the worker dies, the job is picked again once the lock expires.
+	LockedUntil time.Time `json:"locked_until,omitzero"`
+	LastError   string    `json:"last_error,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// WebhookJobVariable is the value of a variable used by the webhooks of a job.
+type WebhookJobVariable struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Webhook returns the webhook the job sends next.
+func (job WebhookJob) Webhook() WebhookConfig {
+	return job.Webhooks[job.Step]
+}
+
+// Due reports whether the job can be picked by a worker at now.
+func (job WebhookJob) Due(now time.Time) bool {
+	return job.Status == WebhookJobPending && !job.DueAt.After(now) && !job.LockedUntil.After(now)
+}
+
+// ValidateWebhookJobStatus checks a status used to filter the webhook jobs. An empty status matches every job.
+func ValidateWebhookJobStatus(status string) error {
+	if status != "" && status != WebhookJobPending && status != WebhookJobFailed {
+		return mockserrors.InvalidRulesError{
+			Message: fmt.Sprintf("status must be '%s' or '%s'", WebhookJobPending, WebhookJobFailed),
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,215 @@
+package model_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/nicopozo/mockserver/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookRetry_Delay(t *testing.T) {
+	retry := &model.WebhookRetry{MaxAttempts: 6, Backoff: 100, MaxBackoff: 1000}
+
+	tests := []struct {
+		name    string
+		retry   *model.WebhookRetry
+		attempt int
+		jitter  float64
+		want    time.Duration
+	}{
+		{name: "Should wait half the backoff without jitter", retry: retry, attempt: 1, want: 50 * time.Millisecond},
+		{
+			name: "Should wait the whole backoff with full jitter", retry: retry, attempt: 1, jitter: 1,
+			want: 100 * time.Millisecond,
+		},
+		{name: "Should double the backoff", retry: retry, attempt: 3, jitter: 1, want: 400 * time.Millisecond},
+		{name: "Should cap the backoff", retry: retry, attempt: 6, jitter: 1, want: time.Second},
+		{name: "Should use the defaults", attempt: 2, jitter: 0.5, want: 750 * time.Millisecond},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.retry.Delay(tt.attempt, tt.jitter))
+		})
+	}
+}
+
+func TestWebhookRetry_Retryable(t *testing.T) {
+	var none *model.WebhookRetry
+
+	assert.Equal(t, 1, none.Attempts())
+	assert.False(t, none.Retryable(model.WebhookResult{Error: "connection refused"}))
+
+	retry := &model.WebhookRetry{MaxAttempts: 3}
+	assert.True(t, retry.Retryable(model.WebhookResult{Error: "connection refused"}))
+	assert.True(t, retry.Retryable(model.WebhookResult{StatusCode: http.StatusTooManyRequests}))
+	assert.False(t, retry.Retryable(model.WebhookResult{StatusCode: http.StatusNotFound}))
+
+	retry.RetryOn = []int{http.StatusNotFound}
+	assert.True(t, retry.Retryable(model.WebhookResult{StatusCode: http.StatusNotFound}))
+	assert.False(t, retry.Retryable(model.WebhookResult{StatusCode: http.StatusServiceUnavailable}))
+
+	assert.EqualError(t, model.WebhookRetry{MaxAttempts: 3, Backoff: 500, MaxBackoff: 100}.Validate(),
+		"webhook max_backoff cannot be lower than backoff")
+	assert.EqualError(t, model.WebhookRetry{MaxAttempts: 3, RetryOn: []int{700}}.Validate(),
+		"700 is not a valid HTTP Status to retry a webhook on")
+}
+
+func TestLogEntry_AddRedelivery(t *testing.T) {
+	entry := model.LogEntry{WebhookResults: []model.WebhookResult{
+		{URL: "http://a", Method: http.MethodPost, Attempt: 1, StatusCode: http.StatusBadGateway, Superseded: true},
+		{URL: "http://a", Method: http.MethodPost, Attempt: 2, StatusCode: http.StatusBadGateway},
+	}}
+	assert.True(t, entry.HasWebhookFailures())
+
+	entry.AddRedelivery(0, model.WebhookResult{URL: "http://a", Method: http.MethodPost, StatusCode: http.StatusOK})
+	assert.True(t, entry.HasWebhookFailures(), "the last attempt has not been redelivered")
+
+	entry.AddRedelivery(1, model.WebhookResult{URL: "http://a", Method: http.MethodPost, StatusCode: http.StatusOK})
+	assert.False(t, entry.HasWebhookFailures())
+
+	assert.Len(t, entry.WebhookResults, 4)
+	assert.Equal(t, 3, entry.WebhookResults[2].Attempt)
+	assert.Equal(t, 4, entry.WebhookResults[3].Attempt)
+	assert.True(t, entry.WebhookResults[3].Redelivery)
+}
+
+func TestWebhookSignature_Sign(t *testing.T) {
+	sentAt := time.Unix(1614556800, 0)
+
+	tests := []struct {
+		name      string
+		signature model.WebhookSignature
+		body      string
+		want      string
+	}{
+		{
+			// https://docs.github.com/en/webhooks/using-webhooks/validating-webhook-deliveries
+			name: "Should match the GitHub scheme",
+			signature: model.WebhookSignature{
+				Algorithm: model.WebhookSignatureHMACSHA256, Secret: "It's a Secret to Everybody",
+				Header: "X-Hub-Signature-256", Format: "sha256={sig}",
+			},
+			body: "Hello, World!",
+			want: "sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17",
+		},
+		{
+			name: "Should match the Stripe scheme",
+			signature: model.WebhookSignature{
+				Algorithm: model.WebhookSignatureHMACSHA256, Secret: "whsec_test_secret", Header: "Stripe-Signature",
+				Format: "t={timestamp},v1={sig}", Payload: "{timestamp}.{body}",
+			},
+			body: `{"id":"evt_1","type":"payment_intent.succeeded"}`,
+			want: "t=1614556800,v1=1411ab71e1a1905be0101b7223f81a87e940f160fef5d9cc1d7117eda7929458",
+		},
+		{
+			// RFC 2202, test case 2.
+			name: "Should sign with SHA-1",
+			signature: model.WebhookSignature{
+				Algorithm: model.WebhookSignatureHMACSHA1, Secret: "Jefe", Header: "X-Hub-Signature",
+				Format: "sha1={sig}",
+			},
+			body: "what do ya want for nothing?",
+			want: "sha1=effcdf6ae5eb2fa2d27416d5f184df9c259a7c79",
+		},
+		{
+			// RFC 4231, test case 2.
+			name: "Should sign with SHA-512",
+			signature: model.WebhookSignature{
+				Algorithm: model.WebhookSignatureHMACSHA512, Secret: "Jefe", Header: "X-Signature",
+			},
+			body: "what do ya want for nothing?",
+			want: "164b7a7bfcf819e2e395fbe73b56e0a387bd64222e831fd610270cd7ea2505549758bf75c05a994a6d034f65f8f0e6" +
+				"fdcaeab1a34d4a6b4b636e070a38bce737",
+		},
+		{
+			name: "Should encode the signature in base64",
+			signature: model.WebhookSignature{
+				Algorithm: model.WebhookSignatureHMACSHA256, Secret: "Jefe", Header: "X-Signature",
+				Encoding: model.WebhookSignatureBase64,
+			},
+			body: "what do ya want for nothing?",
+			want: "W9zBRr9gdU5qBCQmCJV1x1oAPwidJzmDnexYuWTsOEM=",
+		},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NoError(t, tt.signature.Validate())
+			assert.Equal(t, tt.want, tt.signature.Sign(tt.body, sentAt))
+		})
+	}
+}
+
+func TestWebhookSignature_Validate(t *testing.T) {
+	valid := model.WebhookSignature{Algorithm: model.WebhookSignatureHMACSHA256, Secret: "s", Header: "X-Signature"}
+
+	tests := []struct {
+		name    string
+		change  func(signature *model.WebhookSignature)
+		wantErr string
+	}{
+		{
+			name:    "Should fail with an unknown algorithm",
+			change:  func(signature *model.WebhookSignature) { signature.Algorithm = "md5" },
+			wantErr: "webhook signature algorithm must be 'hmac-sha1', 'hmac-sha256' or 'hmac-sha512'",
+		},
+		{
+			name:    "Should fail without a secret",
+			change:  func(signature *model.WebhookSignature) { signature.Secret = "" },
+			wantErr: "webhook signature secret cannot be empty",
+		},
+		{
+			name:    "Should fail with an invalid header",
+			change:  func(signature *model.WebhookSignature) { signature.Header = "X Signature" },
+			wantErr: "'X Signature' is not a valid webhook signature header name",
+		},
+		{
+			name:    "Should fail with a format without the signature",
+			change:  func(signature *model.WebhookSignature) { signature.Format = "t={timestamp}" },
+			wantErr: "webhook signature format must contain {sig}",
+		},
+		{
+			name:    "Should fail with an unknown encoding",
+			change:  func(signature *model.WebhookSignature) { signature.Encoding = "base32" },
+			wantErr: "webhook signature encoding must be 'hex' or 'base64'",
+		},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			signature := valid
+			tt.change(&signature)
+
+			assert.EqualError(t, model.WebhookConfig{Signature: &signature}.Validate(), tt.wantErr)
+		})
+	}
+}
+
+func TestResponse_Webhooks(t *testing.T) {
+	first := model.WebhookConfig{URL: "http://hooks/created", Method: http.MethodPost, Enabled: true}
+	chained := model.WebhookConfig{
+		URL: "http://hooks/approved", Method: http.MethodPost, Enabled: true,
+		Variables: []*model.Variable{{Type: model.VariableTypeWebhook, Name: "approval_id", Key: "$.id"}},
+	}
+
+	response := model.Response{
+		Webhook:  &first,
+		Webhooks: []model.WebhookConfig{{URL: "http://hooks/disabled"}, chained},
+	}
+
+	assert.Equal(t, []model.WebhookConfig{first, chained}, response.EnabledWebhooks())
+	assert.NoError(t, response.ValidateWebhooks())
+
+	response.WebhookMode = "random"
+	assert.EqualError(t, response.ValidateWebhooks(), "webhook_mode must be 'sequential' or 'parallel'")
+
+	response.WebhookMode = model.WebhookModeParallel
+	assert.EqualError(t, response.ValidateWebhooks(), "webhook variables need the 'sequential' webhook_mode")
+
+	response.WebhookMode = model.WebhookModeSequential
+	chained.Variables[0].Type = model.VariableTypeBody
+	assert.EqualError(t, response.ValidateWebhooks(), "webhook variables must be of type 'webhook'")
+}
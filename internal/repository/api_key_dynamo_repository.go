@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/nicopozo/mockserver/internal/configs"
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
+	"github.com/nicopozo/mockserver/internal/model"
+	"github.com/oklog/ulid/v2"
+)
+
+// DynamoAPIKeyRepository stores API keys with the secret hash as partition key, so authenticating a
+// request is a single GetItem.
+type DynamoAPIKeyRepository struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+type apiKeyItem struct {
+	SecretHash string    `dynamodbav:"secret_hash"`
+	ID         string    `dynamodbav:"id"`
+	Name       string    `dynamodbav:"name"`
+	Role       string    `dynamodbav:"role"`
+	CreatedAt  time.Time `dynamodbav:"created_at"`
+}
+
+// NewDynamoAPIKeyRepository creates a new APIKeyRepository for DynamoDB.
+func NewDynamoAPIKeyRepository(client *dynamodb.Client, cfg *configs.Config) APIKeyRepository {
+	return &DynamoAPIKeyRepository{
+		client:    client,
+		tableName: cfg.Dynamo.TablePrefix + "api_keys",
+	}
+}
+
+func (r *DynamoAPIKeyRepository) Create(ctx context.Context, key *model.APIKey) (*model.APIKey, error) {
+	key.ID = ulid.Make().String()
+
+	item, err := attributevalue.MarshalMap(apiKeyItem{
+		SecretHash: key.SecretHash,
+		ID:         key.ID,
+		Name:       key.Name,
+		Role:       key.Role,
+		CreatedAt:  key.CreatedAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling api key: %w", err)
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating api key in DynamoDB: %w", err)
+	}
+
+	return key, nil
+}
+
+func (r *DynamoAPIKeyRepository) GetBySecretHash(ctx context.Context, secretHash string) (*model.APIKey, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"secret_hash": &types.AttributeValueMemberS{Value: secretHash},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting api key from DynamoDB: %w", err)
+	}
+
+	if result.Item == nil {
+		return nil, mockserrors.APIKeyNotFoundError{Message: "no api key found"}
+	}
+
+	var item apiKeyItem
+
+	if err := attributevalue.UnmarshalMap(result.Item, &item); err != nil {
+		return nil, fmt.Errorf("error unmarshaling api key: %w", err)
+	}
+
+	key := item.toModel()
+	key.SecretHash = item.SecretHash
+
+	return &key, nil
+}
+
+func (r *DynamoAPIKeyRepository) List(ctx context.Context) ([]model.APIKey, error) {
+	items, err := r.scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]model.APIKey, 0, len(items))
+	for _, item := range items {
+		keys = append(keys, item.toModel())
+	}
+
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+
+	return keys, nil
+}
+
+func (r *DynamoAPIKeyRepository) Delete(ctx context.Context, id string) error {
+	items, err := r.scan(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if item.ID != id {
+			continue
+		}
+
+		_, err := r.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+			TableName: aws.String(r.tableName),
+			Key: map[string]types.AttributeValue{
+				"secret_hash": &types.AttributeValueMemberS{Value: item.SecretHash},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("error deleting api key from DynamoDB: %w", err)
+		}
+
+		return nil
+	}
+
+	return apiKeyNotFound(id)
+}
+
+// scan reads the whole table, which only holds a handful of keys.
+func (r *DynamoAPIKeyRepository) scan(ctx context.Context) ([]apiKeyItem, error) {
+	var items []apiKeyItem
+
+	paginator := dynamodb.NewScanPaginator(r.client, &dynamodb.ScanInput{TableName: aws.String(r.tableName)})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning api keys in DynamoDB: %w", err)
+		}
+
+		var pageItems []apiKeyItem
+
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &pageItems); err != nil {
+			return nil, fmt.Errorf("error unmarshaling api keys: %w", err)
+		}
+
+		items = append(items, pageItems...)
+	}
+
+	return items, nil
+}
+
+func (item apiKeyItem) toModel() model.APIKey {
+	return model.APIKey{
+		ID:        item.ID,
+		Name:      item.Name,
+		Role:      item.Role,
+		CreatedAt: item.CreatedAt,
+	}
+}
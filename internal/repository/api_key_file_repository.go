@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/nicopozo/mockserver/internal/configs"
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
+	"github.com/nicopozo/mockserver/internal/model"
+	jsonutils "github.com/nicopozo/mockserver/internal/utils/json"
+	"github.com/oklog/ulid/v2"
+)
+
+type apiKeyFileRepository struct {
+	mu       sync.RWMutex
+	keys     []fileAPIKey
+	filePath string
+}
+
+type fileAPIKey struct {
+	model.APIKey
+	SecretHash string `json:"secret_hash"`
+}
+
+// NewAPIKeyFileRepository creates an APIKeyRepository that keeps the keys in cfg.Auth.KeysFile.
+func NewAPIKeyFileRepository(cfg *configs.Config) (APIKeyRepository, error) {
+	repo := &apiKeyFileRepository{
+		keys:     make([]fileAPIKey, 0),
+		filePath: cfg.Auth.KeysFile,
+	}
+
+	file, err := os.Open(repo.filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return repo, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error reading api keys file: %s - %w", repo.filePath, err)
+	}
+
+	defer func(f *os.File) { _ = f.Close() }(file)
+
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("error reading api keys file: %s - %w", repo.filePath, err)
+	}
+
+	if stat.Size() > 0 {
+		if err := jsonutils.Unmarshal(file, &repo.keys); err != nil {
+			return nil, fmt.Errorf("error unmarshalling api keys file: %s - %w", repo.filePath, err)
+		}
+	}
+
+	return repo, nil
+}
+
+func (repository *apiKeyFileRepository) Create(_ context.Context, key *model.APIKey) (*model.APIKey, error) {
+	repository.mu.Lock()
+	defer repository.mu.Unlock()
+
+	key.ID = ulid.Make().String()
+
+	stored := *key
+	stored.Secret = ""
+	stored.SecretHash = ""
+
+	repository.keys = append(repository.keys, fileAPIKey{APIKey: stored, SecretHash: key.SecretHash})
+
+	return key, repository.saveFile()
+}
+
+func (repository *apiKeyFileRepository) GetBySecretHash(_ context.Context, secretHash string) (*model.APIKey,
+	error,
+) {
+	repository.mu.RLock()
+	defer repository.mu.RUnlock()
+
+	for _, stored := range repository.keys {
+		if stored.SecretHash == secretHash {
+			key := stored.APIKey
+			key.SecretHash = stored.SecretHash
+
+			return &key, nil
+		}
+	}
+
+	return nil, mockserrors.APIKeyNotFoundError{Message: "no api key found"}
+}
+
+func (repository *apiKeyFileRepository) List(_ context.Context) ([]model.APIKey, error) {
+	repository.mu.RLock()
+	defer repository.mu.RUnlock()
+
+	keys := make([]model.APIKey, 0, len(repository.keys))
+	for _, stored := range repository.keys {
+		keys = append(keys, stored.APIKey)
+	}
+
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+
+	return keys, nil
+}
+
+func (repository *apiKeyFileRepository) Delete(_ context.Context, id string) error {
+	repository.mu.Lock()
+	defer repository.mu.Unlock()
+
+	for index := range repository.keys {
+		if repository.keys[index].ID == id {
+			repository.keys = append(repository.keys[:index], repository.keys[index+1:]...)
+
+			return repository.saveFile()
+		}
+	}
+
+	return apiKeyNotFound(id)
+}
+
+func (repository *apiKeyFileRepository) saveFile() error {
+	// The file holds credentials, so it is only readable by the owner.
+	err := os.WriteFile(repository.filePath, []byte(jsonutils.Marshal(repository.keys)), 0o600) //nolint:mnd
+	if err != nil {
+		return fmt.Errorf("error saving api keys file: %s - %w", repository.filePath, err)
+	}
+
+	return nil
+}
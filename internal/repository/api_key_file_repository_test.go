@@ -0,0 +1,53 @@
+package repository_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nicopozo/mockserver/internal/configs"
+	mockscontext "github.com/nicopozo/mockserver/internal/context"
+	"github.com/nicopozo/mockserver/internal/model"
+	"github.com/nicopozo/mockserver/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKeyFileRepository_Persistence(t *testing.T) {
+	cfg := &configs.Config{Auth: configs.AuthConfig{KeysFile: filepath.Join(t.TempDir(), "api-keys.json")}}
+
+	repo, err := repository.NewAPIKeyFileRepository(cfg)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	created, err := repo.Create(mockscontext.Background(), &model.APIKey{
+		Name: "ci", Role: model.RoleEditor, Secret: "mks_plain", SecretHash: "hash",
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	stat, err := os.Stat(cfg.Auth.KeysFile)
+	if assert.NoError(t, err) {
+		assert.Equal(t, os.FileMode(0o600), stat.Mode().Perm())
+	}
+
+	content, _ := os.ReadFile(cfg.Auth.KeysFile)
+	assert.NotContains(t, string(content), "mks_plain")
+
+	// A new repository reads the keys saved by the previous one.
+	repo, err = repository.NewAPIKeyFileRepository(cfg)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	key, err := repo.GetBySecretHash(mockscontext.Background(), "hash")
+	if assert.NoError(t, err) {
+		assert.Equal(t, created.ID, key.ID)
+		assert.Equal(t, "ci", key.Name)
+	}
+
+	keys, err := repo.List(mockscontext.Background())
+	assert.NoError(t, err)
+	assert.Len(t, keys, 1)
+}
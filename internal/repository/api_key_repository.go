@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
+
+	"github.com/nicopozo/mockserver/internal/model"
+)
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *model.APIKey) (*model.APIKey, error)
+	GetBySecretHash(ctx context.Context, secretHash string) (*model.APIKey, error)
+	List(ctx context.Context) ([]model.APIKey, error)
+	Delete(ctx context.Context, id string) error
+}
+
+func apiKeyNotFound(id string) error {
+	return mockserrors.APIKeyNotFoundError{Message: "no api key found with id: " + id}
+}
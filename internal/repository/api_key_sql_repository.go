@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
+	"github.com/nicopozo/mockserver/internal/model"
+	"github.com/oklog/ulid/v2"
+)
+
+type apiKeySQLRepository struct {
+	db Database
+}
+
+type APIKeyRow struct {
+	ID         string    `db:"id"`
+	Name       string    `db:"name"`
+	Role       string    `db:"role"`
+	SecretHash string    `db:"secret_hash"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+func (row APIKeyRow) toModel() model.APIKey {
+	return model.APIKey{
+		ID:         row.ID,
+		Name:       row.Name,
+		Role:       row.Role,
+		SecretHash: row.SecretHash,
+		CreatedAt:  row.CreatedAt,
+	}
+}
+
+func NewAPIKeySQLRepository(db Database) APIKeyRepository {
+	return &apiKeySQLRepository{
+		db: db,
+	}
+}
+
+func (r *apiKeySQLRepository) Create(_ context.Context, key *model.APIKey) (*model.APIKey, error) {
+	key.ID = ulid.Make().String()
+
+	query := FormatQuery("INSERT INTO api_keys (id, name, role, secret_hash, created_at) VALUES (?, ?, ?, ?, ?)",
+		r.db.DriverName())
+
+	_, err := r.db.Exec(query, key.ID, key.Name, key.Role, key.SecretHash, key.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("error inserting api key into DB: %w", err)
+	}
+
+	return key, nil
+}
+
+func (r *apiKeySQLRepository) GetBySecretHash(_ context.Context, secretHash string) (*model.APIKey, error) {
+	var row APIKeyRow
+
+	query := FormatQuery("SELECT * FROM api_keys WHERE secret_hash = ?", r.db.DriverName())
+
+	err := r.db.Get(&row, query, secretHash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, mockserrors.APIKeyNotFoundError{Message: "no api key found"}
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error fetching api key from DB: %w", err)
+	}
+
+	key := row.toModel()
+
+	return &key, nil
+}
+
+func (r *apiKeySQLRepository) List(_ context.Context) ([]model.APIKey, error) {
+	var rows []APIKeyRow
+
+	query := FormatQuery("SELECT * FROM api_keys ORDER BY id", r.db.DriverName())
+
+	if err := r.db.Select(&rows, query); err != nil {
+		return nil, fmt.Errorf("error fetching api keys from DB: %w", err)
+	}
+
+	keys := make([]model.APIKey, 0, len(rows))
+
+	for _, row := range rows {
+		key := row.toModel()
+		key.SecretHash = ""
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func (r *apiKeySQLRepository) Delete(_ context.Context, id string) error {
+	query := FormatQuery("DELETE FROM api_keys WHERE id = ?", r.db.DriverName())
+
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return fmt.Errorf("error deleting api key from DB: %w", err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return apiKeyNotFound(id)
+	}
+
+	return nil
+}
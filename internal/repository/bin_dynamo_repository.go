@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/nicopozo/mockserver/internal/configs"
+	"github.com/nicopozo/mockserver/internal/model"
+	jsonutils "github.com/nicopozo/mockserver/internal/utils/json"
+)
+
+// DynamoBinRepository keeps each bin response as a JSON document in an item of the settings table,
+// named after the bin with the "bin:" prefix.
+type DynamoBinRepository struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+// NewDynamoBinRepository creates a new BinRepository for DynamoDB.
+func NewDynamoBinRepository(client *dynamodb.Client, cfg *configs.Config) BinRepository {
+	return &DynamoBinRepository{
+		client:    client,
+		tableName: cfg.Dynamo.TablePrefix + "settings",
+	}
+}
+
+func (r *DynamoBinRepository) Get(ctx context.Context, name string) (*model.Bin, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(r.tableName),
+		Key:            binKey(name),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting bin from DynamoDB: %w", err)
+	}
+
+	if result.Item == nil {
+		return nil, binNotFound(name)
+	}
+
+	var item settingItem
+
+	if err := attributevalue.UnmarshalMap(result.Item, &item); err != nil {
+		return nil, fmt.Errorf("error unmarshaling bin: %w", err)
+	}
+
+	bin, err := item.toBin()
+	if err != nil {
+		return nil, err
+	}
+
+	return &bin, nil
+}
+
+func (r *DynamoBinRepository) List(ctx context.Context) ([]model.Bin, error) {
+	paginator := dynamodb.NewScanPaginator(r.client, &dynamodb.ScanInput{
+		TableName:                aws.String(r.tableName),
+		FilterExpression:         aws.String("begins_with(#name, :prefix)"),
+		ExpressionAttributeNames: map[string]string{"#name": "name"},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":prefix": &types.AttributeValueMemberS{Value: binSettingsPrefix},
+		},
+	})
+
+	var bins []model.Bin
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning bins in DynamoDB: %w", err)
+		}
+
+		var items []settingItem
+
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &items); err != nil {
+			return nil, fmt.Errorf("error unmarshaling bins: %w", err)
+		}
+
+		for _, item := range items {
+			bin, err := item.toBin()
+			if err != nil {
+				return nil, err
+			}
+
+			bins = append(bins, bin)
+		}
+	}
+
+	sort.Slice(bins, func(i, j int) bool { return bins[i].Name < bins[j].Name })
+
+	return bins, nil
+}
+
+func (r *DynamoBinRepository) Save(ctx context.Context, bin model.Bin) error {
+	item, err := attributevalue.MarshalMap(settingItem{
+		Name:      binSettingsPrefix + bin.Name,
+		Value:     jsonutils.Marshal(bin),
+		UpdatedAt: bin.UpdatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("error marshaling bin: %w", err)
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("error saving bin in DynamoDB: %w", err)
+	}
+
+	return nil
+}
+
+func (r *DynamoBinRepository) Delete(ctx context.Context, name string) error {
+	_, err := r.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName:           aws.String(r.tableName),
+		Key:                 binKey(name),
+		ConditionExpression: aws.String("attribute_exists(#name)"),
+		ExpressionAttributeNames: map[string]string{
+			"#name": "name",
+		},
+	})
+
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return binNotFound(name)
+	}
+
+	if err != nil {
+		return fmt.Errorf("error deleting bin from DynamoDB: %w", err)
+	}
+
+	return nil
+}
+
+func binKey(name string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"name": &types.AttributeValueMemberS{Value: binSettingsPrefix + name},
+	}
+}
+
+func (item settingItem) toBin() (model.Bin, error) {
+	var bin model.Bin
+
+	if err := jsonutils.Unmarshal(strings.NewReader(item.Value), &bin); err != nil {
+		return model.Bin{}, fmt.Errorf("error unmarshaling bin %s: %w", item.Name, err)
+	}
+
+	return bin, nil
+}
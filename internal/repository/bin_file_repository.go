@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/nicopozo/mockserver/internal/configs"
+	"github.com/nicopozo/mockserver/internal/model"
+	jsonutils "github.com/nicopozo/mockserver/internal/utils/json"
+)
+
+type binFileRepository struct {
+	mu       sync.RWMutex
+	bins     map[string]model.Bin
+	filePath string
+}
+
+// NewBinFileRepository creates a BinRepository that keeps the bin responses in cfg.BinsFile.
+func NewBinFileRepository(cfg *configs.Config) (BinRepository, error) {
+	repo := &binFileRepository{
+		bins:     make(map[string]model.Bin),
+		filePath: cfg.BinsFile,
+	}
+
+	file, err := os.Open(repo.filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return repo, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error reading bins file: %s - %w", repo.filePath, err)
+	}
+
+	defer func(f *os.File) { _ = f.Close() }(file)
+
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("error reading bins file: %s - %w", repo.filePath, err)
+	}
+
+	if stat.Size() > 0 {
+		if err := jsonutils.Unmarshal(file, &repo.bins); err != nil {
+			return nil, fmt.Errorf("error unmarshalling bins file: %s - %w", repo.filePath, err)
+		}
+	}
+
+	return repo, nil
+}
+
+func (repository *binFileRepository) Get(_ context.Context, name string) (*model.Bin, error) {
+	repository.mu.RLock()
+	defer repository.mu.RUnlock()
+
+	bin, found := repository.bins[name]
+	if !found {
+		return nil, binNotFound(name)
+	}
+
+	return &bin, nil
+}
+
+func (repository *binFileRepository) List(_ context.Context) ([]model.Bin, error) {
+	repository.mu.RLock()
+	defer repository.mu.RUnlock()
+
+	bins := make([]model.Bin, 0, len(repository.bins))
+
+	for _, bin := range repository.bins {
+		bins = append(bins, bin)
+	}
+
+	sort.Slice(bins, func(i, j int) bool { return bins[i].Name < bins[j].Name })
+
+	return bins, nil
+}
+
+func (repository *binFileRepository) Save(_ context.Context, bin model.Bin) error {
+	repository.mu.Lock()
+	defer repository.mu.Unlock()
+
+	repository.bins[bin.Name] = bin
+
+	return repository.saveFile()
+}
+
+func (repository *binFileRepository) Delete(_ context.Context, name string) error {
+	repository.mu.Lock()
+	defer repository.mu.Unlock()
+
+	if _, found := repository.bins[name]; !found {
+		return binNotFound(name)
+	}
+
+	delete(repository.bins, name)
+
+	return repository.saveFile()
+}
+
+func (repository *binFileRepository) saveFile() error {
+	err := os.WriteFile(repository.filePath, []byte(jsonutils.Marshal(repository.bins)), 0o644) //nolint:gosec,mnd
+	if err != nil {
+		return fmt.Errorf("error saving bins file: %s - %w", repository.filePath, err)
+	}
+
+	return nil
+}
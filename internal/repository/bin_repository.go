@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
+	"github.com/nicopozo/mockserver/internal/model"
+)
+
+// binSettingsPrefix prefixes the name of the bins stored in the settings table.
+const binSettingsPrefix = "bin:"
+
+// BinRepository stores the responses configured for the request bins. The captured requests are kept
+// by the LogRepository.
+type BinRepository interface {
+	Get(ctx context.Context, name string) (*model.Bin, error)
+	List(ctx context.Context) ([]model.Bin, error)
+	Save(ctx context.Context, bin model.Bin) error
+	Delete(ctx context.Context, name string) error
+}
+
+func binNotFound(name string) error {
+	return mockserrors.BinNotFoundError{Name: name}
+}
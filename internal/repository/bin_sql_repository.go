@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nicopozo/mockserver/internal/model"
+	jsonutils "github.com/nicopozo/mockserver/internal/utils/json"
+)
+
+// binSQLRepository keeps each bin response as a JSON document in a row of the settings table, named
+// after the bin with the "bin:" prefix.
+type binSQLRepository struct {
+	db Database
+}
+
+func NewBinSQLRepository(db Database) BinRepository {
+	return &binSQLRepository{
+		db: db,
+	}
+}
+
+func (r *binSQLRepository) Get(_ context.Context, name string) (*model.Bin, error) {
+	var value string
+
+	query := FormatQuery("SELECT value FROM settings WHERE name = ?", r.db.DriverName())
+
+	err := r.db.Get(&value, query, binSettingsPrefix+name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, binNotFound(name)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error fetching bin from DB: %w", err)
+	}
+
+	bin := &model.Bin{}
+
+	if err := jsonutils.Unmarshal(strings.NewReader(value), bin); err != nil {
+		return nil, fmt.Errorf("error unmarshalling bin %s: %w", name, err)
+	}
+
+	return bin, nil
+}
+
+func (r *binSQLRepository) List(_ context.Context) ([]model.Bin, error) {
+	var values []string
+
+	query := FormatQuery("SELECT value FROM settings WHERE name LIKE ? ORDER BY name", r.db.DriverName())
+
+	if err := r.db.Select(&values, query, binSettingsPrefix+"%"); err != nil {
+		return nil, fmt.Errorf("error fetching bins from DB: %w", err)
+	}
+
+	bins := make([]model.Bin, 0, len(values))
+
+	for _, value := range values {
+		var bin model.Bin
+
+		if err := jsonutils.Unmarshal(strings.NewReader(value), &bin); err != nil {
+			return nil, fmt.Errorf("error unmarshalling bin: %w", err)
+		}
+
+		bins = append(bins, bin)
+	}
+
+	return bins, nil
+}
+
+// Save inserts the bin or replaces the stored one, using the upsert syntax of the driver.
+func (r *binSQLRepository) Save(_ context.Context, bin model.Bin) error {
+	query := "INSERT INTO settings (name, value, updated_at) VALUES (?, ?, ?) " +
+		"ON DUPLICATE KEY UPDATE value = VALUES(value), updated_at = VALUES(updated_at)"
+
+	if r.db.DriverName() == datasourcePostgres {
+		query = "INSERT INTO settings (name, value, updated_at) VALUES (?, ?, ?) " +
+			"ON CONFLICT (name) DO UPDATE SET value = EXCLUDED.value, updated_at = EXCLUDED.updated_at"
+	}
+
+	_, err := r.db.Exec(FormatQuery(query, r.db.DriverName()), binSettingsPrefix+bin.Name, jsonutils.Marshal(bin),
+		bin.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("error saving bin into DB: %w", err)
+	}
+
+	return nil
+}
+
+func (r *binSQLRepository) Delete(_ context.Context, name string) error {
+	query := FormatQuery("DELETE FROM settings WHERE name = ?", r.db.DriverName())
+
+	result, err := r.db.Exec(query, binSettingsPrefix+name)
+	if err != nil {
+		return fmt.Errorf("error deleting bin from DB: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error deleting bin from DB: %w", err)
+	}
+
+	if affected == 0 {
+		return binNotFound(name)
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/nicopozo/mockserver/internal/configs"
+	"github.com/nicopozo/mockserver/internal/model"
+	jsonutils "github.com/nicopozo/mockserver/internal/utils/json"
+)
+
+// DynamoChaosRepository keeps the chaos settings as a JSON document in an item of the settings table.
+type DynamoChaosRepository struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+type settingItem struct {
+	Name      string    `dynamodbav:"name"`
+	Value     string    `dynamodbav:"value"`
+	UpdatedAt time.Time `dynamodbav:"updated_at"`
+}
+
+// NewDynamoChaosRepository creates a new ChaosRepository for DynamoDB.
+func NewDynamoChaosRepository(client *dynamodb.Client, cfg *configs.Config) ChaosRepository {
+	return &DynamoChaosRepository{
+		client:    client,
+		tableName: cfg.Dynamo.TablePrefix + "settings",
+	}
+}
+
+func (r *DynamoChaosRepository) Get(ctx context.Context) (*model.ChaosSettings, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"name": &types.AttributeValueMemberS{Value: chaosSettingsName},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting chaos settings from DynamoDB: %w", err)
+	}
+
+	settings := model.NewChaosSettings()
+
+	if result.Item == nil {
+		return settings, nil
+	}
+
+	var item settingItem
+
+	if err := attributevalue.UnmarshalMap(result.Item, &item); err != nil {
+		return nil, fmt.Errorf("error unmarshaling chaos settings: %w", err)
+	}
+
+	if err := jsonutils.Unmarshal(strings.NewReader(item.Value), settings); err != nil {
+		return nil, fmt.Errorf("error unmarshaling chaos settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+func (r *DynamoChaosRepository) Save(ctx context.Context, settings model.ChaosSettings) error {
+	item, err := attributevalue.MarshalMap(settingItem{
+		Name:      chaosSettingsName,
+		Value:     jsonutils.Marshal(settings),
+		UpdatedAt: settings.UpdatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("error marshaling chaos settings: %w", err)
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("error saving chaos settings in DynamoDB: %w", err)
+	}
+
+	return nil
+}
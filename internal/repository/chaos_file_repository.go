@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/nicopozo/mockserver/internal/configs"
+	"github.com/nicopozo/mockserver/internal/model"
+	jsonutils "github.com/nicopozo/mockserver/internal/utils/json"
+)
+
+type chaosFileRepository struct {
+	mu       sync.RWMutex
+	settings *model.ChaosSettings
+	filePath string
+}
+
+// NewChaosFileRepository creates a ChaosRepository that keeps the chaos settings in cfg.ChaosFile.
+func NewChaosFileRepository(cfg *configs.Config) (ChaosRepository, error) {
+	repo := &chaosFileRepository{
+		settings: model.NewChaosSettings(),
+		filePath: cfg.ChaosFile,
+	}
+
+	file, err := os.Open(repo.filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return repo, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error reading chaos file: %s - %w", repo.filePath, err)
+	}
+
+	defer func(f *os.File) { _ = f.Close() }(file)
+
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("error reading chaos file: %s - %w", repo.filePath, err)
+	}
+
+	if stat.Size() > 0 {
+		if err := jsonutils.Unmarshal(file, repo.settings); err != nil {
+			return nil, fmt.Errorf("error unmarshalling chaos file: %s - %w", repo.filePath, err)
+		}
+	}
+
+	return repo, nil
+}
+
+func (repository *chaosFileRepository) Get(_ context.Context) (*model.ChaosSettings, error) {
+	repository.mu.RLock()
+	defer repository.mu.RUnlock()
+
+	settings := *repository.settings
+
+	return &settings, nil
+}
+
+func (repository *chaosFileRepository) Save(_ context.Context, settings model.ChaosSettings) error {
+	repository.mu.Lock()
+	defer repository.mu.Unlock()
+
+	err := os.WriteFile(repository.filePath, []byte(jsonutils.Marshal(settings)), 0o644) //nolint:gosec,mnd
+	if err != nil {
+		return fmt.Errorf("error saving chaos file: %s - %w", repository.filePath, err)
+	}
+
+	repository.settings = &settings
+
+	return nil
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/nicopozo/mockserver/internal/model"
+)
+
+// chaosSettingsName is the name the chaos settings are stored under in the settings table.
+const chaosSettingsName = "chaos"
+
+// ChaosRepository stores the chaos settings. Get returns the default settings while none have been saved.
+type ChaosRepository interface {
+	Get(ctx context.Context) (*model.ChaosSettings, error)
+	Save(ctx context.Context, settings model.ChaosSettings) error
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nicopozo/mockserver/internal/model"
+	jsonutils "github.com/nicopozo/mockserver/internal/utils/json"
+)
+
+// chaosSQLRepository keeps the chaos settings as a JSON document in a row of the settings table.
+type chaosSQLRepository struct {
+	db Database
+}
+
+func NewChaosSQLRepository(db Database) ChaosRepository {
+	return &chaosSQLRepository{
+		db: db,
+	}
+}
+
+func (r *chaosSQLRepository) Get(_ context.Context) (*model.ChaosSettings, error) {
+	var value string
+
+	query := FormatQuery("SELECT value FROM settings WHERE name = ?", r.db.DriverName())
+
+	err := r.db.Get(&value, query, chaosSettingsName)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.NewChaosSettings(), nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error fetching chaos settings from DB: %w", err)
+	}
+
+	settings := model.NewChaosSettings()
+
+	if err := jsonutils.Unmarshal(strings.NewReader(value), settings); err != nil {
+		return nil, fmt.Errorf("error unmarshalling chaos settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+// Save inserts the chaos settings or replaces the stored ones, using the upsert syntax of the driver.
+func (r *chaosSQLRepository) Save(_ context.Context, settings model.ChaosSettings) error {
+	query := "INSERT INTO settings (name, value, updated_at) VALUES (?, ?, ?) " +
+		"ON DUPLICATE KEY UPDATE value = VALUES(value), updated_at = VALUES(updated_at)"
+
+	if r.db.DriverName() == datasourcePostgres {
+		query = "INSERT INTO settings (name, value, updated_at) VALUES (?, ?, ?) " +
+			"ON CONFLICT (name) DO UPDATE SET value = EXCLUDED.value, updated_at = EXCLUDED.updated_at"
+	}
+
+	_, err := r.db.Exec(FormatQuery(query, r.db.DriverName()), chaosSettingsName, jsonutils.Marshal(settings),
+		settings.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("error saving chaos settings into DB: %w", err)
+	}
+
+	return nil
+}
@@ -3,16 +3,20 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	_ "embed"
 	"fmt"
 	"net/url"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
 	"github.com/nicopozo/mockserver/internal/configs"
+	"modernc.org/sqlite"
 )
 
 const (
@@ -23,8 +27,27 @@ const (
 
 	datasourceMySQL    = "mysql"
 	datasourcePostgres = "postgres"
+	datasourceSQLite   = "sqlite"
+
+	sqliteBusyTimeout = 5000
+)
+
+// sqliteSchema creates the tables of the SQLite datasource, which has no server to run the init scripts.
+//
+//go:embed sqlite_schema.sql
+var sqliteSchema string
+
+var (
+	backtickRegex      = regexp.MustCompile("`([^`]+)`")
+	upsertValuesRegex  = regexp.MustCompile(`VALUES\((\w+)\)`)
+	sqliteRegexpsCache sync.Map // key: pattern (string), value: *regexp.Regexp
 )
 
+func init() {
+	// SQLite has the REGEXP operator but no function behind it, which the log search needs.
+	sqlite.MustRegisterDeterministicScalarFunction("regexp", 2, sqliteRegexp) //nolint:mnd
+}
+
 //go:generate mockgen -destination=../utils/test/mocks/sql_result_mock.go -package=mocks database/sql Result
 
 type Database interface {
@@ -44,6 +67,10 @@ func connect(cfg *configs.Config) (*sqlx.DB, error) {
 	var err error
 
 	datasource := strings.ToLower(cfg.DataSource)
+	if datasource == datasourceSQLite {
+		return connectSQLite(cfg)
+	}
+
 	connStr := getMySQLDBString(cfg) + "?parseTime=true&charset=utf8"
 
 	if datasource == datasourcePostgres {
@@ -80,6 +107,47 @@ func connect(cfg *configs.Config) (*sqlx.DB, error) {
 	return databaseConn, nil
 }
 
+// connectSQLite opens the SQLite database file and creates its tables. WAL lets the log reads go on while
+// a request is logged, and transactions take the write lock when they start, so that concurrent writers
+// wait for each other up to the busy timeout instead of failing.
+func connectSQLite(cfg *configs.Config) (*sqlx.DB, error) {
+	connStr := fmt.Sprintf("file:%s?_pragma=busy_timeout(%d)&_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)"+
+		"&_time_format=sqlite&_txlock=immediate", cfg.Database.File, sqliteBusyTimeout)
+
+	databaseConn, err := sqlx.Open(datasourceSQLite, connStr)
+	if err != nil {
+		return nil, fmt.Errorf("error opening SQLite database %s: %w", cfg.Database.File, err)
+	}
+
+	if _, err := databaseConn.Exec(sqliteSchema); err != nil {
+		_ = databaseConn.Close()
+
+		return nil, fmt.Errorf("error creating SQLite schema in %s: %w", cfg.Database.File, err)
+	}
+
+	return databaseConn, nil
+}
+
+// sqliteRegexp implements "value REGEXP pattern" for SQLite.
+func sqliteRegexp(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+	pattern, _ := args[0].(string)
+	value, _ := args[1].(string)
+
+	compiled, found := sqliteRegexpsCache.Load(pattern)
+	if !found {
+		expression, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression %q: %w", pattern, err)
+		}
+
+		compiled, _ = sqliteRegexpsCache.LoadOrStore(pattern, expression)
+	}
+
+	expression, _ := compiled.(*regexp.Regexp)
+
+	return expression.MatchString(value), nil
+}
+
 func getMySQLDBString(cfg *configs.Config) string {
 	if cfg.Database.URL != "" && strings.HasPrefix(cfg.Database.URL, "mysql://") {
 		u, err := url.Parse(cfg.Database.URL)
@@ -113,17 +181,24 @@ func getPostgresDBString(cfg *configs.Config) string {
 // FormatQuery translates a MySQL-style query to the target driver dialect.
 // For postgres: replaces backtick-escaped identifiers with double quotes and
 // rebinds positional parameters from ? to $1, $2, etc.
+// For sqlite: rewrites ON DUPLICATE KEY UPDATE upserts to ON CONFLICT DO UPDATE;
+// backticks and ? placeholders are understood as they are.
 func FormatQuery(query string, driver string) string {
-	if driver != datasourcePostgres {
+	switch driver {
+	case datasourcePostgres:
+		// Replace backtick-wrapped identifiers with double-quoted ones
+		query = backtickRegex.ReplaceAllString(query, `"$1"`)
+
+		// Rebind ? placeholders to $1, $2, ...
+		return sqlx.Rebind(sqlx.DOLLAR, query)
+	case datasourceSQLite:
+		insert, update, found := strings.Cut(query, "ON DUPLICATE KEY UPDATE")
+		if !found {
+			return query
+		}
+
+		return insert + "ON CONFLICT DO UPDATE SET" + upsertValuesRegex.ReplaceAllString(update, "excluded.$1")
+	default:
 		return query
 	}
-
-	// Replace backtick-wrapped identifiers with double-quoted ones
-	re := regexp.MustCompile("`([^`]+)`")
-	query = re.ReplaceAllString(query, `"$1"`)
-
-	// Rebind ? placeholders to $1, $2, ...
-	query = sqlx.Rebind(sqlx.DOLLAR, query)
-
-	return query
 }
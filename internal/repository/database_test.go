@@ -0,0 +1,270 @@
+package repository_test
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nicopozo/mockserver/internal/configs"
+	mockscontext "github.com/nicopozo/mockserver/internal/context"
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
+	"github.com/nicopozo/mockserver/internal/model"
+	"github.com/nicopozo/mockserver/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatQuery(t *testing.T) {
+	upsert := "INSERT INTO settings (name, value) VALUES (?, ?) ON DUPLICATE KEY UPDATE value = VALUES(value)"
+
+	tests := []struct {
+		name   string
+		driver string
+		query  string
+		want   string
+	}{
+		{
+			name: "mysql", driver: "mysql", query: upsert, want: upsert,
+		},
+		{
+			name: "postgres", driver: "postgres", query: "SELECT * FROM rules WHERE `key` = ? AND status = ?",
+			want: `SELECT * FROM rules WHERE "key" = $1 AND status = $2`,
+		},
+		{
+			name: "sqlite", driver: "sqlite", query: "SELECT * FROM rules WHERE `key` = ?",
+			want: "SELECT * FROM rules WHERE `key` = ?",
+		},
+		{
+			name: "sqlite upsert", driver: "sqlite", query: upsert,
+			want: "INSERT INTO settings (name, value) VALUES (?, ?) ON CONFLICT DO UPDATE SET value = excluded.value",
+		},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, repository.FormatQuery(tt.query, tt.driver))
+		})
+	}
+}
+
+func newSQLiteDB(t testing.TB, file string) *sqlx.DB {
+	t.Helper()
+
+	db, err := repository.NewSQLDB(&configs.Config{DataSource: "sqlite", Database: configs.DatabaseConfig{File: file}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { _ = db.Close() })
+
+	return db
+}
+
+func TestSQLite(t *testing.T) {
+	ctx := mockscontext.Background()
+	file := filepath.Join(t.TempDir(), "mocks.db")
+	db := newSQLiteDB(t, file)
+
+	rule := &model.Rule{
+		Group: "payments", Name: "get payment", Path: "/v1/payments/{id}", Strategy: model.RuleStrategyNormal,
+		Method: http.MethodGet, Status: model.RuleStatusEnabled,
+		Responses: []model.Response{{Body: `{"id":"{id}"}`, ContentType: "application/json", HTTPStatus: http.StatusOK}},
+		Variables: []*model.Variable{{Type: "path", Name: "id", Key: "id"}},
+	}
+
+	_, err := repository.NewRuleSQLRepository(db).Create(ctx, rule)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// The single response of the rule is served on every turn.
+	for range 2 {
+		turn, err := repository.NewRuleSQLRepository(db).AdvanceResponse(ctx, rule.Key, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, turn)
+	}
+
+	turns := make([]int, 0)
+
+	for range 3 {
+		turn, err := repository.NewRuleSQLRepository(db).AdvanceResponse(ctx, rule.Key, 2)
+		assert.NoError(t, err)
+
+		turns = append(turns, turn)
+	}
+
+	assert.Equal(t, []int{1, 0, 1}, turns)
+
+	_, err = repository.NewRuleSQLRepository(db).AdvanceResponse(ctx, "missing", 1)
+	assert.ErrorAs(t, err, &mockserrors.RuleNotFoundError{})
+
+	logRepo := repository.NewLogSQLRepository(db)
+	assert.NoError(t, logRepo.Add(ctx, model.LogEntry{Method: http.MethodGet, URL: "/v1/payments/42",
+		ResponseStatus: http.StatusOK}))
+	assert.NoError(t, logRepo.Add(ctx, model.LogEntry{Method: http.MethodPost, URL: "/v2/refunds",
+		ResponseStatus: http.StatusCreated}))
+
+	for _, url := range []string{"/v1/payment_methods", "/v1/paymentXmethods"} {
+		assert.NoError(t, logRepo.Add(ctx, model.LogEntry{Method: http.MethodGet, URL: url,
+			ResponseStatus: http.StatusOK}))
+	}
+
+	chaosRepo := repository.NewChaosSQLRepository(db)
+	seed := int64(7)
+
+	for _, status := range []string{model.ChaosStatusEnabled, model.ChaosStatusDisabled} {
+		assert.NoError(t, chaosRepo.Save(ctx, model.ChaosSettings{Status: status, Seed: &seed}), "upsert")
+	}
+
+	now := time.Now()
+	scenarioRepo := repository.NewScenarioSQLRepository(db)
+
+	for _, tt := range []struct {
+		state, expected string
+		want            bool
+	}{
+		{state: "paid", expected: model.ScenarioStateStarted, want: true},
+		{state: "shipped", expected: model.ScenarioStateStarted, want: false},
+		{state: "paid", expected: "paid", want: true},
+		{state: "shipped", expected: "paid", want: true},
+	} {
+		saved, err := scenarioRepo.CompareAndSet(ctx, model.Scenario{Name: "checkout", State: tt.state, UpdatedAt: now},
+			tt.expected)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, saved, "%s from %s", tt.state, tt.expected)
+	}
+
+	jobs := repository.NewWebhookJobSQLRepository(db)
+	assert.NoError(t, jobs.Save(ctx, model.WebhookJob{ID: "01", Status: model.WebhookJobPending, DueAt: now}))
+
+	claimed, err := jobs.Claim(ctx, "01", now.Add(time.Second), now.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+
+	claimed, err = jobs.Claim(ctx, "01", now.Add(time.Second), now.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.False(t, claimed, "a claimed job cannot be claimed again")
+
+	appendRuleRevisions(t, repository.NewRuleRevisionSQLRepository(db), now.UTC().Truncate(time.Millisecond))
+
+	// Opening the file again keeps the data, the schema is only created when it is missing.
+	assert.NoError(t, db.Close())
+	db = newSQLiteDB(t, file)
+
+	rules, err := repository.NewRuleSQLRepository(db).SearchByMethodAndPath(ctx, http.MethodGet, "/v1/payments/42")
+	if assert.NoError(t, err) && assert.Len(t, rules, 1) {
+		assert.Equal(t, rule.Key, rules[0].Key)
+		assert.Len(t, rules[0].Responses, 1)
+		assert.Len(t, rules[0].Variables, 1)
+	}
+
+	logs, err := repository.NewLogSQLRepository(db).GetAll(ctx, model.LogFilter{URLRegex: "^/v1/payments/"},
+		model.Paging{Limit: 10})
+	if assert.NoError(t, err) && assert.Len(t, logs.Results, 1) {
+		assert.Equal(t, "/v1/payments/42", logs.Results[0].URL)
+		assert.False(t, logs.Results[0].Timestamp.IsZero())
+	}
+
+	// The wildcards of LIKE in the filter are matched as plain characters.
+	logs, err = repository.NewLogSQLRepository(db).GetAll(ctx, model.LogFilter{URL: "payment_methods"},
+		model.Paging{Limit: 10})
+	if assert.NoError(t, err) && assert.Len(t, logs.Results, 1) {
+		assert.Equal(t, "/v1/payment_methods", logs.Results[0].URL)
+	}
+
+	settings, err := repository.NewChaosSQLRepository(db).Get(ctx)
+	if assert.NoError(t, err) {
+		assert.Equal(t, model.ChaosStatusDisabled, settings.Status)
+	}
+
+	assertRuleRevisions(t, repository.NewRuleRevisionSQLRepository(db), now.UTC().Truncate(time.Millisecond))
+}
+
+func TestRuleSQLRepository_GetWithBrokenResponse(t *testing.T) {
+	ctx := mockscontext.Background()
+	db := newSQLiteDB(t, filepath.Join(t.TempDir(), "mocks.db"))
+	ruleRepo := repository.NewRuleSQLRepository(db)
+
+	rule := &model.Rule{
+		Group: "payments", Name: "get payment", Path: "/v1/payments", Strategy: model.RuleStrategyNormal,
+		Method: http.MethodGet, Status: model.RuleStatusEnabled,
+		Responses: []model.Response{{Body: "{}", ContentType: "application/json", HTTPStatus: http.StatusOK}},
+	}
+
+	if _, err := ruleRepo.Create(ctx, rule); !assert.NoError(t, err) {
+		return
+	}
+
+	_, err := db.Exec("UPDATE responses SET headers = '{\"X-Id\":' WHERE rule_key = ?", rule.Key)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_, err = ruleRepo.Get(ctx, rule.Key)
+	assert.ErrorContains(t, err, "error unmarshalling headers of response")
+}
+
+func TestRuleSQLRepository_GetWithBrokenMatchers(t *testing.T) {
+	ctx := mockscontext.Background()
+	db := newSQLiteDB(t, filepath.Join(t.TempDir(), "mocks.db"))
+	ruleRepo := repository.NewRuleSQLRepository(db)
+
+	rule := &model.Rule{
+		Group: "payments", Name: "get payment", Path: "/v1/payments", Strategy: model.RuleStrategyNormal,
+		Method: http.MethodGet, Status: model.RuleStatusEnabled,
+		Responses: []model.Response{{Body: "{}", ContentType: "application/json", HTTPStatus: http.StatusOK}},
+	}
+
+	if _, err := ruleRepo.Create(ctx, rule); !assert.NoError(t, err) {
+		return
+	}
+
+	_, err := db.Exec("UPDATE rules SET matchers = '[{\"type\":' WHERE `key` = ?", rule.Key)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_, err = ruleRepo.Get(ctx, rule.Key)
+	assert.ErrorContains(t, err, "error unmarshalling matchers")
+}
+
+func TestLogSQLRepository_UpdateConcurrently(t *testing.T) {
+	ctx := mockscontext.Background()
+	logRepo := repository.NewLogSQLRepository(newSQLiteDB(t, filepath.Join(t.TempDir(), "mocks.db")))
+
+	entry := model.LogEntry{ID: "01", Method: http.MethodPost, URL: "/v1/payments", ResponseStatus: http.StatusOK}
+	if !assert.NoError(t, logRepo.Add(ctx, entry)) {
+		return
+	}
+
+	const webhooks = 10
+
+	var wg sync.WaitGroup
+
+	for webhook := range webhooks {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			err := logRepo.Update(ctx, entry.ID, func(entry *model.LogEntry) {
+				// The webhooks read the entry before any of them writes it back.
+				time.Sleep(10 * time.Millisecond)
+
+				entry.WebhookResults = append(entry.WebhookResults,
+					model.WebhookResult{URL: fmt.Sprintf("/hooks/%d", webhook)})
+			})
+			assert.NoError(t, err)
+		}()
+	}
+
+	wg.Wait()
+
+	updated, err := logRepo.Get(ctx, entry.ID)
+	if assert.NoError(t, err) {
+		assert.Len(t, updated.WebhookResults, webhooks)
+	}
+}
@@ -2,7 +2,11 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -10,10 +14,18 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/nicopozo/mockserver/internal/configs"
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
 	"github.com/nicopozo/mockserver/internal/model"
+	jsonutils "github.com/nicopozo/mockserver/internal/utils/json"
 	"github.com/oklog/ulid/v2"
 )
 
+// maxURLRegexItems is the most log items that a DynamoDB query reads to check url_regex.
+const maxURLRegexItems = 5000
+
+// DynamoLogRepository keeps the mock requests and the requests captured by the bins in the same table.
+// The type attribute, the partition key of type-id-index, tells them apart: "log" for the mock requests
+// and "bin#<name>" for the requests of a bin.
 type DynamoLogRepository struct {
 	client    *dynamodb.Client
 	tableName string
@@ -37,18 +49,27 @@ func (r *DynamoLogRepository) Add(ctx context.Context, entry model.LogEntry) err
 	}
 
 	item := logItem{
-		ID:              entry.ID,
-		Type:            "log",
-		Timestamp:       entry.Timestamp,
-		Method:          entry.Method,
-		URL:             entry.URL,
-		RequestBody:     entry.RequestBody,
-		RequestHeaders:  entry.RequestHeaders,
-		QueryParams:     entry.QueryParams,
-		ResponseStatus:  entry.ResponseStatus,
-		ResponseBody:    entry.ResponseBody,
-		AssertionErrors: entry.AssertionErrors,
-		WebhookResults:  entry.WebhookResults,
+		ID:                 entry.ID,
+		Type:               logType(entry.Bin),
+		Bin:                entry.Bin,
+		Timestamp:          entry.Timestamp,
+		RuleKey:            entry.RuleKey,
+		RuleName:           entry.RuleName,
+		Scene:              entry.Scene,
+		Method:             entry.Method,
+		URL:                entry.URL,
+		RequestBody:        entry.RequestBody,
+		RequestHeaders:     entry.RequestHeaders,
+		QueryParams:        entry.QueryParams,
+		ResponseStatus:     entry.ResponseStatus,
+		ResponseBody:       entry.ResponseBody,
+		DurationMs:         entry.DurationMs,
+		Fault:              entry.Fault,
+		Chaos:              toChaosResultItem(entry.Chaos),
+		AssertionErrors:    entry.AssertionErrors,
+		WebhookResults:     entry.WebhookResults,
+		HasAssertionErrors: entry.HasAssertionErrors(),
+		HasWebhookFailures: entry.HasWebhookFailures(),
 	}
 
 	attributes, err := attributevalue.MarshalMap(item)
@@ -67,54 +88,126 @@ func (r *DynamoLogRepository) Add(ctx context.Context, entry model.LogEntry) err
 	return nil
 }
 
-func (r *DynamoLogRepository) GetAll(ctx context.Context, paging model.Paging) (model.LogList, error) {
+// GetAll queries type-id-index for the entries of the filter, newest first. DynamoDB has no regular
+// expressions, so url_regex is checked on the items the query returns: at most maxURLRegexItems are read,
+// the total is the number of entries returned, and last_id is set to continue when there may be more.
+func (r *DynamoLogRepository) GetAll(ctx context.Context, filter model.LogFilter,
+	paging model.Paging,
+) (model.LogList, error) {
+	urlRegex, err := compileURLRegex(filter.URLRegex)
+	if err != nil {
+		return model.LogList{}, err
+	}
+
+	expression := newLogFilterExpression(filter)
+
 	var exclusiveStartKey map[string]types.AttributeValue
 
+	skip := 0
+
 	if paging.LastID != "" {
 		exclusiveStartKey = map[string]types.AttributeValue{
 			"id":   &types.AttributeValueMemberS{Value: paging.LastID},
-			"type": &types.AttributeValueMemberS{Value: "log"},
+			"type": &types.AttributeValueMemberS{Value: logType(filter.Bin)},
 		}
+	} else {
+		skip = int(paging.Offset)
 	}
 
-	input := &dynamodb.QueryInput{
-		TableName:              aws.String(r.tableName),
-		IndexName:              aws.String("type-id-index"),
-		KeyConditionExpression: aws.String("#t = :t"),
-		ExpressionAttributeNames: map[string]string{
-			"#t": "type",
-		},
-		ExpressionAttributeValues: map[string]types.AttributeValue{
-			":t": &types.AttributeValueMemberS{Value: "log"},
-		},
-		ScanIndexForward:  aws.Bool(false), // Sort order: descending (newest first)
-		Limit:             aws.Int32(paging.Limit),
-		ExclusiveStartKey: exclusiveStartKey,
+	items := make([]logItem, 0, paging.Limit)
+	read := 0
+	cursor := ""
+
+	// DynamoDB applies Limit before FilterExpression, so keep querying until the page is full.
+	for {
+		input := &dynamodb.QueryInput{
+			TableName:                 aws.String(r.tableName),
+			IndexName:                 aws.String("type-id-index"),
+			KeyConditionExpression:    aws.String(expression.keyCondition),
+			FilterExpression:          expression.filter(),
+			ExpressionAttributeNames:  expression.names,
+			ExpressionAttributeValues: expression.values,
+			ScanIndexForward:          aws.Bool(false), // Sort order: descending (newest first)
+			Limit:                     aws.Int32(paging.Limit),
+			ExclusiveStartKey:         exclusiveStartKey,
+		}
+
+		result, err := r.client.Query(ctx, input)
+		if err != nil {
+			return model.LogList{}, fmt.Errorf("error querying logs GSI in DynamoDB: %w", err)
+		}
+
+		var pageItems []logItem
+
+		err = attributevalue.UnmarshalListOfMaps(result.Items, &pageItems)
+		if err != nil {
+			return model.LogList{}, fmt.Errorf("error unmarshaling logs: %w", err)
+		}
+
+		read += int(result.ScannedCount)
+
+		for _, item := range pageItems {
+			if urlRegex != nil && !urlRegex.MatchString(item.URL) {
+				continue
+			}
+
+			if skip > 0 {
+				skip--
+
+				continue
+			}
+
+			items = append(items, item)
+		}
+
+		exclusiveStartKey = result.LastEvaluatedKey
+
+		if len(items) >= int(paging.Limit) || exclusiveStartKey == nil {
+			break
+		}
+
+		if urlRegex != nil && read >= maxURLRegexItems {
+			// The items read so far did not match, so the next request goes on after them.
+			cursor = itemID(exclusiveStartKey)
+
+			break
+		}
 	}
 
-	result, err := r.client.Query(ctx, input)
-	if err != nil {
-		return model.LogList{}, fmt.Errorf("error querying logs GSI in DynamoDB: %w", err)
+	more := len(items) > int(paging.Limit) || exclusiveStartKey != nil
+
+	if len(items) > int(paging.Limit) {
+		items = items[:paging.Limit]
+	}
+
+	if more && cursor == "" && len(items) > 0 {
+		cursor = items[len(items)-1].ID
 	}
 
-	var items []logItem
+	if urlRegex != nil {
+		paging.Total = int64(len(items))
+		paging.LastID = cursor
 
-	err = attributevalue.UnmarshalListOfMaps(result.Items, &items)
-	if err != nil {
-		return model.LogList{}, fmt.Errorf("error unmarshaling logs: %w", err)
+		return model.LogList{
+			Results: toLogEntryModels(items),
+			Paging:  paging,
+		}, nil
 	}
 
 	// Fetch accurate total count using a fast count scan
 	countInput := &dynamodb.ScanInput{
-		TableName: aws.String(r.tableName),
-		Select:    types.SelectCount,
+		TableName:                 aws.String(r.tableName),
+		Select:                    types.SelectCount,
+		FilterExpression:          aws.String(expression.scanFilter()),
+		ExpressionAttributeNames:  expression.names,
+		ExpressionAttributeValues: expression.values,
 	}
 
 	countResult, err := r.client.Scan(ctx, countInput)
 	if err == nil {
 		paging.Total = int64(countResult.Count)
 	} else {
-		paging.Total = int64(result.Count)
+		paging.Total = int64(len(items))
 	}
 
 	return model.LogList{
@@ -123,55 +216,158 @@ func (r *DynamoLogRepository) GetAll(ctx context.Context, paging model.Paging) (
 	}, nil
 }
 
-func toLogEntryModels(items []logItem) []model.LogEntry {
-	results := make([]model.LogEntry, 0, len(items))
-	for _, item := range items {
-		results = append(results, model.LogEntry{
-			ID:              item.ID,
-			Timestamp:       item.Timestamp,
-			Method:          item.Method,
-			URL:             item.URL,
-			RequestBody:     item.RequestBody,
-			RequestHeaders:  item.RequestHeaders,
-			QueryParams:     item.QueryParams,
-			ResponseStatus:  item.ResponseStatus,
-			ResponseBody:    item.ResponseBody,
-			AssertionErrors: item.AssertionErrors,
-			WebhookResults:  item.WebhookResults,
-		})
+// itemID returns the id of the item key, or an empty string when it has none.
+func itemID(key map[string]types.AttributeValue) string {
+	id, _ := key["id"].(*types.AttributeValueMemberS)
+	if id == nil {
+		return ""
 	}
 
-	return results
+	return id.Value
 }
 
-func (r *DynamoLogRepository) Update(ctx context.Context, logID string, updater func(entry *model.LogEntry)) error {
-	// Fetch existing item from DynamoDB
-	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
-		TableName: aws.String(r.tableName),
-		Key: map[string]types.AttributeValue{
-			"id":   &types.AttributeValueMemberS{Value: logID},
-			"type": &types.AttributeValueMemberS{Value: "log"},
-		},
-	})
+// compileURLRegex compiles the url_regex filter, which is nil when it is not set.
+func compileURLRegex(expression string) (*regexp.Regexp, error) {
+	if expression == "" {
+		return nil, nil //nolint:nilnil
+	}
+
+	urlRegex, err := regexp.Compile(expression)
 	if err != nil {
-		return fmt.Errorf("error fetching log item for update: %w", err)
+		return nil, mockserrors.InvalidRulesError{Message: "url_regex is not a valid regular expression: " + err.Error()}
 	}
 
-	if result.Item == nil {
-		return fmt.Errorf("log entry not found: %s", logID) //nolint:err113
+	return urlRegex, nil
+}
+
+// logFilterExpression is a LogFilter translated into DynamoDB expressions. The time window is part of
+// the key condition, since log ids are ULIDs and therefore sorted by creation time.
+type logFilterExpression struct {
+	keyCondition string
+	filters      []string
+	names        map[string]string
+	values       map[string]types.AttributeValue
+}
+
+func newLogFilterExpression(filter model.LogFilter) logFilterExpression {
+	expression := logFilterExpression{
+		keyCondition: "#t = :t",
+		names:        map[string]string{"#t": "type"},
+		values:       map[string]types.AttributeValue{":t": &types.AttributeValueMemberS{Value: logType(filter.Bin)}},
 	}
 
-	var item logItem
+	if !filter.From.IsZero() || !filter.To.IsZero() {
+		from, to := ulidRange(filter.From, filter.To)
+		expression.keyCondition += " AND #id BETWEEN :idFrom AND :idTo"
+		expression.names["#id"] = "id"
+		expression.values[":idFrom"] = &types.AttributeValueMemberS{Value: from}
+		expression.values[":idTo"] = &types.AttributeValueMemberS{Value: to}
+	}
 
-	err = attributevalue.UnmarshalMap(result.Item, &item)
-	if err != nil {
-		return fmt.Errorf("error unmarshaling log item: %w", err)
+	if filter.Method != "" {
+		expression.add("#method = :method", "#method", "method", ":method",
+			&types.AttributeValueMemberS{Value: strings.ToUpper(filter.Method)})
+	}
+
+	if filter.URL != "" {
+		expression.add("contains(#url, :url)", "#url", "url", ":url", &types.AttributeValueMemberS{Value: filter.URL})
+	}
+
+	if filter.StatusFrom != 0 {
+		expression.add("#status >= :statusFrom", "#status", "response_status", ":statusFrom",
+			&types.AttributeValueMemberN{Value: fmt.Sprint(filter.StatusFrom)})
 	}
 
-	// Convert logItem to model.LogEntry
-	entry := model.LogEntry{
+	if filter.StatusTo != 0 {
+		expression.add("#status <= :statusTo", "#status", "response_status", ":statusTo",
+			&types.AttributeValueMemberN{Value: fmt.Sprint(filter.StatusTo)})
+	}
+
+	if filter.RuleKey != "" {
+		expression.add("#rule_key = :rule_key", "#rule_key", "rule_key", ":rule_key",
+			&types.AttributeValueMemberS{Value: filter.RuleKey})
+	}
+
+	if filter.HasAssertionErrors != nil {
+		expression.addFlag("#assertions", "has_assertion_errors", ":assertions", *filter.HasAssertionErrors)
+	}
+
+	if filter.HasWebhookFailures != nil {
+		expression.addFlag("#webhooks", "has_webhook_failures", ":webhooks", *filter.HasWebhookFailures)
+	}
+
+	return expression
+}
+
+func (expression *logFilterExpression) add(condition, name, attribute, placeholder string,
+	value types.AttributeValue,
+) {
+	expression.filters = append(expression.filters, condition)
+	expression.names[name] = attribute
+	expression.values[placeholder] = value
+}
+
+// addFlag filters by a boolean attribute. Entries stored before the attribute existed count as false.
+func (expression *logFilterExpression) addFlag(name, attribute, placeholder string, value bool) {
+	condition := fmt.Sprintf("%s = %s", name, placeholder)
+	if !value {
+		condition = fmt.Sprintf("(attribute_not_exists(%s) OR %s)", name, condition)
+	}
+
+	expression.filters = append(expression.filters, condition)
+	expression.names[name] = attribute
+	expression.values[placeholder] = &types.AttributeValueMemberBOOL{Value: value}
+}
+
+func (expression logFilterExpression) filter() *string {
+	if len(expression.filters) == 0 {
+		return nil
+	}
+
+	return aws.String(strings.Join(expression.filters, " AND "))
+}
+
+// scanFilter merges the key condition into the filter, for scans over the whole table.
+func (expression logFilterExpression) scanFilter() string {
+	return strings.Join(append([]string{expression.keyCondition}, expression.filters...), " AND ")
+}
+
+// ulidRange returns the lowest and highest ULIDs that can be generated within the time window.
+func ulidRange(from, to time.Time) (string, string) {
+	var lowest, highest ulid.ULID
+
+	if !from.IsZero() {
+		_ = lowest.SetTime(ulid.Timestamp(from))
+	}
+
+	if to.IsZero() {
+		highest = ulid.ULID{0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+	} else {
+		_ = highest.SetTime(ulid.Timestamp(to))
+	}
+
+	_ = highest.SetEntropy([]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff})
+
+	return lowest.String(), highest.String()
+}
+
+func toLogEntryModels(items []logItem) []model.LogEntry {
+	results := make([]model.LogEntry, 0, len(items))
+	for _, item := range items {
+		results = append(results, toLogEntryModel(item))
+	}
+
+	return results
+}
+
+func toLogEntryModel(item logItem) model.LogEntry {
+	return model.LogEntry{
 		ID:              item.ID,
+		Bin:             item.Bin,
 		Timestamp:       item.Timestamp,
+		RuleKey:         item.RuleKey,
+		RuleName:        item.RuleName,
+		Scene:           item.Scene,
 		Method:          item.Method,
 		URL:             item.URL,
 		RequestBody:     item.RequestBody,
@@ -179,46 +375,126 @@ func (r *DynamoLogRepository) Update(ctx context.Context, logID string, updater
 		QueryParams:     item.QueryParams,
 		ResponseStatus:  item.ResponseStatus,
 		ResponseBody:    item.ResponseBody,
+		DurationMs:      item.DurationMs,
+		Fault:           item.Fault,
+		Chaos:           toChaosResultModel(item.Chaos),
 		AssertionErrors: item.AssertionErrors,
 		WebhookResults:  item.WebhookResults,
 	}
+}
 
-	// Apply the updater
-	updater(&entry)
-
-	// Update the item's WebhookResults
-	item.WebhookResults = entry.WebhookResults
-
-	// Marshal back
-	attributes, err := attributevalue.MarshalMap(item)
-	if err != nil {
-		return fmt.Errorf("error marshaling updated log item: %w", err)
-	}
-
-	// Save it back
-	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+func (r *DynamoLogRepository) Get(ctx context.Context, logID string) (*model.LogEntry, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
 		TableName: aws.String(r.tableName),
-		Item:      attributes,
+		Key: map[string]types.AttributeValue{
+			"id":   &types.AttributeValueMemberS{Value: logID},
+			"type": &types.AttributeValueMemberS{Value: "log"},
+		},
 	})
 	if err != nil {
-		return fmt.Errorf("error putting updated log item: %w", err)
+		return nil, fmt.Errorf("error fetching log item: %w", err)
 	}
 
-	return nil
+	if result.Item == nil {
+		return nil, mockserrors.NewLogEntryNotFoundError(logID) //nolint:wrapcheck
+	}
+
+	var item logItem
+
+	if err := attributevalue.UnmarshalMap(result.Item, &item); err != nil {
+		return nil, fmt.Errorf("error unmarshaling log item: %w", err)
+	}
+
+	entry := toLogEntryModel(item)
+
+	return &entry, nil
+}
+
+// Update changes the webhook results of the entry with a conditional put on webhook_version, so that
+// webhooks finishing at the same time do not overwrite each other's results. A put that finds the version
+// already moved reads the item again and applies updater to it.
+func (r *DynamoLogRepository) Update(ctx context.Context, logID string, updater func(entry *model.LogEntry)) error {
+	for {
+		result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+			TableName: aws.String(r.tableName),
+			Key: map[string]types.AttributeValue{
+				"id":   &types.AttributeValueMemberS{Value: logID},
+				"type": &types.AttributeValueMemberS{Value: "log"},
+			},
+			ConsistentRead: aws.Bool(true),
+		})
+		if err != nil {
+			return fmt.Errorf("error fetching log item for update: %w", err)
+		}
+
+		if result.Item == nil {
+			return mockserrors.NewLogEntryNotFoundError(logID) //nolint:wrapcheck
+		}
+
+		var item logItem
+
+		err = attributevalue.UnmarshalMap(result.Item, &item)
+		if err != nil {
+			return fmt.Errorf("error unmarshaling log item: %w", err)
+		}
+
+		entry := toLogEntryModel(item)
+
+		updater(&entry)
+
+		version := item.WebhookVersion
+		item.WebhookResults = entry.WebhookResults
+		item.HasWebhookFailures = entry.HasWebhookFailures()
+		item.WebhookVersion = version + 1
+
+		attributes, err := attributevalue.MarshalMap(item)
+		if err != nil {
+			return fmt.Errorf("error marshaling updated log item: %w", err)
+		}
+
+		condition := "webhook_version = :version"
+		if version == 0 {
+			condition = "(attribute_not_exists(webhook_version) OR " + condition + ")"
+		}
+
+		_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+			TableName:           aws.String(r.tableName),
+			Item:                attributes,
+			ConditionExpression: aws.String("attribute_exists(id) AND " + condition),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":version": &types.AttributeValueMemberN{Value: strconv.Itoa(version)},
+			},
+		})
+
+		var conditionFailed *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionFailed) {
+			continue
+		}
+
+		if err != nil {
+			return fmt.Errorf("error putting updated log item: %w", err)
+		}
+
+		return nil
+	}
 }
 
-func (r *DynamoLogRepository) Clear(ctx context.Context) error {
-	input := &dynamodb.ScanInput{
-		TableName:            aws.String(r.tableName),
-		ProjectionExpression: aws.String("id"),
+func (r *DynamoLogRepository) Clear(ctx context.Context, bin string) error {
+	input := &dynamodb.QueryInput{
+		TableName:                 aws.String(r.tableName),
+		IndexName:                 aws.String("type-id-index"),
+		KeyConditionExpression:    aws.String("#t = :t"),
+		ExpressionAttributeNames:  map[string]string{"#t": "type"},
+		ExpressionAttributeValues: map[string]types.AttributeValue{":t": &types.AttributeValueMemberS{Value: logType(bin)}},
+		ProjectionExpression:      aws.String("id"),
 	}
 
-	paginator := dynamodb.NewScanPaginator(r.client, input)
+	paginator := dynamodb.NewQueryPaginator(r.client, input)
 
 	for paginator.HasMorePages() {
 		page, err := paginator.NextPage(ctx)
 		if err != nil {
-			return fmt.Errorf("error scanning for clear: %w", err)
+			return fmt.Errorf("error querying for clear: %w", err)
 		}
 
 		for _, item := range page.Items {
@@ -239,17 +515,58 @@ func (r *DynamoLogRepository) Clear(ctx context.Context) error {
 	return nil
 }
 
+// logType returns the type of the items that keep the requests of bin.
+func logType(bin string) string {
+	if bin == "" {
+		return "log"
+	}
+
+	return "bin#" + bin
+}
+
 type logItem struct {
-	ID              string                `dynamodbav:"id"`
-	Type            string                `dynamodbav:"type"`
-	Timestamp       time.Time             `dynamodbav:"timestamp"`
-	Method          string                `dynamodbav:"method"`
-	URL             string                `dynamodbav:"url"`
-	RequestBody     string                `dynamodbav:"request_body"`
-	RequestHeaders  map[string]string     `dynamodbav:"request_headers"`
-	QueryParams     map[string]string     `dynamodbav:"query_params"`
-	ResponseStatus  int                   `dynamodbav:"response_status"`
-	ResponseBody    string                `dynamodbav:"response_body"`
-	AssertionErrors []string              `dynamodbav:"assertion_errors"`
-	WebhookResults  []model.WebhookResult `dynamodbav:"webhook_results,omitempty"`
+	ID                 string                `dynamodbav:"id"`
+	Type               string                `dynamodbav:"type"`
+	Bin                string                `dynamodbav:"bin,omitempty"`
+	Timestamp          time.Time             `dynamodbav:"timestamp"`
+	RuleKey            string                `dynamodbav:"rule_key,omitempty"`
+	RuleName           string                `dynamodbav:"rule_name,omitempty"`
+	Scene              string                `dynamodbav:"scene,omitempty"`
+	Method             string                `dynamodbav:"method"`
+	URL                string                `dynamodbav:"url"`
+	RequestBody        string                `dynamodbav:"request_body"`
+	RequestHeaders     map[string]string     `dynamodbav:"request_headers"`
+	QueryParams        map[string]string     `dynamodbav:"query_params"`
+	ResponseStatus     int                   `dynamodbav:"response_status"`
+	ResponseBody       string                `dynamodbav:"response_body"`
+	DurationMs         int64                 `dynamodbav:"duration_ms"`
+	Fault              string                `dynamodbav:"fault,omitempty"`
+	Chaos              string                `dynamodbav:"chaos,omitempty"`
+	AssertionErrors    []string              `dynamodbav:"assertion_errors"`
+	WebhookResults     []model.WebhookResult `dynamodbav:"webhook_results,omitempty"`
+	HasAssertionErrors bool                  `dynamodbav:"has_assertion_errors"`
+	HasWebhookFailures bool                  `dynamodbav:"has_webhook_failures"`
+	WebhookVersion     int                   `dynamodbav:"webhook_version,omitempty"`
+}
+
+func toChaosResultItem(chaos *model.ChaosResult) string {
+	if chaos == nil {
+		return ""
+	}
+
+	return jsonutils.Marshal(chaos)
+}
+
+func toChaosResultModel(chaos string) *model.ChaosResult {
+	if chaos == "" {
+		return nil
+	}
+
+	result := &model.ChaosResult{}
+
+	if err := jsonutils.Unmarshal(strings.NewReader(chaos), result); err != nil {
+		return nil
+	}
+
+	return result
 }
@@ -0,0 +1,162 @@
+package repository_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/nicopozo/mockserver/internal/configs"
+	"github.com/nicopozo/mockserver/internal/model"
+	"github.com/nicopozo/mockserver/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type dynamoValue map[string]string
+
+// fakeDynamoLogs answers the Query and Scan calls of DynamoLogRepository with the logs, newest first.
+// Queries return pages of queryLimit items at most, like DynamoDB does when it reaches its size limit.
+type fakeDynamoLogs struct {
+	logs       []model.LogEntry
+	queryLimit int
+}
+
+func (fake *fakeDynamoLogs) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	var input struct {
+		Limit             int
+		ExclusiveStartKey map[string]dynamoValue
+	}
+
+	if err := json.NewDecoder(request.Body).Decode(&input); err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	items := make([]map[string]dynamoValue, 0, len(fake.logs))
+	for _, entry := range fake.logs {
+		items = append(items, map[string]dynamoValue{
+			"id": {"S": entry.ID}, "type": {"S": "log"}, "url": {"S": entry.URL},
+		})
+	}
+
+	output := map[string]interface{}{"Items": items, "Count": len(items)}
+
+	if strings.HasSuffix(request.Header.Get("X-Amz-Target"), ".Query") {
+		start := 0
+		if input.ExclusiveStartKey != nil {
+			start = 1 + slices.IndexFunc(fake.logs, func(entry model.LogEntry) bool {
+				return entry.ID == input.ExclusiveStartKey["id"]["S"]
+			})
+		}
+
+		end := min(start+min(input.Limit, fake.queryLimit), len(items))
+		output = map[string]interface{}{"Items": items[start:end], "Count": end - start, "ScannedCount": end - start}
+
+		if end < len(items) {
+			output["LastEvaluatedKey"] = map[string]dynamoValue{"id": {"S": fake.logs[end-1].ID}, "type": {"S": "log"}}
+		}
+	}
+
+	writer.Header().Set("Content-Type", "application/x-amz-json-1.0")
+	_ = json.NewEncoder(writer).Encode(output)
+}
+
+//nolint:paralleltest
+func TestDynamoLogRepository_GetAllURLRegex(t *testing.T) {
+	fake := &fakeDynamoLogs{
+		logs: []model.LogEntry{
+			{ID: "05", URL: "/v1/payments/3"},
+			{ID: "04", URL: "/v2/refunds/2"},
+			{ID: "03", URL: "/v1/payments/2"},
+			{ID: "02", URL: "/v2/refunds/1"},
+			{ID: "01", URL: "/v1/payments/1"},
+		},
+		queryLimit: 2,
+	}
+
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	client := dynamodb.New(dynamodb.Options{
+		BaseEndpoint: aws.String(server.URL),
+		Region:       "us-east-1",
+		Credentials:  aws.AnonymousCredentials{},
+	})
+	logRepository := repository.NewDynamoLogRepository(client, &configs.Config{})
+
+	tests := []struct {
+		name       string
+		paging     model.Paging
+		wantIDs    []string
+		wantLastID string
+	}{
+		{
+			name:    "offset skips the matching entries",
+			paging:  model.Paging{Limit: 2, Offset: 1},
+			wantIDs: []string{"03", "01"},
+		},
+		{
+			name:       "last id continues after the entry",
+			paging:     model.Paging{Limit: 1, LastID: "05"},
+			wantIDs:    []string{"03"},
+			wantLastID: "03",
+		},
+		{
+			name:       "limit stops reading",
+			paging:     model.Paging{Limit: 1},
+			wantIDs:    []string{"05"},
+			wantLastID: "05",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := logRepository.GetAll(t.Context(), model.LogFilter{URLRegex: "^/v1/"}, tt.paging)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			ids := make([]string, 0, len(got.Results))
+			for _, entry := range got.Results {
+				ids = append(ids, entry.ID)
+			}
+
+			assert.Equal(t, tt.wantIDs, ids)
+			assert.Equal(t, int64(len(tt.wantIDs)), got.Paging.Total)
+			assert.Equal(t, tt.wantLastID, got.Paging.LastID)
+		})
+	}
+}
+
+func TestDynamoLogRepository_GetAllURLRegexReadsAtMost(t *testing.T) {
+	fake := &fakeDynamoLogs{queryLimit: 1000}
+
+	for id := 6000; id > 0; id-- {
+		fake.logs = append(fake.logs, model.LogEntry{ID: fmt.Sprintf("%05d", id), URL: "/v1/payments"})
+	}
+
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	client := dynamodb.New(dynamodb.Options{
+		BaseEndpoint: aws.String(server.URL),
+		Region:       "us-east-1",
+		Credentials:  aws.AnonymousCredentials{},
+	})
+
+	got, err := repository.NewDynamoLogRepository(client, &configs.Config{}).GetAll(t.Context(),
+		model.LogFilter{URLRegex: "^/v2/"}, model.Paging{Limit: 1000})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// The query stops after 5000 items, and the next one goes on from the last of them.
+	assert.Empty(t, got.Results)
+	assert.Equal(t, "01001", got.Paging.LastID)
+}
@@ -11,16 +11,27 @@ import (
 	"github.com/oklog/ulid/v2"
 )
 
-const maxLogEntries = 500
+const (
+	maxLogEntries = 500
+	// maxBinEntries is how many of the requests received by each bin are kept.
+	maxBinEntries = 100
+	// maxBins is how many bins keep their requests. Bins need no setup, so the one that received a request
+	// last the longest time ago is dropped to make room for a new one.
+	maxBins = 100
+)
 
+// logMemoryRepository keeps the last maxLogEntries mock requests and, apart from them, the last
+// maxBinEntries requests of up to maxBins bins, so that a busy bin does not push the mock requests out.
 type logMemoryRepository struct {
 	mu      sync.RWMutex
 	entries []model.LogEntry
+	bins    map[string][]model.LogEntry
 }
 
 func NewLogMemoryRepository() LogRepository {
 	return &logMemoryRepository{
 		entries: make([]model.LogEntry, 0, maxLogEntries),
+		bins:    make(map[string][]model.LogEntry),
 	}
 }
 
@@ -36,22 +47,66 @@ func (r *logMemoryRepository) Add(ctx context.Context, entry model.LogEntry) err
 		entry.Timestamp = time.Now()
 	}
 
-	r.entries = append(r.entries, entry)
+	if entry.Bin != "" {
+		if _, found := r.bins[entry.Bin]; !found && len(r.bins) >= maxBins {
+			r.dropIdlestBin()
+		}
+
+		r.bins[entry.Bin] = appendBounded(r.bins[entry.Bin], entry, maxBinEntries)
 
-	if len(r.entries) > maxLogEntries {
-		r.entries = r.entries[len(r.entries)-maxLogEntries:]
+		return nil
 	}
 
+	r.entries = appendBounded(r.entries, entry, maxLogEntries)
+
 	return nil
 }
 
-func (r *logMemoryRepository) GetAll(ctx context.Context, paging model.Paging) (model.LogList, error) {
+// dropIdlestBin removes the requests of the bin whose last request is the oldest. Log ids are ULIDs, so
+// the oldest request has the lowest id. The caller must hold the write lock.
+func (r *logMemoryRepository) dropIdlestBin() {
+	idlest, lastID := "", ""
+
+	for bin, entries := range r.bins {
+		if id := entries[len(entries)-1].ID; lastID == "" || id < lastID {
+			idlest, lastID = bin, id
+		}
+	}
+
+	delete(r.bins, idlest)
+}
+
+// appendBounded appends entry to entries and drops the oldest ones beyond limit.
+func appendBounded(entries []model.LogEntry, entry model.LogEntry, limit int) []model.LogEntry {
+	entries = append(entries, entry)
+
+	if len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+
+	return entries
+}
+
+func (r *logMemoryRepository) GetAll(ctx context.Context, filter model.LogFilter,
+	paging model.Paging,
+) (model.LogList, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	// Sort a copy of all entries newest first
-	allEntries := make([]model.LogEntry, len(r.entries))
-	copy(allEntries, r.entries)
+	entries := r.entries
+	if filter.Bin != "" {
+		entries = r.bins[filter.Bin]
+	}
+
+	// Sort a copy of the matching entries newest first
+	allEntries := make([]model.LogEntry, 0, len(entries))
+	matches := filter.Matcher()
+
+	for _, entry := range entries {
+		if matches(entry) {
+			allEntries = append(allEntries, entry)
+		}
+	}
 
 	sort.Slice(allEntries, func(i, j int) bool {
 		return allEntries[i].ID > allEntries[j].ID
@@ -82,20 +137,54 @@ func (r *logMemoryRepository) GetAll(ctx context.Context, paging model.Paging) (
 	}, nil
 }
 
+func (r *logMemoryRepository) Get(ctx context.Context, logID string) (*model.LogEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entry := r.find(logID)
+	if entry == nil {
+		//nolint:wrapcheck
+		return nil, mockserrors.NewLogEntryNotFoundError(logID)
+	}
+
+	found := *entry
+
+	return &found, nil
+}
+
 func (r *logMemoryRepository) Update(ctx context.Context, logID string, updater func(entry *model.LogEntry)) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	entry := r.find(logID)
+	if entry == nil {
+		//nolint:wrapcheck
+		return mockserrors.NewLogEntryNotFoundError(logID)
+	}
+
+	updater(entry)
+
+	return nil
+}
+
+// find returns the stored entry logID, among the mock requests and the requests of the bins, or nil. The
+// caller must hold the lock.
+func (r *logMemoryRepository) find(logID string) *model.LogEntry {
 	for i := range r.entries {
 		if r.entries[i].ID == logID {
-			updater(&r.entries[i])
+			return &r.entries[i]
+		}
+	}
 
-			return nil
+	for _, entries := range r.bins {
+		for i := range entries {
+			if entries[i].ID == logID {
+				return &entries[i]
+			}
 		}
 	}
 
-	//nolint:wrapcheck
-	return mockserrors.NewLogEntryNotFoundError(logID)
+	return nil
 }
 
 func findStartIndex(entries []model.LogEntry, lastID string) int {
@@ -108,10 +197,16 @@ func findStartIndex(entries []model.LogEntry, lastID string) int {
 	return -1
 }
 
-func (r *logMemoryRepository) Clear(ctx context.Context) error {
+func (r *logMemoryRepository) Clear(ctx context.Context, bin string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	if bin != "" {
+		delete(r.bins, bin)
+
+		return nil
+	}
+
 	r.entries = make([]model.LogEntry, 0, maxLogEntries)
 
 	return nil
@@ -2,9 +2,11 @@ package repository_test
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
 	"github.com/nicopozo/mockserver/internal/model"
 	"github.com/nicopozo/mockserver/internal/repository"
 	"github.com/stretchr/testify/assert"
@@ -23,7 +25,7 @@ func TestLogMemoryRepository(t *testing.T) {
 		err = repo.Add(ctx, entry2)
 		assert.NoError(t, err)
 
-		list, err := repo.GetAll(ctx, model.Paging{Limit: 10})
+		list, err := repo.GetAll(ctx, model.LogFilter{}, model.Paging{Limit: 10})
 		assert.NoError(t, err)
 		assert.Equal(t, int64(2), list.Paging.Total)
 		assert.Len(t, list.Results, 2)
@@ -33,19 +35,147 @@ func TestLogMemoryRepository(t *testing.T) {
 	})
 
 	t.Run("Keyset Pagination", func(t *testing.T) {
-		list, err := repo.GetAll(ctx, model.Paging{Limit: 1, LastID: "2"})
+		list, err := repo.GetAll(ctx, model.LogFilter{}, model.Paging{Limit: 1, LastID: "2"})
 		assert.NoError(t, err)
 		assert.Len(t, list.Results, 1)
 		assert.Equal(t, "1", list.Results[0].ID)
 	})
 
+	t.Run("Bins", func(t *testing.T) {
+		err := repo.Add(ctx, model.LogEntry{ID: "3", Bin: "callbacks", Method: "POST", URL: "/done"})
+		assert.NoError(t, err)
+
+		list, err := repo.GetAll(ctx, model.LogFilter{}, model.Paging{Limit: 10})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), list.Paging.Total, "bin requests are not mock requests")
+
+		list, err = repo.GetAll(ctx, model.LogFilter{Bin: "callbacks"}, model.Paging{Limit: 10})
+		assert.NoError(t, err)
+
+		if assert.Len(t, list.Results, 1) {
+			assert.Equal(t, "3", list.Results[0].ID)
+		}
+	})
+
 	t.Run("Clear", func(t *testing.T) {
-		err := repo.Clear(ctx)
+		err := repo.Clear(ctx, "")
 		assert.NoError(t, err)
 
-		list, err := repo.GetAll(ctx, model.Paging{Limit: 10})
+		list, err := repo.GetAll(ctx, model.LogFilter{}, model.Paging{Limit: 10})
 		assert.NoError(t, err)
 		assert.Equal(t, int64(0), list.Paging.Total)
 		assert.Len(t, list.Results, 0)
+
+		list, err = repo.GetAll(ctx, model.LogFilter{Bin: "callbacks"}, model.Paging{Limit: 10})
+		assert.NoError(t, err)
+		assert.Len(t, list.Results, 1, "clearing the mock requests keeps the bins")
+
+		assert.NoError(t, repo.Clear(ctx, "callbacks"))
+
+		list, err = repo.GetAll(ctx, model.LogFilter{Bin: "callbacks"}, model.Paging{Limit: 10})
+		assert.NoError(t, err)
+		assert.Len(t, list.Results, 0)
 	})
 }
+
+func TestLogMemoryRepository_BinsAreBoundedApart(t *testing.T) {
+	repo := repository.NewLogMemoryRepository()
+	ctx := context.Background()
+
+	assert.NoError(t, repo.Add(ctx, model.LogEntry{ID: "mock", Method: "GET", URL: "/payments"}))
+
+	for index := range 600 {
+		assert.NoError(t, repo.Add(ctx, model.LogEntry{ID: fmt.Sprintf("bin-%03d", index), Bin: "callbacks",
+			Method: "POST", URL: "/done"}))
+	}
+
+	// A busy bin does not push the mock requests out.
+	list, err := repo.GetAll(ctx, model.LogFilter{}, model.Paging{Limit: 10})
+	if assert.NoError(t, err) && assert.Len(t, list.Results, 1) {
+		assert.Equal(t, "mock", list.Results[0].ID)
+	}
+
+	list, err = repo.GetAll(ctx, model.LogFilter{Bin: "callbacks"}, model.Paging{Limit: 1})
+	if assert.NoError(t, err) && assert.Len(t, list.Results, 1) {
+		assert.Equal(t, int64(100), list.Paging.Total)
+		assert.Equal(t, "bin-599", list.Results[0].ID)
+	}
+
+	entry, err := repo.Get(ctx, "bin-599")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "callbacks", entry.Bin)
+	}
+
+	_, err = repo.Get(ctx, "bin-000")
+	assert.ErrorAs(t, err, &mockserrors.LogEntryNotFoundError{})
+
+	// A new bin beyond the limit takes the place of the one that received a request the longest time ago.
+	for index := range 100 {
+		id := fmt.Sprintf("other-%03d", index)
+		assert.NoError(t, repo.Add(ctx, model.LogEntry{ID: id, Bin: id, Method: "POST", URL: "/done"}))
+	}
+
+	list, err = repo.GetAll(ctx, model.LogFilter{Bin: "callbacks"}, model.Paging{Limit: 1})
+	if assert.NoError(t, err) {
+		assert.Empty(t, list.Results)
+	}
+
+	_, err = repo.Get(ctx, "other-000")
+	assert.NoError(t, err)
+}
+
+func TestLogMemoryRepository_GetAllFiltered(t *testing.T) {
+	now := time.Now()
+	yes, no := true, false
+
+	repo := repository.NewLogMemoryRepository()
+	ctx := context.Background()
+
+	entries := []model.LogEntry{
+		{ID: "1", Method: "GET", URL: "/v1/users/1", ResponseStatus: 200, RuleKey: "users", Timestamp: now.Add(-time.Hour)},
+		{
+			ID: "2", Method: "POST", URL: "/v1/payments?source=web", ResponseStatus: 201, RuleKey: "payments",
+			Timestamp:      now.Add(-time.Minute),
+			WebhookResults: []model.WebhookResult{{StatusCode: 500}},
+		},
+		{
+			ID: "3", Method: "POST", URL: "/v1/payments", ResponseStatus: 400, RuleKey: "payments",
+			Timestamp: now, AssertionErrors: []string{"amount is required"},
+		},
+	}
+
+	for _, entry := range entries {
+		assert.NoError(t, repo.Add(ctx, entry))
+	}
+
+	tests := []struct {
+		name    string
+		filter  model.LogFilter
+		wantIDs []string
+	}{
+		{name: "no filter", filter: model.LogFilter{}, wantIDs: []string{"3", "2", "1"}},
+		{name: "method ignores case", filter: model.LogFilter{Method: "post"}, wantIDs: []string{"3", "2"}},
+		{name: "url substring", filter: model.LogFilter{URL: "source=web"}, wantIDs: []string{"2"}},
+		{name: "url regex", filter: model.LogFilter{URLRegex: `^/v1/users/\d+$`}, wantIDs: []string{"1"}},
+		{name: "status range", filter: model.LogFilter{StatusFrom: 200, StatusTo: 299}, wantIDs: []string{"2", "1"}},
+		{name: "time window", filter: model.LogFilter{From: now.Add(-2 * time.Minute)}, wantIDs: []string{"3", "2"}},
+		{name: "rule key", filter: model.LogFilter{RuleKey: "users"}, wantIDs: []string{"1"}},
+		{name: "with assertion errors", filter: model.LogFilter{HasAssertionErrors: &yes}, wantIDs: []string{"3"}},
+		{name: "without webhook failures", filter: model.LogFilter{HasWebhookFailures: &no}, wantIDs: []string{"3", "1"}},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := repo.GetAll(ctx, tt.filter, model.Paging{Limit: 10})
+			assert.NoError(t, err)
+			assert.Equal(t, int64(len(tt.wantIDs)), list.Paging.Total)
+
+			ids := make([]string, 0, len(list.Results))
+			for _, entry := range list.Results {
+				ids = append(ids, entry.ID)
+			}
+
+			assert.Equal(t, tt.wantIDs, ids)
+		})
+	}
+}
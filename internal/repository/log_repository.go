@@ -8,7 +8,9 @@ import (
 
 type LogRepository interface {
 	Add(ctx context.Context, entry model.LogEntry) error
+	Get(ctx context.Context, id string) (*model.LogEntry, error)
 	Update(ctx context.Context, id string, updater func(entry *model.LogEntry)) error
-	GetAll(ctx context.Context, paging model.Paging) (model.LogList, error)
-	Clear(ctx context.Context) error
+	GetAll(ctx context.Context, filter model.LogFilter, paging model.Paging) (model.LogList, error)
+	// Clear deletes the entries of bin, or the mock requests when bin is empty.
+	Clear(ctx context.Context, bin string) error
 }
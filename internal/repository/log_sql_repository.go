@@ -2,31 +2,46 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
 	"github.com/nicopozo/mockserver/internal/model"
 	jsonutils "github.com/nicopozo/mockserver/internal/utils/json"
 	"github.com/oklog/ulid/v2"
 )
 
+// logSQLRepository keeps the mock requests in request_logs and the requests captured by the bins in
+// bin_requests, which has the same columns plus the name of the bin.
 type logSQLRepository struct {
 	db Database
 }
 
 type LogRow struct {
-	ID              string    `db:"id"`
-	Timestamp       time.Time `db:"timestamp"`
-	Method          string    `db:"method"`
-	URL             string    `db:"url"`
-	RequestBody     string    `db:"request_body"`
-	ResponseStatus  int       `db:"response_status"`
-	ResponseBody    string    `db:"response_body"`
-	RequestHeaders  string    `db:"request_headers"`
-	QueryParams     string    `db:"query_params"`
-	AssertionErrors string    `db:"assertion_errors"`
-	WebhookResults  *string   `db:"webhook_results"`
+	ID                 string    `db:"id"`
+	Bin                *string   `db:"bin"`
+	Timestamp          time.Time `db:"timestamp"`
+	RuleKey            *string   `db:"rule_key"`
+	RuleName           *string   `db:"rule_name"`
+	Scene              *string   `db:"scene"`
+	Method             string    `db:"method"`
+	URL                string    `db:"url"`
+	RequestBody        string    `db:"request_body"`
+	ResponseStatus     int       `db:"response_status"`
+	ResponseBody       string    `db:"response_body"`
+	DurationMs         int64     `db:"duration_ms"`
+	Fault              *string   `db:"fault"`
+	Chaos              *string   `db:"chaos"`
+	RequestHeaders     string    `db:"request_headers"`
+	QueryParams        string    `db:"query_params"`
+	AssertionErrors    string    `db:"assertion_errors"`
+	WebhookResults     *string   `db:"webhook_results"`
+	HasAssertionErrors bool      `db:"has_assertion_errors"`
+	HasWebhookFailures bool      `db:"has_webhook_failures"`
+	WebhookVersion     int       `db:"webhook_version"`
 }
 
 func rowToLogEntry(row LogRow) model.LogEntry {
@@ -38,6 +53,31 @@ func rowToLogEntry(row LogRow) model.LogEntry {
 		RequestBody:    row.RequestBody,
 		ResponseStatus: row.ResponseStatus,
 		ResponseBody:   row.ResponseBody,
+		DurationMs:     row.DurationMs,
+	}
+
+	if row.Bin != nil {
+		entry.Bin = *row.Bin
+	}
+
+	if row.RuleKey != nil {
+		entry.RuleKey = *row.RuleKey
+	}
+
+	if row.RuleName != nil {
+		entry.RuleName = *row.RuleName
+	}
+
+	if row.Scene != nil {
+		entry.Scene = *row.Scene
+	}
+
+	if row.Fault != nil {
+		entry.Fault = *row.Fault
+	}
+
+	if row.Chaos != nil && *row.Chaos != "" {
+		_ = jsonutils.Unmarshal(strings.NewReader(*row.Chaos), &entry.Chaos)
 	}
 
 	if row.RequestHeaders != "" {
@@ -79,15 +119,32 @@ func (r *logSQLRepository) Add(ctx context.Context, entry model.LogEntry) error
 	rawAssertions := jsonutils.Marshal(entry.AssertionErrors)
 	webhookResultsJSON := jsonutils.Marshal(entry.WebhookResults)
 
-	query := FormatQuery(
-		"INSERT INTO request_logs (id, timestamp, method, url, request_body, "+
-			"request_headers, query_params, response_status, response_body, assertion_errors, webhook_results) "+
-			"VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
-		r.db.DriverName(),
-	)
+	var chaosJSON *string
+
+	if entry.Chaos != nil {
+		c := jsonutils.Marshal(entry.Chaos)
+		chaosJSON = &c
+	}
+
+	columns := "id, timestamp, rule_key, rule_name, scene, method, url, request_body, request_headers, " +
+		"query_params, response_status, response_body, duration_ms, fault, chaos, assertion_errors, " +
+		"webhook_results, has_assertion_errors, has_webhook_failures"
+	args := []interface{}{
+		entry.ID, entry.Timestamp, entry.RuleKey, entry.RuleName, entry.Scene, entry.Method, entry.URL,
+		entry.RequestBody, rawHeaders, rawParams, entry.ResponseStatus, entry.ResponseBody, entry.DurationMs,
+		entry.Fault, chaosJSON, rawAssertions, webhookResultsJSON, entry.HasAssertionErrors(), entry.HasWebhookFailures(),
+	}
+
+	if entry.Bin != "" {
+		columns += ", bin"
+		args = append(args, entry.Bin)
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(args)), ", ")
+	query := FormatQuery(fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", logsTable(entry.Bin), columns, placeholders),
+		r.db.DriverName())
 
-	_, err := r.db.Exec(query, entry.ID, entry.Timestamp, entry.Method, entry.URL, entry.RequestBody,
-		rawHeaders, rawParams, entry.ResponseStatus, entry.ResponseBody, rawAssertions, webhookResultsJSON)
+	_, err := r.db.Exec(query, args...)
 	if err != nil {
 		return fmt.Errorf("error inserting log into DB: %w", err)
 	}
@@ -95,15 +152,20 @@ func (r *logSQLRepository) Add(ctx context.Context, entry model.LogEntry) error
 	return nil
 }
 
-func (r *logSQLRepository) GetAll(ctx context.Context, paging model.Paging) (model.LogList, error) {
+func (r *logSQLRepository) GetAll(ctx context.Context, filter model.LogFilter,
+	paging model.Paging,
+) (model.LogList, error) {
 	var rows []LogRow
 
+	conditions, args := r.logConditions(filter)
+	table := logsTable(filter.Bin)
+
 	// Get total count
 	var total int64
 
-	countQuery := FormatQuery("SELECT COUNT(*) FROM request_logs", r.db.DriverName())
+	countQuery := FormatQuery("SELECT COUNT(*) FROM "+table+whereClause(conditions), r.db.DriverName())
 
-	err := r.db.Get(&total, countQuery)
+	err := r.db.Get(&total, countQuery, args...)
 	if err != nil {
 		return model.LogList{}, fmt.Errorf("error counting logs in DB: %w", err)
 	}
@@ -116,11 +178,16 @@ func (r *logSQLRepository) GetAll(ctx context.Context, paging model.Paging) (mod
 	var errSelect error
 
 	if paging.LastID != "" {
-		query = FormatQuery("SELECT * FROM request_logs WHERE id < ? ORDER BY id DESC LIMIT ?", r.db.DriverName())
-		errSelect = r.db.Select(&rows, query, paging.LastID, paging.Limit)
+		conditions = append(conditions, "id < ?")
+		args = append(args, paging.LastID, paging.Limit)
+		query = FormatQuery("SELECT * FROM "+table+whereClause(conditions)+" ORDER BY id DESC LIMIT ?",
+			r.db.DriverName())
+		errSelect = r.db.Select(&rows, query, args...)
 	} else {
-		query = FormatQuery("SELECT * FROM request_logs ORDER BY id DESC LIMIT ? OFFSET ?", r.db.DriverName())
-		errSelect = r.db.Select(&rows, query, paging.Limit, paging.Offset)
+		args = append(args, paging.Limit, paging.Offset)
+		query = FormatQuery("SELECT * FROM "+table+whereClause(conditions)+" ORDER BY id DESC LIMIT ? OFFSET ?",
+			r.db.DriverName())
+		errSelect = r.db.Select(&rows, query, args...)
 	}
 
 	if errSelect != nil {
@@ -138,41 +205,174 @@ func (r *logSQLRepository) GetAll(ctx context.Context, paging model.Paging) (mod
 	}, nil
 }
 
-func (r *logSQLRepository) Update(ctx context.Context, logID string, updater func(entry *model.LogEntry)) error {
-	// Fetch existing entry
+func (r *logSQLRepository) Get(_ context.Context, logID string) (*model.LogEntry, error) {
 	query := FormatQuery("SELECT * FROM request_logs WHERE id = ?", r.db.DriverName())
 	row := LogRow{}
 
 	err := r.db.Get(&row, query, logID)
+	if errors.Is(err, sql.ErrNoRows) {
+		query = FormatQuery("SELECT * FROM bin_requests WHERE id = ?", r.db.DriverName())
+		err = r.db.Get(&row, query, logID)
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, mockserrors.NewLogEntryNotFoundError(logID) //nolint:wrapcheck
+	}
+
 	if err != nil {
-		return fmt.Errorf("error fetching log entry for update: %w", err)
+		return nil, fmt.Errorf("error fetching log entry: %w", err)
 	}
 
 	entry := rowToLogEntry(row)
 
-	// Apply the updater function
-	updater(&entry)
-
-	// Serialize webhook results back
-	webhookResultsJSON := jsonutils.Marshal(entry.WebhookResults)
+	return &entry, nil
+}
 
+// Update changes the webhook results of the entry with a compare-and-set on webhook_version, so that
+// webhooks finishing at the same time do not overwrite each other's results. An update that finds the
+// version already moved reads the entry again and applies updater to it.
+func (r *logSQLRepository) Update(_ context.Context, logID string, updater func(entry *model.LogEntry)) error {
+	query := FormatQuery("SELECT * FROM request_logs WHERE id = ?", r.db.DriverName())
 	updateQuery := FormatQuery(
-		"UPDATE request_logs SET webhook_results = ? WHERE id = ?",
+		"UPDATE request_logs SET webhook_results = ?, has_webhook_failures = ?, webhook_version = ? "+
+			"WHERE id = ? AND webhook_version = ?",
 		r.db.DriverName(),
 	)
 
-	_, err = r.db.Exec(updateQuery, webhookResultsJSON, logID)
-	if err != nil {
-		return fmt.Errorf("error updating webhook_results in DB: %w", err)
+	for {
+		row := LogRow{}
+
+		err := r.db.Get(&row, query, logID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return mockserrors.NewLogEntryNotFoundError(logID) //nolint:wrapcheck
+		}
+
+		if err != nil {
+			return fmt.Errorf("error fetching log entry for update: %w", err)
+		}
+
+		entry := rowToLogEntry(row)
+
+		updater(&entry)
+
+		webhookResultsJSON := jsonutils.Marshal(entry.WebhookResults)
+
+		result, err := r.db.Exec(updateQuery, webhookResultsJSON, entry.HasWebhookFailures(), row.WebhookVersion+1,
+			logID, row.WebhookVersion)
+		if err != nil {
+			return fmt.Errorf("error updating webhook_results in DB: %w", err)
+		}
+
+		updated, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("error updating webhook_results in DB: %w", err)
+		}
+
+		if updated == 1 {
+			return nil
+		}
 	}
+}
 
-	return nil
+// logConditions translates filter into SQL conditions and their arguments.
+func (r *logSQLRepository) logConditions(filter model.LogFilter) ([]string, []interface{}) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+
+	add := func(condition string, arg ...interface{}) {
+		conditions = append(conditions, condition)
+		args = append(args, arg...)
+	}
+
+	if filter.Bin != "" {
+		add("bin = ?", filter.Bin)
+	}
+
+	if filter.Method != "" {
+		add("method = ?", strings.ToUpper(filter.Method))
+	}
+
+	if filter.URL != "" {
+		// The escape character is bound rather than written as a literal, since MySQL and the other
+		// databases do not agree on how a backslash is written in a string.
+		add("url LIKE ? ESCAPE ?", "%"+escapeLike(filter.URL)+"%", likeEscape)
+	}
+
+	if filter.URLRegex != "" {
+		if r.db.DriverName() == datasourcePostgres {
+			add("url ~ ?", filter.URLRegex)
+		} else {
+			add("url REGEXP ?", filter.URLRegex)
+		}
+	}
+
+	if filter.StatusFrom != 0 {
+		add("response_status >= ?", filter.StatusFrom)
+	}
+
+	if filter.StatusTo != 0 {
+		add("response_status <= ?", filter.StatusTo)
+	}
+
+	if !filter.From.IsZero() {
+		add("timestamp >= ?", filter.From)
+	}
+
+	if !filter.To.IsZero() {
+		add("timestamp <= ?", filter.To)
+	}
+
+	if filter.RuleKey != "" {
+		add("rule_key = ?", filter.RuleKey)
+	}
+
+	if filter.HasAssertionErrors != nil {
+		add("has_assertion_errors = ?", *filter.HasAssertionErrors)
+	}
+
+	if filter.HasWebhookFailures != nil {
+		add("has_webhook_failures = ?", *filter.HasWebhookFailures)
+	}
+
+	return conditions, args
+}
+
+// logsTable returns the table that keeps the requests of bin.
+func logsTable(bin string) string {
+	if bin == "" {
+		return "request_logs"
+	}
+
+	return "bin_requests"
 }
 
-func (r *logSQLRepository) Clear(ctx context.Context) error {
-	query := FormatQuery("DELETE FROM request_logs", r.db.DriverName())
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND ")
+}
+
+// likeEscape is the escape character of the LIKE patterns built by escapeLike.
+const likeEscape = `\`
+
+// escapeLike escapes the LIKE wildcards so the value is matched as a plain substring.
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+func (r *logSQLRepository) Clear(ctx context.Context, bin string) error {
+	var err error
+
+	if bin == "" {
+		_, err = r.db.Exec(FormatQuery("DELETE FROM request_logs", r.db.DriverName()))
+	} else {
+		_, err = r.db.Exec(FormatQuery("DELETE FROM bin_requests WHERE bin = ?", r.db.DriverName()), bin)
+	}
 
-	_, err := r.db.Exec(query)
 	if err != nil {
 		return fmt.Errorf("error clearing logs from DB: %w", err)
 	}
@@ -0,0 +1,125 @@
+package repository_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nicopozo/mockserver/internal/model"
+	"github.com/nicopozo/mockserver/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+// queryRecorder is a Database that records the executed queries instead of running them.
+type queryRecorder struct {
+	driver  string
+	queries []string
+	args    [][]interface{}
+}
+
+func (db *queryRecorder) record(query string, args []interface{}) {
+	db.queries = append(db.queries, query)
+	db.args = append(db.args, args)
+}
+
+func (db *queryRecorder) Exec(query string, args ...interface{}) (sql.Result, error) {
+	db.record(query, args)
+
+	return nil, nil //nolint:nilnil
+}
+
+func (db *queryRecorder) Get(_ interface{}, query string, args ...interface{}) error {
+	db.record(query, args)
+
+	return nil
+}
+
+func (db *queryRecorder) Select(_ interface{}, query string, args ...interface{}) error {
+	db.record(query, args)
+
+	return nil
+}
+
+func (db *queryRecorder) Prepare(string) (*sql.Stmt, error) { return nil, nil } //nolint:nilnil
+
+func (db *queryRecorder) Beginx() (*sqlx.Tx, error) { return nil, nil } //nolint:nilnil
+
+func (db *queryRecorder) DriverName() string { return db.driver }
+
+func TestLogSQLRepository_GetAllFiltered(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	yes := true
+
+	tests := []struct {
+		name       string
+		driver     string
+		filter     model.LogFilter
+		paging     model.Paging
+		wantSelect string
+		wantArgs   []interface{}
+	}{
+		{
+			name:       "without filters",
+			driver:     "mysql",
+			paging:     model.Paging{Limit: 10, Offset: 20},
+			wantSelect: "SELECT * FROM request_logs ORDER BY id DESC LIMIT ? OFFSET ?",
+			wantArgs:   []interface{}{int32(10), int32(20)},
+		},
+		{
+			name:   "mysql with every filter",
+			driver: "mysql",
+			filter: model.LogFilter{
+				Method: "post", URL: "100%_off", StatusFrom: 400, StatusTo: 499, From: from, RuleKey: "payments",
+				HasAssertionErrors: &yes,
+			},
+			paging: model.Paging{Limit: 10, LastID: "01HZ"},
+			wantSelect: "SELECT * FROM request_logs WHERE method = ? AND url LIKE ? ESCAPE ? AND response_status >= ? " +
+				"AND response_status <= ? AND timestamp >= ? AND rule_key = ? AND has_assertion_errors = ? " +
+				"AND id < ? ORDER BY id DESC LIMIT ?",
+			wantArgs: []interface{}{
+				"POST", `%100\%\_off%`, `\`, 400, 499, from, "payments", true, "01HZ", int32(10),
+			},
+		},
+		{
+			name:       "mysql regex",
+			driver:     "mysql",
+			filter:     model.LogFilter{URLRegex: "^/v1/"},
+			paging:     model.Paging{Limit: 10},
+			wantSelect: "SELECT * FROM request_logs WHERE url REGEXP ? ORDER BY id DESC LIMIT ? OFFSET ?",
+			wantArgs:   []interface{}{"^/v1/", int32(10), int32(0)},
+		},
+		{
+			name:       "bin requests",
+			driver:     "postgres",
+			filter:     model.LogFilter{Bin: "callbacks", Method: "post"},
+			paging:     model.Paging{Limit: 10},
+			wantSelect: "SELECT * FROM bin_requests WHERE bin = $1 AND method = $2 ORDER BY id DESC LIMIT $3 OFFSET $4",
+			wantArgs:   []interface{}{"callbacks", "POST", int32(10), int32(0)},
+		},
+		{
+			name:       "postgres regex",
+			driver:     "postgres",
+			filter:     model.LogFilter{URLRegex: "^/v1/", HasWebhookFailures: &yes},
+			paging:     model.Paging{Limit: 10},
+			wantSelect: "SELECT * FROM request_logs WHERE url ~ $1 AND has_webhook_failures = $2 ORDER BY id DESC LIMIT $3 OFFSET $4",
+			wantArgs:   []interface{}{"^/v1/", true, int32(10), int32(0)},
+		},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			db := &queryRecorder{driver: tt.driver}
+
+			_, err := repository.NewLogSQLRepository(db).GetAll(t.Context(), tt.filter, tt.paging)
+			if !assert.NoError(t, err) || !assert.Len(t, db.queries, 2) {
+				return
+			}
+
+			// The count shares the conditions of the select, without the two paging arguments.
+			assert.ElementsMatch(t, tt.wantArgs[:len(tt.wantArgs)-2], db.args[0])
+			assert.Equal(t, tt.wantSelect, db.queries[1])
+			assert.Equal(t, tt.wantArgs, db.args[1])
+		})
+	}
+}
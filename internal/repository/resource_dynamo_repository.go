@@ -0,0 +1,282 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/nicopozo/mockserver/internal/configs"
+	"github.com/nicopozo/mockserver/internal/model"
+	jsonutils "github.com/nicopozo/mockserver/internal/utils/json"
+)
+
+// DynamoResourceRepository stores the entities with the collection as partition key and the entity id as
+// sort key. Collections are small, so they are read whole and sorted by creation time when listed.
+type DynamoResourceRepository struct {
+	client          *dynamodb.Client
+	collectionTable string
+	entityTable     string
+}
+
+type resourceCollectionItem struct {
+	Name          string    `dynamodbav:"name"`
+	InitializedAt time.Time `dynamodbav:"initialized_at"`
+}
+
+type resourceEntityItem struct {
+	Collection string    `dynamodbav:"collection"`
+	ID         string    `dynamodbav:"id"`
+	Data       string    `dynamodbav:"data"`
+	CreatedAt  time.Time `dynamodbav:"created_at"`
+	UpdatedAt  time.Time `dynamodbav:"updated_at"`
+}
+
+// NewDynamoResourceRepository creates a new ResourceRepository for DynamoDB.
+func NewDynamoResourceRepository(client *dynamodb.Client, cfg *configs.Config) ResourceRepository {
+	return &DynamoResourceRepository{
+		client:          client,
+		collectionTable: cfg.Dynamo.TablePrefix + "resource_collections",
+		entityTable:     cfg.Dynamo.TablePrefix + "resource_entities",
+	}
+}
+
+func (r *DynamoResourceRepository) Initialized(ctx context.Context, collection string) (bool, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.collectionTable),
+		Key: map[string]types.AttributeValue{
+			"name": &types.AttributeValueMemberS{Value: collection},
+		},
+	})
+	if err != nil {
+		return false, fmt.Errorf("error getting resource collection from DynamoDB: %w", err)
+	}
+
+	return result.Item != nil, nil
+}
+
+func (r *DynamoResourceRepository) Reset(ctx context.Context, collection string,
+	entities []model.ResourceEntity,
+) error {
+	existing, err := r.query(ctx, collection)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range existing {
+		if err := r.deleteItem(ctx, collection, item.ID, false); err != nil {
+			return err
+		}
+	}
+
+	for _, entity := range entities {
+		if err := r.putItem(ctx, collection, entity, ""); err != nil {
+			return err
+		}
+	}
+
+	item, err := attributevalue.MarshalMap(resourceCollectionItem{Name: collection, InitializedAt: time.Now().UTC()})
+	if err != nil {
+		return fmt.Errorf("error marshaling resource collection: %w", err)
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.collectionTable),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("error saving resource collection in DynamoDB: %w", err)
+	}
+
+	return nil
+}
+
+func (r *DynamoResourceRepository) List(ctx context.Context, collection string,
+	paging model.Paging,
+) (*model.ResourceEntityList, error) {
+	items, err := r.query(ctx, collection)
+	if err != nil {
+		return nil, err
+	}
+
+	entities := make([]model.ResourceEntity, 0, len(items))
+	for _, item := range items {
+		entities = append(entities, item.toModel())
+	}
+
+	sort.SliceStable(entities, func(i, j int) bool {
+		if !entities[i].CreatedAt.Equal(entities[j].CreatedAt) {
+			return entities[i].CreatedAt.Before(entities[j].CreatedAt)
+		}
+
+		return entities[i].ID < entities[j].ID
+	})
+
+	return pageOf(entities, paging), nil
+}
+
+func (r *DynamoResourceRepository) Get(ctx context.Context, collection, id string) (*model.ResourceEntity, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(r.entityTable),
+		Key:            resourceEntityKey(collection, id),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting resource entity from DynamoDB: %w", err)
+	}
+
+	if result.Item == nil {
+		return nil, resourceEntityNotFound(id)
+	}
+
+	var item resourceEntityItem
+
+	if err := attributevalue.UnmarshalMap(result.Item, &item); err != nil {
+		return nil, fmt.Errorf("error unmarshaling resource entity: %w", err)
+	}
+
+	entity := item.toModel()
+
+	return &entity, nil
+}
+
+func (r *DynamoResourceRepository) Create(ctx context.Context, collection string,
+	entity model.ResourceEntity,
+) error {
+	return r.putItem(ctx, collection, entity, "attribute_not_exists(id)")
+}
+
+func (r *DynamoResourceRepository) Update(ctx context.Context, collection string,
+	entity model.ResourceEntity,
+) error {
+	return r.putItem(ctx, collection, entity, "attribute_exists(id)")
+}
+
+func (r *DynamoResourceRepository) Delete(ctx context.Context, collection, id string) error {
+	return r.deleteItem(ctx, collection, id, true)
+}
+
+// putItem saves the entity. condition, when set, guards the write; a failed condition means that the
+// entity already exists when creating, or does not exist when updating.
+func (r *DynamoResourceRepository) putItem(ctx context.Context, collection string, entity model.ResourceEntity,
+	condition string,
+) error {
+	item, err := attributevalue.MarshalMap(resourceEntityItem{
+		Collection: collection,
+		ID:         entity.ID,
+		Data:       jsonutils.Marshal(entity.Data),
+		CreatedAt:  entity.CreatedAt,
+		UpdatedAt:  entity.UpdatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("error marshaling resource entity: %w", err)
+	}
+
+	input := &dynamodb.PutItemInput{
+		TableName: aws.String(r.entityTable),
+		Item:      item,
+	}
+
+	if condition != "" {
+		input.ConditionExpression = aws.String(condition)
+	}
+
+	_, err = r.client.PutItem(ctx, input)
+
+	var conditionErr *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionErr) {
+		if strings.HasPrefix(condition, "attribute_not_exists") {
+			return resourceEntityConflict(entity.ID)
+		}
+
+		return resourceEntityNotFound(entity.ID)
+	}
+
+	if err != nil {
+		return fmt.Errorf("error saving resource entity in DynamoDB: %w", err)
+	}
+
+	return nil
+}
+
+func (r *DynamoResourceRepository) deleteItem(ctx context.Context, collection, id string, mustExist bool) error {
+	input := &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.entityTable),
+		Key:       resourceEntityKey(collection, id),
+	}
+
+	if mustExist {
+		input.ConditionExpression = aws.String("attribute_exists(id)")
+	}
+
+	_, err := r.client.DeleteItem(ctx, input)
+
+	var conditionErr *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionErr) {
+		return resourceEntityNotFound(id)
+	}
+
+	if err != nil {
+		return fmt.Errorf("error deleting resource entity from DynamoDB: %w", err)
+	}
+
+	return nil
+}
+
+func (r *DynamoResourceRepository) query(ctx context.Context, collection string) ([]resourceEntityItem, error) {
+	var items []resourceEntityItem
+
+	paginator := dynamodb.NewQueryPaginator(r.client, &dynamodb.QueryInput{
+		TableName:              aws.String(r.entityTable),
+		KeyConditionExpression: aws.String("#c = :collection"),
+		ExpressionAttributeNames: map[string]string{
+			"#c": "collection",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":collection": &types.AttributeValueMemberS{Value: collection},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error querying resource entities in DynamoDB: %w", err)
+		}
+
+		var pageItems []resourceEntityItem
+
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &pageItems); err != nil {
+			return nil, fmt.Errorf("error unmarshaling resource entities: %w", err)
+		}
+
+		items = append(items, pageItems...)
+	}
+
+	return items, nil
+}
+
+func resourceEntityKey(collection, id string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"collection": &types.AttributeValueMemberS{Value: collection},
+		"id":         &types.AttributeValueMemberS{Value: id},
+	}
+}
+
+func (item resourceEntityItem) toModel() model.ResourceEntity {
+	entity := model.ResourceEntity{
+		ID:        item.ID,
+		CreatedAt: item.CreatedAt,
+		UpdatedAt: item.UpdatedAt,
+	}
+
+	_ = jsonutils.Unmarshal(strings.NewReader(item.Data), &entity.Data)
+
+	return entity
+}
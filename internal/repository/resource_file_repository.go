@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/nicopozo/mockserver/internal/configs"
+	"github.com/nicopozo/mockserver/internal/model"
+	jsonutils "github.com/nicopozo/mockserver/internal/utils/json"
+)
+
+type resourceFileRepository struct {
+	mu          sync.RWMutex
+	collections map[string][]model.ResourceEntity
+	filePath    string
+}
+
+// NewResourceFileRepository creates a ResourceRepository that keeps the collections in cfg.ResourcesFile.
+func NewResourceFileRepository(cfg *configs.Config) (ResourceRepository, error) {
+	repo := &resourceFileRepository{
+		collections: make(map[string][]model.ResourceEntity),
+		filePath:    cfg.ResourcesFile,
+	}
+
+	file, err := os.Open(repo.filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return repo, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error reading resources file: %s - %w", repo.filePath, err)
+	}
+
+	defer func(f *os.File) { _ = f.Close() }(file)
+
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("error reading resources file: %s - %w", repo.filePath, err)
+	}
+
+	if stat.Size() > 0 {
+		if err := jsonutils.Unmarshal(file, &repo.collections); err != nil {
+			return nil, fmt.Errorf("error unmarshalling resources file: %s - %w", repo.filePath, err)
+		}
+	}
+
+	return repo, nil
+}
+
+func (repository *resourceFileRepository) Initialized(_ context.Context, collection string) (bool, error) {
+	repository.mu.RLock()
+	defer repository.mu.RUnlock()
+
+	_, found := repository.collections[collection]
+
+	return found, nil
+}
+
+func (repository *resourceFileRepository) Reset(_ context.Context, collection string,
+	entities []model.ResourceEntity,
+) error {
+	repository.mu.Lock()
+	defer repository.mu.Unlock()
+
+	repository.collections[collection] = append(make([]model.ResourceEntity, 0, len(entities)), entities...)
+
+	return repository.saveFile()
+}
+
+func (repository *resourceFileRepository) List(_ context.Context, collection string,
+	paging model.Paging,
+) (*model.ResourceEntityList, error) {
+	repository.mu.RLock()
+	defer repository.mu.RUnlock()
+
+	return pageOf(repository.collections[collection], paging), nil
+}
+
+func (repository *resourceFileRepository) Get(_ context.Context, collection, id string) (*model.ResourceEntity,
+	error,
+) {
+	repository.mu.RLock()
+	defer repository.mu.RUnlock()
+
+	index := repository.indexOf(collection, id)
+	if index < 0 {
+		return nil, resourceEntityNotFound(id)
+	}
+
+	entity := repository.collections[collection][index]
+
+	return &entity, nil
+}
+
+func (repository *resourceFileRepository) Create(_ context.Context, collection string,
+	entity model.ResourceEntity,
+) error {
+	repository.mu.Lock()
+	defer repository.mu.Unlock()
+
+	if repository.indexOf(collection, entity.ID) >= 0 {
+		return resourceEntityConflict(entity.ID)
+	}
+
+	repository.collections[collection] = append(repository.collections[collection], entity)
+
+	return repository.saveFile()
+}
+
+func (repository *resourceFileRepository) Update(_ context.Context, collection string,
+	entity model.ResourceEntity,
+) error {
+	repository.mu.Lock()
+	defer repository.mu.Unlock()
+
+	index := repository.indexOf(collection, entity.ID)
+	if index < 0 {
+		return resourceEntityNotFound(entity.ID)
+	}
+
+	repository.collections[collection][index] = entity
+
+	return repository.saveFile()
+}
+
+func (repository *resourceFileRepository) Delete(_ context.Context, collection, id string) error {
+	repository.mu.Lock()
+	defer repository.mu.Unlock()
+
+	index := repository.indexOf(collection, id)
+	if index < 0 {
+		return resourceEntityNotFound(id)
+	}
+
+	entities := repository.collections[collection]
+	repository.collections[collection] = append(entities[:index], entities[index+1:]...)
+
+	return repository.saveFile()
+}
+
+func (repository *resourceFileRepository) indexOf(collection, id string) int {
+	for index, entity := range repository.collections[collection] {
+		if entity.ID == id {
+			return index
+		}
+	}
+
+	return -1
+}
+
+func (repository *resourceFileRepository) saveFile() error {
+	err := os.WriteFile(repository.filePath, []byte(jsonutils.Marshal(repository.collections)), 0o644) //nolint:gosec,mnd
+	if err != nil {
+		return fmt.Errorf("error saving resources file: %s - %w", repository.filePath, err)
+	}
+
+	return nil
+}
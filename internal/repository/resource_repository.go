@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
+	"github.com/nicopozo/mockserver/internal/model"
+)
+
+// ResourceRepository stores the entities of the resource rules, one collection per rule. Entities are
+// listed in creation order.
+type ResourceRepository interface {
+	// Initialized reports whether the collection has been seeded.
+	Initialized(ctx context.Context, collection string) (bool, error)
+	// Reset replaces the entities of the collection with entities and marks it as initialized.
+	Reset(ctx context.Context, collection string, entities []model.ResourceEntity) error
+	List(ctx context.Context, collection string, paging model.Paging) (*model.ResourceEntityList, error)
+	Get(ctx context.Context, collection, id string) (*model.ResourceEntity, error)
+	Create(ctx context.Context, collection string, entity model.ResourceEntity) error
+	Update(ctx context.Context, collection string, entity model.ResourceEntity) error
+	Delete(ctx context.Context, collection, id string) error
+}
+
+func resourceEntityNotFound(id string) error {
+	return mockserrors.ResourceEntityNotFoundError{Message: "no entity found with id: " + id}
+}
+
+func resourceEntityConflict(id string) error {
+	return mockserrors.ResourceEntityConflictError{Message: "an entity with id " + id + " already exists"}
+}
+
+// pageOf returns the entities of the page, and the paging with its total set.
+func pageOf(entities []model.ResourceEntity, paging model.Paging) *model.ResourceEntityList {
+	paging.Total = int64(len(entities))
+
+	start := min(int(paging.Offset), len(entities))
+	end := min(start+int(paging.Limit), len(entities))
+
+	results := make([]model.ResourceEntity, end-start)
+	copy(results, entities[start:end])
+
+	return &model.ResourceEntityList{Paging: paging, Results: results}
+}
@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	mockscontext "github.com/nicopozo/mockserver/internal/context"
+	"github.com/nicopozo/mockserver/internal/model"
+	jsonutils "github.com/nicopozo/mockserver/internal/utils/json"
+)
+
+type resourceSQLRepository struct {
+	db Database
+}
+
+type ResourceEntityRow struct {
+	Collection string    `db:"collection"`
+	ID         string    `db:"id"`
+	Data       string    `db:"data"`
+	CreatedAt  time.Time `db:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at"`
+}
+
+func (row ResourceEntityRow) toModel() model.ResourceEntity {
+	entity := model.ResourceEntity{
+		ID:        row.ID,
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}
+
+	_ = jsonutils.Unmarshal(strings.NewReader(row.Data), &entity.Data)
+
+	return entity
+}
+
+func NewResourceSQLRepository(db Database) ResourceRepository {
+	return &resourceSQLRepository{
+		db: db,
+	}
+}
+
+func (r *resourceSQLRepository) Initialized(_ context.Context, collection string) (bool, error) {
+	var name string
+
+	query := FormatQuery("SELECT name FROM resource_collections WHERE name = ?", r.db.DriverName())
+
+	err := r.db.Get(&name, query, collection)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("error fetching resource collection from DB: %w", err)
+	}
+
+	return true, nil
+}
+
+func (r *resourceSQLRepository) Reset(ctx context.Context, collection string,
+	entities []model.ResourceEntity,
+) error {
+	var err error
+
+	trx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting transaction, %w", err)
+	}
+
+	defer func() {
+		r.commitOrRollback(ctx, trx, err)
+	}()
+
+	driver := r.db.DriverName()
+
+	if _, err = trx.ExecContext(ctx, FormatQuery("DELETE FROM resource_entities WHERE collection = ?", driver),
+		collection); err != nil {
+		return fmt.Errorf("error deleting resource entities from DB: %w", err)
+	}
+
+	if _, err = trx.ExecContext(ctx, FormatQuery("DELETE FROM resource_collections WHERE name = ?", driver),
+		collection); err != nil {
+		return fmt.Errorf("error deleting resource collection from DB: %w", err)
+	}
+
+	if _, err = trx.ExecContext(ctx, FormatQuery(
+		"INSERT INTO resource_collections (name, initialized_at) VALUES (?, ?)", driver),
+		collection, time.Now().UTC()); err != nil {
+		return fmt.Errorf("error inserting resource collection into DB: %w", err)
+	}
+
+	insert := FormatQuery("INSERT INTO resource_entities (collection, id, data, created_at, updated_at) "+
+		"VALUES (?, ?, ?, ?, ?)", driver)
+
+	for _, entity := range entities {
+		if _, err = trx.ExecContext(ctx, insert, collection, entity.ID, jsonutils.Marshal(entity.Data),
+			entity.CreatedAt, entity.UpdatedAt); err != nil {
+			return fmt.Errorf("error inserting resource entity into DB: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (r *resourceSQLRepository) List(_ context.Context, collection string,
+	paging model.Paging,
+) (*model.ResourceEntityList, error) {
+	var total int64
+
+	driver := r.db.DriverName()
+
+	if err := r.db.Get(&total, FormatQuery("SELECT COUNT(*) FROM resource_entities WHERE collection = ?", driver),
+		collection); err != nil {
+		return nil, fmt.Errorf("error counting resource entities in DB: %w", err)
+	}
+
+	var rows []ResourceEntityRow
+
+	query := FormatQuery("SELECT * FROM resource_entities WHERE collection = ? "+
+		"ORDER BY created_at, id LIMIT ? OFFSET ?", driver)
+
+	if err := r.db.Select(&rows, query, collection, paging.Limit, paging.Offset); err != nil {
+		return nil, fmt.Errorf("error fetching resource entities from DB: %w", err)
+	}
+
+	results := make([]model.ResourceEntity, 0, len(rows))
+	for _, row := range rows {
+		results = append(results, row.toModel())
+	}
+
+	paging.Total = total
+
+	return &model.ResourceEntityList{Paging: paging, Results: results}, nil
+}
+
+func (r *resourceSQLRepository) Get(_ context.Context, collection, id string) (*model.ResourceEntity, error) {
+	var row ResourceEntityRow
+
+	query := FormatQuery("SELECT * FROM resource_entities WHERE collection = ? AND id = ?", r.db.DriverName())
+
+	err := r.db.Get(&row, query, collection, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, resourceEntityNotFound(id)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error fetching resource entity from DB: %w", err)
+	}
+
+	entity := row.toModel()
+
+	return &entity, nil
+}
+
+func (r *resourceSQLRepository) Create(ctx context.Context, collection string, entity model.ResourceEntity) error {
+	if _, err := r.Get(ctx, collection, entity.ID); err == nil {
+		return resourceEntityConflict(entity.ID)
+	}
+
+	query := FormatQuery("INSERT INTO resource_entities (collection, id, data, created_at, updated_at) "+
+		"VALUES (?, ?, ?, ?, ?)", r.db.DriverName())
+
+	_, err := r.db.Exec(query, collection, entity.ID, jsonutils.Marshal(entity.Data), entity.CreatedAt,
+		entity.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("error inserting resource entity into DB: %w", err)
+	}
+
+	return nil
+}
+
+func (r *resourceSQLRepository) Update(_ context.Context, collection string, entity model.ResourceEntity) error {
+	query := FormatQuery("UPDATE resource_entities SET data = ?, updated_at = ? WHERE collection = ? AND id = ?",
+		r.db.DriverName())
+
+	result, err := r.db.Exec(query, jsonutils.Marshal(entity.Data), entity.UpdatedAt, collection, entity.ID)
+	if err != nil {
+		return fmt.Errorf("error updating resource entity in DB: %w", err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return resourceEntityNotFound(entity.ID)
+	}
+
+	return nil
+}
+
+func (r *resourceSQLRepository) Delete(_ context.Context, collection, id string) error {
+	query := FormatQuery("DELETE FROM resource_entities WHERE collection = ? AND id = ?", r.db.DriverName())
+
+	result, err := r.db.Exec(query, collection, id)
+	if err != nil {
+		return fmt.Errorf("error deleting resource entity from DB: %w", err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return resourceEntityNotFound(id)
+	}
+
+	return nil
+}
+
+func (r *resourceSQLRepository) commitOrRollback(ctx context.Context, trx *sqlx.Tx, err error) {
+	if err != nil {
+		_ = trx.Rollback()
+
+		return
+	}
+
+	if err := trx.Commit(); err != nil {
+		mockscontext.Logger(ctx).Error(r, nil, err, "Error committing changes")
+
+		_ = trx.Rollback()
+	}
+}
@@ -2,8 +2,9 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -14,6 +15,7 @@ import (
 	mockscontext "github.com/nicopozo/mockserver/internal/context"
 	mockserrors "github.com/nicopozo/mockserver/internal/errors"
 	"github.com/nicopozo/mockserver/internal/model"
+	jsonutils "github.com/nicopozo/mockserver/internal/utils/json"
 	"github.com/oklog/ulid/v2"
 )
 
@@ -127,6 +129,12 @@ func (r *DynamoRuleRepository) Search(
 	}
 
 	paging.Total = int64(result.ScannedCount)
+	paging.LastID = ""
+
+	// The scan stops at the limit or at 1 MB of data, only LastEvaluatedKey tells whether rules remain.
+	if lastKey, ok := result.LastEvaluatedKey["key"].(*types.AttributeValueMemberS); ok {
+		paging.LastID = lastKey.Value
+	}
 
 	return &model.RuleList{
 		Results: rules,
@@ -193,43 +201,58 @@ func (r *DynamoRuleRepository) SearchByMethodAndPath(
 	ctx context.Context,
 	method string,
 	path string,
-) (*model.Rule, error) {
-	input := &dynamodb.QueryInput{
-		TableName:              aws.String(r.tableName),
-		IndexName:              aws.String("method-index"),
-		KeyConditionExpression: aws.String("#m = :method"),
-		ExpressionAttributeNames: map[string]string{
-			"#m": "method",
-		},
-		ExpressionAttributeValues: map[string]types.AttributeValue{
-			":method": &types.AttributeValueMemberS{Value: strings.ToUpper(method)},
-		},
-	}
-
-	result, err := r.client.Query(ctx, input)
-	if err != nil {
-		return nil, fmt.Errorf("error querying rules by method: %w", err)
-	}
-
-	for _, itemAV := range result.Items {
-		var item ruleItem
-		if err := attributevalue.UnmarshalMap(itemAV, &item); err != nil {
-			continue
+) ([]*model.Rule, error) {
+	method = strings.ToUpper(method)
+	candidates := make([]*model.Rule, 0)
+
+	// Resource rules are stored with the ANY method and answer to the routes of their collection.
+	for _, storedMethod := range []string{method, model.RuleMethodAny} {
+		input := &dynamodb.QueryInput{
+			TableName:              aws.String(r.tableName),
+			IndexName:              aws.String("method-index"),
+			KeyConditionExpression: aws.String("#m = :method"),
+			ExpressionAttributeNames: map[string]string{
+				"#m": "method",
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":method": &types.AttributeValueMemberS{Value: storedMethod},
+			},
 		}
 
-		rule, ok, err := r.matchItem(&item, path)
+		result, err := r.client.Query(ctx, input)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("error querying rules by method: %w", err)
 		}
 
-		if ok {
-			return rule, nil
+		for _, itemAV := range result.Items {
+			var item ruleItem
+			if err := attributevalue.UnmarshalMap(itemAV, &item); err != nil {
+				continue
+			}
+
+			if storedMethod == model.RuleMethodAny {
+				if rule := toRuleModel(&item); rule.Status == model.RuleStatusEnabled &&
+					matchesRoute(rule, method, path) {
+					candidates = append(candidates, rule)
+				}
+
+				continue
+			}
+
+			rule, ok, err := r.matchItem(&item, path)
+			if err != nil {
+				return nil, err
+			}
+
+			if ok {
+				candidates = append(candidates, rule)
+			}
 		}
 	}
 
-	return nil, mockserrors.RuleNotFoundError{
-		Message: fmt.Sprintf("no rule found for path: %s and method %s", path, method),
-	}
+	SortByPrecedence(candidates)
+
+	return candidates, nil
 }
 
 func (r *DynamoRuleRepository) matchItem(item *ruleItem, path string) (*model.Rule, bool, error) {
@@ -238,9 +261,9 @@ func (r *DynamoRuleRepository) matchItem(item *ruleItem, path string) (*model.Ru
 		pattern = CreateExpression(item.Path)
 	}
 
-	regex, err := regexp.Compile(pattern)
+	regex, err := rulePatterns.compile(pattern)
 	if err != nil {
-		return nil, false, fmt.Errorf("invalid regex pattern %s: %w", pattern, err)
+		return nil, false, err
 	}
 
 	if regex.MatchString(path) {
@@ -252,6 +275,68 @@ func (r *DynamoRuleRepository) matchItem(item *ruleItem, path string) (*model.Ru
 	return nil, false, nil
 }
 
+// AdvanceResponse moves the next response index with a conditional update, so that instances sharing the
+// table do not serve the same turn. An update that finds the index already moved reads it again.
+func (r *DynamoRuleRepository) AdvanceResponse(ctx context.Context, key string, responses int) (int, error) {
+	itemKey := map[string]types.AttributeValue{
+		"key": &types.AttributeValueMemberS{Value: key},
+	}
+
+	for {
+		result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+			TableName:            aws.String(r.tableName),
+			Key:                  itemKey,
+			ProjectionExpression: aws.String("next_response_index"),
+			ConsistentRead:       aws.Bool(true),
+		})
+		if err != nil {
+			return 0, fmt.Errorf("error getting rule from DynamoDB: %w", err)
+		}
+
+		if result.Item == nil {
+			return 0, mockserrors.RuleNotFoundError{
+				Message: fmt.Sprintf("no rule found with key: %s", key),
+			}
+		}
+
+		var item ruleItem
+
+		if err := attributevalue.UnmarshalMap(result.Item, &item); err != nil {
+			return 0, fmt.Errorf("error unmarshaling rule: %w", err)
+		}
+
+		current := responseTurn(item.NextResponseIndex, responses)
+
+		condition := "next_response_index = :current"
+		if item.NextResponseIndex == 0 {
+			condition = "(attribute_not_exists(next_response_index) OR " + condition + ")"
+		}
+
+		_, err = r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+			TableName:                aws.String(r.tableName),
+			Key:                      itemKey,
+			UpdateExpression:         aws.String("SET next_response_index = :next"),
+			ConditionExpression:      aws.String("attribute_exists(#key) AND " + condition),
+			ExpressionAttributeNames: map[string]string{"#key": "key"},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":next":    &types.AttributeValueMemberN{Value: strconv.Itoa(current + 1)},
+				":current": &types.AttributeValueMemberN{Value: strconv.Itoa(item.NextResponseIndex)},
+			},
+		})
+
+		var conditionFailed *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionFailed) {
+			continue
+		}
+
+		if err != nil {
+			return 0, fmt.Errorf("error updating next response index in DynamoDB: %w", err)
+		}
+
+		return current, nil
+	}
+}
+
 func (r *DynamoRuleRepository) Delete(ctx context.Context, key string) error {
 	logger := mockscontext.Logger(ctx)
 
@@ -282,18 +367,55 @@ type ruleItem struct {
 	Strategy   string         `dynamodbav:"strategy"`
 	Method     string         `dynamodbav:"method"`
 	Status     string         `dynamodbav:"status"`
+	Priority   int            `dynamodbav:"priority"`
+	Matchers   []matcherItem  `dynamodbav:"matchers,omitempty"`
+	Scenario   *scenarioItem  `dynamodbav:"scenario,omitempty"`
+	Resource   string         `dynamodbav:"resource,omitempty"`
+	Chaos      string         `dynamodbav:"chaos,omitempty"`
 	Responses  []responseItem `dynamodbav:"responses"`
 	Variables  []variableItem `dynamodbav:"variables"`
 	Pattern    string         `dynamodbav:"pattern"`
+	// NextResponseIndex is the next response of a sequential rule, rules saved without it are at the first one.
+	NextResponseIndex int `dynamodbav:"next_response_index,omitempty"`
 }
 
 type responseItem struct {
-	Body        string `dynamodbav:"body"`
-	ContentType string `dynamodbav:"content_type"`
-	HTTPStatus  int    `dynamodbav:"http_status"`
-	Delay       int    `dynamodbav:"delay"`
-	Scene       string `dynamodbav:"scene"`
-	Description string `dynamodbav:"description"`
+	Body        string            `dynamodbav:"body"`
+	ContentType string            `dynamodbav:"content_type"`
+	HTTPStatus  int               `dynamodbav:"http_status"`
+	Delay       int               `dynamodbav:"delay"`
+	Scene       string            `dynamodbav:"scene"`
+	Description string            `dynamodbav:"description"`
+	Headers     map[string]string `dynamodbav:"headers,omitempty"`
+	Cookies     []cookieItem      `dynamodbav:"cookies,omitempty"`
+	Webhook     string            `dynamodbav:"webhook,omitempty"`
+	Webhooks    string            `dynamodbav:"webhooks,omitempty"`
+	WebhookMode string            `dynamodbav:"webhook_mode,omitempty"`
+	Fault       string            `dynamodbav:"fault,omitempty"`
+}
+
+type cookieItem struct {
+	Name     string `dynamodbav:"name"`
+	Value    string `dynamodbav:"value"`
+	Path     string `dynamodbav:"path,omitempty"`
+	Domain   string `dynamodbav:"domain,omitempty"`
+	MaxAge   int    `dynamodbav:"max_age,omitempty"`
+	Secure   bool   `dynamodbav:"secure,omitempty"`
+	HTTPOnly bool   `dynamodbav:"http_only,omitempty"`
+	SameSite string `dynamodbav:"same_site,omitempty"`
+}
+
+type matcherItem struct {
+	Type     string `dynamodbav:"type"`
+	Key      string `dynamodbav:"key"`
+	Operator string `dynamodbav:"operator"`
+	Value    string `dynamodbav:"value,omitempty"`
+}
+
+type scenarioItem struct {
+	Name          string `dynamodbav:"name"`
+	RequiredState string `dynamodbav:"required_state,omitempty"`
+	NewState      string `dynamodbav:"new_state,omitempty"`
 }
 
 type variableItem struct {
@@ -325,6 +447,12 @@ func toRuleItem(rule *model.Rule) *ruleItem {
 			Delay:       resp.Delay,
 			Scene:       resp.Scene,
 			Description: resp.Description,
+			Headers:     resp.Headers,
+			Cookies:     toCookieItems(resp.Cookies),
+			Webhook:     toWebhookItem(resp.Webhook),
+			Webhooks:    toWebhooksItem(resp.Webhooks),
+			WebhookMode: resp.WebhookMode,
+			Fault:       resp.Fault,
 		})
 	}
 
@@ -353,18 +481,24 @@ func toRuleItem(rule *model.Rule) *ruleItem {
 	}
 
 	return &ruleItem{
-		Key:        rule.Key,
-		Group:      rule.Group,
-		GroupLower: strings.ToLower(rule.Group),
-		Name:       rule.Name,
-		NameLower:  strings.ToLower(rule.Name),
-		Path:       rule.Path,
-		PathLower:  strings.ToLower(rule.Path),
-		Strategy:   rule.Strategy,
-		Method:     rule.Method,
-		Status:     rule.Status,
-		Responses:  responses,
-		Variables:  variables,
+		Key:               rule.Key,
+		Group:             rule.Group,
+		GroupLower:        strings.ToLower(rule.Group),
+		Name:              rule.Name,
+		NameLower:         strings.ToLower(rule.Name),
+		Path:              rule.Path,
+		PathLower:         strings.ToLower(rule.Path),
+		Strategy:          rule.Strategy,
+		Method:            rule.Method,
+		Status:            rule.Status,
+		Priority:          rule.Priority,
+		Matchers:          toMatcherItems(rule.Matchers),
+		NextResponseIndex: rule.NextResponseIndex,
+		Scenario:          (*scenarioItem)(rule.Scenario),
+		Resource:          toResourceItem(rule.Resource),
+		Chaos:             toChaosItem(rule.Chaos),
+		Responses:         responses,
+		Variables:         variables,
 	}
 }
 
@@ -378,6 +512,12 @@ func toRuleModel(item *ruleItem) *model.Rule {
 			Delay:       resp.Delay,
 			Scene:       resp.Scene,
 			Description: resp.Description,
+			Headers:     resp.Headers,
+			Cookies:     toCookieModels(resp.Cookies),
+			Webhook:     toWebhookModel(resp.Webhook),
+			Webhooks:    toWebhooksModel(resp.Webhooks),
+			WebhookMode: resp.WebhookMode,
+			Fault:       resp.Fault,
 		})
 	}
 
@@ -406,14 +546,163 @@ func toRuleModel(item *ruleItem) *model.Rule {
 	}
 
 	return &model.Rule{
-		Key:       item.Key,
-		Group:     item.Group,
-		Name:      item.Name,
-		Path:      item.Path,
-		Strategy:  item.Strategy,
-		Method:    item.Method,
-		Status:    item.Status,
-		Responses: responses,
-		Variables: variables,
+		Key:               item.Key,
+		Group:             item.Group,
+		Name:              item.Name,
+		Path:              item.Path,
+		Strategy:          item.Strategy,
+		Method:            item.Method,
+		Status:            item.Status,
+		Priority:          item.Priority,
+		NextResponseIndex: item.NextResponseIndex,
+		Matchers:          toMatcherModels(item.Matchers),
+		Scenario:          (*model.RuleScenario)(item.Scenario),
+		Resource:          toResourceModel(item.Resource),
+		Chaos:             toChaosModel(item.Chaos),
+		Responses:         responses,
+		Variables:         variables,
+	}
+}
+
+func toResourceItem(resource *model.ResourceConfig) string {
+	if resource == nil {
+		return ""
 	}
+
+	return jsonutils.Marshal(resource)
+}
+
+// toResourceModel parses the resource configuration, which is stored as JSON because seed entities and
+// schemas are free-form documents.
+func toResourceModel(resource string) *model.ResourceConfig {
+	if resource == "" {
+		return nil
+	}
+
+	config := &model.ResourceConfig{}
+
+	if err := jsonutils.Unmarshal(strings.NewReader(resource), config); err != nil {
+		return nil
+	}
+
+	return config
+}
+
+// toChaosItem stores the chaos policy as JSON, like the resource configuration.
+func toChaosItem(chaos *model.ChaosPolicy) string {
+	if chaos == nil {
+		return ""
+	}
+
+	return jsonutils.Marshal(chaos)
+}
+
+func toChaosModel(chaos string) *model.ChaosPolicy {
+	if chaos == "" {
+		return nil
+	}
+
+	policy := &model.ChaosPolicy{}
+
+	if err := jsonutils.Unmarshal(strings.NewReader(chaos), policy); err != nil {
+		return nil
+	}
+
+	return policy
+}
+
+func toWebhookItem(webhook *model.WebhookConfig) string {
+	if webhook == nil {
+		return ""
+	}
+
+	return jsonutils.Marshal(webhook)
+}
+
+func toWebhookModel(webhook string) *model.WebhookConfig {
+	if webhook == "" {
+		return nil
+	}
+
+	config := &model.WebhookConfig{}
+
+	if err := jsonutils.Unmarshal(strings.NewReader(webhook), config); err != nil {
+		return nil
+	}
+
+	return config
+}
+
+func toWebhooksItem(webhooks []model.WebhookConfig) string {
+	if len(webhooks) == 0 {
+		return ""
+	}
+
+	return jsonutils.Marshal(webhooks)
+}
+
+func toWebhooksModel(webhooks string) []model.WebhookConfig {
+	if webhooks == "" {
+		return nil
+	}
+
+	var configs []model.WebhookConfig
+
+	if err := jsonutils.Unmarshal(strings.NewReader(webhooks), &configs); err != nil {
+		return nil
+	}
+
+	return configs
+}
+
+func toCookieItems(cookies []model.Cookie) []cookieItem {
+	if len(cookies) == 0 {
+		return nil
+	}
+
+	items := make([]cookieItem, 0, len(cookies))
+	for _, cookie := range cookies {
+		items = append(items, cookieItem(cookie))
+	}
+
+	return items
+}
+
+func toCookieModels(items []cookieItem) []model.Cookie {
+	if len(items) == 0 {
+		return nil
+	}
+
+	cookies := make([]model.Cookie, 0, len(items))
+	for _, item := range items {
+		cookies = append(cookies, model.Cookie(item))
+	}
+
+	return cookies
+}
+
+func toMatcherItems(matchers []model.Matcher) []matcherItem {
+	if len(matchers) == 0 {
+		return nil
+	}
+
+	items := make([]matcherItem, 0, len(matchers))
+	for _, matcher := range matchers {
+		items = append(items, matcherItem(matcher))
+	}
+
+	return items
+}
+
+func toMatcherModels(items []matcherItem) []model.Matcher {
+	if len(items) == 0 {
+		return nil
+	}
+
+	matchers := make([]model.Matcher, 0, len(items))
+	for _, item := range items {
+		matchers = append(matchers, model.Matcher(item))
+	}
+
+	return matchers
 }
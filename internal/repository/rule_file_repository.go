@@ -1,30 +1,51 @@
 package repository
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"os"
-	"regexp"
+	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/nicopozo/mockserver/internal/configs"
 	mockscontext "github.com/nicopozo/mockserver/internal/context"
 	mockserrors "github.com/nicopozo/mockserver/internal/errors"
 	"github.com/nicopozo/mockserver/internal/model"
 	jsonutils "github.com/nicopozo/mockserver/internal/utils/json"
+	yamlutils "github.com/nicopozo/mockserver/internal/utils/yaml"
 	"github.com/oklog/ulid/v2"
 )
 
+// directoryRulesFile is the file of a rules directory that keeps the rules created through the API.
+const directoryRulesFile = "mocks.json"
+
+// ruleFileRepository keeps the rules in memory and persists them as JSON, or YAML, in cfg.MocksFile.
+// Every change is written to a temporary file that is synced and renamed over the mocks file, so a crash
+// never leaves it half written, and the previous version is kept in "<file>.bak".
+//
+// When cfg.MocksFile is a directory, every *.json, *.yaml and *.yml file in it holds a rule or an array
+// of rules. Changes are written back to the file the rule was read from, new rules go to mocks.json.
 type ruleFileRepository struct {
+	mu       sync.RWMutex
 	rules    []fileRule
 	filePath string
+	isDir    bool
+	// version identifies the files as they were last read or written, to detect the changes made by hand.
+	version string
 }
 
 type fileRule struct {
 	model.Rule
-	NextResponseIndex int `json:"next_response_index"`
+	NextResponseIndex int `json:"next_response_index,omitempty"`
+	// source is the file that holds the rule.
+	source string
 }
 
 func NewRuleFileRepository(cfg *configs.Config) (RuleRepository, error) {
@@ -35,41 +56,47 @@ func NewRuleFileRepository(cfg *configs.Config) (RuleRepository, error) {
 		filePath: filePath,
 	}
 
-	file, err := os.Open(filePath)
+	if stat, err := os.Stat(filePath); err == nil && stat.IsDir() {
+		repo.isDir = true
+	}
+
+	version, err := repo.sourceVersion()
 	if err != nil {
-		errMsg := err.Error()
-		if !strings.Contains(errMsg, "no such file or directory") {
-			return nil, fmt.Errorf("error creating file repository when reading file: %s - %w", filePath, err)
-		}
+		return nil, fmt.Errorf("error creating file repository: %w", err)
+	}
 
-		err = repo.SaveFile()
-		if err != nil {
+	rules, err := repo.readSource()
+	if errors.Is(err, os.ErrNotExist) && !repo.isDir {
+		if err := repo.saveFile(repo.rules, filePath); err != nil {
 			return nil, fmt.Errorf("error creating file repository when creating file: %s - %w", filePath, err)
 		}
 
-		file, err = os.Open(filePath)
+		return &repo, nil
+	}
+
+	if !repo.isDir && isTornFile(filePath) {
+		// A mocks file left empty or cut short by an interrupted write is restored from the copy kept before
+		// the last change. Any other error, like a typo made by hand, is reported instead.
+		rules, err = readRulesFrom(backupPath(filePath), filePath)
 		if err != nil {
 			return nil, fmt.Errorf("error creating file repository when reading file: %s - %w", filePath, err)
 		}
-	}
 
-	defer func(f *os.File) { _ = f.Close() }(file)
+		if version, err = repo.restoreFile(rules); err != nil {
+			return nil, fmt.Errorf("error creating file repository when restoring file: %s - %w", filePath, err)
+		}
+	}
 
-	repo.rules = make([]fileRule, 0)
+	if err != nil && !repo.isDir {
+		return nil, fmt.Errorf("error creating file repository when reading file: %s - %w", filePath, err)
+	}
 
-	stat, err := file.Stat()
 	if err != nil {
-		return nil, fmt.Errorf("error reading file: %s - %w", filePath, err)
+		return nil, fmt.Errorf("error creating file repository when reading directory: %s - %w", filePath, err)
 	}
 
-	if stat.Size() > 0 {
-		rules, err := unmarshalRules(file)
-		if err != nil {
-			return nil, fmt.Errorf("error creating file repository when unmarshaling file: %s - %w", filePath, err)
-		}
-
-		repo.rules = rules
-	}
+	repo.rules = rules
+	repo.version = version
 
 	return &repo, nil
 }
@@ -79,16 +106,32 @@ func (repository *ruleFileRepository) Create(ctx context.Context, rule *model.Ru
 
 	logger.Debug(repository, nil, "Saving new rule into file")
 
-	rule.Key = ulid.Make().String()
+	repository.mu.Lock()
+	defer repository.mu.Unlock()
+
+	if rule.Key == "" {
+		rule.Key = ulid.Make().String()
+	}
 
 	fRule := fileRule{
 		Rule:              *rule,
 		NextResponseIndex: rule.NextResponseIndex,
+		source:            repository.filePath,
+	}
+
+	if repository.isDir {
+		fRule.source = filepath.Join(repository.filePath, directoryRulesFile)
 	}
 
-	repository.rules = append(repository.rules, fRule)
+	rules := append(slices.Clone(repository.rules), fRule)
 
-	return rule, repository.SaveFile()
+	if err := repository.saveFile(rules, fRule.source); err != nil {
+		return nil, err
+	}
+
+	repository.rules = rules
+
+	return rule, nil
 }
 
 func (repository *ruleFileRepository) Update(ctx context.Context, rule *model.Rule) (*model.Rule, error) {
@@ -96,27 +139,61 @@ func (repository *ruleFileRepository) Update(ctx context.Context, rule *model.Ru
 
 	logger.Debug(repository, nil, "Updating rule.")
 
-	found := false
-
-	for index := range repository.rules {
-		if repository.rules[index].Key == rule.Key {
-			repository.rules[index] = fileRule{
-				Rule:              *rule,
-				NextResponseIndex: rule.NextResponseIndex,
-			}
-			found = true
+	repository.mu.Lock()
+	defer repository.mu.Unlock()
 
-			break
+	index := slices.IndexFunc(repository.rules, func(fRule fileRule) bool { return fRule.Key == rule.Key })
+	if index == -1 {
+		return nil, mockserrors.RuleNotFoundError{
+			Message: fmt.Sprintf("no rule found with key: %s", rule.Key),
 		}
 	}
 
-	if !found {
-		return nil, mockserrors.RuleNotFoundError{
-			Message: fmt.Sprintf("no rule found with key: %s", rule.Key),
+	rules := slices.Clone(repository.rules)
+	rules[index] = fileRule{
+		Rule:              *rule,
+		NextResponseIndex: rule.NextResponseIndex,
+		source:            rules[index].source,
+	}
+
+	if err := repository.saveFile(rules, rules[index].source); err != nil {
+		return nil, err
+	}
+
+	repository.rules = rules
+
+	return rule, nil
+}
+
+func (repository *ruleFileRepository) AdvanceResponse(ctx context.Context, key string,
+	responses int,
+) (int, error) {
+	logger := mockscontext.Logger(ctx)
+
+	logger.Debug(repository, nil, "Advancing rule response.")
+
+	repository.mu.Lock()
+	defer repository.mu.Unlock()
+
+	index := slices.IndexFunc(repository.rules, func(fRule fileRule) bool { return fRule.Key == key })
+	if index == -1 {
+		return 0, mockserrors.RuleNotFoundError{
+			Message: fmt.Sprintf("no rule found with key: %s", key),
 		}
 	}
 
-	return rule, repository.SaveFile()
+	current := responseTurn(repository.rules[index].NextResponseIndex, responses)
+
+	rules := slices.Clone(repository.rules)
+	rules[index].NextResponseIndex = current + 1
+
+	if err := repository.saveFile(rules, rules[index].source); err != nil {
+		return 0, err
+	}
+
+	repository.rules = rules
+
+	return current, nil
 }
 
 func (repository *ruleFileRepository) Get(ctx context.Context, key string) (*model.Rule, error) {
@@ -124,12 +201,12 @@ func (repository *ruleFileRepository) Get(ctx context.Context, key string) (*mod
 
 	logger.Debug(repository, nil, "Updating rule.")
 
-	for index := range repository.rules {
-		if repository.rules[index].Key == key {
-			result := &repository.rules[index].Rule
-			result.NextResponseIndex = repository.rules[index].NextResponseIndex
+	repository.mu.RLock()
+	defer repository.mu.RUnlock()
 
-			return result, nil
+	for _, fRule := range repository.rules {
+		if fRule.Key == key {
+			return fRule.toRule(), nil
 		}
 	}
 
@@ -156,15 +233,16 @@ func (repository *ruleFileRepository) Search(ctx context.Context, params map[str
 
 	filtered := make([]*model.Rule, 0)
 
-	for index := range repository.rules {
-		if applies(repository.rules[index].Rule, params) {
-			result := &repository.rules[index].Rule
-			result.NextResponseIndex = repository.rules[index].NextResponseIndex
+	repository.mu.RLock()
 
-			filtered = append(filtered, result)
+	for _, fRule := range repository.rules {
+		if applies(fRule.Rule, params) {
+			filtered = append(filtered, fRule.toRule())
 		}
 	}
 
+	repository.mu.RUnlock()
+
 	// Sort newest first
 	sort.Slice(filtered, func(i, j int) bool {
 		return filtered[i].Key > filtered[j].Key
@@ -186,6 +264,8 @@ func (repository *ruleFileRepository) Search(ctx context.Context, params map[str
 		}
 	}
 
+	ruleList.Paging.LastID = ""
+
 	if start == -1 || start >= len(filtered) {
 		ruleList.Results = make([]*model.Rule, 0)
 
@@ -199,6 +279,10 @@ func (repository *ruleFileRepository) Search(ctx context.Context, params map[str
 
 	ruleList.Results = filtered[start:to]
 
+	if to < len(filtered) {
+		ruleList.Paging.LastID = filtered[to-1].Key
+	}
+
 	return ruleList, nil
 }
 
@@ -235,78 +319,459 @@ func (repository *ruleFileRepository) Delete(ctx context.Context, key string) er
 
 	logger.Debug(repository, nil, "Updating rule.")
 
-	var result []fileRule
-
-	for index := range repository.rules {
-		if repository.rules[index].Key == key {
-			switch index {
-			case 0:
-				result = repository.rules[index+1 : len(repository.rules)]
-			case len(repository.rules) - 1:
-				result = repository.rules[0:index]
-			default:
-				result = repository.rules[0:index]
-				result = append(result, repository.rules[index+1:len(repository.rules)]...)
-			}
+	repository.mu.Lock()
+	defer repository.mu.Unlock()
 
-			repository.rules = result
+	index := slices.IndexFunc(repository.rules, func(fRule fileRule) bool { return fRule.Key == key })
+	if index == -1 {
+		return nil
+	}
 
-			break
-		}
+	rules := slices.Delete(slices.Clone(repository.rules), index, index+1)
+
+	if err := repository.saveFile(rules, repository.rules[index].source); err != nil {
+		return err
 	}
 
-	return repository.SaveFile()
+	repository.rules = rules
+
+	return nil
 }
 
 func (repository *ruleFileRepository) SearchByMethodAndPath(ctx context.Context, method string,
 	path string,
-) (*model.Rule, error) {
+) ([]*model.Rule, error) {
 	logger := mockscontext.Logger(ctx)
 
 	logger.Debug(repository, nil, "Searching by method and path rule.")
 
-	for _, rule := range repository.rules {
-		expr := CreateExpression(rule.Path)
-		regex := regexp.MustCompile(expr)
+	candidates := make([]*model.Rule, 0)
 
-		if rule.Method == method && rule.Status == model.RuleStatusEnabled && regex.MatchString(path) {
-			result := rule.Rule
-			result.NextResponseIndex = rule.NextResponseIndex
+	repository.mu.RLock()
 
-			return &result, nil
+	for _, fRule := range repository.rules {
+		if fRule.Status == model.RuleStatusEnabled && matchesRoute(&fRule.Rule, method, path) {
+			candidates = append(candidates, fRule.toRule())
 		}
 	}
 
-	return nil, mockserrors.RuleNotFoundError{
-		Message: fmt.Sprintf("no rule found for path: %s and method %s", path, method),
+	repository.mu.RUnlock()
+
+	SortByPrecedence(candidates)
+
+	return candidates, nil
+}
+
+// toRule returns a copy of the stored rule, so callers never share memory with the repository.
+func (fRule fileRule) toRule() *model.Rule {
+	rule := fRule.Rule
+	rule.NextResponseIndex = fRule.NextResponseIndex
+
+	return &rule
+}
+
+// Reload reads the mocks file, or the files of the rules directory, again when they changed since they
+// were last read or written. A source that fails is not read again until it changes.
+func (repository *ruleFileRepository) Reload(ctx context.Context,
+	check func(rule model.Rule) (model.Rule, error),
+) (bool, error) {
+	logger := mockscontext.Logger(ctx)
+
+	repository.mu.Lock()
+	defer repository.mu.Unlock()
+
+	version, err := repository.sourceVersion()
+	if err != nil || version == repository.version {
+		return false, err
 	}
+
+	logger.Debug(repository, nil, "Reloading rules from file.")
+
+	repository.version = version
+
+	rules, err := repository.readSource()
+	if err != nil {
+		return false, err
+	}
+
+	for index := range rules {
+		rule, err := check(rules[index].Rule)
+		if err != nil {
+			return false, fmt.Errorf("invalid rule '%s' in %s - %w", rules[index].Name, rules[index].source, err)
+		}
+
+		rules[index].Rule = rule
+	}
+
+	repository.rules = rules
+
+	return true, nil
 }
 
-func (repository *ruleFileRepository) SaveFile() error {
-	file, err := os.Create(repository.filePath)
+// sourceFiles returns the files that hold the rules, sorted by name.
+func (repository *ruleFileRepository) sourceFiles() ([]string, error) {
+	if !repository.isDir {
+		return []string{repository.filePath}, nil
+	}
+
+	entries, err := os.ReadDir(repository.filePath)
 	if err != nil {
-		return fmt.Errorf("error saving file: %s - %w", repository.filePath, err)
+		return nil, fmt.Errorf("error reading directory: %s - %w", repository.filePath, err)
 	}
 
-	defer func(f *os.File) { _ = f.Close() }(file)
+	files := make([]string, 0, len(entries))
+
+	for _, entry := range entries {
+		if !entry.IsDir() && isRulesFile(entry.Name()) {
+			files = append(files, filepath.Join(repository.filePath, entry.Name()))
+		}
+	}
+
+	return files, nil
+}
+
+// sourceVersion is made of the size and modification time of every file that holds rules.
+func (repository *ruleFileRepository) sourceVersion() (string, error) {
+	files, err := repository.sourceFiles()
+	if err != nil {
+		return "", err
+	}
+
+	var version strings.Builder
+
+	for _, file := range files {
+		stat, err := os.Stat(file)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+
+		if err != nil {
+			return "", fmt.Errorf("error reading file: %s - %w", file, err)
+		}
+
+		fmt.Fprintf(&version, "%s:%d:%d;", file, stat.ModTime().UnixNano(), stat.Size())
+	}
+
+	return version.String(), nil
+}
+
+// readSource reads the rules of every source file. Two files cannot hold rules with the same key.
+func (repository *ruleFileRepository) readSource() ([]fileRule, error) {
+	files, err := repository.sourceFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make([]fileRule, 0)
+	sources := make(map[string]string)
+
+	for _, file := range files {
+		fileRules, err := readRulesFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, fRule := range fileRules {
+			if source, found := sources[fRule.Key]; found {
+				return nil, mockserrors.InvalidRulesError{
+					Message: fmt.Sprintf("rule key '%s' is used in %s and %s", fRule.Key, source, file),
+				}
+			}
+
+			sources[fRule.Key] = file
+		}
+
+		rules = append(rules, fileRules...)
+	}
+
+	return rules, nil
+}
+
+// readRulesFile reads a file that holds a rule or an array of rules. Rules written by hand may not have
+// a key, they get one made of the file name and their position in it.
+func readRulesFile(filePath string) ([]fileRule, error) {
+	return readRulesFrom(filePath, filePath)
+}
+
+// readRulesFrom reads the rules of source from filePath, which is source itself or its backup. The rules
+// belong to source, so its name gives their format and the file they are saved to.
+func readRulesFrom(filePath, source string) ([]fileRule, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading file: %s - %w", filePath, err)
+	}
+
+	rules, err := unmarshalRules(source, content)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshaling file: %s - %w", filePath, err)
+	}
+
+	for index := range rules {
+		rules[index].source = source
+
+		if rules[index].Key == "" {
+			rules[index].Key = fmt.Sprintf("%s-%d", filepath.Base(source), index+1)
+		}
+	}
+
+	return rules, nil
+}
+
+// restoreFile writes the rules restored from the backup over the mocks file and returns its new version.
+// The torn file is first moved aside to "<file>.corrupt-<timestamp>", so that nothing in it is lost, and
+// the backup is not replaced by it.
+func (repository *ruleFileRepository) restoreFile(rules []fileRule) (string, error) {
+	content, err := repository.marshalSource(rules, repository.filePath)
+	if err != nil {
+		return "", err
+	}
+
+	corruptPath := fmt.Sprintf("%s.corrupt-%s", repository.filePath, time.Now().UTC().Format("20060102T150405.000"))
+	if err := os.Rename(repository.filePath, corruptPath); err != nil {
+		return "", fmt.Errorf("error moving aside file: %s - %w", repository.filePath, err)
+	}
+
+	mockscontext.Logger(mockscontext.Background()).Warn(repository, map[string]string{"file": corruptPath},
+		"mocks file %s was left empty or cut short, restored it from %s", repository.filePath,
+		backupPath(repository.filePath))
+
+	if err := replaceFile(repository.filePath, content); err != nil {
+		return "", err
+	}
+
+	return repository.sourceVersion()
+}
+
+// isTornFile tells whether filePath was left empty, or with JSON that ends before it is complete, as an
+// interrupted write leaves it, and there is a backup to restore it from.
+func isTornFile(filePath string) bool {
+	if _, err := os.Stat(backupPath(filePath)); err != nil {
+		return false
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return false
+	}
+
+	if len(bytes.TrimSpace(content)) == 0 {
+		return true
+	}
+
+	if isYAMLFile(filePath) {
+		return false
+	}
+
+	var syntaxError *json.SyntaxError
+
+	return errors.As(json.Unmarshal(content, new(any)), &syntaxError) && syntaxError.Offset == int64(len(content))
+}
+
+// saveFile writes the rules to the mocks file or, when the rules are kept in a directory, the rules of
+// source to that file. A mocks file with a YAML extension is written in YAML. The caller must hold the
+// write lock.
+func (repository *ruleFileRepository) saveFile(rules []fileRule, source string) error {
+	content, err := repository.marshalSource(rules, source)
+	if err != nil {
+		return err
+	}
 
-	content := jsonutils.Marshal(repository.rules)
+	if err := writeFileAtomically(source, content); err != nil {
+		return err
+	}
 
-	_, err = file.Write([]byte(content))
+	version, err := repository.sourceVersion()
 	if err != nil {
-		return fmt.Errorf("error writing file content: %s - %w", repository.filePath, err)
+		return err
 	}
 
+	repository.version = version
+
 	return nil
 }
 
-func unmarshalRules(content io.Reader) ([]fileRule, error) {
-	rules := new([]fileRule)
+// marshalSource encodes the content of source, which holds every rule unless the rules are kept in a
+// directory.
+func (repository *ruleFileRepository) marshalSource(rules []fileRule, source string) ([]byte, error) {
+	if !repository.isDir && !isYAMLFile(source) {
+		return []byte(jsonutils.Marshal(rules)), nil
+	}
+
+	sourceRules := slices.DeleteFunc(slices.Clone(rules), func(fRule fileRule) bool {
+		return fRule.source != source
+	})
+
+	return marshalRules(source, sourceRules)
+}
+
+// writeFileAtomically writes content to a temporary file in the same directory, syncs it and renames it
+// over filePath. The current file is copied to the backup before it is replaced.
+func writeFileAtomically(filePath string, content []byte) error {
+	return writeFile(filePath, content, true)
+}
+
+// replaceFile writes content to filePath like writeFileAtomically, without copying the current file to
+// its backup.
+func replaceFile(filePath string, content []byte) error {
+	return writeFile(filePath, content, false)
+}
+
+func writeFile(filePath string, content []byte, backup bool) error {
+	dir, name := filepath.Split(filePath)
 
-	err := jsonutils.Unmarshal(content, rules)
+	tmp, err := os.CreateTemp(dir, name+".*.tmp")
 	if err != nil {
+		return fmt.Errorf("error saving file: %s - %w", filePath, err)
+	}
+
+	defer func(tmpPath string) { _ = os.Remove(tmpPath) }(tmp.Name())
+
+	err = writeAndSync(tmp, content)
+	if err != nil {
+		return fmt.Errorf("error writing file content: %s - %w", filePath, err)
+	}
+
+	if backup {
+		if err := backupFile(filePath); err != nil {
+			return err
+		}
+	}
+
+	if err := os.Rename(tmp.Name(), filePath); err != nil {
+		return fmt.Errorf("error saving file: %s - %w", filePath, err)
+	}
+
+	syncDir(dir)
+
+	return nil
+}
+
+// backupFile copies filePath to "<file>.bak". A missing file is not an error.
+func backupFile(filePath string) error {
+	content, err := os.ReadFile(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("error reading file: %s - %w", filePath, err)
+	}
+
+	err = os.WriteFile(backupPath(filePath), content, 0o644) //nolint:gosec,mnd
+	if err != nil {
+		return fmt.Errorf("error saving backup file: %s - %w", backupPath(filePath), err)
+	}
+
+	return nil
+}
+
+func writeAndSync(file *os.File, content []byte) error {
+	defer func(f *os.File) { _ = f.Close() }(file)
+
+	if _, err := file.Write(content); err != nil {
+		return fmt.Errorf("error writing %s: %w", file.Name(), err)
+	}
+
+	if err := file.Chmod(0o644); err != nil { //nolint:mnd
+		return fmt.Errorf("error setting permissions of %s: %w", file.Name(), err)
+	}
+
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("error syncing %s: %w", file.Name(), err)
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("error closing %s: %w", file.Name(), err)
+	}
+
+	return nil
+}
+
+// syncDir flushes the rename of the mocks file to disk. Not every platform can sync a directory, so
+// errors are ignored.
+func syncDir(dir string) {
+	if dir == "" {
+		dir = "."
+	}
+
+	file, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+
+	_ = file.Sync()
+	_ = file.Close()
+}
+
+func backupPath(filePath string) string {
+	return filePath + ".bak"
+}
+
+// unmarshalRules decodes a rule or an array of rules, in YAML when fileName has a YAML extension.
+func unmarshalRules(fileName string, content []byte) ([]fileRule, error) {
+	if len(bytes.TrimSpace(content)) == 0 {
+		return make([]fileRule, 0), nil
+	}
+
+	unmarshal := jsonutils.Unmarshal
+	if isYAMLFile(fileName) {
+		unmarshal = yamlutils.Unmarshal
+	}
+
+	var raw any
+	if err := unmarshal(bytes.NewReader(content), &raw); err != nil {
 		return nil, fmt.Errorf("error unmarshalling content, %w", err)
 	}
 
-	return *rules, nil
+	if _, isRule := raw.(map[string]any); isRule {
+		rule := fileRule{}
+		if err := unmarshal(bytes.NewReader(content), &rule); err != nil {
+			return nil, fmt.Errorf("error unmarshalling content, %w", err)
+		}
+
+		return []fileRule{rule}, nil
+	}
+
+	rules := make([]fileRule, 0)
+	if err := unmarshal(bytes.NewReader(content), &rules); err != nil {
+		return nil, fmt.Errorf("error unmarshalling content, %w", err)
+	}
+
+	return rules, nil
+}
+
+// marshalRules encodes the rules of a file of the rules directory in the format of its extension. A
+// single rule is written as an object.
+func marshalRules(fileName string, rules []fileRule) ([]byte, error) {
+	var value any = rules
+	if len(rules) == 1 {
+		value = rules[0]
+	}
+
+	if isYAMLFile(fileName) {
+		content, err := yamlutils.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("error marshalling rules: %s - %w", fileName, err)
+		}
+
+		return []byte(content), nil
+	}
+
+	content, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling rules: %s - %w", fileName, err)
+	}
+
+	return append(content, '\n'), nil
+}
+
+func isRulesFile(fileName string) bool {
+	return !strings.HasPrefix(fileName, ".") &&
+		(strings.EqualFold(filepath.Ext(fileName), ".json") || isYAMLFile(fileName))
+}
+
+func isYAMLFile(fileName string) bool {
+	ext := strings.ToLower(filepath.Ext(fileName))
+
+	return ext == ".yaml" || ext == ".yml"
 }
@@ -2,10 +2,15 @@ package repository_test
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/nicopozo/mockserver/internal/configs"
 	ruleserrors "github.com/nicopozo/mockserver/internal/errors"
@@ -66,8 +71,7 @@ func Test_ruleFileRepository_Get(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			name := getMocksFile()
-			defer func(fileName string) { _ = os.Remove(fileName) }(name)
+			name := getMocksFile(t)
 
 			fileRepository, err := repository.NewRuleFileRepository(&configs.Config{MocksFile: name})
 			if assert.Nil(t, err) {
@@ -144,8 +148,7 @@ func Test_ruleFileRepository_Search(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			name := getMocksFile()
-			defer func(fileName string) { _ = os.Remove(fileName) }(name)
+			name := getMocksFile(t)
 
 			fileRepository, err := repository.NewRuleFileRepository(&configs.Config{MocksFile: name})
 			if assert.Nil(t, err) {
@@ -159,8 +162,487 @@ func Test_ruleFileRepository_Search(t *testing.T) {
 	}
 }
 
-func getMocksFile() string {
-	dest, err := os.OpenFile("mocks.json", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+func Test_ruleFileRepository_SearchPages(t *testing.T) {
+	fileRepository, err := repository.NewRuleFileRepository(&configs.Config{MocksFile: getMocksFile(t)})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	keys := make([]string, 0)
+	paging := model.Paging{Limit: 3}
+
+	for range 10 {
+		ruleList, err := fileRepository.Search(context.Background(), map[string]interface{}{}, paging)
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		for _, rule := range ruleList.Results {
+			keys = append(keys, rule.Key)
+		}
+
+		if ruleList.Paging.LastID == "" {
+			break
+		}
+
+		paging.LastID = ruleList.Paging.LastID
+	}
+
+	assert.Len(t, keys, 4, "the cursor visits every rule once")
+}
+
+func Test_ruleFileRepository_SearchByMethodAndPath(t *testing.T) {
+	name := getMocksFile(t)
+
+	fileRepository, err := repository.NewRuleFileRepository(&configs.Config{MocksFile: name})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	got, err := fileRepository.SearchByMethodAndPath(context.Background(), "GET", "/test/new")
+	assert.Nil(t, err)
+
+	if assert.Len(t, got, 1) {
+		assert.Equal(t, "b2", got[0].Key)
+	}
+
+	got, err = fileRepository.SearchByMethodAndPath(context.Background(), "GET", "/unknown")
+	assert.Nil(t, err)
+	assert.Empty(t, got)
+
+	for _, path := range []string{"/v1/users/{id}", "/v1/users/me"} {
+		_, err = fileRepository.Create(context.Background(), &model.Rule{
+			Name: path, Path: path, Method: "GET", Status: model.RuleStatusEnabled,
+		})
+		assert.Nil(t, err)
+	}
+
+	got, err = fileRepository.SearchByMethodAndPath(context.Background(), "GET", "/v1/users/me")
+	assert.Nil(t, err)
+
+	if assert.Len(t, got, 2) {
+		assert.Equal(t, "/v1/users/me", got[0].Path)
+		assert.Equal(t, "/v1/users/{id}", got[1].Path)
+	}
+}
+
+//nolint:paralleltest
+func Test_ruleFileRepository_Concurrency(t *testing.T) {
+	const (
+		workers    = 8
+		iterations = 20
+	)
+
+	ctx := context.Background()
+	name := filepath.Join(t.TempDir(), "mocks.json")
+
+	fileRepository, err := repository.NewRuleFileRepository(&configs.Config{MocksFile: name})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	const responses = 4
+
+	sequential, err := fileRepository.Create(ctx, &model.Rule{
+		Name: "sequential", Path: "/sequential", Method: "GET", Strategy: model.RuleStrategySequential,
+		Status: model.RuleStatusEnabled, Responses: make([]model.Response, responses),
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	var (
+		wg    sync.WaitGroup
+		turns [responses]atomic.Int32
+	)
+
+	for worker := range workers {
+		wg.Add(4) //nolint:mnd
+
+		// Every worker moves the same sequential rule forward, so each turn is served the same number of times.
+		go func() {
+			defer wg.Done()
+
+			for range iterations {
+				turn, err := fileRepository.AdvanceResponse(ctx, sequential.Key, responses)
+				if assert.Nil(t, err) {
+					turns[turn].Add(1)
+				}
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			for iteration := range iterations {
+				path := fmt.Sprintf("/worker/%d/%d", worker, iteration)
+
+				rule, err := fileRepository.Create(ctx, &model.Rule{
+					Name: path, Path: path, Method: "POST", Status: model.RuleStatusEnabled,
+				})
+				if !assert.Nil(t, err) {
+					return
+				}
+
+				if iteration%2 == 0 {
+					assert.Nil(t, fileRepository.Delete(ctx, rule.Key))
+				}
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			for range iterations {
+				got, err := fileRepository.SearchByMethodAndPath(ctx, "GET", "/sequential")
+				if assert.Nil(t, err) && assert.Len(t, got, 1) {
+					got[0].NextResponseIndex = -1
+				}
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			for range iterations {
+				_, err := fileRepository.Search(ctx, map[string]interface{}{"method": "post"}, model.Paging{Limit: 10})
+				assert.Nil(t, err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	// A new repository reads everything that was saved.
+	fileRepository, err = repository.NewRuleFileRepository(&configs.Config{MocksFile: name})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	for turn := range turns {
+		assert.Equal(t, int32(workers*iterations/responses), turns[turn].Load(), "turn %d", turn)
+	}
+
+	rule, err := fileRepository.Get(ctx, sequential.Key)
+	if assert.Nil(t, err) {
+		assert.Equal(t, responses, rule.NextResponseIndex)
+	}
+
+	got, err := fileRepository.Search(ctx, map[string]interface{}{"method": "post"}, model.Paging{Limit: 1000})
+	if assert.Nil(t, err) {
+		assert.Equal(t, int64(workers*iterations/2), got.Paging.Total)
+	}
+
+	tmpFiles, err := filepath.Glob(name + ".*.tmp")
+	assert.Nil(t, err)
+	assert.Empty(t, tmpFiles)
+}
+
+//nolint:paralleltest
+func Test_ruleFileRepository_Backup(t *testing.T) {
+	ctx := context.Background()
+	name := filepath.Join(t.TempDir(), "mocks.json")
+
+	fileRepository, err := repository.NewRuleFileRepository(&configs.Config{MocksFile: name})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	first, err := fileRepository.Create(ctx, &model.Rule{Name: "first", Path: "/first", Method: "GET"})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	_, err = fileRepository.Create(ctx, &model.Rule{Name: "second", Path: "/second", Method: "GET"})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	// The backup holds the file as it was before the last change.
+	backupRepository, err := repository.NewRuleFileRepository(&configs.Config{MocksFile: name + ".bak"})
+	if assert.Nil(t, err) {
+		got, err := backupRepository.Search(ctx, map[string]interface{}{}, model.Paging{Limit: 10})
+		if assert.Nil(t, err) && assert.Len(t, got.Results, 1) {
+			assert.Equal(t, first.Key, got.Results[0].Key)
+		}
+	}
+
+	// A mocks file that was left corrupted is restored from the backup.
+	assert.Nil(t, os.WriteFile(name, []byte(`[{"key":`), 0o600))
+
+	fileRepository, err = repository.NewRuleFileRepository(&configs.Config{MocksFile: name})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	got, err := fileRepository.Get(ctx, first.Key)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	assert.Equal(t, "first", got.Name)
+
+	// The torn file is kept aside.
+	corrupted, err := filepath.Glob(name + ".corrupt-*")
+	if assert.Nil(t, err) && assert.Len(t, corrupted, 1) {
+		content, err := os.ReadFile(corrupted[0])
+		assert.Nil(t, err)
+		assert.Equal(t, `[{"key":`, string(content))
+	}
+
+	// The restored rules are saved to the mocks file, not to the backup.
+	got.Name = "first updated"
+
+	_, err = fileRepository.Update(ctx, got)
+	assert.Nil(t, err)
+
+	for _, file := range []string{name, name + ".bak"} {
+		reopened, err := repository.NewRuleFileRepository(&configs.Config{MocksFile: file})
+		if !assert.Nil(t, err, file) {
+			continue
+		}
+
+		rules, err := reopened.Search(ctx, map[string]interface{}{}, model.Paging{Limit: 10})
+		if assert.Nil(t, err, file) && assert.Len(t, rules.Results, 1, file) {
+			assert.Equal(t, first.Key, rules.Results[0].Key, file)
+		}
+	}
+
+	content, err := os.ReadFile(name)
+	assert.Nil(t, err)
+	assert.Contains(t, string(content), "first updated")
+
+	// A mistake made by hand is reported rather than replaced by the backup, and the file is left as it is.
+	edited := []byte(`[{"key": "first",}]`)
+	assert.Nil(t, os.WriteFile(name, edited, 0o600))
+
+	_, err = repository.NewRuleFileRepository(&configs.Config{MocksFile: name})
+	assert.ErrorContains(t, err, "invalid character '}'")
+
+	content, err = os.ReadFile(name)
+	assert.Nil(t, err)
+	assert.Equal(t, edited, content)
+
+	corrupted, err = filepath.Glob(name + ".corrupt-*")
+	assert.Nil(t, err)
+	assert.Len(t, corrupted, 1)
+}
+
+//nolint:paralleltest
+func Test_ruleFileRepository_Reload(t *testing.T) {
+	ctx := context.Background()
+	name := getMocksFile(t)
+
+	fileRepository, err := repository.NewRuleFileRepository(&configs.Config{MocksFile: name})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	reloadable, ok := fileRepository.(repository.ReloadableRuleRepository)
+	if !assert.True(t, ok) {
+		return
+	}
+
+	checkPath := func(rule model.Rule) (model.Rule, error) {
+		if rule.Path == "" {
+			return model.Rule{}, ruleserrors.InvalidRulesError{Message: "path cannot be empty"}
+		}
+
+		rule.Status = model.RuleStatusEnabled
+
+		return rule, nil
+	}
+
+	reloaded, err := reloadable.Reload(ctx, checkPath)
+	assert.Nil(t, err)
+	assert.False(t, reloaded, "the file did not change")
+
+	// Rules saved through the repository are not read again.
+	_, err = fileRepository.Create(ctx, &model.Rule{Name: "created", Path: "/created", Method: "GET"})
+	assert.Nil(t, err)
+
+	reloaded, err = reloadable.Reload(ctx, checkPath)
+	assert.Nil(t, err)
+	assert.False(t, reloaded)
+
+	writeRulesFile(t, name, `[{"key":"e5","name":"edited","path":"/edited","method":"GET","status":"disabled"}]`)
+
+	reloaded, err = reloadable.Reload(ctx, checkPath)
+	assert.Nil(t, err)
+	assert.True(t, reloaded)
+
+	got, err := fileRepository.Search(ctx, map[string]interface{}{}, model.Paging{Limit: 10})
+	if assert.Nil(t, err) && assert.Len(t, got.Results, 1) {
+		assert.Equal(t, "edited", got.Results[0].Name)
+		assert.Equal(t, model.RuleStatusEnabled, got.Results[0].Status, "the rules go through check")
+	}
+
+	// A file that fails the check, or cannot be read, is rejected and the previous rules are kept.
+	for _, content := range []string{`[{"key":"f6","name":"no path","method":"GET"}]`, `[{"key":`} {
+		writeRulesFile(t, name, content)
+
+		reloaded, err = reloadable.Reload(ctx, checkPath)
+		assert.NotNil(t, err)
+		assert.False(t, reloaded)
+
+		_, err = fileRepository.Get(ctx, "e5")
+		assert.Nil(t, err)
+	}
+}
+
+//nolint:paralleltest
+func Test_ruleFileRepository_Directory(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	writeRulesFile(t, filepath.Join(dir, "payments.json"),
+		`{"key":"p1","name":"payment","path":"/payments","method":"POST","status":"enabled"}`)
+	writeRulesFile(t, filepath.Join(dir, "users.yaml"), `
+# Owned by the users team
+- name: list users
+  path: /users
+  method: GET
+  status: enabled
+  responses:
+    - http_status: 200
+      content_type: application/json
+      body: |
+        [{"id": 1}]
+- name: get user
+  path: /users/{id}
+  method: GET
+  status: enabled
+`)
+	writeRulesFile(t, filepath.Join(dir, "notes.txt"), "not a rule")
+
+	fileRepository, err := repository.NewRuleFileRepository(&configs.Config{MocksFile: dir})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	got, err := fileRepository.Search(ctx, map[string]interface{}{}, model.Paging{Limit: 10})
+	if assert.Nil(t, err) {
+		assert.Equal(t, int64(3), got.Paging.Total)
+	}
+
+	// Rules written by hand without a key get one from their file.
+	rule, err := fileRepository.Get(ctx, "users.yaml-1")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	assert.Equal(t, "[{\"id\": 1}]\n", rule.Responses[0].Body)
+
+	// Changes go back to the file of the rule, in its format.
+	rule.Name = "list all users"
+
+	_, err = fileRepository.Update(ctx, rule)
+	assert.Nil(t, err)
+
+	content, err := os.ReadFile(filepath.Join(dir, "users.yaml"))
+	if assert.Nil(t, err) {
+		assert.Contains(t, string(content), "- key: users.yaml-1\n  group: \"\"\n  name: list all users\n")
+	}
+
+	// New rules go to mocks.json.
+	created, err := fileRepository.Create(ctx, &model.Rule{Name: "created", Path: "/created", Method: "GET"})
+	assert.Nil(t, err)
+
+	content, err = os.ReadFile(filepath.Join(dir, "mocks.json"))
+	if assert.Nil(t, err) {
+		assert.Contains(t, string(content), created.Key)
+	}
+
+	assert.Nil(t, fileRepository.Delete(ctx, "p1"))
+
+	content, err = os.ReadFile(filepath.Join(dir, "payments.json"))
+	if assert.Nil(t, err) {
+		assert.Equal(t, "[]\n", string(content))
+	}
+
+	// New files are picked up on reload, a key cannot be used twice.
+	writeRulesFile(t, filepath.Join(dir, "orders.yml"), "name: orders\npath: /orders\nmethod: GET\n")
+
+	reloaded, err := fileRepository.(repository.ReloadableRuleRepository).Reload(ctx, noCheck)
+	assert.Nil(t, err)
+	assert.True(t, reloaded)
+
+	got, err = fileRepository.Search(ctx, map[string]interface{}{}, model.Paging{Limit: 10})
+	if assert.Nil(t, err) {
+		assert.Equal(t, int64(4), got.Paging.Total)
+	}
+
+	writeRulesFile(t, filepath.Join(dir, "orders.yml"), "key: users.yaml-2\nname: orders\npath: /orders\n")
+
+	reloaded, err = fileRepository.(repository.ReloadableRuleRepository).Reload(ctx, noCheck)
+	assert.NotNil(t, err)
+	assert.False(t, reloaded)
+
+	_, err = fileRepository.Get(ctx, "orders.yml-1")
+	assert.Nil(t, err)
+}
+
+//nolint:paralleltest
+func Test_ruleFileRepository_YAMLFile(t *testing.T) {
+	ctx := context.Background()
+	name := filepath.Join(t.TempDir(), "mocks.yaml")
+
+	fileRepository, err := repository.NewRuleFileRepository(&configs.Config{MocksFile: name})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	created, err := fileRepository.Create(ctx, &model.Rule{
+		Name: "created", Path: "/created", Method: "GET", Status: model.RuleStatusEnabled,
+		Responses: []model.Response{{Body: "{\n  \"id\": 1\n}", HTTPStatus: http.StatusOK}},
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	content, err := os.ReadFile(name)
+	if assert.Nil(t, err) {
+		assert.Contains(t, string(content), "  - body: |-\n      {\n        \"id\": 1\n      }\n")
+	}
+
+	// A new repository reads the rules saved by the previous one.
+	fileRepository, err = repository.NewRuleFileRepository(&configs.Config{MocksFile: name})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	got, err := fileRepository.Get(ctx, created.Key)
+	if assert.Nil(t, err) {
+		assert.Equal(t, created.Responses, got.Responses)
+	}
+}
+
+func noCheck(rule model.Rule) (model.Rule, error) {
+	return rule, nil
+}
+
+// writeRulesFile writes a file as if it was edited by hand, with a modification time that is always new.
+func writeRulesFile(t *testing.T, name, content string) {
+	t.Helper()
+
+	if err := os.WriteFile(name, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	modTime := time.Now().Add(time.Duration(len(content)) * time.Second)
+	if err := os.Chtimes(name, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// getMocksFile copies the test mocks into a temporary directory, so the backups written by the
+// repository are removed with it.
+func getMocksFile(t *testing.T) string {
+	t.Helper()
+
+	name := filepath.Join(t.TempDir(), "mocks.json")
+
+	dest, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
 	if err != nil {
 		panic(err)
 	}
@@ -177,5 +659,5 @@ func getMocksFile() string {
 		panic(err)
 	}
 
-	return "mocks.json"
+	return name
 }
@@ -0,0 +1,335 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	mockscontext "github.com/nicopozo/mockserver/internal/context"
+	"github.com/nicopozo/mockserver/internal/model"
+)
+
+const indexLoadPageSize = 500
+
+// indexedRuleRepository decorates a RuleRepository with an in-memory routing index so that
+// SearchByMethodAndPath walks a path-segment trie instead of compiling every stored pattern on each
+// request. The index is loaded lazily from the decorated repository and kept up to date on every
+// Create, Update and Delete that goes through it, so it is only correct while this process is the only
+// writer of the rules, as with the file datasource. Rules are cloned on the way in and out, since the
+// callers write to the rules they get, like the values of their variables.
+type indexedRuleRepository struct {
+	RuleRepository
+
+	mutex  sync.RWMutex
+	loaded bool
+	rules  map[string]*model.Rule
+	roots  map[string]*routeNode
+}
+
+// routeNode is a node of the routing trie. Literal segments are looked up by exact value, segments
+// with placeholders or regex characters are kept as compiled per-segment patterns.
+type routeNode struct {
+	literals map[string]*routeNode
+	patterns []*patternRoute
+	keys     []string
+}
+
+type patternRoute struct {
+	segment string
+	regex   *regexp.Regexp
+	node    *routeNode
+}
+
+// NewIndexedRuleRepository returns a RuleRepository that resolves method and path lookups through
+// an in-memory routing index in front of repository.
+func NewIndexedRuleRepository(repository RuleRepository) RuleRepository {
+	return &indexedRuleRepository{
+		RuleRepository: repository,
+	}
+}
+
+func (repository *indexedRuleRepository) Create(ctx context.Context, rule *model.Rule) (*model.Rule, error) {
+	created, err := repository.RuleRepository.Create(ctx, rule)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	repository.put(created)
+
+	return created, nil
+}
+
+func (repository *indexedRuleRepository) Update(ctx context.Context, rule *model.Rule) (*model.Rule, error) {
+	updated, err := repository.RuleRepository.Update(ctx, rule)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	repository.put(updated)
+
+	return updated, nil
+}
+
+func (repository *indexedRuleRepository) Delete(ctx context.Context, key string) error {
+	if err := repository.RuleRepository.Delete(ctx, key); err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	if repository.loaded {
+		repository.remove(key)
+	}
+
+	return nil
+}
+
+func (repository *indexedRuleRepository) SearchByMethodAndPath(ctx context.Context, method string,
+	path string,
+) ([]*model.Rule, error) {
+	if err := repository.load(ctx); err != nil {
+		return nil, err
+	}
+
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	candidates := make([]*model.Rule, 0)
+
+	root, ok := repository.roots[method]
+	if !ok {
+		return candidates, nil
+	}
+
+	for _, key := range root.lookup(strings.Split(path, "/")) {
+		rule := repository.rules[key].Clone()
+		candidates = append(candidates, &rule)
+	}
+
+	SortByPrecedence(candidates)
+
+	return candidates, nil
+}
+
+// Invalidate drops the routing index so that it is loaded again from the decorated repository on
+// the next lookup. It is needed when rules change without going through this repository.
+func (repository *indexedRuleRepository) Invalidate() {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	repository.loaded = false
+	repository.rules = nil
+	repository.roots = nil
+}
+
+// Reload reloads the decorated repository, when it can be reloaded, and drops the routing index when its
+// rules were replaced.
+func (repository *indexedRuleRepository) Reload(ctx context.Context,
+	check func(rule model.Rule) (model.Rule, error),
+) (bool, error) {
+	reloadable, ok := repository.RuleRepository.(ReloadableRuleRepository)
+	if !ok {
+		return false, nil
+	}
+
+	reloaded, err := reloadable.Reload(ctx, check)
+	if reloaded {
+		repository.Invalidate()
+	}
+
+	return reloaded, err //nolint:wrapcheck
+}
+
+func (repository *indexedRuleRepository) load(ctx context.Context) error {
+	repository.mutex.RLock()
+	loaded := repository.loaded
+	repository.mutex.RUnlock()
+
+	if loaded {
+		return nil
+	}
+
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	if repository.loaded {
+		return nil
+	}
+
+	mockscontext.Logger(ctx).Debug(repository, nil, "Loading routing index.")
+
+	repository.rules = make(map[string]*model.Rule)
+	repository.roots = make(map[string]*routeNode)
+
+	paging := model.Paging{Limit: indexLoadPageSize}
+
+	for {
+		ruleList, err := repository.RuleRepository.Search(ctx, map[string]interface{}{}, paging)
+		if err != nil {
+			return fmt.Errorf("error loading routing index, %w", err)
+		}
+
+		for _, rule := range ruleList.Results {
+			repository.insert(rule)
+		}
+
+		// A page can be shorter than the limit while more rules remain, like a DynamoDB scan page that
+		// reached its size limit, so only the cursor tells when the rules are over.
+		if ruleList.Paging.LastID == "" {
+			break
+		}
+
+		paging.LastID = ruleList.Paging.LastID
+	}
+
+	repository.loaded = true
+
+	return nil
+}
+
+func (repository *indexedRuleRepository) put(rule *model.Rule) {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	if !repository.loaded {
+		return
+	}
+
+	repository.remove(rule.Key)
+	repository.insert(rule)
+}
+
+func (repository *indexedRuleRepository) insert(rule *model.Rule) {
+	stored := rule.Clone()
+	repository.rules[rule.Key] = &stored
+
+	if stored.Status != model.RuleStatusEnabled {
+		return
+	}
+
+	for _, route := range stored.Routes() {
+		root, ok := repository.roots[route.Method]
+		if !ok {
+			root = newRouteNode()
+			repository.roots[route.Method] = root
+		}
+
+		node := root
+		for _, segment := range strings.Split(route.Path, "/") {
+			node = node.child(segment)
+		}
+
+		node.keys = append(node.keys, stored.Key)
+	}
+}
+
+func (repository *indexedRuleRepository) remove(key string) {
+	rule, ok := repository.rules[key]
+	if !ok {
+		return
+	}
+
+	delete(repository.rules, key)
+
+	for _, route := range rule.Routes() {
+		if node := repository.routeNode(route); node != nil {
+			node.removeKey(key)
+		}
+	}
+}
+
+// routeNode returns the node where the rules of route are stored, or nil when there is none.
+func (repository *indexedRuleRepository) routeNode(route model.RuleRoute) *routeNode {
+	node, ok := repository.roots[route.Method]
+	if !ok {
+		return nil
+	}
+
+	for _, segment := range strings.Split(route.Path, "/") {
+		if node = node.find(segment); node == nil {
+			return nil
+		}
+	}
+
+	return node
+}
+
+func newRouteNode() *routeNode {
+	return &routeNode{literals: make(map[string]*routeNode)}
+}
+
+// child returns the node for segment, creating it when it does not exist yet.
+func (node *routeNode) child(segment string) *routeNode {
+	if existing := node.find(segment); existing != nil {
+		return existing
+	}
+
+	created := newRouteNode()
+
+	if isLiteralSegment(segment) {
+		node.literals[segment] = created
+
+		return created
+	}
+
+	node.patterns = append(node.patterns, &patternRoute{
+		segment: segment,
+		regex:   regexp.MustCompile(CreateExpression(segment)),
+		node:    created,
+	})
+
+	return created
+}
+
+func (node *routeNode) removeKey(key string) {
+	for index, nodeKey := range node.keys {
+		if nodeKey == key {
+			node.keys = append(node.keys[:index], node.keys[index+1:]...)
+
+			return
+		}
+	}
+}
+
+// find returns the node stored for segment as it was written in the rule path.
+func (node *routeNode) find(segment string) *routeNode {
+	if isLiteralSegment(segment) {
+		return node.literals[segment]
+	}
+
+	for _, pattern := range node.patterns {
+		if pattern.segment == segment {
+			return pattern.node
+		}
+	}
+
+	return nil
+}
+
+// lookup collects the keys of every rule whose path matches segments.
+func (node *routeNode) lookup(segments []string) []string {
+	if len(segments) == 0 {
+		return node.keys
+	}
+
+	var keys []string
+
+	if literal, ok := node.literals[segments[0]]; ok {
+		keys = append(keys, literal.lookup(segments[1:])...)
+	}
+
+	for _, pattern := range node.patterns {
+		if pattern.regex.MatchString(segments[0]) {
+			keys = append(keys, pattern.node.lookup(segments[1:])...)
+		}
+	}
+
+	return keys
+}
+
+func isLiteralSegment(segment string) bool {
+	return !strings.Contains(segment, "{") && regexp.QuoteMeta(segment) == segment
+}
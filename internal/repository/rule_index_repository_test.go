@@ -0,0 +1,252 @@
+package repository_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/nicopozo/mockserver/internal/configs"
+	"github.com/nicopozo/mockserver/internal/model"
+	"github.com/nicopozo/mockserver/internal/repository"
+	"github.com/nicopozo/mockserver/internal/utils/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_indexedRuleRepository_SearchByMethodAndPath(t *testing.T) {
+	name := getMocksFile(t)
+
+	fileRepository, err := repository.NewRuleFileRepository(&configs.Config{MocksFile: name})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	indexedRepository := repository.NewIndexedRuleRepository(fileRepository)
+	ctx := context.Background()
+
+	for _, path := range []string{
+		"/v1/users/{id}", "/v1/users/me", "/v1/users/{id}/orders/{order}", "/v1/files/{name}.json",
+	} {
+		_, err = indexedRepository.Create(ctx, &model.Rule{
+			Name: path, Path: path, Method: "GET", Status: model.RuleStatusEnabled,
+		})
+		assert.Nil(t, err)
+	}
+
+	tests := []struct {
+		method string
+		path   string
+		want   []string
+	}{
+		{method: "GET", path: "/test/new", want: []string{"/test/new"}},
+		{method: "POST", path: "/test/new/2", want: []string{"/test/new/2"}},
+		{method: "GET", path: "/v1/users/me", want: []string{"/v1/users/me", "/v1/users/{id}"}},
+		{method: "GET", path: "/v1/users/123", want: []string{"/v1/users/{id}"}},
+		{method: "GET", path: "/v1/users/123/orders/9", want: []string{"/v1/users/{id}/orders/{order}"}},
+		{method: "GET", path: "/v1/files/report.json", want: []string{"/v1/files/{name}.json"}},
+		{method: "GET", path: "/v1/files/report.xml", want: []string{}},
+		{method: "GET", path: "/v1/users/", want: []string{}},
+		{method: "DELETE", path: "/v1/users/me", want: []string{}},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			got, err := indexedRepository.SearchByMethodAndPath(ctx, tt.method, tt.path)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, rulePaths(got))
+
+			want, err := fileRepository.SearchByMethodAndPath(ctx, tt.method, tt.path)
+			assert.Nil(t, err)
+			assert.Equal(t, rulePaths(want), rulePaths(got))
+		})
+	}
+}
+
+func Test_indexedRuleRepository_KeepsIndexUpToDate(t *testing.T) {
+	name := getMocksFile(t)
+
+	fileRepository, err := repository.NewRuleFileRepository(&configs.Config{MocksFile: name})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	indexedRepository := repository.NewIndexedRuleRepository(fileRepository)
+	ctx := context.Background()
+
+	got, err := indexedRepository.SearchByMethodAndPath(ctx, "GET", "/test/new")
+	assert.Nil(t, err)
+	assert.Len(t, got, 1)
+
+	rule := *got[0]
+	rule.Path = "/test/moved"
+
+	_, err = indexedRepository.Update(ctx, &rule)
+	assert.Nil(t, err)
+
+	got, err = indexedRepository.SearchByMethodAndPath(ctx, "GET", "/test/new")
+	assert.Nil(t, err)
+	assert.Empty(t, got)
+
+	got, err = indexedRepository.SearchByMethodAndPath(ctx, "GET", "/test/moved")
+	assert.Nil(t, err)
+	assert.Len(t, got, 1)
+
+	rule.Status = model.RuleStatusDisabled
+
+	_, err = indexedRepository.Update(ctx, &rule)
+	assert.Nil(t, err)
+
+	got, err = indexedRepository.SearchByMethodAndPath(ctx, "GET", "/test/moved")
+	assert.Nil(t, err)
+	assert.Empty(t, got)
+
+	got, err = indexedRepository.SearchByMethodAndPath(ctx, "DELETE", "/test")
+	assert.Nil(t, err)
+	assert.Len(t, got, 1)
+
+	err = indexedRepository.Delete(ctx, "a1")
+	assert.Nil(t, err)
+
+	got, err = indexedRepository.SearchByMethodAndPath(ctx, "DELETE", "/test")
+	assert.Nil(t, err)
+	assert.Empty(t, got)
+}
+
+func Test_indexedRuleRepository_ReturnsCopies(t *testing.T) {
+	fileRepository, err := repository.NewRuleFileRepository(&configs.Config{MocksFile: getMocksFile(t)})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	indexedRepository := repository.NewIndexedRuleRepository(fileRepository)
+	ctx := context.Background()
+
+	minimum := 1.0
+
+	_, err = indexedRepository.Create(ctx, &model.Rule{
+		Name: "copies", Path: "/copies/{id}", Method: "GET", Status: model.RuleStatusEnabled,
+		Responses: []model.Response{{
+			Body: "{id}", HTTPStatus: 200, Headers: map[string]string{"X-Id": "{id}"},
+			Cookies: []model.Cookie{{Name: "session", Value: "{id}"}},
+			Webhook: &model.WebhookConfig{URL: "http://localhost/hook", Headers: map[string]string{"X-Id": "{id}"}},
+		}},
+		Variables: []*model.Variable{{Type: model.VariableTypePath, Name: "id", Key: "id", Min: &minimum}},
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	got, err := indexedRepository.SearchByMethodAndPath(ctx, "GET", "/copies/1")
+	if !assert.Nil(t, err) || !assert.Len(t, got, 1) {
+		return
+	}
+
+	// Serving a request writes to the rule, none of it can reach the next request.
+	got[0].Variables[0].Value = "LEAKED"
+	*got[0].Variables[0].Min = 99
+	got[0].Responses[0].Body = "LEAKED"
+	got[0].Responses[0].Headers["X-Id"] = "LEAKED"
+	got[0].Responses[0].Cookies[0].Value = "LEAKED"
+	got[0].Responses[0].Webhook.Headers["X-Id"] = "LEAKED"
+
+	got, err = indexedRepository.SearchByMethodAndPath(ctx, "GET", "/copies/1")
+	if !assert.Nil(t, err) || !assert.Len(t, got, 1) {
+		return
+	}
+
+	assert.Equal(t, "", got[0].Variables[0].Value)
+	assert.Equal(t, 1.0, *got[0].Variables[0].Min)
+	assert.Equal(t, "{id}", got[0].Responses[0].Body)
+	assert.Equal(t, "{id}", got[0].Responses[0].Headers["X-Id"])
+	assert.Equal(t, "{id}", got[0].Responses[0].Cookies[0].Value)
+	assert.Equal(t, "{id}", got[0].Responses[0].Webhook.Headers["X-Id"])
+}
+
+func Test_indexedRuleRepository_LoadsEveryPage(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	ruleRepositoryMock := mocks.NewMockRuleRepository(mockCtrl)
+
+	page := func(key, lastID string) *model.RuleList {
+		return &model.RuleList{
+			Paging: model.Paging{LastID: lastID},
+			Results: []*model.Rule{
+				{Key: key, Path: "/pages", Method: "GET", Status: model.RuleStatusEnabled},
+			},
+		}
+	}
+
+	// The first page is shorter than the limit, like a DynamoDB scan that reached its size limit.
+	gomock.InOrder(
+		ruleRepositoryMock.EXPECT().Search(gomock.Any(), gomock.Any(), model.Paging{Limit: 500}).
+			Return(page("k2", "k2"), nil),
+		ruleRepositoryMock.EXPECT().Search(gomock.Any(), gomock.Any(), model.Paging{Limit: 500, LastID: "k2"}).
+			Return(page("k1", ""), nil),
+	)
+
+	got, err := repository.NewIndexedRuleRepository(ruleRepositoryMock).
+		SearchByMethodAndPath(context.Background(), "GET", "/pages")
+	assert.Nil(t, err)
+	assert.Len(t, got, 2)
+}
+
+func BenchmarkSearchByMethodAndPath(b *testing.B) {
+	const rules = 2000
+
+	fileRepository, err := repository.NewRuleFileRepository(&configs.Config{
+		MocksFile: filepath.Join(b.TempDir(), "mocks.json"),
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	sqliteRepository := repository.NewRuleSQLRepository(newSQLiteDB(b, filepath.Join(b.TempDir(), "mocks.db")))
+
+	for index := 0; index < rules; index++ {
+		for _, ruleRepository := range []repository.RuleRepository{fileRepository, sqliteRepository} {
+			_, err = ruleRepository.Create(context.Background(), &model.Rule{
+				Name:      fmt.Sprintf("rule %d", index),
+				Path:      fmt.Sprintf("/v1/resource%d/{id}/items/{item}", index),
+				Method:    "GET",
+				Status:    model.RuleStatusEnabled,
+				Strategy:  model.RuleStrategyNormal,
+				Responses: []model.Response{{Body: "{}", HTTPStatus: http.StatusOK}},
+			})
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+
+	benchmarks := []struct {
+		name       string
+		repository repository.RuleRepository
+	}{
+		{name: "file", repository: fileRepository},
+		{name: "indexed", repository: repository.NewIndexedRuleRepository(fileRepository)},
+		{name: "sqlite", repository: sqliteRepository},
+	}
+
+	path := fmt.Sprintf("/v1/resource%d/42/items/7", rules/2)
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				got, err := bm.repository.SearchByMethodAndPath(context.Background(), "GET", path)
+				if err != nil || len(got) != 1 {
+					b.Fatalf("unexpected result: %v, %v", got, err)
+				}
+			}
+		})
+	}
+}
+
+func rulePaths(rules []*model.Rule) []string {
+	paths := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		paths = append(paths, rule.Path)
+	}
+
+	return paths
+}
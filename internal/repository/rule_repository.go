@@ -4,31 +4,74 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/nicopozo/mockserver/internal/model"
 )
 
 //go:generate mockgen -destination=../utils/test/mocks/rule_repository_mock.go -package=mocks -source=./rule_repository.go
 
+// RuleRepository persists rules. SearchByMethodAndPath returns every enabled rule whose method and
+// path pattern match, sorted with SortByPrecedence; request matchers are evaluated by the caller.
+// Search returns in Paging.LastID the LastID of the next page, or an empty string when there are no more
+// rules.
 type RuleRepository interface {
 	Create(ctx context.Context, rule *model.Rule) (*model.Rule, error)
 	Update(ctx context.Context, rule *model.Rule) (*model.Rule, error)
 	Get(ctx context.Context, key string) (*model.Rule, error)
 	Search(ctx context.Context, params map[string]interface{}, paging model.Paging) (*model.RuleList, error)
-	SearchByMethodAndPath(ctx context.Context, method string, path string) (*model.Rule, error)
+	SearchByMethodAndPath(ctx context.Context, method string, path string) ([]*model.Rule, error)
 	Delete(ctx context.Context, key string) error
+	// AdvanceResponse returns the index of the response that a sequential rule with the given number of
+	// responses serves next, and moves the rule on to the following one. Both happen as a single step, so
+	// concurrent requests to the rule are each served a different turn.
+	AdvanceResponse(ctx context.Context, key string, responses int) (int, error)
 }
 
+// ReloadableRuleRepository is a RuleRepository whose rules can be changed outside the API, like the
+// mocks file of the file datasource when it is edited by hand.
+type ReloadableRuleRepository interface {
+	RuleRepository
+	// Reload reads the rules again when their source changed since it was last read or written, and
+	// reports whether they were replaced. Every rule goes through check, which returns the rule to keep;
+	// when the source cannot be read or a rule fails the check, the current rules are kept.
+	Reload(ctx context.Context, check func(rule model.Rule) (model.Rule, error)) (bool, error)
+}
+
+// nextPageID returns the LastID of the page that follows results, which is empty when results is shorter
+// than limit, since then there are no more rules.
+func nextPageID(results []*model.Rule, limit int32) string {
+	if len(results) == 0 || len(results) < int(limit) {
+		return ""
+	}
+
+	return results[len(results)-1].Key
+}
+
+// responseTurn returns the response that a sequential rule serves when its next response index is next,
+// which starts over after the last one.
+func responseTurn(next, responses int) int {
+	if next >= responses {
+		return 0
+	}
+
+	return next
+}
+
+var (
+	paramSegmentRegex = regexp.MustCompile("{.+?}/")
+	paramRegex        = regexp.MustCompile("{.+?}")
+)
+
 func CreateExpression(path string) string {
-	paramRegex := regexp.MustCompile("{.+?}/")
-	params := paramRegex.FindAllString(path, -1)
+	params := paramSegmentRegex.FindAllString(path, -1)
 
 	for _, param := range params {
 		path = strings.ReplaceAll(path, param, "[^/]+?/")
 	}
 
-	paramRegex = regexp.MustCompile("{.+?}")
 	params = paramRegex.FindAllString(path, -1)
 
 	for _, param := range params {
@@ -37,3 +80,122 @@ func CreateExpression(path string) string {
 
 	return fmt.Sprintf("^%s$", path)
 }
+
+// SortByPrecedence orders candidate rules the way every repository must resolve them: higher priority
+// first, then rules that are not resources, then the most specific path (fewer placeholders, then more
+// literal segments), then rules with more matchers, then rules that require a scenario state, and finally
+// by key so that the order is stable across backends.
+func SortByPrecedence(rules []*model.Rule) {
+	sort.SliceStable(rules, func(i, j int) bool {
+		left, right := rules[i], rules[j]
+
+		if left.Priority != right.Priority {
+			return left.Priority > right.Priority
+		}
+
+		if left.IsResource() != right.IsResource() {
+			return right.IsResource()
+		}
+
+		leftPlaceholders, leftLiterals := pathSpecificity(left.Path)
+		rightPlaceholders, rightLiterals := pathSpecificity(right.Path)
+
+		if leftPlaceholders != rightPlaceholders {
+			return leftPlaceholders < rightPlaceholders
+		}
+
+		if leftLiterals != rightLiterals {
+			return leftLiterals > rightLiterals
+		}
+
+		if len(left.Matchers) != len(right.Matchers) {
+			return len(left.Matchers) > len(right.Matchers)
+		}
+
+		if leftState, rightState := requiresScenarioState(left), requiresScenarioState(right); leftState != rightState {
+			return leftState
+		}
+
+		return left.Key < right.Key
+	})
+}
+
+func pathSpecificity(path string) (int, int) {
+	placeholders, literals := 0, 0
+
+	for _, segment := range strings.Split(path, "/") {
+		switch {
+		case segment == "":
+			continue
+		case strings.Contains(segment, "{"):
+			placeholders++
+		default:
+			literals++
+		}
+	}
+
+	return placeholders, literals
+}
+
+// requiresScenarioState reports whether the rule is only selected in a given scenario state, which makes it
+// more specific than an otherwise equal rule.
+func requiresScenarioState(rule *model.Rule) bool {
+	return rule.Scenario != nil && rule.Scenario.RequiredState != ""
+}
+
+// matchesRoute reports whether one of the routes of the rule answers to method and path.
+func matchesRoute(rule *model.Rule, method, path string) bool {
+	for _, route := range rule.Routes() {
+		if route.Method != method {
+			continue
+		}
+
+		if regex, err := rulePatterns.compile(CreateExpression(route.Path)); err == nil && regex.MatchString(path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// maxCachedPatterns bounds patternCache, which starts over when it is full.
+const maxCachedPatterns = 10000
+
+// rulePatterns keeps the compiled patterns of the rules for the repositories that read the patterns from
+// their storage on every request.
+//
+//nolint:gochecknoglobals
+var rulePatterns = &patternCache{regexes: make(map[string]*regexp.Regexp)}
+
+// patternCache compiles each pattern once. A compiled pattern only depends on its text, so a rule whose
+// path changes gets the entry of its new pattern and no entry is ever stale.
+type patternCache struct {
+	mu      sync.RWMutex
+	regexes map[string]*regexp.Regexp
+}
+
+func (cache *patternCache) compile(pattern string) (*regexp.Regexp, error) {
+	cache.mu.RLock()
+	regex, found := cache.regexes[pattern]
+	cache.mu.RUnlock()
+
+	if found {
+		return regex, nil
+	}
+
+	regex, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex pattern %s: %w", pattern, err)
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if len(cache.regexes) >= maxCachedPatterns {
+		cache.regexes = make(map[string]*regexp.Regexp)
+	}
+
+	cache.regexes[pattern] = regex
+
+	return regex, nil
+}
@@ -3,6 +3,7 @@ package repository_test
 import (
 	"testing"
 
+	"github.com/nicopozo/mockserver/internal/model"
 	"github.com/nicopozo/mockserver/internal/repository"
 	"github.com/stretchr/testify/assert"
 )
@@ -47,3 +48,73 @@ func Test_CreateExpression(t *testing.T) {
 		})
 	}
 }
+
+func Test_SortByPrecedence(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []*model.Rule
+		want  []string
+	}{
+		{
+			name: "literal path wins over placeholder",
+			rules: []*model.Rule{
+				{Key: "a", Path: "/v1/users/{id}"},
+				{Key: "b", Path: "/v1/users/me"},
+			},
+			want: []string{"b", "a"},
+		},
+		{
+			name: "higher priority wins over specificity",
+			rules: []*model.Rule{
+				{Key: "a", Path: "/v1/users/me"},
+				{Key: "b", Path: "/v1/users/{id}", Priority: 10},
+			},
+			want: []string{"b", "a"},
+		},
+		{
+			name: "more literal segments win when placeholders are equal",
+			rules: []*model.Rule{
+				{Key: "a", Path: "/v1/{id}"},
+				{Key: "b", Path: "/v1/{id}/detail"},
+			},
+			want: []string{"b", "a"},
+		},
+		{
+			name: "rules with matchers win over rules without",
+			rules: []*model.Rule{
+				{Key: "a", Path: "/v1/payments"},
+				{Key: "b", Path: "/v1/payments", Matchers: []model.Matcher{{Type: "query", Key: "type", Operator: "present"}}},
+			},
+			want: []string{"b", "a"},
+		},
+		{
+			name: "rules that require a scenario state win over rules without",
+			rules: []*model.Rule{
+				{Key: "a", Path: "/v1/orders", Scenario: &model.RuleScenario{Name: "checkout", NewState: "paid"}},
+				{Key: "b", Path: "/v1/orders", Scenario: &model.RuleScenario{Name: "checkout", RequiredState: "paid"}},
+			},
+			want: []string{"b", "a"},
+		},
+		{
+			name: "key breaks ties",
+			rules: []*model.Rule{
+				{Key: "b", Path: "/v1/payments"},
+				{Key: "a", Path: "/v1/payments"},
+			},
+			want: []string{"a", "b"},
+		},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			repository.SortByPrecedence(tt.rules)
+
+			got := make([]string, 0, len(tt.rules))
+			for _, rule := range tt.rules {
+				got = append(got, rule.Key)
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/nicopozo/mockserver/internal/configs"
+	"github.com/nicopozo/mockserver/internal/model"
+	jsonutils "github.com/nicopozo/mockserver/internal/utils/json"
+)
+
+// DynamoRuleRevisionRepository stores one item per revision, with the rule key as partition key and the
+// revision number as sort key. The rule of the revision is kept as a JSON document.
+type DynamoRuleRevisionRepository struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+type ruleRevisionItem struct {
+	RuleKey   string `dynamodbav:"rule_key"`
+	Revision  int    `dynamodbav:"revision"`
+	Action    string `dynamodbav:"action"`
+	Author    string `dynamodbav:"author"`
+	CreatedAt int64  `dynamodbav:"created_at"`
+	Data      string `dynamodbav:"data"`
+}
+
+// NewDynamoRuleRevisionRepository creates a new RuleRevisionRepository for DynamoDB.
+func NewDynamoRuleRevisionRepository(client *dynamodb.Client, cfg *configs.Config) RuleRevisionRepository {
+	return &DynamoRuleRevisionRepository{
+		client:    client,
+		tableName: cfg.Dynamo.TablePrefix + "rule_revisions",
+	}
+}
+
+// Append puts the revision with the number that follows the last one of its rule. The put is conditional,
+// so when two instances append to the same rule at once, one of them tries again with the next number.
+func (r *DynamoRuleRevisionRepository) Append(ctx context.Context,
+	revision model.RuleRevision,
+) (model.RuleRevision, error) {
+	for range ruleRevisionAppendAttempts {
+		last, err := r.lastRevision(ctx, revision.RuleKey)
+		if err != nil {
+			return model.RuleRevision{}, err
+		}
+
+		revision.Revision = last + 1
+
+		item, err := attributevalue.MarshalMap(ruleRevisionItem{
+			RuleKey:   revision.RuleKey,
+			Revision:  revision.Revision,
+			Action:    revision.Action,
+			Author:    revision.Author,
+			CreatedAt: revision.CreatedAt.UnixMilli(),
+			Data:      jsonutils.Marshal(revision.Rule),
+		})
+		if err != nil {
+			return model.RuleRevision{}, fmt.Errorf("error marshaling revision: %w", err)
+		}
+
+		_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+			TableName:           aws.String(r.tableName),
+			Item:                item,
+			ConditionExpression: aws.String("attribute_not_exists(revision)"),
+		})
+
+		var conditionFailed *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionFailed) {
+			continue
+		}
+
+		if err != nil {
+			return model.RuleRevision{}, fmt.Errorf("error saving revision in DynamoDB: %w", err)
+		}
+
+		return revision, nil
+	}
+
+	return model.RuleRevision{}, fmt.Errorf("error saving revision in DynamoDB: revision %d of rule %s "+
+		"was taken by another instance", revision.Revision, revision.RuleKey) //nolint:err113
+}
+
+func (r *DynamoRuleRevisionRepository) List(ctx context.Context, ruleKey string) ([]model.RuleRevision, error) {
+	revisions := make([]model.RuleRevision, 0)
+
+	paginator := dynamodb.NewQueryPaginator(r.client, &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		KeyConditionExpression: aws.String("rule_key = :rule_key"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":rule_key": &types.AttributeValueMemberS{Value: ruleKey},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error querying revisions in DynamoDB: %w", err)
+		}
+
+		var items []ruleRevisionItem
+
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &items); err != nil {
+			return nil, fmt.Errorf("error unmarshaling revisions: %w", err)
+		}
+
+		for _, item := range items {
+			revision, err := item.toModel()
+			if err != nil {
+				return nil, err
+			}
+
+			revisions = append(revisions, revision)
+		}
+	}
+
+	return revisions, nil
+}
+
+func (r *DynamoRuleRevisionRepository) Get(ctx context.Context, ruleKey string,
+	revision int,
+) (*model.RuleRevision, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"rule_key": &types.AttributeValueMemberS{Value: ruleKey},
+			"revision": &types.AttributeValueMemberN{Value: strconv.Itoa(revision)},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting revision from DynamoDB: %w", err)
+	}
+
+	if result.Item == nil {
+		return nil, ruleRevisionNotFound(ruleKey, revision)
+	}
+
+	var item ruleRevisionItem
+
+	if err := attributevalue.UnmarshalMap(result.Item, &item); err != nil {
+		return nil, fmt.Errorf("error unmarshaling revision: %w", err)
+	}
+
+	ruleRevision, err := item.toModel()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ruleRevision, nil
+}
+
+// lastRevision reads the number of the last revision of a rule, 0 when it has none.
+func (r *DynamoRuleRevisionRepository) lastRevision(ctx context.Context, ruleKey string) (int, error) {
+	result, err := r.client.Query(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		KeyConditionExpression: aws.String("rule_key = :rule_key"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":rule_key": &types.AttributeValueMemberS{Value: ruleKey},
+		},
+		ProjectionExpression: aws.String("revision"),
+		ScanIndexForward:     aws.Bool(false),
+		Limit:                aws.Int32(1),
+		ConsistentRead:       aws.Bool(true),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error querying last revision in DynamoDB: %w", err)
+	}
+
+	if len(result.Items) == 0 {
+		return 0, nil
+	}
+
+	var item ruleRevisionItem
+
+	if err := attributevalue.UnmarshalMap(result.Items[0], &item); err != nil {
+		return 0, fmt.Errorf("error unmarshaling revision: %w", err)
+	}
+
+	return item.Revision, nil
+}
+
+func (item ruleRevisionItem) toModel() (model.RuleRevision, error) {
+	var rule model.Rule
+
+	if err := jsonutils.Unmarshal(strings.NewReader(item.Data), &rule); err != nil {
+		return model.RuleRevision{}, fmt.Errorf("error unmarshaling revision %d of rule %s: %w", item.Revision,
+			item.RuleKey, err)
+	}
+
+	return model.RuleRevision{
+		RuleKey:   item.RuleKey,
+		Revision:  item.Revision,
+		Action:    item.Action,
+		Author:    item.Author,
+		CreatedAt: time.UnixMilli(item.CreatedAt).UTC(),
+		Rule:      rule,
+	}, nil
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/nicopozo/mockserver/internal/configs"
+	"github.com/nicopozo/mockserver/internal/model"
+	jsonutils "github.com/nicopozo/mockserver/internal/utils/json"
+)
+
+type ruleRevisionFileRepository struct {
+	mu        sync.RWMutex
+	revisions map[string][]model.RuleRevision
+	filePath  string
+}
+
+// NewRuleRevisionFileRepository creates a RuleRevisionRepository that keeps the revisions of every rule in
+// cfg.RevisionsFile.
+func NewRuleRevisionFileRepository(cfg *configs.Config) (RuleRevisionRepository, error) {
+	repo := &ruleRevisionFileRepository{
+		revisions: make(map[string][]model.RuleRevision),
+		filePath:  cfg.RevisionsFile,
+	}
+
+	file, err := os.Open(repo.filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return repo, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error reading revisions file: %s - %w", repo.filePath, err)
+	}
+
+	defer func(f *os.File) { _ = f.Close() }(file)
+
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("error reading revisions file: %s - %w", repo.filePath, err)
+	}
+
+	if stat.Size() > 0 {
+		if err := jsonutils.Unmarshal(file, &repo.revisions); err != nil {
+			return nil, fmt.Errorf("error unmarshalling revisions file: %s - %w", repo.filePath, err)
+		}
+	}
+
+	return repo, nil
+}
+
+func (repository *ruleRevisionFileRepository) Append(_ context.Context,
+	revision model.RuleRevision,
+) (model.RuleRevision, error) {
+	repository.mu.Lock()
+	defer repository.mu.Unlock()
+
+	current := repository.revisions[revision.RuleKey]
+	revision.Revision = len(current) + 1
+
+	// The stored slice is never modified in place, so the revisions returned by List stay untouched.
+	revisions := make([]model.RuleRevision, len(current), len(current)+1)
+	copy(revisions, current)
+	revisions = append(revisions, revision)
+
+	repository.revisions[revision.RuleKey] = revisions
+
+	if err := repository.saveFile(); err != nil {
+		repository.revisions[revision.RuleKey] = current
+
+		return model.RuleRevision{}, err
+	}
+
+	return revision, nil
+}
+
+func (repository *ruleRevisionFileRepository) List(_ context.Context, ruleKey string) ([]model.RuleRevision, error) {
+	repository.mu.RLock()
+	defer repository.mu.RUnlock()
+
+	revisions := repository.revisions[ruleKey]
+	if revisions == nil {
+		return make([]model.RuleRevision, 0), nil
+	}
+
+	return revisions, nil
+}
+
+func (repository *ruleRevisionFileRepository) Get(_ context.Context, ruleKey string,
+	revision int,
+) (*model.RuleRevision, error) {
+	repository.mu.RLock()
+	defer repository.mu.RUnlock()
+
+	revisions := repository.revisions[ruleKey]
+	if revision < 1 || revision > len(revisions) {
+		return nil, ruleRevisionNotFound(ruleKey, revision)
+	}
+
+	result := revisions[revision-1]
+
+	return &result, nil
+}
+
+func (repository *ruleRevisionFileRepository) saveFile() error {
+	err := writeFileAtomically(repository.filePath, []byte(jsonutils.Marshal(repository.revisions)))
+	if err != nil {
+		return fmt.Errorf("error saving revisions file: %s - %w", repository.filePath, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package repository_test
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nicopozo/mockserver/internal/configs"
+	mockscontext "github.com/nicopozo/mockserver/internal/context"
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
+	"github.com/nicopozo/mockserver/internal/model"
+	"github.com/nicopozo/mockserver/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuleRevisionFileRepository(t *testing.T) {
+	cfg := &configs.Config{RevisionsFile: filepath.Join(t.TempDir(), "revisions.json")}
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	repo, err := repository.NewRuleRevisionFileRepository(cfg)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	appendRuleRevisions(t, repo, now)
+
+	// A new repository reads the revisions saved by the previous one.
+	repo, err = repository.NewRuleRevisionFileRepository(cfg)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assertRuleRevisions(t, repo, now)
+}
+
+// appendRuleRevisions appends two revisions to rule k1 and one to rule k2.
+func appendRuleRevisions(t *testing.T, repo repository.RuleRevisionRepository, now time.Time) {
+	t.Helper()
+
+	ctx := mockscontext.Background()
+	rule := model.Rule{Key: "k1", Name: "get payment", Path: "/v1/payments", Method: http.MethodGet,
+		Responses: []model.Response{{Body: `{"id":1}`, HTTPStatus: http.StatusOK}}}
+
+	for index, revision := range []model.RuleRevision{
+		{RuleKey: "k1", Action: model.RuleRevisionCreate, Author: "ci", CreatedAt: now, Rule: rule},
+		{RuleKey: "k2", Action: model.RuleRevisionCreate, CreatedAt: now, Rule: model.Rule{Key: "k2"}},
+		{RuleKey: "k1", Action: model.RuleRevisionDelete, Author: "ci", CreatedAt: now.Add(time.Minute), Rule: rule},
+	} {
+		got, err := repo.Append(ctx, revision)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []int{1, 1, 2}[index], got.Revision, "revisions are numbered per rule")
+		}
+	}
+}
+
+func assertRuleRevisions(t *testing.T, repo repository.RuleRevisionRepository, now time.Time) {
+	t.Helper()
+
+	ctx := mockscontext.Background()
+
+	revisions, err := repo.List(ctx, "k1")
+	if assert.NoError(t, err) && assert.Len(t, revisions, 2) {
+		assert.Equal(t, 1, revisions[0].Revision)
+		assert.Equal(t, model.RuleRevisionCreate, revisions[0].Action)
+		assert.Equal(t, 2, revisions[1].Revision)
+		assert.Equal(t, model.RuleRevisionDelete, revisions[1].Action)
+		assert.Equal(t, "ci", revisions[1].Author)
+		assert.True(t, now.Add(time.Minute).Equal(revisions[1].CreatedAt))
+		assert.Equal(t, `{"id":1}`, revisions[1].Rule.Responses[0].Body)
+	}
+
+	revision, err := repo.Get(ctx, "k1", 2)
+	if assert.NoError(t, err) {
+		assert.Equal(t, model.RuleRevisionDelete, revision.Action)
+		assert.Equal(t, "get payment", revision.Rule.Name)
+	}
+
+	_, err = repo.Get(ctx, "k1", 3)
+	assert.ErrorAs(t, err, &mockserrors.RuleRevisionNotFoundError{})
+
+	revisions, err = repo.List(ctx, "unknown")
+	assert.NoError(t, err)
+	assert.Empty(t, revisions)
+}
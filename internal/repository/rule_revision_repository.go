@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
+	"github.com/nicopozo/mockserver/internal/model"
+)
+
+//go:generate mockgen -destination=../utils/test/mocks/rule_revision_repository_mock.go -package=mocks -source=./rule_revision_repository.go
+
+// RuleRevisionRepository keeps the revision history of the rules. It is append-only: Append stores the
+// revision with the next number of its rule and returns it, and revisions are never updated or deleted,
+// not even when their rule is. List returns the revisions of a rule sorted by number.
+type RuleRevisionRepository interface {
+	Append(ctx context.Context, revision model.RuleRevision) (model.RuleRevision, error)
+	List(ctx context.Context, ruleKey string) ([]model.RuleRevision, error)
+	Get(ctx context.Context, ruleKey string, revision int) (*model.RuleRevision, error)
+}
+
+func ruleRevisionNotFound(ruleKey string, revision int) error {
+	return mockserrors.RuleRevisionNotFoundError{
+		Message: fmt.Sprintf("revision %d not found for rule %s", revision, ruleKey),
+	}
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nicopozo/mockserver/internal/model"
+	jsonutils "github.com/nicopozo/mockserver/internal/utils/json"
+)
+
+// ruleRevisionAppendAttempts is how many times Append picks the next number of a rule when another
+// instance sharing the database inserted that number first.
+const ruleRevisionAppendAttempts = 3
+
+type ruleRevisionSQLRepository struct {
+	db Database
+}
+
+// RuleRevisionRow keeps the rule of the revision as JSON in data, so that later changes to the rules
+// tables do not affect the history.
+type RuleRevisionRow struct {
+	RuleKey   string    `db:"rule_key"`
+	Revision  int       `db:"revision"`
+	Action    string    `db:"action"`
+	Author    string    `db:"author"`
+	CreatedAt time.Time `db:"created_at"`
+	Data      string    `db:"data"`
+}
+
+func (row RuleRevisionRow) toModel() (model.RuleRevision, error) {
+	var rule model.Rule
+
+	if err := jsonutils.Unmarshal(strings.NewReader(row.Data), &rule); err != nil {
+		return model.RuleRevision{}, fmt.Errorf("error unmarshalling revision %d of rule %s: %w", row.Revision,
+			row.RuleKey, err)
+	}
+
+	return model.RuleRevision{
+		RuleKey:   row.RuleKey,
+		Revision:  row.Revision,
+		Action:    row.Action,
+		Author:    row.Author,
+		CreatedAt: row.CreatedAt.UTC(),
+		Rule:      rule,
+	}, nil
+}
+
+func NewRuleRevisionSQLRepository(db Database) RuleRevisionRepository {
+	return &ruleRevisionSQLRepository{
+		db: db,
+	}
+}
+
+// Append inserts the revision with the number that follows the last one of its rule. The rule key and the
+// number are the primary key, so when two instances append to the same rule at once, one of the inserts
+// fails and is tried again with the next number.
+func (r *ruleRevisionSQLRepository) Append(_ context.Context,
+	revision model.RuleRevision,
+) (model.RuleRevision, error) {
+	lastQuery := FormatQuery("SELECT COALESCE(MAX(revision), 0) FROM rule_revisions WHERE rule_key = ?",
+		r.db.DriverName())
+	insertQuery := FormatQuery("INSERT INTO rule_revisions (rule_key, revision, action, author, created_at, data) "+
+		"VALUES (?, ?, ?, ?, ?, ?)", r.db.DriverName())
+
+	var err error
+
+	for range ruleRevisionAppendAttempts {
+		var last int
+
+		if err = r.db.Get(&last, lastQuery, revision.RuleKey); err != nil {
+			return model.RuleRevision{}, fmt.Errorf("error fetching last revision from DB: %w", err)
+		}
+
+		revision.Revision = last + 1
+
+		_, err = r.db.Exec(insertQuery, revision.RuleKey, revision.Revision, revision.Action, revision.Author,
+			revision.CreatedAt.UTC(), jsonutils.Marshal(revision.Rule))
+		if err == nil {
+			return revision, nil
+		}
+	}
+
+	return model.RuleRevision{}, fmt.Errorf("error saving revision into DB: %w", err)
+}
+
+func (r *ruleRevisionSQLRepository) List(_ context.Context, ruleKey string) ([]model.RuleRevision, error) {
+	var rows []RuleRevisionRow
+
+	query := FormatQuery("SELECT rule_key, revision, action, author, created_at, data FROM rule_revisions "+
+		"WHERE rule_key = ? ORDER BY revision", r.db.DriverName())
+
+	if err := r.db.Select(&rows, query, ruleKey); err != nil {
+		return nil, fmt.Errorf("error fetching revisions from DB: %w", err)
+	}
+
+	revisions := make([]model.RuleRevision, 0, len(rows))
+
+	for _, row := range rows {
+		revision, err := row.toModel()
+		if err != nil {
+			return nil, err
+		}
+
+		revisions = append(revisions, revision)
+	}
+
+	return revisions, nil
+}
+
+func (r *ruleRevisionSQLRepository) Get(_ context.Context, ruleKey string,
+	revision int,
+) (*model.RuleRevision, error) {
+	var row RuleRevisionRow
+
+	query := FormatQuery("SELECT rule_key, revision, action, author, created_at, data FROM rule_revisions "+
+		"WHERE rule_key = ? AND revision = ?", r.db.DriverName())
+
+	err := r.db.Get(&row, query, ruleKey, revision)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ruleRevisionNotFound(ruleKey, revision)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error fetching revision from DB: %w", err)
+	}
+
+	result, err := row.toModel()
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
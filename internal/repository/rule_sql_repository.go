@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"regexp"
 	"strings"
 
 	"github.com/jmoiron/sqlx"
@@ -22,15 +21,20 @@ type ruleSQLRepository struct {
 }
 
 type RuleRow struct {
-	Key               string `db:"key"`
-	Group             string `db:"group"`
-	Name              string `db:"name"`
-	Path              string `db:"path"`
-	Strategy          string `db:"strategy"`
-	Method            string `db:"method"`
-	Status            string `db:"status"`
-	Pattern           string `db:"pattern"`
-	NextResponseIndex int    `db:"next_response_index"`
+	Key               string  `db:"key"`
+	Group             string  `db:"group"`
+	Name              string  `db:"name"`
+	Path              string  `db:"path"`
+	Strategy          string  `db:"strategy"`
+	Method            string  `db:"method"`
+	Status            string  `db:"status"`
+	Pattern           string  `db:"pattern"`
+	NextResponseIndex int     `db:"next_response_index"`
+	Priority          int     `db:"priority"`
+	Matchers          *string `db:"matchers"`
+	Scenario          *string `db:"scenario"`
+	Resource          *string `db:"resource"`
+	Chaos             *string `db:"chaos"`
 }
 
 type VariableRow struct {
@@ -54,10 +58,15 @@ type ResponseRow struct {
 	Scene       *string `db:"scene"`
 	RuleKey     string  `db:"rule_key"`
 	Description *string `db:"description"`
+	Headers     *string `db:"headers"`
+	Cookies     *string `db:"cookies"`
 	Webhook     *string `db:"webhook"`
+	Webhooks    *string `db:"webhooks"`
+	WebhookMode *string `db:"webhook_mode"`
+	Fault       *string `db:"fault"`
 }
 
-// NewRuleSQLRepository creates a repository that works with both MySQL and PostgreSQL.
+// NewRuleSQLRepository creates a repository that works with MySQL, PostgreSQL and SQLite.
 // The driver is inspected at runtime to translate queries accordingly.
 func NewRuleSQLRepository(db Database) RuleRepository {
 	return &ruleSQLRepository{
@@ -76,8 +85,8 @@ func (repository *ruleSQLRepository) Create(ctx context.Context, rule *model.Rul
 	var err error
 
 	query := FormatQuery(
-		"INSERT INTO rules (`key`, `group`, name, path, strategy, method, status, pattern, next_response_index) "+
-			" VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		"INSERT INTO rules (`key`, `group`, name, path, strategy, method, status, pattern, next_response_index, "+
+			"priority, matchers, scenario, resource, chaos) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
 		repository.db.DriverName(),
 	)
 
@@ -95,7 +104,9 @@ func (repository *ruleSQLRepository) Create(ctx context.Context, rule *model.Rul
 	}
 
 	_, err = trx.ExecContext(ctx, query, rule.Key, rule.Group, rule.Name, rule.Path,
-		rule.Strategy, rule.Method, rule.Status, CreateExpression(rule.Path), rule.NextResponseIndex)
+		rule.Strategy, rule.Method, rule.Status, CreateExpression(rule.Path), rule.NextResponseIndex, rule.Priority,
+		marshalMatchers(rule.Matchers), marshalScenario(rule.Scenario), marshalResource(rule.Resource),
+		marshalChaos(rule.Chaos))
 	if err != nil {
 		logger.Error(repository, nil, err, "error creating rule in DB")
 
@@ -122,7 +133,7 @@ func (repository *ruleSQLRepository) Update(ctx context.Context, rule *model.Rul
 
 	query := FormatQuery(
 		"UPDATE rules SET `group`=?, name=?, path=?, strategy=?, method=?, status=?, pattern=?,"+
-			" next_response_index=? WHERE `key`=?",
+			" next_response_index=?, priority=?, matchers=?, scenario=?, resource=?, chaos=? WHERE `key`=?",
 		repository.db.DriverName(),
 	)
 
@@ -136,7 +147,8 @@ func (repository *ruleSQLRepository) Update(ctx context.Context, rule *model.Rul
 	}()
 
 	res, err := trx.ExecContext(ctx, query, rule.Group, rule.Name, rule.Path, rule.Strategy, rule.Method, rule.Status,
-		CreateExpression(rule.Path), rule.NextResponseIndex, rule.Key)
+		CreateExpression(rule.Path), rule.NextResponseIndex, rule.Priority, marshalMatchers(rule.Matchers),
+		marshalScenario(rule.Scenario), marshalResource(rule.Resource), marshalChaos(rule.Chaos), rule.Key)
 	if err != nil {
 		logger.Error(repository, nil, err, "error updating rule in DB")
 
@@ -269,7 +281,58 @@ func (repository *ruleSQLRepository) Get(ctx context.Context, key string) (*mode
 		return nil, fmt.Errorf("error getting responses for rule with key '%s' from DB, %w", key, err)
 	}
 
-	return parseRule(row, variables, responses), nil
+	return parseRule(row, variables, responses)
+}
+
+// AdvanceResponse moves the next response index with a compare-and-set, so that instances sharing the
+// database do not serve the same turn. An update that finds the index already moved reads it again.
+func (repository *ruleSQLRepository) AdvanceResponse(ctx context.Context, key string,
+	responses int,
+) (int, error) {
+	logger := mockscontext.Logger(ctx)
+
+	selectQuery := FormatQuery("SELECT next_response_index FROM rules WHERE `key` = ?", repository.db.DriverName())
+	updateQuery := FormatQuery("UPDATE rules SET next_response_index = ? WHERE `key` = ? AND next_response_index = ?",
+		repository.db.DriverName())
+
+	for {
+		var next int
+
+		err := repository.db.Get(&next, selectQuery, key)
+		if err != nil && err.Error() == noRowsMessage {
+			return 0, mockserrors.RuleNotFoundError{
+				Message: fmt.Sprintf("no rule found with key: %s", key),
+			}
+		}
+
+		if err != nil {
+			logger.Error(repository, nil, err, "error getting next response index from DB")
+
+			return 0, fmt.Errorf("error getting next response index from DB, %w", err)
+		}
+
+		current := responseTurn(next, responses)
+		if current+1 == next {
+			// The index does not change, MySQL would report the update as not affecting the row.
+			return current, nil
+		}
+
+		result, err := repository.db.Exec(updateQuery, current+1, key, next)
+		if err != nil {
+			logger.Error(repository, nil, err, "error updating next response index in DB")
+
+			return 0, fmt.Errorf("error updating next response index in DB, %w", err)
+		}
+
+		updated, err := result.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("error updating next response index in DB, %w", err)
+		}
+
+		if updated == 1 {
+			return current, nil
+		}
+	}
 }
 
 func (repository *ruleSQLRepository) Search(ctx context.Context, params map[string]interface{},
@@ -321,6 +384,8 @@ func (repository *ruleSQLRepository) Search(ctx context.Context, params map[stri
 		}
 	}
 
+	paging.LastID = nextPageID(rules, paging.Limit)
+
 	return &model.RuleList{Paging: paging, Results: rules}, nil
 }
 
@@ -366,38 +431,61 @@ func (repository *ruleSQLRepository) Delete(ctx context.Context, key string) err
 
 func (repository *ruleSQLRepository) SearchByMethodAndPath(ctx context.Context, method string,
 	path string,
-) (*model.Rule, error) {
+) ([]*model.Rule, error) {
 	logger := mockscontext.Logger(ctx)
 
 	var err error
 
 	var rows []RuleRow
 
+	// Resource rules are stored with the ANY method and answer to the routes of their collection.
 	searchQuery := FormatQuery(
-		"SELECT `key`, pattern, status FROM rules WHERE "+columnMethod+" = ?",
+		"SELECT `key`, "+columnMethod+", pattern, status FROM rules WHERE "+columnMethod+" IN (?, ?)",
 		repository.db.DriverName(),
 	)
 
-	err = repository.db.Select(&rows, searchQuery, strings.ToUpper(method))
+	method = strings.ToUpper(method)
+
+	err = repository.db.Select(&rows, searchQuery, method, model.RuleMethodAny)
 	if err != nil {
 		logger.Error(repository, nil, err, "error executing SQL query")
 
 		return nil, fmt.Errorf("error searching rules in DB, %w", err)
 	}
 
+	candidates := make([]*model.Rule, 0)
+
 	for _, row := range rows {
-		regex := regexp.MustCompile(row.Pattern)
+		if row.Status != model.RuleStatusEnabled {
+			continue
+		}
+
+		if row.Method != model.RuleMethodAny {
+			regex, err := rulePatterns.compile(row.Pattern)
+			if err != nil {
+				logger.Error(repository, nil, err, "error compiling rule pattern")
+
+				return nil, err
+			}
 
-		if regex.MatchString(path) {
-			if row.Status == model.RuleStatusEnabled {
-				return repository.Get(ctx, row.Key)
+			if !regex.MatchString(path) {
+				continue
 			}
 		}
-	}
 
-	return nil, mockserrors.RuleNotFoundError{
-		Message: fmt.Sprintf("no rule found for path: %s and method %s", path, method),
+		rule, err := repository.Get(ctx, row.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		if row.Method != model.RuleMethodAny || matchesRoute(rule, method, path) {
+			candidates = append(candidates, rule)
+		}
 	}
+
+	SortByPrecedence(candidates)
+
+	return candidates, nil
 }
 
 func (repository *ruleSQLRepository) insertVariables(ctx context.Context, rule *model.Rule, trx *sqlx.Tx) error {
@@ -432,21 +520,45 @@ func (repository *ruleSQLRepository) insertResponses(ctx context.Context, rule *
 	logger := mockscontext.Logger(ctx)
 
 	query := FormatQuery(
-		"INSERT INTO responses (body, content_type, http_status, delay, scene, rule_key, description, webhook) "+
-			"VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		"INSERT INTO responses (body, content_type, http_status, delay, scene, rule_key, description, headers, "+
+			"cookies, webhook, webhooks, webhook_mode, fault) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
 		repository.db.DriverName(),
 	)
 
 	for _, resp := range rule.Responses {
-		var webhookJSON *string
+		var webhookJSON, webhooksJSON, webhookMode, headersJSON, cookiesJSON, fault *string
 
 		if resp.Webhook != nil {
 			w := jsonutils.Marshal(resp.Webhook)
 			webhookJSON = &w
 		}
 
+		if len(resp.Webhooks) > 0 {
+			w := jsonutils.Marshal(resp.Webhooks)
+			webhooksJSON = &w
+		}
+
+		if resp.WebhookMode != "" {
+			webhookMode = &resp.WebhookMode
+		}
+
+		if len(resp.Headers) > 0 {
+			h := jsonutils.Marshal(resp.Headers)
+			headersJSON = &h
+		}
+
+		if len(resp.Cookies) > 0 {
+			c := jsonutils.Marshal(resp.Cookies)
+			cookiesJSON = &c
+		}
+
+		if resp.Fault != "" {
+			fault = &resp.Fault
+		}
+
 		_, err := trx.ExecContext(ctx, query, resp.Body, resp.ContentType, resp.HTTPStatus,
-			resp.Delay, resp.Scene, rule.Key, resp.Description, webhookJSON)
+			resp.Delay, resp.Scene, rule.Key, resp.Description, headersJSON, cookiesJSON, webhookJSON, webhooksJSON,
+			webhookMode, fault)
 		if err != nil {
 			logger.Error(repository, nil, err, "error creating rule response in DB")
 
@@ -552,7 +664,7 @@ func parseVariables(variables []VariableRow) []*model.Variable {
 	return vars
 }
 
-func parseResponses(responses []ResponseRow) []model.Response {
+func parseResponses(responses []ResponseRow) ([]model.Response, error) {
 	resps := make([]model.Response, 0, len(responses))
 
 	for _, row := range responses {
@@ -574,20 +686,158 @@ func parseResponses(responses []ResponseRow) []model.Response {
 			newResp.Description = *row.Description
 		}
 
+		if row.Fault != nil {
+			newResp.Fault = *row.Fault
+		}
+
+		if row.Headers != nil && *row.Headers != "" {
+			if err := json.Unmarshal([]byte(*row.Headers), &newResp.Headers); err != nil {
+				return nil, fmt.Errorf("error unmarshalling headers of response %d of rule %s: %w", row.ID,
+					row.RuleKey, err)
+			}
+		}
+
+		if row.Cookies != nil && *row.Cookies != "" {
+			if err := json.Unmarshal([]byte(*row.Cookies), &newResp.Cookies); err != nil {
+				return nil, fmt.Errorf("error unmarshalling cookies of response %d of rule %s: %w", row.ID,
+					row.RuleKey, err)
+			}
+		}
+
 		if row.Webhook != nil && *row.Webhook != "" {
 			var webhook model.WebhookConfig
-			if err := json.Unmarshal([]byte(*row.Webhook), &webhook); err == nil {
-				newResp.Webhook = &webhook
+			if err := json.Unmarshal([]byte(*row.Webhook), &webhook); err != nil {
+				return nil, fmt.Errorf("error unmarshalling webhook of response %d of rule %s: %w", row.ID,
+					row.RuleKey, err)
 			}
+
+			newResp.Webhook = &webhook
+		}
+
+		if row.Webhooks != nil && *row.Webhooks != "" {
+			if err := json.Unmarshal([]byte(*row.Webhooks), &newResp.Webhooks); err != nil {
+				return nil, fmt.Errorf("error unmarshalling webhooks of response %d of rule %s: %w", row.ID,
+					row.RuleKey, err)
+			}
+		}
+
+		if row.WebhookMode != nil {
+			newResp.WebhookMode = *row.WebhookMode
 		}
 
 		resps = append(resps, newResp)
 	}
 
-	return resps
+	return resps, nil
+}
+
+func marshalMatchers(matchers []model.Matcher) *string {
+	if len(matchers) == 0 {
+		return nil
+	}
+
+	m := jsonutils.Marshal(matchers)
+
+	return &m
+}
+
+func parseMatchers(matchers *string) ([]model.Matcher, error) {
+	if matchers == nil || *matchers == "" {
+		return nil, nil
+	}
+
+	var result []model.Matcher
+
+	if err := jsonutils.Unmarshal(strings.NewReader(*matchers), &result); err != nil {
+		return nil, fmt.Errorf("error unmarshalling matchers: %w", err)
+	}
+
+	return result, nil
 }
 
-func parseRule(row RuleRow, variables []VariableRow, responses []ResponseRow) *model.Rule {
+func marshalScenario(scenario *model.RuleScenario) *string {
+	if scenario == nil {
+		return nil
+	}
+
+	s := jsonutils.Marshal(scenario)
+
+	return &s
+}
+
+func parseScenario(scenario *string) *model.RuleScenario {
+	if scenario == nil || *scenario == "" {
+		return nil
+	}
+
+	result := &model.RuleScenario{}
+
+	if err := jsonutils.Unmarshal(strings.NewReader(*scenario), result); err != nil {
+		return nil
+	}
+
+	return result
+}
+
+func marshalResource(resource *model.ResourceConfig) *string {
+	if resource == nil {
+		return nil
+	}
+
+	r := jsonutils.Marshal(resource)
+
+	return &r
+}
+
+func parseResource(resource *string) *model.ResourceConfig {
+	if resource == nil || *resource == "" {
+		return nil
+	}
+
+	result := &model.ResourceConfig{}
+
+	if err := jsonutils.Unmarshal(strings.NewReader(*resource), result); err != nil {
+		return nil
+	}
+
+	return result
+}
+
+func marshalChaos(chaos *model.ChaosPolicy) *string {
+	if chaos == nil {
+		return nil
+	}
+
+	c := jsonutils.Marshal(chaos)
+
+	return &c
+}
+
+func parseChaos(chaos *string) *model.ChaosPolicy {
+	if chaos == nil || *chaos == "" {
+		return nil
+	}
+
+	result := &model.ChaosPolicy{}
+
+	if err := jsonutils.Unmarshal(strings.NewReader(*chaos), result); err != nil {
+		return nil
+	}
+
+	return result
+}
+
+func parseRule(row RuleRow, variables []VariableRow, responses []ResponseRow) (*model.Rule, error) {
+	matchers, err := parseMatchers(row.Matchers)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing rule %s, %w", row.Key, err)
+	}
+
+	resps, err := parseResponses(responses)
+	if err != nil {
+		return nil, err
+	}
+
 	return &model.Rule{
 		Key:               row.Key,
 		Group:             row.Group,
@@ -596,10 +846,15 @@ func parseRule(row RuleRow, variables []VariableRow, responses []ResponseRow) *m
 		Strategy:          row.Strategy,
 		Method:            row.Method,
 		Status:            row.Status,
+		Priority:          row.Priority,
+		Matchers:          matchers,
+		Scenario:          parseScenario(row.Scenario),
+		Resource:          parseResource(row.Resource),
+		Chaos:             parseChaos(row.Chaos),
 		Variables:         parseVariables(variables),
-		Responses:         parseResponses(responses),
+		Responses:         resps,
 		NextResponseIndex: row.NextResponseIndex,
-	}
+	}, nil
 }
 
 func (repository *ruleSQLRepository) commitOrRollback(ctx context.Context, trx *sqlx.Tx, err error) {
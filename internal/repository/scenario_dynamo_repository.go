@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/nicopozo/mockserver/internal/configs"
+	"github.com/nicopozo/mockserver/internal/model"
+)
+
+// DynamoScenarioRepository stores one item per scenario, with the scenario name as partition key.
+type DynamoScenarioRepository struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+type scenarioStateItem struct {
+	Name      string    `dynamodbav:"name"`
+	State     string    `dynamodbav:"state"`
+	UpdatedAt time.Time `dynamodbav:"updated_at"`
+}
+
+// NewDynamoScenarioRepository creates a new ScenarioRepository for DynamoDB.
+func NewDynamoScenarioRepository(client *dynamodb.Client, cfg *configs.Config) ScenarioRepository {
+	return &DynamoScenarioRepository{
+		client:    client,
+		tableName: cfg.Dynamo.TablePrefix + "scenarios",
+	}
+}
+
+func (r *DynamoScenarioRepository) Get(ctx context.Context, name string) (*model.Scenario, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(r.tableName),
+		Key:            scenarioKey(name),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting scenario from DynamoDB: %w", err)
+	}
+
+	if result.Item == nil {
+		return nil, scenarioNotFound(name)
+	}
+
+	var item scenarioStateItem
+
+	if err := attributevalue.UnmarshalMap(result.Item, &item); err != nil {
+		return nil, fmt.Errorf("error unmarshaling scenario: %w", err)
+	}
+
+	scenario := item.toModel()
+
+	return &scenario, nil
+}
+
+func (r *DynamoScenarioRepository) List(ctx context.Context) ([]model.Scenario, error) {
+	items, err := r.scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	scenarios := make([]model.Scenario, 0, len(items))
+	for _, item := range items {
+		scenarios = append(scenarios, item.toModel())
+	}
+
+	sort.Slice(scenarios, func(i, j int) bool { return scenarios[i].Name < scenarios[j].Name })
+
+	return scenarios, nil
+}
+
+func (r *DynamoScenarioRepository) Save(ctx context.Context, scenario model.Scenario) error {
+	item, err := attributevalue.MarshalMap(scenarioStateItem{
+		Name:      scenario.Name,
+		State:     scenario.State,
+		UpdatedAt: scenario.UpdatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("error marshaling scenario: %w", err)
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("error saving scenario in DynamoDB: %w", err)
+	}
+
+	return nil
+}
+
+// CompareAndSet puts the item with a condition on the stored state. A scenario in the Started state may
+// have no item.
+func (r *DynamoScenarioRepository) CompareAndSet(ctx context.Context, scenario model.Scenario,
+	expected string,
+) (bool, error) {
+	item, err := attributevalue.MarshalMap(scenarioStateItem{
+		Name:      scenario.Name,
+		State:     scenario.State,
+		UpdatedAt: scenario.UpdatedAt,
+	})
+	if err != nil {
+		return false, fmt.Errorf("error marshaling scenario: %w", err)
+	}
+
+	condition := "#state = :expected"
+	if expected == model.ScenarioStateStarted {
+		condition = "attribute_not_exists(#name) OR " + condition
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:                aws.String(r.tableName),
+		Item:                     item,
+		ConditionExpression:      aws.String(condition),
+		ExpressionAttributeNames: map[string]string{"#name": "name", "#state": "state"},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":expected": &types.AttributeValueMemberS{Value: expected},
+		},
+	})
+
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("error saving scenario in DynamoDB: %w", err)
+	}
+
+	return true, nil
+}
+
+func (r *DynamoScenarioRepository) Delete(ctx context.Context, name string) error {
+	_, err := r.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.tableName),
+		Key:       scenarioKey(name),
+	})
+	if err != nil {
+		return fmt.Errorf("error deleting scenario from DynamoDB: %w", err)
+	}
+
+	return nil
+}
+
+func (r *DynamoScenarioRepository) DeleteAll(ctx context.Context) error {
+	items, err := r.scan(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if err := r.Delete(ctx, item.Name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// scan reads the whole table, which holds one small item per scenario.
+func (r *DynamoScenarioRepository) scan(ctx context.Context) ([]scenarioStateItem, error) {
+	var items []scenarioStateItem
+
+	paginator := dynamodb.NewScanPaginator(r.client, &dynamodb.ScanInput{TableName: aws.String(r.tableName)})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning scenarios in DynamoDB: %w", err)
+		}
+
+		var pageItems []scenarioStateItem
+
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &pageItems); err != nil {
+			return nil, fmt.Errorf("error unmarshaling scenarios: %w", err)
+		}
+
+		items = append(items, pageItems...)
+	}
+
+	return items, nil
+}
+
+func scenarioKey(name string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"name": &types.AttributeValueMemberS{Value: name},
+	}
+}
+
+func (item scenarioStateItem) toModel() model.Scenario {
+	return model.Scenario{
+		Name:      item.Name,
+		State:     item.State,
+		UpdatedAt: item.UpdatedAt,
+	}
+}
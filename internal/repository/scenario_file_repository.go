@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/nicopozo/mockserver/internal/configs"
+	"github.com/nicopozo/mockserver/internal/model"
+	jsonutils "github.com/nicopozo/mockserver/internal/utils/json"
+)
+
+type scenarioFileRepository struct {
+	mu        sync.RWMutex
+	scenarios map[string]model.Scenario
+	filePath  string
+}
+
+// NewScenarioFileRepository creates a ScenarioRepository that keeps the scenario states in
+// cfg.ScenariosFile.
+func NewScenarioFileRepository(cfg *configs.Config) (ScenarioRepository, error) {
+	repo := &scenarioFileRepository{
+		scenarios: make(map[string]model.Scenario),
+		filePath:  cfg.ScenariosFile,
+	}
+
+	file, err := os.Open(repo.filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return repo, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error reading scenarios file: %s - %w", repo.filePath, err)
+	}
+
+	defer func(f *os.File) { _ = f.Close() }(file)
+
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("error reading scenarios file: %s - %w", repo.filePath, err)
+	}
+
+	if stat.Size() > 0 {
+		if err := jsonutils.Unmarshal(file, &repo.scenarios); err != nil {
+			return nil, fmt.Errorf("error unmarshalling scenarios file: %s - %w", repo.filePath, err)
+		}
+	}
+
+	return repo, nil
+}
+
+func (repository *scenarioFileRepository) Get(_ context.Context, name string) (*model.Scenario, error) {
+	repository.mu.RLock()
+	defer repository.mu.RUnlock()
+
+	scenario, found := repository.scenarios[name]
+	if !found {
+		return nil, scenarioNotFound(name)
+	}
+
+	return &scenario, nil
+}
+
+func (repository *scenarioFileRepository) List(_ context.Context) ([]model.Scenario, error) {
+	repository.mu.RLock()
+	defer repository.mu.RUnlock()
+
+	scenarios := make([]model.Scenario, 0, len(repository.scenarios))
+	for _, scenario := range repository.scenarios {
+		scenarios = append(scenarios, scenario)
+	}
+
+	sort.Slice(scenarios, func(i, j int) bool { return scenarios[i].Name < scenarios[j].Name })
+
+	return scenarios, nil
+}
+
+func (repository *scenarioFileRepository) Save(_ context.Context, scenario model.Scenario) error {
+	repository.mu.Lock()
+	defer repository.mu.Unlock()
+
+	repository.scenarios[scenario.Name] = scenario
+
+	return repository.saveFile()
+}
+
+func (repository *scenarioFileRepository) CompareAndSet(_ context.Context, scenario model.Scenario,
+	expected string,
+) (bool, error) {
+	repository.mu.Lock()
+	defer repository.mu.Unlock()
+
+	current, found := repository.scenarios[scenario.Name]
+	if !found {
+		current.State = model.ScenarioStateStarted
+	}
+
+	if current.State != expected {
+		return false, nil
+	}
+
+	repository.scenarios[scenario.Name] = scenario
+
+	if err := repository.saveFile(); err != nil {
+		if found {
+			repository.scenarios[scenario.Name] = current
+		} else {
+			delete(repository.scenarios, scenario.Name)
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (repository *scenarioFileRepository) Delete(_ context.Context, name string) error {
+	repository.mu.Lock()
+	defer repository.mu.Unlock()
+
+	if _, found := repository.scenarios[name]; !found {
+		return nil
+	}
+
+	delete(repository.scenarios, name)
+
+	return repository.saveFile()
+}
+
+func (repository *scenarioFileRepository) DeleteAll(_ context.Context) error {
+	repository.mu.Lock()
+	defer repository.mu.Unlock()
+
+	repository.scenarios = make(map[string]model.Scenario)
+
+	return repository.saveFile()
+}
+
+func (repository *scenarioFileRepository) saveFile() error {
+	err := os.WriteFile(repository.filePath, []byte(jsonutils.Marshal(repository.scenarios)), 0o644) //nolint:gosec,mnd
+	if err != nil {
+		return fmt.Errorf("error saving scenarios file: %s - %w", repository.filePath, err)
+	}
+
+	return nil
+}
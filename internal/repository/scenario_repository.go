@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
+	"github.com/nicopozo/mockserver/internal/model"
+)
+
+// ScenarioRepository stores the current state of the scenarios that have left their Started state.
+// Scenarios without a stored state are Started.
+type ScenarioRepository interface {
+	Get(ctx context.Context, name string) (*model.Scenario, error)
+	List(ctx context.Context) ([]model.Scenario, error)
+	Save(ctx context.Context, scenario model.Scenario) error
+	// CompareAndSet saves the scenario only while its stored state is still expected, and reports whether
+	// it was saved. The check and the save are a single step, so two requests that saw the same state
+	// cannot both move the scenario from it.
+	CompareAndSet(ctx context.Context, scenario model.Scenario, expected string) (bool, error)
+	Delete(ctx context.Context, name string) error
+	DeleteAll(ctx context.Context) error
+}
+
+func scenarioNotFound(name string) error {
+	return mockserrors.ScenarioNotFoundError{Message: "no state stored for scenario: " + name}
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
+	"github.com/nicopozo/mockserver/internal/model"
+)
+
+type scenarioSQLRepository struct {
+	db Database
+}
+
+type ScenarioRow struct {
+	Name      string    `db:"name"`
+	State     string    `db:"state"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func (row ScenarioRow) toModel() model.Scenario {
+	return model.Scenario{
+		Name:      row.Name,
+		State:     row.State,
+		UpdatedAt: row.UpdatedAt,
+	}
+}
+
+func NewScenarioSQLRepository(db Database) ScenarioRepository {
+	return &scenarioSQLRepository{
+		db: db,
+	}
+}
+
+func (r *scenarioSQLRepository) Get(_ context.Context, name string) (*model.Scenario, error) {
+	var row ScenarioRow
+
+	query := FormatQuery("SELECT name, state, updated_at FROM scenarios WHERE name = ?", r.db.DriverName())
+
+	err := r.db.Get(&row, query, name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, scenarioNotFound(name)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error fetching scenario from DB: %w", err)
+	}
+
+	scenario := row.toModel()
+
+	return &scenario, nil
+}
+
+func (r *scenarioSQLRepository) List(_ context.Context) ([]model.Scenario, error) {
+	var rows []ScenarioRow
+
+	query := FormatQuery("SELECT name, state, updated_at FROM scenarios ORDER BY name", r.db.DriverName())
+
+	if err := r.db.Select(&rows, query); err != nil {
+		return nil, fmt.Errorf("error fetching scenarios from DB: %w", err)
+	}
+
+	scenarios := make([]model.Scenario, 0, len(rows))
+	for _, row := range rows {
+		scenarios = append(scenarios, row.toModel())
+	}
+
+	return scenarios, nil
+}
+
+// Save inserts the scenario state or replaces the stored one, using the upsert syntax of the driver.
+func (r *scenarioSQLRepository) Save(_ context.Context, scenario model.Scenario) error {
+	query := "INSERT INTO scenarios (name, state, updated_at) VALUES (?, ?, ?) " +
+		"ON DUPLICATE KEY UPDATE state = VALUES(state), updated_at = VALUES(updated_at)"
+
+	if r.db.DriverName() == datasourcePostgres {
+		query = "INSERT INTO scenarios (name, state, updated_at) VALUES (?, ?, ?) " +
+			"ON CONFLICT (name) DO UPDATE SET state = EXCLUDED.state, updated_at = EXCLUDED.updated_at"
+	}
+
+	_, err := r.db.Exec(FormatQuery(query, r.db.DriverName()), scenario.Name, scenario.State, scenario.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("error saving scenario into DB: %w", err)
+	}
+
+	return nil
+}
+
+// CompareAndSet updates the row only while it holds the expected state. A scenario in the Started state may
+// have no row, then the row is inserted, and an insert that fails because another instance inserted it
+// first means the state was changed.
+func (r *scenarioSQLRepository) CompareAndSet(ctx context.Context, scenario model.Scenario,
+	expected string,
+) (bool, error) {
+	if scenario.State == expected {
+		// Nothing changes, and MySQL would not report the row as updated.
+		return r.hasState(ctx, scenario.Name, expected)
+	}
+
+	query := FormatQuery("UPDATE scenarios SET state = ?, updated_at = ? WHERE name = ? AND state = ?",
+		r.db.DriverName())
+
+	result, err := r.db.Exec(query, scenario.State, scenario.UpdatedAt, scenario.Name, expected)
+	if err != nil {
+		return false, fmt.Errorf("error updating scenario in DB: %w", err)
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("error updating scenario in DB: %w", err)
+	}
+
+	if updated == 1 || expected != model.ScenarioStateStarted {
+		return updated == 1, nil
+	}
+
+	query = FormatQuery("INSERT INTO scenarios (name, state, updated_at) VALUES (?, ?, ?)", r.db.DriverName())
+
+	if _, err := r.db.Exec(query, scenario.Name, scenario.State, scenario.UpdatedAt); err != nil {
+		if _, getErr := r.Get(ctx, scenario.Name); getErr == nil {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("error saving scenario into DB: %w", err)
+	}
+
+	return true, nil
+}
+
+// hasState reports whether the scenario is in state, where a scenario without a row is Started.
+func (r *scenarioSQLRepository) hasState(ctx context.Context, name, state string) (bool, error) {
+	scenario, err := r.Get(ctx, name)
+	if errors.As(err, &mockserrors.ScenarioNotFoundError{}) {
+		return state == model.ScenarioStateStarted, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return scenario.State == state, nil
+}
+
+func (r *scenarioSQLRepository) Delete(_ context.Context, name string) error {
+	query := FormatQuery("DELETE FROM scenarios WHERE name = ?", r.db.DriverName())
+
+	if _, err := r.db.Exec(query, name); err != nil {
+		return fmt.Errorf("error deleting scenario from DB: %w", err)
+	}
+
+	return nil
+}
+
+func (r *scenarioSQLRepository) DeleteAll(_ context.Context) error {
+	if _, err := r.db.Exec(FormatQuery("DELETE FROM scenarios", r.db.DriverName())); err != nil {
+		return fmt.Errorf("error deleting scenarios from DB: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nicopozo/mockserver/internal/model"
+	"github.com/nicopozo/mockserver/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScenarioSQLRepository_Save(t *testing.T) {
+	updatedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		driver    string
+		wantQuery string
+	}{
+		{
+			name:   "mysql upserts with on duplicate key",
+			driver: "mysql",
+			wantQuery: "INSERT INTO scenarios (name, state, updated_at) VALUES (?, ?, ?) " +
+				"ON DUPLICATE KEY UPDATE state = VALUES(state), updated_at = VALUES(updated_at)",
+		},
+		{
+			name:   "postgres upserts with on conflict",
+			driver: "postgres",
+			wantQuery: "INSERT INTO scenarios (name, state, updated_at) VALUES ($1, $2, $3) " +
+				"ON CONFLICT (name) DO UPDATE SET state = EXCLUDED.state, updated_at = EXCLUDED.updated_at",
+		},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			db := &queryRecorder{driver: tt.driver}
+			repo := repository.NewScenarioSQLRepository(db)
+
+			err := repo.Save(context.Background(), model.Scenario{Name: "checkout", State: "paid", UpdatedAt: updatedAt})
+			assert.NoError(t, err)
+
+			if assert.Len(t, db.queries, 1) {
+				assert.Equal(t, tt.wantQuery, db.queries[0])
+				assert.Equal(t, []interface{}{"checkout", "paid", updatedAt}, db.args[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+CREATE TABLE IF NOT EXISTS rules
+(
+    `key`               varchar(255) NOT NULL PRIMARY KEY,
+    `group`             varchar(255) NOT NULL,
+    name                varchar(255) NOT NULL,
+    path                varchar(255) NOT NULL,
+    strategy            varchar(255) NOT NULL,
+    method              varchar(45)  NOT NULL,
+    status              varchar(255) NOT NULL,
+    pattern             varchar(255) NOT NULL,
+    next_response_index int          NOT NULL DEFAULT 0,
+    priority            int          NOT NULL DEFAULT 0,
+    matchers            text         DEFAULT NULL,
+    scenario            text         DEFAULT NULL,
+    resource            text         DEFAULT NULL,
+    chaos               text         DEFAULT NULL
+);
+
+CREATE TABLE IF NOT EXISTS responses
+(
+    id           integer      NOT NULL PRIMARY KEY AUTOINCREMENT,
+    body         text         NOT NULL,
+    content_type varchar(255) NOT NULL,
+    http_status  int          NOT NULL,
+    delay        int          DEFAULT 0,
+    scene        varchar(255) DEFAULT NULL,
+    rule_key     varchar(255) NOT NULL REFERENCES rules (`key`),
+    description  varchar(255) DEFAULT NULL,
+    headers      text         DEFAULT NULL,
+    cookies      text         DEFAULT NULL,
+    webhook      text         DEFAULT NULL,
+    webhooks     text         DEFAULT NULL,
+    webhook_mode varchar(20)  DEFAULT NULL,
+    fault        varchar(50)  DEFAULT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_responses_rule_key ON responses (rule_key);
+
+CREATE TABLE IF NOT EXISTS variables
+(
+    id         integer      NOT NULL PRIMARY KEY AUTOINCREMENT,
+    type       varchar(255) NOT NULL,
+    name       varchar(255) NOT NULL,
+    `key`      varchar(255) NOT NULL,
+    rule_key   varchar(255) NOT NULL REFERENCES rules (`key`),
+    min        double       DEFAULT NULL,
+    max        double       DEFAULT NULL,
+    decimals   int          DEFAULT NULL,
+    assertions text         DEFAULT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_variables_rule_key ON variables (rule_key);
+
+CREATE TABLE IF NOT EXISTS request_logs
+(
+    id                   varchar(27)  NOT NULL PRIMARY KEY,
+    timestamp            timestamp    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    rule_key             varchar(100) DEFAULT NULL,
+    rule_name            varchar(255) DEFAULT NULL,
+    scene                varchar(255) DEFAULT NULL,
+    method               varchar(10)  NOT NULL,
+    url                  text         NOT NULL,
+    request_body         text,
+    request_headers      text,
+    query_params         text,
+    response_status      int,
+    response_body        text,
+    duration_ms          bigint       NOT NULL DEFAULT 0,
+    fault                varchar(50)  DEFAULT NULL,
+    chaos                text         DEFAULT NULL,
+    assertion_errors     text,
+    webhook_results      text,
+    has_assertion_errors boolean      NOT NULL DEFAULT FALSE,
+    has_webhook_failures boolean      NOT NULL DEFAULT FALSE,
+    webhook_version      int          NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_request_logs_timestamp ON request_logs (timestamp);
+CREATE INDEX IF NOT EXISTS idx_request_logs_rule_key ON request_logs (rule_key);
+
+CREATE TABLE IF NOT EXISTS bin_requests
+(
+    id                   varchar(27)  NOT NULL PRIMARY KEY,
+    bin                  varchar(64)  NOT NULL,
+    timestamp            timestamp    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    rule_key             varchar(100) DEFAULT NULL,
+    rule_name            varchar(255) DEFAULT NULL,
+    scene                varchar(255) DEFAULT NULL,
+    method               varchar(10)  NOT NULL,
+    url                  text         NOT NULL,
+    request_body         text,
+    request_headers      text,
+    query_params         text,
+    response_status      int,
+    response_body        text,
+    duration_ms          bigint       NOT NULL DEFAULT 0,
+    fault                varchar(50)  DEFAULT NULL,
+    chaos                text         DEFAULT NULL,
+    assertion_errors     text,
+    webhook_results      text,
+    has_assertion_errors boolean      NOT NULL DEFAULT FALSE,
+    has_webhook_failures boolean      NOT NULL DEFAULT FALSE,
+    webhook_version      int          NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_bin_requests_bin ON bin_requests (bin, id);
+
+CREATE TABLE IF NOT EXISTS api_keys
+(
+    id          varchar(27)  NOT NULL PRIMARY KEY,
+    name        varchar(100) NOT NULL,
+    role        varchar(20)  NOT NULL,
+    secret_hash char(64)     NOT NULL UNIQUE,
+    created_at  timestamp    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS scenarios
+(
+    name       varchar(255) NOT NULL PRIMARY KEY,
+    state      varchar(255) NOT NULL,
+    updated_at timestamp    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS settings
+(
+    name       varchar(100) NOT NULL PRIMARY KEY,
+    value      text         NOT NULL,
+    updated_at timestamp    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS resource_collections
+(
+    name           varchar(255) NOT NULL PRIMARY KEY,
+    initialized_at timestamp    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS resource_entities
+(
+    collection varchar(255) NOT NULL,
+    id         varchar(255) NOT NULL,
+    data       text         NOT NULL,
+    created_at timestamp    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (collection, id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_resource_entities_created_at ON resource_entities (collection, created_at);
+
+CREATE TABLE IF NOT EXISTS webhook_jobs
+(
+    id           varchar(27) NOT NULL PRIMARY KEY,
+    status       varchar(20) NOT NULL,
+    due_at       timestamp   NOT NULL,
+    locked_until timestamp   NULL,
+    data         text        NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_jobs_due_at ON webhook_jobs (status, due_at);
+
+CREATE TABLE IF NOT EXISTS rule_revisions
+(
+    rule_key   varchar(255) NOT NULL,
+    revision   int          NOT NULL,
+    action     varchar(20)  NOT NULL,
+    author     varchar(255) NOT NULL,
+    created_at timestamp    NOT NULL,
+    data       text         NOT NULL,
+    PRIMARY KEY (rule_key, revision)
+);
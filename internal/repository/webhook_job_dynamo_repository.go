@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/nicopozo/mockserver/internal/configs"
+	"github.com/nicopozo/mockserver/internal/model"
+	jsonutils "github.com/nicopozo/mockserver/internal/utils/json"
+)
+
+// DynamoWebhookJobRepository stores one item per queued webhook, with the job id as partition key. The
+// job is kept as a JSON document; status, due_at and locked_until (Unix milliseconds, 0 when the job is
+// not locked) are kept apart to filter the due jobs and to claim them with a conditional update.
+type DynamoWebhookJobRepository struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+type webhookJobItem struct {
+	ID          string `dynamodbav:"id"`
+	Status      string `dynamodbav:"status"`
+	DueAt       int64  `dynamodbav:"due_at"`
+	LockedUntil int64  `dynamodbav:"locked_until"`
+	Data        string `dynamodbav:"data"`
+}
+
+// NewDynamoWebhookJobRepository creates a new WebhookJobRepository for DynamoDB.
+func NewDynamoWebhookJobRepository(client *dynamodb.Client, cfg *configs.Config) WebhookJobRepository {
+	return &DynamoWebhookJobRepository{
+		client:    client,
+		tableName: cfg.Dynamo.TablePrefix + "webhook_jobs",
+	}
+}
+
+func (r *DynamoWebhookJobRepository) Save(ctx context.Context, job model.WebhookJob) error {
+	item, err := attributevalue.MarshalMap(webhookJobItem{
+		ID:          job.ID,
+		Status:      job.Status,
+		DueAt:       job.DueAt.UnixMilli(),
+		LockedUntil: unixMilliOrZero(job.LockedUntil),
+		Data:        jsonutils.Marshal(job),
+	})
+	if err != nil {
+		return fmt.Errorf("error marshaling webhook job: %w", err)
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("error saving webhook job in DynamoDB: %w", err)
+	}
+
+	return nil
+}
+
+func (r *DynamoWebhookJobRepository) Get(ctx context.Context, id string) (*model.WebhookJob, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(r.tableName),
+		Key:            webhookJobKey(id),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting webhook job from DynamoDB: %w", err)
+	}
+
+	if result.Item == nil {
+		return nil, webhookJobNotFound(id)
+	}
+
+	var item webhookJobItem
+
+	if err := attributevalue.UnmarshalMap(result.Item, &item); err != nil {
+		return nil, fmt.Errorf("error unmarshaling webhook job: %w", err)
+	}
+
+	job, err := item.toModel()
+	if err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+func (r *DynamoWebhookJobRepository) List(ctx context.Context, status string) ([]model.WebhookJob, error) {
+	input := &dynamodb.ScanInput{TableName: aws.String(r.tableName)}
+
+	if status != "" {
+		input.FilterExpression = aws.String("#status = :status")
+		input.ExpressionAttributeNames = map[string]string{"#status": "status"}
+		input.ExpressionAttributeValues = map[string]types.AttributeValue{
+			":status": &types.AttributeValueMemberS{Value: status},
+		}
+	}
+
+	jobs, err := r.scan(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].ID < jobs[j].ID })
+
+	return jobs, nil
+}
+
+func (r *DynamoWebhookJobRepository) Due(ctx context.Context, now time.Time, limit int) ([]model.WebhookJob, error) {
+	jobs, err := r.scan(ctx, &dynamodb.ScanInput{
+		TableName:                 aws.String(r.tableName),
+		FilterExpression:          aws.String(webhookJobDueCondition),
+		ExpressionAttributeNames:  map[string]string{"#status": "status"},
+		ExpressionAttributeValues: webhookJobDueValues(now),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(jobs, func(i, j int) bool { return jobs[i].DueAt.Before(jobs[j].DueAt) })
+
+	if len(jobs) > limit {
+		jobs = jobs[:limit]
+	}
+
+	return jobs, nil
+}
+
+// Claim locks the job with a conditional update, so that only one of the instances sharing the table
+// gets it.
+func (r *DynamoWebhookJobRepository) Claim(ctx context.Context, id string, now, until time.Time) (bool, error) {
+	values := webhookJobDueValues(now)
+	values[":until"] = &types.AttributeValueMemberN{Value: strconv.FormatInt(until.UnixMilli(), 10)}
+
+	_, err := r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(r.tableName),
+		Key:                       webhookJobKey(id),
+		UpdateExpression:          aws.String("SET locked_until = :until"),
+		ConditionExpression:       aws.String(webhookJobDueCondition),
+		ExpressionAttributeNames:  map[string]string{"#status": "status"},
+		ExpressionAttributeValues: values,
+	})
+
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("error claiming webhook job in DynamoDB: %w", err)
+	}
+
+	return true, nil
+}
+
+func (r *DynamoWebhookJobRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.tableName),
+		Key:       webhookJobKey(id),
+	})
+	if err != nil {
+		return fmt.Errorf("error deleting webhook job from DynamoDB: %w", err)
+	}
+
+	return nil
+}
+
+// scan reads the jobs that match input. The queue only holds the deliveries in progress and the
+// failed ones, so it stays small.
+func (r *DynamoWebhookJobRepository) scan(ctx context.Context, input *dynamodb.ScanInput) ([]model.WebhookJob, error) {
+	var jobs []model.WebhookJob
+
+	paginator := dynamodb.NewScanPaginator(r.client, input)
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning webhook jobs in DynamoDB: %w", err)
+		}
+
+		var items []webhookJobItem
+
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &items); err != nil {
+			return nil, fmt.Errorf("error unmarshaling webhook jobs: %w", err)
+		}
+
+		for _, item := range items {
+			job, err := item.toModel()
+			if err != nil {
+				return nil, err
+			}
+
+			jobs = append(jobs, job)
+		}
+	}
+
+	return jobs, nil
+}
+
+const webhookJobDueCondition = "#status = :pending AND due_at <= :now AND locked_until <= :now"
+
+func webhookJobDueValues(now time.Time) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		":pending": &types.AttributeValueMemberS{Value: model.WebhookJobPending},
+		":now":     &types.AttributeValueMemberN{Value: strconv.FormatInt(now.UnixMilli(), 10)},
+	}
+}
+
+func webhookJobKey(id string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"id": &types.AttributeValueMemberS{Value: id},
+	}
+}
+
+func unixMilliOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return t.UnixMilli()
+}
+
+func (item webhookJobItem) toModel() (model.WebhookJob, error) {
+	var job model.WebhookJob
+
+	if err := jsonutils.Unmarshal(strings.NewReader(item.Data), &job); err != nil {
+		return model.WebhookJob{}, fmt.Errorf("error unmarshaling webhook job %s: %w", item.ID, err)
+	}
+
+	job.LockedUntil = time.Time{}
+	if item.LockedUntil > 0 {
+		job.LockedUntil = time.UnixMilli(item.LockedUntil).UTC()
+	}
+
+	return job, nil
+}
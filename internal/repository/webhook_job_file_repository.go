@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nicopozo/mockserver/internal/configs"
+	"github.com/nicopozo/mockserver/internal/model"
+	jsonutils "github.com/nicopozo/mockserver/internal/utils/json"
+)
+
+type webhookJobFileRepository struct {
+	mu       sync.Mutex
+	jobs     map[string]model.WebhookJob
+	filePath string
+}
+
+// NewWebhookJobFileRepository creates a WebhookJobRepository that keeps the queued webhooks in
+// cfg.WebhookJobsFile.
+func NewWebhookJobFileRepository(cfg *configs.Config) (WebhookJobRepository, error) {
+	repo := &webhookJobFileRepository{
+		jobs:     make(map[string]model.WebhookJob),
+		filePath: cfg.WebhookJobsFile,
+	}
+
+	file, err := os.Open(repo.filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return repo, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error reading webhook jobs file: %s - %w", repo.filePath, err)
+	}
+
+	defer func(f *os.File) { _ = f.Close() }(file)
+
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("error reading webhook jobs file: %s - %w", repo.filePath, err)
+	}
+
+	if stat.Size() > 0 {
+		if err := jsonutils.Unmarshal(file, &repo.jobs); err != nil {
+			return nil, fmt.Errorf("error unmarshalling webhook jobs file: %s - %w", repo.filePath, err)
+		}
+	}
+
+	return repo, nil
+}
+
+func (repository *webhookJobFileRepository) Save(_ context.Context, job model.WebhookJob) error {
+	repository.mu.Lock()
+	defer repository.mu.Unlock()
+
+	repository.jobs[job.ID] = job
+
+	return repository.saveFile()
+}
+
+func (repository *webhookJobFileRepository) Get(_ context.Context, id string) (*model.WebhookJob, error) {
+	repository.mu.Lock()
+	defer repository.mu.Unlock()
+
+	job, found := repository.jobs[id]
+	if !found {
+		return nil, webhookJobNotFound(id)
+	}
+
+	return &job, nil
+}
+
+func (repository *webhookJobFileRepository) List(_ context.Context, status string) ([]model.WebhookJob, error) {
+	repository.mu.Lock()
+	defer repository.mu.Unlock()
+
+	return repository.filter(func(job model.WebhookJob) bool { return status == "" || job.Status == status }), nil
+}
+
+func (repository *webhookJobFileRepository) Due(
+	_ context.Context, now time.Time, limit int,
+) ([]model.WebhookJob, error) {
+	repository.mu.Lock()
+	defer repository.mu.Unlock()
+
+	jobs := repository.filter(func(job model.WebhookJob) bool { return job.Due(now) })
+
+	sort.SliceStable(jobs, func(i, j int) bool { return jobs[i].DueAt.Before(jobs[j].DueAt) })
+
+	if len(jobs) > limit {
+		jobs = jobs[:limit]
+	}
+
+	return jobs, nil
+}
+
+func (repository *webhookJobFileRepository) Claim(_ context.Context, id string, now, until time.Time) (bool, error) {
+	repository.mu.Lock()
+	defer repository.mu.Unlock()
+
+	job, found := repository.jobs[id]
+	if !found || !job.Due(now) {
+		return false, nil
+	}
+
+	job.LockedUntil = until
+	repository.jobs[id] = job
+
+	if err := repository.saveFile(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (repository *webhookJobFileRepository) Delete(_ context.Context, id string) error {
+	repository.mu.Lock()
+	defer repository.mu.Unlock()
+
+	if _, found := repository.jobs[id]; !found {
+		return nil
+	}
+
+	delete(repository.jobs, id)
+
+	return repository.saveFile()
+}
+
+// filter returns the jobs that satisfy keep, oldest first.
+func (repository *webhookJobFileRepository) filter(keep func(job model.WebhookJob) bool) []model.WebhookJob {
+	jobs := make([]model.WebhookJob, 0, len(repository.jobs))
+
+	for _, job := range repository.jobs {
+		if keep(job) {
+			jobs = append(jobs, job)
+		}
+	}
+
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].ID < jobs[j].ID })
+
+	return jobs
+}
+
+func (repository *webhookJobFileRepository) saveFile() error {
+	err := os.WriteFile(repository.filePath, []byte(jsonutils.Marshal(repository.jobs)), 0o644) //nolint:gosec,mnd
+	if err != nil {
+		return fmt.Errorf("error saving webhook jobs file: %s - %w", repository.filePath, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package repository_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nicopozo/mockserver/internal/configs"
+	mockscontext "github.com/nicopozo/mockserver/internal/context"
+	"github.com/nicopozo/mockserver/internal/model"
+	"github.com/nicopozo/mockserver/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookJobFileRepository_Claim(t *testing.T) {
+	cfg := &configs.Config{WebhookJobsFile: filepath.Join(t.TempDir(), "webhook-jobs.json")}
+	ctx := mockscontext.Background()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	repo, err := repository.NewWebhookJobFileRepository(cfg)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, job := range []model.WebhookJob{
+		{ID: "01", Status: model.WebhookJobPending, DueAt: now.Add(-time.Second)},
+		{ID: "02", Status: model.WebhookJobPending, DueAt: now.Add(-time.Minute)},
+		{ID: "03", Status: model.WebhookJobPending, DueAt: now.Add(time.Minute)},
+		{ID: "04", Status: model.WebhookJobFailed, DueAt: now.Add(-time.Minute)},
+	} {
+		assert.NoError(t, repo.Save(ctx, job))
+	}
+
+	due, err := repo.Due(ctx, now, 10)
+	assert.NoError(t, err)
+
+	if assert.Len(t, due, 2) {
+		assert.Equal(t, "02", due[0].ID, "the job that is due first goes first")
+		assert.Equal(t, "01", due[1].ID)
+	}
+
+	claimed, err := repo.Claim(ctx, "02", now, now.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+
+	claimed, err = repo.Claim(ctx, "02", now, now.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.False(t, claimed, "a claimed job cannot be claimed again")
+
+	// A new repository reads the jobs saved by the previous one, lock included.
+	repo, err = repository.NewWebhookJobFileRepository(cfg)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	due, err = repo.Due(ctx, now, 10)
+	assert.NoError(t, err)
+
+	if assert.Len(t, due, 1) {
+		assert.Equal(t, "01", due[0].ID)
+	}
+
+	due, err = repo.Due(ctx, now.Add(time.Hour), 10)
+	assert.NoError(t, err)
+	assert.Len(t, due, 3, "the lock of a job expires")
+
+	failed, err := repo.List(ctx, model.WebhookJobFailed)
+	assert.NoError(t, err)
+	assert.Len(t, failed, 1)
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
+	"github.com/nicopozo/mockserver/internal/model"
+)
+
+// WebhookJobRepository stores the webhook deliveries that are waiting to be sent, so that they survive a
+// restart. Claim locks a due job for a worker until the given time, and reports false when the job is
+// no longer due, for example because another worker claimed it first.
+type WebhookJobRepository interface {
+	Save(ctx context.Context, job model.WebhookJob) error
+	Get(ctx context.Context, id string) (*model.WebhookJob, error)
+	List(ctx context.Context, status string) ([]model.WebhookJob, error)
+	Due(ctx context.Context, now time.Time, limit int) ([]model.WebhookJob, error)
+	Claim(ctx context.Context, id string, now, until time.Time) (bool, error)
+	Delete(ctx context.Context, id string) error
+}
+
+func webhookJobNotFound(id string) error {
+	return mockserrors.WebhookJobNotFoundError{ID: id}
+}
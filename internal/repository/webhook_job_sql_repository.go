@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nicopozo/mockserver/internal/model"
+	jsonutils "github.com/nicopozo/mockserver/internal/utils/json"
+)
+
+type webhookJobSQLRepository struct {
+	db Database
+}
+
+// WebhookJobRow keeps the whole job as JSON in data; the other columns are only there to query it.
+// locked_until is stored apart, since claiming a job only updates that column.
+type WebhookJobRow struct {
+	ID          string       `db:"id"`
+	LockedUntil sql.NullTime `db:"locked_until"`
+	Data        string       `db:"data"`
+}
+
+func (row WebhookJobRow) toModel() (model.WebhookJob, error) {
+	var job model.WebhookJob
+
+	if err := jsonutils.Unmarshal(strings.NewReader(row.Data), &job); err != nil {
+		return model.WebhookJob{}, fmt.Errorf("error unmarshalling webhook job %s: %w", row.ID, err)
+	}
+
+	job.LockedUntil = time.Time{}
+	if row.LockedUntil.Valid {
+		job.LockedUntil = row.LockedUntil.Time
+	}
+
+	return job, nil
+}
+
+func NewWebhookJobSQLRepository(db Database) WebhookJobRepository {
+	return &webhookJobSQLRepository{
+		db: db,
+	}
+}
+
+// Save inserts the job or replaces the stored one, using the upsert syntax of the driver.
+func (r *webhookJobSQLRepository) Save(_ context.Context, job model.WebhookJob) error {
+	query := "INSERT INTO webhook_jobs (id, status, due_at, locked_until, data) VALUES (?, ?, ?, ?, ?) " +
+		"ON DUPLICATE KEY UPDATE status = VALUES(status), due_at = VALUES(due_at), " +
+		"locked_until = VALUES(locked_until), data = VALUES(data)"
+
+	if r.db.DriverName() == datasourcePostgres {
+		query = "INSERT INTO webhook_jobs (id, status, due_at, locked_until, data) VALUES (?, ?, ?, ?, ?) " +
+			"ON CONFLICT (id) DO UPDATE SET status = EXCLUDED.status, due_at = EXCLUDED.due_at, " +
+			"locked_until = EXCLUDED.locked_until, data = EXCLUDED.data"
+	}
+
+	lockedUntil := sql.NullTime{Time: job.LockedUntil.UTC(), Valid: !job.LockedUntil.IsZero()}
+
+	_, err := r.db.Exec(FormatQuery(query, r.db.DriverName()), job.ID, job.Status, job.DueAt.UTC(), lockedUntil,
+		jsonutils.Marshal(job))
+	if err != nil {
+		return fmt.Errorf("error saving webhook job into DB: %w", err)
+	}
+
+	return nil
+}
+
+func (r *webhookJobSQLRepository) Get(_ context.Context, id string) (*model.WebhookJob, error) {
+	var row WebhookJobRow
+
+	query := FormatQuery("SELECT id, locked_until, data FROM webhook_jobs WHERE id = ?", r.db.DriverName())
+
+	err := r.db.Get(&row, query, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, webhookJobNotFound(id)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error fetching webhook job from DB: %w", err)
+	}
+
+	job, err := row.toModel()
+	if err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+func (r *webhookJobSQLRepository) List(_ context.Context, status string) ([]model.WebhookJob, error) {
+	query := "SELECT id, locked_until, data FROM webhook_jobs"
+	args := make([]interface{}, 0, 1)
+
+	if status != "" {
+		query += " WHERE status = ?"
+		args = append(args, status)
+	}
+
+	return r.selectJobs(query+" ORDER BY id", args...)
+}
+
+func (r *webhookJobSQLRepository) Due(_ context.Context, now time.Time, limit int) ([]model.WebhookJob, error) {
+	query := "SELECT id, locked_until, data FROM webhook_jobs " +
+		"WHERE status = ? AND due_at <= ? AND (locked_until IS NULL OR locked_until <= ?) " +
+		"ORDER BY due_at LIMIT ?"
+
+	return r.selectJobs(query, model.WebhookJobPending, now.UTC(), now.UTC(), limit)
+}
+
+// Claim locks the job with a conditional update, so that only one of the instances sharing the database
+// gets it.
+func (r *webhookJobSQLRepository) Claim(_ context.Context, id string, now, until time.Time) (bool, error) {
+	query := FormatQuery("UPDATE webhook_jobs SET locked_until = ? "+
+		"WHERE id = ? AND status = ? AND due_at <= ? AND (locked_until IS NULL OR locked_until <= ?)",
+		r.db.DriverName())
+
+	result, err := r.db.Exec(query, until.UTC(), id, model.WebhookJobPending, now.UTC(), now.UTC())
+	if err != nil {
+		return false, fmt.Errorf("error claiming webhook job in DB: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("error claiming webhook job in DB: %w", err)
+	}
+
+	return affected == 1, nil
+}
+
+func (r *webhookJobSQLRepository) Delete(_ context.Context, id string) error {
+	query := FormatQuery("DELETE FROM webhook_jobs WHERE id = ?", r.db.DriverName())
+
+	if _, err := r.db.Exec(query, id); err != nil {
+		return fmt.Errorf("error deleting webhook job from DB: %w", err)
+	}
+
+	return nil
+}
+
+func (r *webhookJobSQLRepository) selectJobs(query string, args ...interface{}) ([]model.WebhookJob, error) {
+	var rows []WebhookJobRow
+
+	if err := r.db.Select(&rows, FormatQuery(query, r.db.DriverName()), args...); err != nil {
+		return nil, fmt.Errorf("error fetching webhook jobs from DB: %w", err)
+	}
+
+	jobs := make([]model.WebhookJob, 0, len(rows))
+
+	for _, row := range rows {
+		job, err := row.toModel()
+		if err != nil {
+			return nil, err
+		}
+
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
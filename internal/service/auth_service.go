@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nicopozo/mockserver/internal/configs"
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
+	"github.com/nicopozo/mockserver/internal/model"
+	"github.com/nicopozo/mockserver/internal/repository"
+)
+
+const (
+	apiKeyPrefix      = "mks_"
+	apiKeySecretBytes = 32
+	staticKeyParts    = 3
+)
+
+// AuthService authenticates the callers of the admin API and manages their API keys.
+type AuthService interface {
+	Enabled() bool
+	ProtectMocks() bool
+	Authenticate(ctx context.Context, secret string) (model.APIKey, error)
+	CreateKey(ctx context.Context, key model.APIKey) (model.APIKey, error)
+	ListKeys(ctx context.Context) ([]model.APIKey, error)
+	DeleteKey(ctx context.Context, id string) error
+}
+
+type authService struct {
+	config     configs.AuthConfig
+	staticKeys []model.APIKey
+	repository repository.APIKeyRepository
+}
+
+// NewAuthService creates an AuthService with the static keys of the configuration and the keys stored in
+// repository.
+func NewAuthService(cfg *configs.Config, repository repository.APIKeyRepository) (AuthService, error) {
+	staticKeys, err := parseStaticKeys(cfg.Auth.Keys)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Auth.IsEnabled() && len(staticKeys) == 0 {
+		return nil, errors.New("auth is enabled but MOCKS_AUTH_KEYS has no static api key") //nolint:err113
+	}
+
+	return &authService{
+		config:     cfg.Auth,
+		staticKeys: staticKeys,
+		repository: repository,
+	}, nil
+}
+
+func (svc *authService) Enabled() bool {
+	return svc.config.IsEnabled()
+}
+
+func (svc *authService) ProtectMocks() bool {
+	return svc.config.ProtectMocks
+}
+
+// Authenticate returns the key that secret belongs to, or an UnauthorizedError.
+func (svc *authService) Authenticate(ctx context.Context, secret string) (model.APIKey, error) {
+	if secret == "" {
+		return model.APIKey{}, mockserrors.UnauthorizedError{Message: "missing api key"}
+	}
+
+	secretHash := hashSecret(secret)
+
+	for _, key := range svc.staticKeys {
+		if subtle.ConstantTimeCompare([]byte(key.SecretHash), []byte(secretHash)) == 1 {
+			return key, nil
+		}
+	}
+
+	key, err := svc.repository.GetBySecretHash(ctx, secretHash)
+	if err != nil {
+		if errors.As(err, &mockserrors.APIKeyNotFoundError{}) {
+			return model.APIKey{}, mockserrors.UnauthorizedError{Message: "invalid api key"}
+		}
+
+		return model.APIKey{}, fmt.Errorf("error authenticating api key, %w", err)
+	}
+
+	return *key, nil
+}
+
+// CreateKey stores a new key with a random secret. The secret is only available in the returned key.
+func (svc *authService) CreateKey(ctx context.Context, key model.APIKey) (model.APIKey, error) {
+	if err := key.Validate(); err != nil {
+		return model.APIKey{}, err //nolint:wrapcheck
+	}
+
+	secret, err := newSecret()
+	if err != nil {
+		return model.APIKey{}, err
+	}
+
+	key.Secret = secret
+	key.SecretHash = hashSecret(secret)
+	key.Static = false
+	key.CreatedAt = time.Now().UTC()
+
+	created, err := svc.repository.Create(ctx, &key)
+	if err != nil {
+		return model.APIKey{}, fmt.Errorf("error creating api key, %w", err)
+	}
+
+	created.SecretHash = ""
+
+	return *created, nil
+}
+
+// ListKeys returns the static and the stored keys, without their secrets.
+func (svc *authService) ListKeys(ctx context.Context) ([]model.APIKey, error) {
+	stored, err := svc.repository.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing api keys, %w", err)
+	}
+
+	keys := make([]model.APIKey, 0, len(svc.staticKeys)+len(stored))
+
+	for _, key := range svc.staticKeys {
+		key.SecretHash = ""
+		keys = append(keys, key)
+	}
+
+	return append(keys, stored...), nil
+}
+
+func (svc *authService) DeleteKey(ctx context.Context, id string) error {
+	for _, key := range svc.staticKeys {
+		if key.ID == id {
+			return mockserrors.InvalidRulesError{Message: "static api keys can only be removed from the configuration"}
+		}
+	}
+
+	return svc.repository.Delete(ctx, id) //nolint:wrapcheck
+}
+
+// parseStaticKeys reads comma separated "name:role:secret" entries.
+func parseStaticKeys(value string) ([]model.APIKey, error) {
+	keys := make([]model.APIKey, 0)
+
+	if strings.TrimSpace(value) == "" {
+		return keys, nil
+	}
+
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			return nil, errors.New("invalid static api keys, empty entry") //nolint:err113
+		}
+
+		parts := strings.SplitN(entry, ":", staticKeyParts)
+		if len(parts) != staticKeyParts || parts[1] == "" || parts[2] == "" {
+			return nil, fmt.Errorf("invalid static api key '%s', expected name:role:secret", //nolint:err113
+				parts[0])
+		}
+
+		key := model.APIKey{
+			ID:         "static:" + parts[0],
+			Name:       parts[0],
+			Role:       parts[1],
+			SecretHash: hashSecret(parts[2]),
+			Static:     true,
+		}
+
+		if err := key.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid static api key '%s', %w", parts[0], err)
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func newSecret() (string, error) {
+	random := make([]byte, apiKeySecretBytes)
+
+	if _, err := rand.Read(random); err != nil {
+		return "", fmt.Errorf("error generating api key secret, %w", err)
+	}
+
+	return apiKeyPrefix + hex.EncodeToString(random), nil
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+
+	return hex.EncodeToString(sum[:])
+}
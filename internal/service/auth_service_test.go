@@ -0,0 +1,132 @@
+package service_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/nicopozo/mockserver/internal/configs"
+	mockscontext "github.com/nicopozo/mockserver/internal/context"
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
+	"github.com/nicopozo/mockserver/internal/model"
+	"github.com/nicopozo/mockserver/internal/repository"
+	"github.com/nicopozo/mockserver/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func newAuthService(t *testing.T, staticKeys string) (service.AuthService, error) {
+	t.Helper()
+
+	cfg := &configs.Config{
+		Auth: configs.AuthConfig{Keys: staticKeys, KeysFile: filepath.Join(t.TempDir(), "api-keys.json")},
+	}
+
+	repo, err := repository.NewAPIKeyFileRepository(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return service.NewAuthService(cfg, repo)
+}
+
+func TestAuthService_InvalidStaticKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		keys    string
+		wantErr string
+	}{
+		{name: "Should fail without secret", keys: "ci:editor", wantErr: "invalid static api key 'ci', expected name:role:secret"},
+		{name: "Should fail with unknown role", keys: "ci:owner:s3cr3t", wantErr: "invalid role 'owner'"},
+		{name: "Should fail without role", keys: "ci::s3cr3t", wantErr: "invalid static api key 'ci'"},
+		{name: "Should fail without name", keys: ":editor:s3cr3t", wantErr: "name is required"},
+		{name: "Should fail with empty entry", keys: "ci:editor:s3cr3t,,ops:admin:s3cr3t", wantErr: "empty entry"},
+		{name: "Should fail without any key", keys: " , ", wantErr: "empty entry"},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newAuthService(t, tt.keys)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestAuthService_EnabledWithoutKeys(t *testing.T) {
+	t.Parallel()
+
+	cfg := &configs.Config{
+		Auth: configs.AuthConfig{Enabled: true, KeysFile: filepath.Join(t.TempDir(), "api-keys.json")},
+	}
+
+	repo, err := repository.NewAPIKeyFileRepository(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = service.NewAuthService(cfg, repo)
+	assert.ErrorContains(t, err, "auth is enabled but MOCKS_AUTH_KEYS has no static api key")
+}
+
+func TestAuthService_Authenticate(t *testing.T) {
+	srv, err := newAuthService(t, " ci:editor:ci-secret , ops:admin:ops:secret")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.True(t, srv.Enabled())
+
+	key, err := srv.Authenticate(mockscontext.Background(), "ops:secret")
+	assert.NoError(t, err)
+	assert.Equal(t, "ops", key.Name)
+	assert.Equal(t, model.RoleAdmin, key.Role)
+
+	_, err = srv.Authenticate(mockscontext.Background(), "wrong")
+	assert.ErrorAs(t, err, &mockserrors.UnauthorizedError{})
+
+	_, err = srv.Authenticate(mockscontext.Background(), "")
+	assert.ErrorContains(t, err, "missing api key")
+}
+
+func TestAuthService_StoredKeys(t *testing.T) {
+	srv, err := newAuthService(t, "ci:editor:ci-secret")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_, err = srv.CreateKey(mockscontext.Background(), model.APIKey{Name: "viewer", Role: "viewer"})
+	assert.ErrorAs(t, err, &mockserrors.InvalidRulesError{})
+
+	created, err := srv.CreateKey(mockscontext.Background(), model.APIKey{Name: "viewer", Role: model.RoleReadOnly})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.NotEmpty(t, created.ID)
+	assert.Regexp(t, "^mks_[0-9a-f]{64}$", created.Secret)
+	assert.Empty(t, created.SecretHash)
+
+	key, err := srv.Authenticate(mockscontext.Background(), created.Secret)
+	assert.NoError(t, err)
+	assert.Equal(t, created.ID, key.ID)
+	assert.Equal(t, model.RoleReadOnly, key.Role)
+
+	keys, err := srv.ListKeys(mockscontext.Background())
+	if assert.NoError(t, err) && assert.Len(t, keys, 2) {
+		assert.Equal(t, "ci", keys[0].Name)
+		assert.True(t, keys[0].Static)
+		assert.Equal(t, "viewer", keys[1].Name)
+
+		for _, key := range keys {
+			assert.Empty(t, key.Secret)
+			assert.Empty(t, key.SecretHash)
+		}
+	}
+
+	err = srv.DeleteKey(mockscontext.Background(), keys[0].ID)
+	assert.ErrorAs(t, err, &mockserrors.InvalidRulesError{})
+
+	assert.NoError(t, srv.DeleteKey(mockscontext.Background(), created.ID))
+	assert.ErrorAs(t, srv.DeleteKey(mockscontext.Background(), created.ID), &mockserrors.APIKeyNotFoundError{})
+
+	_, err = srv.Authenticate(mockscontext.Background(), created.Secret)
+	assert.ErrorAs(t, err, &mockserrors.UnauthorizedError{})
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	mockscontext "github.com/nicopozo/mockserver/internal/context"
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
+	"github.com/nicopozo/mockserver/internal/model"
+	"github.com/nicopozo/mockserver/internal/repository"
+)
+
+//go:generate mockgen -destination=../utils/test/mocks/bin_service_mock.go -package=mocks -source=./bin_service.go
+
+// BinService manages the responses of the request bins. Get returns the default response of a bin that
+// has not been configured, since a bin captures requests without any setup.
+type BinService interface {
+	Get(ctx context.Context, name string) (*model.Bin, error)
+	List(ctx context.Context) ([]model.Bin, error)
+	Save(ctx context.Context, bin model.Bin) (*model.Bin, error)
+	Delete(ctx context.Context, name string) error
+}
+
+type binService struct {
+	repository repository.BinRepository
+}
+
+func NewBinService(binRepository repository.BinRepository) (BinService, error) {
+	if binRepository == nil {
+		return nil, fmt.Errorf("bin repository cannot be nil") //nolint:err113
+	}
+
+	return &binService{
+		repository: binRepository,
+	}, nil
+}
+
+func (svc *binService) Get(ctx context.Context, name string) (*model.Bin, error) {
+	if err := model.ValidateBinName(name); err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	bin, err := svc.repository.Get(ctx, name)
+	if errors.As(err, &mockserrors.BinNotFoundError{}) {
+		defaultBin := model.NewBin(name)
+
+		return &defaultBin, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error getting bin %s, %w", name, err)
+	}
+
+	return bin, nil
+}
+
+func (svc *binService) List(ctx context.Context) ([]model.Bin, error) {
+	bins, err := svc.repository.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing bins, %w", err)
+	}
+
+	return bins, nil
+}
+
+func (svc *binService) Save(ctx context.Context, bin model.Bin) (*model.Bin, error) {
+	logger := mockscontext.Logger(ctx)
+
+	logger.Debug(svc, nil, "Entering binService Save()")
+
+	if bin.Status == 0 {
+		bin.Status = model.NewBin(bin.Name).Status
+	}
+
+	if err := bin.Validate(); err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	bin.UpdatedAt = time.Now().UTC()
+
+	if err := svc.repository.Save(ctx, bin); err != nil {
+		return nil, fmt.Errorf("error saving bin %s, %w", bin.Name, err)
+	}
+
+	return &bin, nil
+}
+
+func (svc *binService) Delete(ctx context.Context, name string) error {
+	if err := svc.repository.Delete(ctx, name); err != nil {
+		return fmt.Errorf("error deleting bin %s, %w", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package service_test
+
+import (
+	"net/http"
+	"testing"
+
+	mockscontext "github.com/nicopozo/mockserver/internal/context"
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
+	"github.com/nicopozo/mockserver/internal/model"
+	"github.com/nicopozo/mockserver/internal/repository"
+	"github.com/nicopozo/mockserver/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBinService(t *testing.T) {
+	ctx := mockscontext.Background()
+	cfg := newScenarioConfig(t)
+
+	binRepo, err := repository.NewBinFileRepository(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	binService, err := service.NewBinService(binRepo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bin, err := binService.Get(ctx, "callbacks")
+	assert.NoError(t, err)
+	assert.Equal(t, model.NewBin("callbacks"), *bin, "a bin answers with an empty 200 until it is configured")
+
+	_, err = binService.Save(ctx, model.Bin{Name: "callbacks", Status: 42})
+	assert.ErrorAs(t, err, &mockserrors.InvalidRulesError{})
+
+	_, err = binService.Get(ctx, "not/valid")
+	assert.ErrorAs(t, err, &mockserrors.InvalidRulesError{})
+
+	saved, err := binService.Save(ctx, model.Bin{
+		Name: "callbacks", Status: http.StatusAccepted, Body: `{"received":true}`,
+		Headers: map[string]string{"X-Bin": "callbacks"},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.False(t, saved.UpdatedAt.IsZero())
+
+	// A new repository reads the bins saved by the previous one.
+	binRepo, err = repository.NewBinFileRepository(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	binService, err = service.NewBinService(binRepo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bin, err = binService.Get(ctx, "callbacks")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, bin.Status)
+	assert.Equal(t, "callbacks", bin.Headers["X-Bin"])
+
+	bins, err := binService.List(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, bins, 1)
+
+	assert.NoError(t, binService.Delete(ctx, "callbacks"))
+	assert.ErrorAs(t, binService.Delete(ctx, "callbacks"), &mockserrors.BinNotFoundError{})
+
+	bin, err = binService.Get(ctx, "callbacks")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, bin.Status)
+}
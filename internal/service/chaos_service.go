@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	mockscontext "github.com/nicopozo/mockserver/internal/context"
+	"github.com/nicopozo/mockserver/internal/model"
+	"github.com/nicopozo/mockserver/internal/repository"
+)
+
+// chaosSettingsTTL is how long the chaos settings are cached, so that changes made through other
+// instances are picked up without reading the settings on every request.
+const chaosSettingsTTL = 5 * time.Second
+
+//go:generate mockgen -destination=../utils/test/mocks/chaos_service_mock.go -package=mocks -source=./chaos_service.go
+
+// ChaosService manages the chaos settings and rolls the chaos policies of the rules and groups.
+type ChaosService interface {
+	Get(ctx context.Context) (*model.ChaosSettings, error)
+	Save(ctx context.Context, settings model.ChaosSettings) (*model.ChaosSettings, error)
+	SetStatus(ctx context.Context, status string) (*model.ChaosSettings, error)
+	Decide(ctx context.Context, rule model.Rule) (*model.ChaosResult, error)
+}
+
+type chaosService struct {
+	repository repository.ChaosRepository
+
+	mu       sync.Mutex
+	settings *model.ChaosSettings
+	loadedAt time.Time
+	random   *rand.Rand
+}
+
+func NewChaosService(chaosRepository repository.ChaosRepository) (ChaosService, error) {
+	if chaosRepository == nil {
+		return nil, fmt.Errorf("chaos repository cannot be nil") //nolint:err113
+	}
+
+	return &chaosService{
+		repository: chaosRepository,
+	}, nil
+}
+
+func (svc *chaosService) Get(ctx context.Context) (*model.ChaosSettings, error) {
+	settings, err := svc.repository.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting chaos settings, %w", err)
+	}
+
+	return settings, nil
+}
+
+// Save replaces the chaos settings. The random decisions start over from the seed, so a run that saves
+// the same seed and sends the same requests gets the same chaos.
+func (svc *chaosService) Save(ctx context.Context, settings model.ChaosSettings) (*model.ChaosSettings, error) {
+	logger := mockscontext.Logger(ctx)
+
+	logger.Debug(svc, nil, "Entering chaosService Save()")
+
+	if settings.Status == "" {
+		settings.Status = model.ChaosStatusEnabled
+	}
+
+	if err := settings.Validate(); err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	settings.UpdatedAt = time.Now().UTC()
+
+	if err := svc.repository.Save(ctx, settings); err != nil {
+		return nil, fmt.Errorf("error saving chaos settings, %w", err)
+	}
+
+	svc.mu.Lock()
+	svc.load(&settings)
+	svc.mu.Unlock()
+
+	return &settings, nil
+}
+
+// SetStatus switches every chaos policy on or off.
+func (svc *chaosService) SetStatus(ctx context.Context, status string) (*model.ChaosSettings, error) {
+	if err := (model.ChaosStatus{Status: status}).Validate(); err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	settings, err := svc.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	settings.Status = status
+
+	return svc.Save(ctx, *settings)
+}
+
+// Decide rolls the chaos policy of the rule, or the one of its group when the rule has none. It returns
+// nil when chaos is disabled, there is no policy or the policy does not fire.
+func (svc *chaosService) Decide(ctx context.Context, rule model.Rule) (*model.ChaosResult, error) {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+
+	if svc.settings == nil || time.Since(svc.loadedAt) > chaosSettingsTTL {
+		settings, err := svc.repository.Get(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error getting chaos settings, %w", err)
+		}
+
+		svc.load(settings)
+	}
+
+	if !svc.settings.Enabled() {
+		return nil, nil //nolint:nilnil
+	}
+
+	policy, source := rule.Chaos, model.ChaosSourceRule
+
+	if policy == nil {
+		groupPolicy, found := svc.settings.Groups[rule.Group]
+		if !found {
+			return nil, nil //nolint:nilnil
+		}
+
+		policy, source = &groupPolicy, model.ChaosSourceGroup
+	}
+
+	result := policy.Decide(svc.random)
+	if result != nil {
+		result.Source = source
+		result.Group = rule.Group
+
+		mockscontext.Logger(ctx).Debug(svc, map[string]string{"source": source}, "applying chaos to rule %s",
+			rule.Key)
+	}
+
+	return result, nil
+}
+
+// load caches the settings. The random source is seeded again only when the settings have changed, so
+// refreshing the cache does not restart the sequence of decisions. It must be called with mu held.
+func (svc *chaosService) load(settings *model.ChaosSettings) {
+	changed := svc.settings == nil || !svc.settings.UpdatedAt.Equal(settings.UpdatedAt)
+
+	svc.settings = settings
+	svc.loadedAt = time.Now()
+
+	if !changed && svc.random != nil {
+		return
+	}
+
+	seed := time.Now().UnixNano()
+	if settings.Seed != nil {
+		seed = *settings.Seed
+	}
+
+	svc.random = rand.New(rand.NewSource(seed)) //nolint:gosec
+}
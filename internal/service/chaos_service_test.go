@@ -0,0 +1,184 @@
+package service_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mockscontext "github.com/nicopozo/mockserver/internal/context"
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
+	"github.com/nicopozo/mockserver/internal/model"
+	"github.com/nicopozo/mockserver/internal/repository"
+	"github.com/nicopozo/mockserver/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func newChaosService(t *testing.T) service.ChaosService {
+	t.Helper()
+
+	chaosRepo, err := repository.NewChaosFileRepository(newScenarioConfig(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chaosService, err := service.NewChaosService(chaosRepo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return chaosService
+}
+
+func chaosSeed(seed int64) *int64 {
+	return &seed
+}
+
+func TestChaosService_DecideIsReproducible(t *testing.T) {
+	ctx := mockscontext.Background()
+	chaosService := newChaosService(t)
+
+	settings := model.ChaosSettings{
+		Seed: chaosSeed(42),
+		Groups: map[string]model.ChaosPolicy{
+			"payments": {
+				Probability: 0.5,
+				ErrorStatus: http.StatusServiceUnavailable,
+				Latency:     &model.ChaosLatency{Distribution: model.ChaosLatencyUniform, Min: 10, Max: 100},
+			},
+		},
+	}
+
+	run := func() []int {
+		_, err := chaosService.Save(ctx, settings)
+		assert.NoError(t, err)
+
+		decisions := make([]int, 0, 50)
+
+		for range 50 {
+			result, err := chaosService.Decide(ctx, model.Rule{Key: "rule-1", Group: "payments"})
+			assert.NoError(t, err)
+
+			if result == nil {
+				decisions = append(decisions, -1)
+
+				continue
+			}
+
+			assert.Equal(t, model.ChaosSourceGroup, result.Source)
+			assert.Equal(t, http.StatusServiceUnavailable, result.ErrorStatus)
+			assert.True(t, result.LatencyMs >= 10 && result.LatencyMs <= 100)
+
+			decisions = append(decisions, result.LatencyMs)
+		}
+
+		return decisions
+	}
+
+	first := run()
+	assert.Equal(t, first, run())
+	assert.Contains(t, first, -1)
+	assert.NotEqual(t, -1, max(first[0], first[1], first[2], first[3], first[4], first[5]))
+
+	result, err := chaosService.Decide(ctx, model.Rule{Key: "rule-2", Group: "orders"})
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+}
+
+func TestChaosService_RulePolicyAndSwitch(t *testing.T) {
+	ctx := mockscontext.Background()
+	chaosService := newChaosService(t)
+
+	_, err := chaosService.Save(ctx, model.ChaosSettings{
+		Groups: map[string]model.ChaosPolicy{
+			"payments": {Probability: 1, ErrorStatus: http.StatusInternalServerError},
+		},
+	})
+	assert.NoError(t, err)
+
+	rule := model.Rule{
+		Key:   "rule-1",
+		Group: "payments",
+		Chaos: &model.ChaosPolicy{Probability: 1, Fault: model.ResponseFaultConnectionReset},
+	}
+
+	result, err := chaosService.Decide(ctx, rule)
+	assert.NoError(t, err)
+	assert.Equal(t, &model.ChaosResult{
+		Source: model.ChaosSourceRule,
+		Group:  "payments",
+		Fault:  model.ResponseFaultConnectionReset,
+	}, result)
+
+	settings, err := chaosService.SetStatus(ctx, model.ChaosStatusDisabled)
+	assert.NoError(t, err)
+	assert.Equal(t, model.ChaosStatusDisabled, settings.Status)
+	assert.Len(t, settings.Groups, 1)
+
+	result, err = chaosService.Decide(ctx, rule)
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+
+	_, err = chaosService.SetStatus(ctx, "paused")
+	assert.ErrorAs(t, err, &mockserrors.InvalidRulesError{})
+
+	_, err = chaosService.Save(ctx, model.ChaosSettings{
+		Groups: map[string]model.ChaosPolicy{"payments": {Probability: 2, ErrorStatus: http.StatusBadGateway}},
+	})
+	assert.EqualError(t, err, "group 'payments': chaos probability must be between 0 and 1")
+}
+
+func TestMockService_Chaos(t *testing.T) {
+	ctx := mockscontext.Background()
+	ruleService, scenarioService := newScenarioServices(t, newScenarioConfig(t))
+	chaosService := newChaosService(t)
+
+	mockService, err := service.NewMockService(ruleService, newWebhookService(t), scenarioService,
+		newResourceService(t), chaosService)
+	assert.NoError(t, err)
+
+	rule := scenarioRule("pending order", "", "paid", "pending")
+	rule.Chaos = &model.ChaosPolicy{Probability: 1, ErrorStatus: http.StatusServiceUnavailable}
+
+	saved, err := ruleService.Save(ctx, rule)
+	assert.NoError(t, err)
+
+	execute := func() (model.Response, model.ExecutionResult) {
+		request := httptest.NewRequest(http.MethodGet, "/orders/1", nil)
+
+		response, result, err := mockService.SearchResponseForRequest(ctx, request, "/orders/1", "", "")
+		assert.NoError(t, err)
+
+		return response, result
+	}
+
+	response, result := execute()
+	assert.Equal(t, http.StatusServiceUnavailable, response.HTTPStatus)
+	assert.JSONEq(t, `{"message":"error injected by chaos policy","status":503}`, response.Body)
+	assert.Equal(t, model.ChaosSourceRule, result.Chaos.Source)
+
+	// The request did not reach the rule, so the scenario did not move.
+	state, err := scenarioService.State(ctx, "checkout")
+	assert.NoError(t, err)
+	assert.Equal(t, model.ScenarioStateStarted, state)
+
+	saved.Chaos = &model.ChaosPolicy{
+		Probability: 1,
+		Latency:     &model.ChaosLatency{Distribution: model.ChaosLatencyFixed, Value: 150},
+	}
+
+	_, err = ruleService.Update(ctx, saved.Key, saved)
+	assert.NoError(t, err)
+
+	response, result = execute()
+	assert.Equal(t, http.StatusOK, response.HTTPStatus)
+	assert.Equal(t, "pending", response.Body)
+	assert.Equal(t, 150, response.Delay)
+	assert.Equal(t, 150, result.Chaos.LatencyMs)
+
+	_, err = chaosService.SetStatus(ctx, model.ChaosStatusDisabled)
+	assert.NoError(t, err)
+
+	response, result = execute()
+	assert.Equal(t, 0, response.Delay)
+	assert.Nil(t, result.Chaos)
+}
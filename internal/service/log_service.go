@@ -2,24 +2,40 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
 	"sync"
+	"time"
 
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
 	"github.com/nicopozo/mockserver/internal/model"
 	"github.com/nicopozo/mockserver/internal/repository"
 	"github.com/oklog/ulid/v2"
 )
 
+const (
+	verifyPageSize    = 1000
+	maxClosestEntries = 3
+)
+
 // LogService stores request/response log entries.
 type LogService interface {
 	Add(entry model.LogEntry) string
-	Update(id string, updater func(entry *model.LogEntry))
-	GetAll(paging model.Paging) model.LogList
-	Clear()
+	Get(id string) (model.LogEntry, error)
+	Update(id string, updater func(entry *model.LogEntry)) error
+	GetAll(filter model.LogFilter, paging model.Paging) (model.LogList, error)
+	Verify(request model.VerificationRequest) (model.VerificationResult, error)
+	Subscribe(filter model.LogFilter) (*LogSubscription, error)
+	Clear(bin string)
 }
 
 type logService struct {
 	repo                  repository.LogRepository
 	pendingWebhookResults sync.Map // key: logID (string), value: []model.WebhookResult
+	broker                logBroker
 }
 
 // NewLogService creates a new LogService with the provided repository.
@@ -45,16 +61,58 @@ func (s *logService) Add(entry model.LogEntry) string {
 		s.pendingWebhookResults.Delete(entry.ID)
 	}
 
-	_ = s.repo.Add(context.Background(), entry)
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
+	}
+
+	if err := s.repo.Add(context.Background(), entry); err == nil {
+		s.broker.publish(model.LogEvent{Type: model.LogEventCreated, Entry: entry})
+	}
 
 	return entry.ID
 }
 
-func (s *logService) Update(logID string, updater func(entry *model.LogEntry)) {
-	err := s.repo.Update(context.Background(), logID, updater)
+func (s *logService) Get(logID string) (model.LogEntry, error) {
+	entry, err := s.repo.Get(context.Background(), logID)
 	if err != nil {
+		return model.LogEntry{}, fmt.Errorf("error reading log entry, %w", err)
+	}
+
+	return *entry, nil
+}
+
+// Update applies updater to the entry logID. When the entry is not stored yet, as when a webhook
+// finishes before the request is logged, the results are kept until Add is called.
+func (s *logService) Update(logID string, updater func(entry *model.LogEntry)) error {
+	var updated model.LogEntry
+
+	err := s.repo.Update(context.Background(), logID, func(entry *model.LogEntry) {
+		updater(entry)
+		updated = *entry
+	})
+	if errors.As(err, &mockserrors.LogEntryNotFoundError{}) {
 		s.handlePendingWebhook(logID, updater)
+
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("error updating log entry, %w", err)
 	}
+
+	s.broker.publish(model.LogEvent{Type: model.LogEventUpdated, Entry: updated})
+
+	return nil
+}
+
+// Subscribe starts delivering the log events that match filter. Callers must close the subscription
+// when they are done.
+func (s *logService) Subscribe(filter model.LogFilter) (*LogSubscription, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return s.broker.subscribe(filter), nil
 }
 
 func (s *logService) handlePendingWebhook(logID string, updater func(entry *model.LogEntry)) {
@@ -84,18 +142,130 @@ func (s *logService) handlePendingWebhook(logID string, updater func(entry *mode
 	s.pendingWebhookResults.Store(logID, newResults)
 }
 
-func (s *logService) GetAll(paging model.Paging) model.LogList {
-	logs, err := s.repo.GetAll(context.Background(), paging)
+func (s *logService) GetAll(filter model.LogFilter, paging model.Paging) (model.LogList, error) {
+	if err := filter.Validate(); err != nil {
+		return model.LogList{}, err //nolint:wrapcheck
+	}
+
+	logs, err := s.repo.GetAll(context.Background(), filter, paging)
 	if err != nil {
-		return model.LogList{
-			Results: []model.LogEntry{},
-			Paging:  paging,
+		return model.LogList{}, fmt.Errorf("error reading logs, %w", err)
+	}
+
+	if logs.Results == nil {
+		logs.Results = []model.LogEntry{}
+	}
+
+	return logs, nil
+}
+
+// Verify checks the captured log entries against request. Besides the matching entries, the result
+// holds the entries that failed the fewest criteria, to help explaining why a verification failed.
+func (s *logService) Verify(request model.VerificationRequest) (model.VerificationResult, error) {
+	if err := request.Validate(); err != nil {
+		return model.VerificationResult{}, err //nolint:wrapcheck
+	}
+
+	var pathRegex *regexp.Regexp
+
+	if request.Path != "" {
+		var err error
+
+		pathRegex, err = regexp.Compile(repository.CreateExpression(request.Path))
+		if err != nil {
+			return model.VerificationResult{}, mockserrors.InvalidRulesError{
+				Message: fmt.Sprintf("invalid path '%s': %s", request.Path, err.Error()),
+			}
+		}
+	}
+
+	result := model.VerificationResult{
+		Matches: make([]model.LogEntry, 0),
+		Closest: make([]model.VerificationMatch, 0),
+	}
+
+	paging := model.Paging{Limit: verifyPageSize}
+
+	for {
+		logs, err := s.repo.GetAll(context.Background(), model.LogFilter{Bin: request.Bin}, paging)
+		if err != nil {
+			return model.VerificationResult{}, fmt.Errorf("error reading logs, %w", err)
+		}
+
+		for _, entry := range logs.Results {
+			mismatches := verificationMismatches(request, pathRegex, entry)
+			if len(mismatches) == 0 {
+				result.Matches = append(result.Matches, entry)
+			} else {
+				result.Closest = addClosest(result.Closest, model.VerificationMatch{Entry: entry, Mismatches: mismatches})
+			}
+		}
+
+		if len(logs.Results) < verifyPageSize {
+			break
+		}
+
+		paging.LastID = logs.Results[len(logs.Results)-1].ID
+	}
+
+	result.Count = len(result.Matches)
+	result.Passed = request.Count.Satisfied(result.Count)
+	result.Message = fmt.Sprintf("expected %s matching requests, found %d", request.Count, result.Count)
+
+	return result, nil
+}
+
+// addClosest adds match to closest, which keeps the maxClosestEntries entries with the fewest mismatches.
+// Entries with as many mismatches as one already kept go after it, so the newest ones come first.
+func addClosest(closest []model.VerificationMatch, match model.VerificationMatch) []model.VerificationMatch {
+	index := len(closest)
+	for index > 0 && len(closest[index-1].Mismatches) > len(match.Mismatches) {
+		index--
+	}
+
+	if index == maxClosestEntries {
+		return closest
+	}
+
+	closest = slices.Insert(closest, index, match)
+	if len(closest) > maxClosestEntries {
+		closest = closest[:maxClosestEntries]
+	}
+
+	return closest
+}
+
+func verificationMismatches(request model.VerificationRequest, pathRegex *regexp.Regexp,
+	entry model.LogEntry,
+) []string {
+	var mismatches []string
+
+	if request.Method != "" && !strings.EqualFold(request.Method, entry.Method) {
+		mismatches = append(mismatches, fmt.Sprintf("method is %s, expected %s", entry.Method,
+			strings.ToUpper(request.Method)))
+	}
+
+	if pathRegex != nil && !pathRegex.MatchString(entry.Path()) {
+		mismatches = append(mismatches, fmt.Sprintf("path %s does not match %s", entry.Path(), request.Path))
+	}
+
+	if request.RuleKey != "" && request.RuleKey != entry.RuleKey {
+		mismatches = append(mismatches, fmt.Sprintf("rule key is '%s', expected '%s'", entry.RuleKey, request.RuleKey))
+	}
+
+	matchRequest := entry.MatchRequest()
+
+	for _, matcher := range request.Matchers {
+		if !matcher.Matches(matchRequest) {
+			mismatches = append(mismatches, fmt.Sprintf("%s '%s' does not satisfy %s %s", matcher.Type, matcher.Key,
+				matcher.Operator, matcher.Value))
 		}
 	}
 
-	return logs
+	return mismatches
 }
 
-func (s *logService) Clear() {
-	_ = s.repo.Clear(context.Background())
+// Clear deletes the requests captured by bin, or the mock requests when bin is empty.
+func (s *logService) Clear(bin string) {
+	_ = s.repo.Clear(context.Background(), bin)
 }
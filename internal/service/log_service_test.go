@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
 	"github.com/nicopozo/mockserver/internal/model"
 	"github.com/nicopozo/mockserver/internal/repository"
 	"github.com/nicopozo/mockserver/internal/service"
@@ -26,9 +27,9 @@ func TestLogService_RaceCondition(t *testing.T) {
 		ResponseBody: `{"status":"success"}`,
 	}
 
-	logSvc.Update(logID, func(entry *model.LogEntry) {
+	assert.NoError(t, logSvc.Update(logID, func(entry *model.LogEntry) {
 		entry.WebhookResults = append(entry.WebhookResults, webhookResult)
-	})
+	}))
 
 	// 2. Main thread completes and calls Add.
 	logEntry := model.LogEntry{
@@ -45,7 +46,8 @@ func TestLogService_RaceCondition(t *testing.T) {
 	assert.Equal(t, logID, savedID)
 
 	// 3. Fetch all logs and verify that the webhook results were correctly merged!
-	logs := logSvc.GetAll(model.Paging{Limit: 10})
+	logs, err := logSvc.GetAll(model.LogFilter{}, model.Paging{Limit: 10})
+	assert.NoError(t, err)
 	assert.Len(t, logs.Results, 1)
 
 	savedEntry := logs.Results[0]
@@ -92,12 +94,13 @@ func TestLogService_NormalFlow(t *testing.T) {
 		ResponseBody: `{"status":"success"}`,
 	}
 
-	logSvc.Update(logID, func(entry *model.LogEntry) {
+	assert.NoError(t, logSvc.Update(logID, func(entry *model.LogEntry) {
 		entry.WebhookResults = append(entry.WebhookResults, webhookResult)
-	})
+	}))
 
 	// 3. Fetch all logs and verify everything is correct.
-	logs := logSvc.GetAll(model.Paging{Limit: 10})
+	logs, err := logSvc.GetAll(model.LogFilter{}, model.Paging{Limit: 10})
+	assert.NoError(t, err)
 	assert.Len(t, logs.Results, 1)
 
 	savedEntry := logs.Results[0]
@@ -108,3 +111,234 @@ func TestLogService_NormalFlow(t *testing.T) {
 	assert.Equal(t, webhookResult.URL, savedWebhookResult.URL)
 	assert.Equal(t, webhookResult.ResponseBody, savedWebhookResult.ResponseBody)
 }
+
+func TestLogService_Verify(t *testing.T) {
+	one, two := 1, 2
+
+	entries := []model.LogEntry{
+		{
+			ID: "01", Method: "POST", URL: "/v1/payments/10?source=web", RuleKey: "payments",
+			RequestHeaders: map[string]string{"X-Country": "AR"}, QueryParams: map[string]string{"source": "web"},
+			RequestBody: `{"amount":10}`,
+		},
+		{
+			ID: "02", Method: "POST", URL: "/v1/payments/20", RuleKey: "payments",
+			RequestHeaders: map[string]string{"X-Country": "BR"}, RequestBody: `{"amount":20}`,
+		},
+		{ID: "03", Method: "GET", URL: "/v1/users/1", RuleKey: "users"},
+	}
+
+	tests := []struct {
+		name        string
+		request     model.VerificationRequest
+		wantPassed  bool
+		wantCount   int
+		wantMessage string
+		wantClosest []string
+	}{
+		{
+			name:        "Should pass when at least one request matches by default",
+			request:     model.VerificationRequest{Method: "post", Path: "/v1/payments/{id}"},
+			wantPassed:  true,
+			wantCount:   2,
+			wantMessage: "expected at least 1 matching requests, found 2",
+			wantClosest: []string{"method is GET, expected POST", "path /v1/users/1 does not match /v1/payments/{id}"},
+		},
+		{
+			name: "Should filter by matchers",
+			request: model.VerificationRequest{
+				Path: "/v1/payments/{id}",
+				Matchers: []model.Matcher{
+					{Type: model.MatcherTypeHeader, Key: "X-Country", Operator: model.MatcherOperatorEquals, Value: "AR"},
+					{Type: model.MatcherTypeQuery, Key: "source", Operator: model.MatcherOperatorPresent},
+				},
+				Count: model.VerificationCount{Exactly: &one},
+			},
+			wantPassed:  true,
+			wantCount:   1,
+			wantMessage: "expected exactly 1 matching requests, found 1",
+			wantClosest: []string{
+				"header 'X-Country' does not satisfy equals AR",
+				"query 'source' does not satisfy present ",
+			},
+		},
+		{
+			name:        "Should fail when the count is not satisfied",
+			request:     model.VerificationRequest{RuleKey: "users", Count: model.VerificationCount{AtLeast: &two}},
+			wantPassed:  false,
+			wantCount:   1,
+			wantMessage: "expected at least 2 matching requests, found 1",
+			wantClosest: []string{"rule key is 'payments', expected 'users'"},
+		},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			logSvc := service.NewLogService(repository.NewLogMemoryRepository())
+			for _, entry := range entries {
+				logSvc.Add(entry)
+			}
+
+			result, err := logSvc.Verify(tt.request)
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			assert.Equal(t, tt.wantPassed, result.Passed)
+			assert.Equal(t, tt.wantCount, result.Count)
+			assert.Len(t, result.Matches, tt.wantCount)
+			assert.Equal(t, tt.wantMessage, result.Message)
+
+			if assert.NotEmpty(t, result.Closest) {
+				assert.Equal(t, tt.wantClosest, result.Closest[0].Mismatches)
+			}
+		})
+	}
+}
+
+func TestLogService_VerifyClosest(t *testing.T) {
+	logSvc := service.NewLogService(repository.NewLogMemoryRepository())
+
+	for _, entry := range []model.LogEntry{
+		{ID: "01", Method: "GET", URL: "/v1/users/1", RuleKey: "users"},
+		{ID: "02", Method: "POST", URL: "/v1/users/2", RuleKey: "users"},
+		{ID: "03", Method: "GET", URL: "/v1/payments/3", RuleKey: "payments"},
+		{ID: "04", Method: "POST", URL: "/v1/users/4", RuleKey: "payments"},
+		{ID: "05", Method: "GET", URL: "/v1/users/5", RuleKey: "payments"},
+	} {
+		logSvc.Add(entry)
+	}
+
+	result, err := logSvc.Verify(model.VerificationRequest{Method: "POST", Path: "/v1/payments/{id}", RuleKey: "payments"})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	ids := make([]string, 0, len(result.Closest))
+	for _, closest := range result.Closest {
+		ids = append(ids, closest.Entry.ID)
+	}
+
+	assert.Equal(t, []string{"04", "03", "05"}, ids)
+}
+
+func TestLogService_VerifyInvalidRequest(t *testing.T) {
+	one, two := 1, 2
+
+	tests := []struct {
+		name    string
+		request model.VerificationRequest
+		wantErr string
+	}{
+		{
+			name:    "Should fail with relative path",
+			request: model.VerificationRequest{Path: "v1/payments"},
+			wantErr: "path must start with '/'",
+		},
+		{
+			name:    "Should fail with a path that is not a valid expression",
+			request: model.VerificationRequest{Path: "/orders/a("},
+			wantErr: "invalid path '/orders/a('",
+		},
+		{
+			name:    "Should fail when combining exactly with ranges",
+			request: model.VerificationRequest{Count: model.VerificationCount{Exactly: &one, AtMost: &two}},
+			wantErr: "count exactly cannot be combined with at_least or at_most",
+		},
+		{
+			name:    "Should fail with inverted range",
+			request: model.VerificationRequest{Count: model.VerificationCount{AtLeast: &two, AtMost: &one}},
+			wantErr: "count at_least must be lower than or equal to at_most",
+		},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			logSvc := service.NewLogService(repository.NewLogMemoryRepository())
+
+			_, err := logSvc.Verify(tt.request)
+			assert.ErrorAs(t, err, &mockserrors.InvalidRulesError{})
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestLogService_GetAllInvalidFilter(t *testing.T) {
+	logSvc := service.NewLogService(repository.NewLogMemoryRepository())
+
+	_, err := logSvc.GetAll(model.LogFilter{StatusFrom: 500, StatusTo: 400}, model.Paging{Limit: 10})
+	assert.ErrorAs(t, err, &mockserrors.InvalidRulesError{})
+	assert.ErrorContains(t, err, "status_from must be lower than or equal to status_to")
+
+	_, err = logSvc.GetAll(model.LogFilter{URLRegex: "("}, model.Paging{Limit: 10})
+	assert.ErrorContains(t, err, "url_regex is not a valid regular expression")
+}
+
+func TestLogService_Subscribe(t *testing.T) {
+	logSvc := service.NewLogService(repository.NewLogMemoryRepository())
+
+	subscription, err := logSvc.Subscribe(model.LogFilter{Method: "POST"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer subscription.Close()
+
+	logSvc.Add(model.LogEntry{ID: "1", Method: "GET", URL: "/ignored"})
+	logSvc.Add(model.LogEntry{ID: "2", Method: "POST", URL: "/payments"})
+	assert.NoError(t, logSvc.Update("2", func(entry *model.LogEntry) {
+		entry.WebhookResults = append(entry.WebhookResults, model.WebhookResult{StatusCode: 200})
+	}))
+
+	created := <-subscription.Events()
+	assert.Equal(t, model.LogEventCreated, created.Type)
+	assert.Equal(t, "2", created.Entry.ID)
+	assert.False(t, created.Entry.Timestamp.IsZero())
+
+	updated := <-subscription.Events()
+	assert.Equal(t, model.LogEventUpdated, updated.Type)
+	assert.Equal(t, "/payments", updated.Entry.URL)
+	assert.Len(t, updated.Entry.WebhookResults, 1)
+
+	subscription.Close()
+
+	_, open := <-subscription.Events()
+	assert.False(t, open)
+}
+
+func TestLogService_SubscribeSlowSubscriberDoesNotBlock(t *testing.T) {
+	logSvc := service.NewLogService(repository.NewLogMemoryRepository())
+
+	subscription, err := logSvc.Subscribe(model.LogFilter{})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer subscription.Close()
+
+	const entries = 300
+
+	done := make(chan struct{})
+
+	go func() {
+		for range entries {
+			logSvc.Add(model.LogEntry{Method: "GET", URL: "/test"})
+		}
+
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Add blocked on a subscriber that is not reading")
+	}
+
+	assert.Positive(t, subscription.Dropped())
+	assert.Equal(t, uint64(entries), uint64(len(subscription.Events()))+subscription.Dropped())
+}
+
+func TestLogService_SubscribeInvalidFilter(t *testing.T) {
+	logSvc := service.NewLogService(repository.NewLogMemoryRepository())
+
+	_, err := logSvc.Subscribe(model.LogFilter{URL: "/a", URLRegex: "^/a"})
+	assert.ErrorAs(t, err, &mockserrors.InvalidRulesError{})
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/nicopozo/mockserver/internal/model"
+)
+
+// logSubscriptionBuffer is the number of events a subscriber can fall behind before events are dropped.
+const logSubscriptionBuffer = 256
+
+// LogSubscription receives the log events matching its filter. Events are delivered through a bounded
+// buffer: when a subscriber does not keep up, new events are dropped for it instead of blocking the
+// requests being logged.
+type LogSubscription struct {
+	events  chan model.LogEvent
+	matches func(entry model.LogEntry) bool
+	dropped atomic.Uint64
+	cancel  func()
+}
+
+// Events returns the channel the events are delivered to. It is closed when the subscription is closed.
+func (subscription *LogSubscription) Events() <-chan model.LogEvent {
+	return subscription.events
+}
+
+// Dropped returns how many events were discarded because the buffer was full.
+func (subscription *LogSubscription) Dropped() uint64 {
+	return subscription.dropped.Load()
+}
+
+// Close stops the delivery of events. It is safe to call it more than once.
+func (subscription *LogSubscription) Close() {
+	subscription.cancel()
+}
+
+func (subscription *LogSubscription) deliver(event model.LogEvent) {
+	if !subscription.matches(event.Entry) {
+		return
+	}
+
+	select {
+	case subscription.events <- event:
+	default:
+		subscription.dropped.Add(1)
+	}
+}
+
+// logBroker fans log events out to the current subscriptions.
+type logBroker struct {
+	mu            sync.RWMutex
+	subscriptions map[*LogSubscription]struct{}
+}
+
+func (broker *logBroker) subscribe(filter model.LogFilter) *LogSubscription {
+	subscription := &LogSubscription{
+		events:  make(chan model.LogEvent, logSubscriptionBuffer),
+		matches: filter.Matcher(),
+	}
+
+	var once sync.Once
+
+	subscription.cancel = func() {
+		once.Do(func() {
+			broker.mu.Lock()
+			defer broker.mu.Unlock()
+
+			delete(broker.subscriptions, subscription)
+			close(subscription.events)
+		})
+	}
+
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+
+	if broker.subscriptions == nil {
+		broker.subscriptions = make(map[*LogSubscription]struct{})
+	}
+
+	broker.subscriptions[subscription] = struct{}{}
+
+	return subscription
+}
+
+func (broker *logBroker) publish(event model.LogEvent) {
+	broker.mu.RLock()
+	defer broker.mu.RUnlock()
+
+	for subscription := range broker.subscriptions {
+		subscription.deliver(event)
+	}
+}
@@ -27,12 +27,13 @@ const maxRand = 9999999999
 
 type MockService interface {
 	SearchResponseForRequest(
-		ctx context.Context, request *http.Request, path, body string,
-		onWebhookResult func(model.WebhookResult),
-	) (model.Response, model.AssertionResult, error)
+		ctx context.Context, request *http.Request, path, body, logID string,
+	) (model.Response, model.ExecutionResult, error)
 }
 
-func NewMockService(ruleService RuleService, webhookService WebhookService) (MockService, error) {
+func NewMockService(ruleService RuleService, webhookService WebhookService,
+	scenarioService ScenarioService, resourceService ResourceService, chaosService ChaosService,
+) (MockService, error) {
 	if ruleService == nil {
 		return nil, fmt.Errorf("rule service cannot be nil") //nolint:err113
 	}
@@ -41,62 +42,254 @@ func NewMockService(ruleService RuleService, webhookService WebhookService) (Moc
 		return nil, fmt.Errorf("webhook service cannot be nil") //nolint:err113
 	}
 
+	if scenarioService == nil {
+		return nil, fmt.Errorf("scenario service cannot be nil") //nolint:err113
+	}
+
+	if resourceService == nil {
+		return nil, fmt.Errorf("resource service cannot be nil") //nolint:err113
+	}
+
+	if chaosService == nil {
+		return nil, fmt.Errorf("chaos service cannot be nil") //nolint:err113
+	}
+
 	return &mockService{
-		RuleService:    ruleService,
-		webhookService: webhookService,
+		RuleService:     ruleService,
+		webhookService:  webhookService,
+		scenarioService: scenarioService,
+		resourceService: resourceService,
+		chaosService:    chaosService,
 	}, nil
 }
 
 type mockService struct {
-	RuleService    RuleService
-	webhookService WebhookService
+	RuleService     RuleService
+	webhookService  WebhookService
+	scenarioService ScenarioService
+	resourceService ResourceService
+	chaosService    ChaosService
 }
 
+// scenarioClaimAttempts is how many times a request is matched when the scenario state that the selected
+// rule requires is changed by a concurrent request.
+const scenarioClaimAttempts = 5
+
 func (svc *mockService) SearchResponseForRequest(ctx context.Context,
-	request *http.Request, path, body string,
-	onWebhookResult func(model.WebhookResult),
-) (model.Response, model.AssertionResult, error) {
+	request *http.Request, path, body, logID string,
+) (model.Response, model.ExecutionResult, error) {
 	logger := mockscontext.Logger(ctx)
 
 	logger.Debug(svc, nil, "Entering mockService Execute()")
 
+	rule, claimed, err := svc.selectRule(ctx, request, path, body)
+	if err != nil {
+		logger.Error(svc, nil, err, "error searching responses")
+
+		return model.Response{}, model.ExecutionResult{}, fmt.Errorf("error searching rule, %w", err)
+	}
+
+	response, result, err := svc.executeRule(ctx, rule, request, path, body, logID)
+	if claimed && (err != nil || result.Chaos.Interrupts()) {
+		// The rule did not run, so the scenario goes back to the state that the rule requires.
+		svc.releaseScenario(ctx, rule)
+	}
+
+	return response, result, err
+}
+
+// selectRule searches the rule that answers the request. A rule that requires a scenario state and moves
+// the scenario moves it right away with a compare-and-set, so that concurrent requests are not answered by
+// the same rule from the same state; the request that loses the race is matched again. It reports whether
+// the scenario was moved.
+func (svc *mockService) selectRule(ctx context.Context, request *http.Request, path,
+	body string,
+) (model.Rule, bool, error) {
 	method := strings.ToUpper(request.Method)
 
-	rule, err := svc.RuleService.SearchByMethodAndPath(ctx, method, path)
+	for range scenarioClaimAttempts {
+		scenarios := &scenarioStateLookup{ctx: ctx, service: svc.scenarioService, states: make(map[string]string)}
+
+		matchRequest := model.NewMatchRequest(request, body)
+		matchRequest.ScenarioState = scenarios.State
+
+		rule, err := svc.RuleService.SearchByMethodAndPath(ctx, method, path, matchRequest)
+		if err == nil {
+			err = scenarios.err
+		}
+
+		if err != nil {
+			return model.Rule{}, false, err //nolint:wrapcheck
+		}
+
+		if !claimsScenario(rule) {
+			return rule, false, nil
+		}
+
+		moved, err := svc.scenarioService.Transition(ctx, rule.Scenario.Name, rule.Scenario.RequiredState,
+			rule.Scenario.NewState)
+		if err != nil {
+			return model.Rule{}, false, fmt.Errorf("error updating scenario, %w", err)
+		}
+
+		if moved {
+			return rule, true, nil
+		}
+	}
+
+	return model.Rule{}, false, fmt.Errorf("scenarios kept changing while the request was matched") //nolint:err113
+}
+
+func (svc *mockService) executeRule(ctx context.Context, rule model.Rule, request *http.Request, path, body,
+	logID string,
+) (model.Response, model.ExecutionResult, error) {
+	logger := mockscontext.Logger(ctx)
+
+	result := model.ExecutionResult{RuleKey: rule.Key, RuleName: rule.Name}
+
+	chaos, err := svc.chaosService.Decide(ctx, rule)
 	if err != nil {
-		logger.Error(svc, nil, err, "error searching responses")
+		logger.Error(svc, nil, err, "error deciding chaos")
 
-		return model.Response{}, model.AssertionResult{}, fmt.Errorf("error searching rule, %w", err)
+		return model.Response{}, result, fmt.Errorf("error deciding chaos, %w", err)
+	}
+
+	result.Chaos = chaos
+
+	// An injected error stands for a request that never reached the rule, so the rule is not executed.
+	if chaos.Interrupts() {
+		return chaos.Apply(model.Response{}), result, nil
+	}
+
+	if rule.IsResource() {
+		response, err := svc.resourceService.Handle(ctx, rule, request, path, body)
+		if err != nil {
+			logger.Error(svc, nil, err, "error handling resource request")
+
+			return model.Response{}, result, fmt.Errorf("error handling resource request, %w", err)
+		}
+
+		if err := svc.moveScenario(ctx, rule); err != nil {
+			return model.Response{}, result, err
+		}
+
+		return chaos.Apply(response), result, nil
 	}
 
 	variableValues, err := svc.getVariableValues(*request, body, rule, path)
 	if err != nil {
-		return model.Response{}, model.AssertionResult{}, err
+		return model.Response{}, result, err
 	}
 
-	assertionResult := svc.applyAssertionsFromRule(rule, variableValues)
+	result.AssertionResult = svc.applyAssertionsFromRule(rule, variableValues)
 
-	assertionResult.Print(ctx)
+	result.Print(ctx)
 
-	if assertionResult.Fail {
-		return model.Response{}, assertionResult, assertionResult.GetError() //nolint:wrapcheck
+	if result.Fail {
+		return model.Response{}, result, result.GetError() //nolint:wrapcheck
 	}
 
 	response, err := svc.getResponseFromRule(ctx, rule, request, body, path)
 	if err != nil {
-		return model.Response{}, assertionResult, err
+		return model.Response{}, result, err
 	}
 
-	body = svc.applyVariables(response.Body, variableValues)
+	result.Scene = response.Scene
 
-	response.Body = body
+	webhookVariables := variableValues
 
-	if response.Webhook != nil && response.Webhook.Enabled {
-		logger.Debug(svc, map[string]string{"webhook_url": response.Webhook.URL}, "firing webhook")
-		svc.webhookService.Fire(ctx, *response.Webhook, variableValues, onWebhookResult)
+	if response.Template == model.ResponseTemplateGo {
+		pathParams, _ := svc.getPathParams(rule.Path, path)
+
+		response, err = renderResponseTemplate(response, newTemplateData(request, path, pathParams, body,
+			variableValues))
+		if err != nil {
+			logger.Error(svc, nil, err, "error rendering response template")
+
+			return model.Response{}, result, err
+		}
+
+		// The webhook has already been rendered, so it must not go through {name} replacement again.
+		webhookVariables = nil
+	} else {
+		response.Body = svc.applyVariables(response.Body, variableValues)
+		response.Headers = svc.applyHeaderVariables(response.Headers, variableValues)
+		response.Cookies = svc.applyCookieVariables(response.Cookies, variableValues)
 	}
 
-	return response, assertionResult, nil
+	if err := svc.moveScenario(ctx, rule); err != nil {
+		return model.Response{}, result, err
+	}
+
+	if webhooks := response.EnabledWebhooks(); len(webhooks) > 0 {
+		logger.Debug(svc, map[string]string{"webhooks": strconv.Itoa(len(webhooks))}, "firing webhooks")
+		svc.webhookService.Fire(ctx, logID, webhooks, response.WebhookMode, webhookVariables)
+	}
+
+	return chaos.Apply(response), result, nil
+}
+
+// claimsScenario reports whether the rule moves its scenario from the state it requires, which is done
+// when the rule is selected.
+func claimsScenario(rule model.Rule) bool {
+	return rule.Scenario != nil && rule.Scenario.RequiredState != "" && rule.Scenario.NewState != ""
+}
+
+// releaseScenario moves the scenario of a rule that did not run back to the state the rule requires,
+// unless another request moved it in the meantime.
+func (svc *mockService) releaseScenario(ctx context.Context, rule model.Rule) {
+	logger := mockscontext.Logger(ctx)
+
+	_, err := svc.scenarioService.Transition(ctx, rule.Scenario.Name, rule.Scenario.NewState,
+		rule.Scenario.RequiredState)
+	if err != nil {
+		logger.Error(svc, nil, err, "error moving scenario back to its required state")
+	}
+}
+
+// moveScenario moves the scenario of the rule to its new state, if the rule sets one and it was not already
+// moved when the rule was selected.
+func (svc *mockService) moveScenario(ctx context.Context, rule model.Rule) error {
+	if rule.Scenario == nil || rule.Scenario.NewState == "" || claimsScenario(rule) {
+		return nil
+	}
+
+	logger := mockscontext.Logger(ctx)
+
+	logger.Debug(svc, map[string]string{"scenario": rule.Scenario.Name}, "moving scenario to state %s",
+		rule.Scenario.NewState)
+
+	if _, err := svc.scenarioService.SetState(ctx, rule.Scenario.Name, rule.Scenario.NewState); err != nil {
+		logger.Error(svc, nil, err, "error moving scenario to its new state")
+
+		return fmt.Errorf("error updating scenario, %w", err)
+	}
+
+	return nil
+}
+
+// scenarioStateLookup reads the state of each scenario at most once per request. Matchers cannot fail, so
+// the first error is kept and checked once the rule has been selected.
+type scenarioStateLookup struct {
+	ctx     context.Context //nolint:containedctx
+	service ScenarioService
+	states  map[string]string
+	err     error
+}
+
+func (lookup *scenarioStateLookup) State(name string) string {
+	if state, found := lookup.states[name]; found {
+		return state
+	}
+
+	state, err := lookup.service.State(lookup.ctx, name)
+	if err != nil && lookup.err == nil {
+		lookup.err = err
+	}
+
+	lookup.states[name] = state
+
+	return state
 }
 
 func (svc *mockService) applyVariables(respBody string, variables []*model.Variable) string {
@@ -107,6 +300,37 @@ func (svc *mockService) applyVariables(respBody string, variables []*model.Varia
 	return respBody
 }
 
+func (svc *mockService) applyHeaderVariables(headers map[string]string,
+	variables []*model.Variable,
+) map[string]string {
+	if headers == nil {
+		return nil
+	}
+
+	result := make(map[string]string, len(headers))
+
+	for name, value := range headers {
+		result[name] = svc.applyVariables(value, variables)
+	}
+
+	return result
+}
+
+func (svc *mockService) applyCookieVariables(cookies []model.Cookie, variables []*model.Variable) []model.Cookie {
+	if cookies == nil {
+		return nil
+	}
+
+	result := make([]model.Cookie, len(cookies))
+
+	for index, cookie := range cookies {
+		cookie.Value = svc.applyVariables(cookie.Value, variables)
+		result[index] = cookie
+	}
+
+	return result
+}
+
 func (svc *mockService) getResponseFromRule(ctx context.Context, rule model.Rule, request *http.Request, body,
 	path string,
 ) (model.Response, error) {
@@ -222,6 +446,11 @@ func (svc *mockService) getHeaderVariableValue(key string, request *http.Request
 }
 
 func (svc *mockService) getBodyVariableValue(key, body string) (string, error) {
+	return jsonPathValue(key, body)
+}
+
+// jsonPathValue returns the JSON encoding of the value found at the JSON path key of body.
+func jsonPathValue(key, body string) (string, error) {
 	apply, err := jsonpath.Prepare(key)
 	if err != nil {
 		return "", fmt.Errorf("invalid JSON path for key %s - %w", key, err)
@@ -476,20 +705,12 @@ func (svc *mockService) applyAssertionsFromRule(rule model.Rule, variables []*mo
 }
 
 func (svc *mockService) getNextResponse(ctx context.Context, rule model.Rule) (model.Response, error) {
-	currentIndex := rule.NextResponseIndex
-
-	if currentIndex >= len(rule.Responses) {
-		currentIndex = 0
-	}
-
-	rule.NextResponseIndex = currentIndex + 1
-
-	rule, err := svc.RuleService.Update(ctx, rule.Key, rule)
+	response, err := svc.RuleService.NextResponse(ctx, rule)
 	if err != nil {
 		return model.Response{}, fmt.Errorf("error updating next response - %w", err)
 	}
 
-	return rule.Responses[currentIndex], nil
+	return response, nil
 }
 
 func getVariableValue(variables []*model.Variable, name string) *model.Variable {
@@ -5,7 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
 
 	mockscontext "github.com/nicopozo/mockserver/internal/context"
@@ -919,15 +921,17 @@ func TestMockService_SearchResponseForRequest(t *testing.T) {
 			defer mockCtrl.Finish()
 
 			for idx := range tt.args {
-				ruleServiceMock.EXPECT().SearchByMethodAndPath(tt.args[idx].ctx, tt.args[idx].request.Method, tt.args[idx].path).
+				ruleServiceMock.EXPECT().
+					SearchByMethodAndPath(tt.args[idx].ctx, tt.args[idx].request.Method, tt.args[idx].path, gomock.Any()).
 					Return(tt.rulesServiceCall[idx].searchByMethodAndPathResult, tt.rulesServiceCall[idx].searchByMethodAndPathErr).
 					Times(tt.rulesServiceCall[idx].searchByMethodAndPathTimes)
 
-				srv, err := service.NewMockService(ruleServiceMock, service.NewWebhookService())
+				srv, err := service.NewMockService(ruleServiceMock, newWebhookService(t), newScenarioService(t),
+					newResourceService(t), newChaosService(t))
 				assert.Nil(t, err)
 
 				got, _, err := srv.SearchResponseForRequest(
-					tt.args[idx].ctx, tt.args[idx].request, tt.args[idx].path, tt.args[idx].body, nil)
+					tt.args[idx].ctx, tt.args[idx].request, tt.args[idx].path, tt.args[idx].body, "")
 				if tt.want[idx].err != nil {
 					assert.Equal(t, tt.want[idx].err, err)
 
@@ -1046,16 +1050,79 @@ func TestMockService_CompositeVariables(t *testing.T) {
 		},
 	}
 
-	ruleServiceMock.EXPECT().SearchByMethodAndPath(gomock.Any(), "POST", "/test").Return(rule, nil)
+	ruleServiceMock.EXPECT().SearchByMethodAndPath(gomock.Any(), "POST", "/test", gomock.Any()).Return(rule, nil)
 
-	srv, err := service.NewMockService(ruleServiceMock, service.NewWebhookService())
+	srv, err := service.NewMockService(ruleServiceMock, newWebhookService(t), newScenarioService(t),
+		newResourceService(t), newChaosService(t))
 	assert.Nil(t, err)
 
-	resp, _, err := srv.SearchResponseForRequest(context.Background(), req, "/test", `{"action": "BuscarTicket"}`, nil)
+	resp, _, err := srv.SearchResponseForRequest(context.Background(), req, "/test", `{"action": "BuscarTicket"}`, "")
 	assert.Nil(t, err)
 	assert.Equal(t, "VIP response", resp.Body)
 }
 
+func TestMockService_SequentialConcurrentRequests(t *testing.T) {
+	const (
+		responses = 3
+		requests  = 60
+	)
+
+	ctx := mockscontext.Background()
+	ruleService, _, srv := newResourceServices(t, newScenarioConfig(t))
+
+	rule := model.Rule{
+		Group: "payments", Name: "sequential", Path: "/sequential", Method: http.MethodGet,
+		Strategy: model.RuleStrategySequential, Status: model.RuleStatusEnabled,
+	}
+
+	for index := range responses {
+		rule.Responses = append(rule.Responses, model.Response{
+			Body: strconv.Itoa(index), ContentType: "text/plain", HTTPStatus: http.StatusOK,
+		})
+	}
+
+	rule, err := ruleService.Save(ctx, rule)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	var (
+		wg    sync.WaitGroup
+		mutex sync.Mutex
+	)
+
+	served := make(map[string]int)
+
+	for range requests {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			request := getMockRequest(http.MethodGet, "/sequential", "", nil, nil)
+
+			response, _, err := srv.SearchResponseForRequest(ctx, request, "/sequential", "", "")
+			if assert.Nil(t, err) {
+				mutex.Lock()
+				served[response.Body]++
+				mutex.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	// No turn is lost or served twice, so every response is served the same number of times.
+	for index := range responses {
+		assert.Equal(t, requests/responses, served[strconv.Itoa(index)], "response %d", index)
+	}
+
+	got, err := ruleService.Get(ctx, rule.Key)
+	if assert.Nil(t, err) {
+		assert.Equal(t, responses, got.NextResponseIndex)
+	}
+}
+
 func TestMockService_WildcardScenes(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -1104,7 +1171,8 @@ func TestMockService_WildcardScenes(t *testing.T) {
 		},
 	}
 
-	srv, err := service.NewMockService(ruleServiceMock, service.NewWebhookService())
+	srv, err := service.NewMockService(ruleServiceMock, newWebhookService(t), newScenarioService(t),
+		newResourceService(t), newChaosService(t))
 	assert.Nil(t, err)
 
 	tests := []struct {
@@ -1135,7 +1203,7 @@ func TestMockService_WildcardScenes(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.sceneInput, func(t *testing.T) {
-			ruleServiceMock.EXPECT().SearchByMethodAndPath(gomock.Any(), "POST", "/test").Return(rule, nil)
+			ruleServiceMock.EXPECT().SearchByMethodAndPath(gomock.Any(), "POST", "/test", gomock.Any()).Return(rule, nil)
 
 			req := getMockRequest(
 				http.MethodPost,
@@ -1145,9 +1213,223 @@ func TestMockService_WildcardScenes(t *testing.T) {
 				nil,
 			)
 
-			resp, _, err := srv.SearchResponseForRequest(context.Background(), req, "/test", fmt.Sprintf(`{"scene_name": "%s"}`, tt.sceneInput), nil)
+			resp, _, err := srv.SearchResponseForRequest(context.Background(), req, "/test", fmt.Sprintf(`{"scene_name": "%s"}`, tt.sceneInput), "")
 			assert.Nil(t, err)
 			assert.Equal(t, tt.expectedBody, resp.Body)
 		})
 	}
 }
+
+func TestMockService_ResponseHeadersAndCookies(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	ruleServiceMock := mocks.NewMockRuleService(mockCtrl)
+
+	rule := model.Rule{
+		Key:      "test_headers",
+		Path:     "/payments/{id}",
+		Strategy: model.RuleStrategyNormal,
+		Method:   "POST",
+		Status:   "enabled",
+		Variables: []*model.Variable{
+			{
+				Type: model.VariableTypePath,
+				Name: "payment_id",
+				Key:  "id",
+			},
+		},
+		Responses: []model.Response{
+			{
+				Body:        "{}",
+				ContentType: "application/json",
+				HTTPStatus:  http.StatusCreated,
+				Headers: map[string]string{
+					"Location":    "/payments/{payment_id}",
+					"Retry-After": "30",
+				},
+				Cookies: []model.Cookie{
+					{Name: "last_payment", Value: "{payment_id}", HTTPOnly: true},
+				},
+			},
+		},
+	}
+
+	ruleServiceMock.EXPECT().SearchByMethodAndPath(gomock.Any(), "POST", "/payments/123", gomock.Any()).Return(rule, nil)
+
+	srv, err := service.NewMockService(ruleServiceMock, newWebhookService(t), newScenarioService(t),
+		newResourceService(t), newChaosService(t))
+	assert.Nil(t, err)
+
+	req := getMockRequest(http.MethodPost, "url", "{}", nil, nil)
+
+	resp, _, err := srv.SearchResponseForRequest(context.Background(), req, "/payments/123", "{}", "")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"Location": "/payments/123", "Retry-After": "30"}, resp.Headers)
+	assert.Equal(t, []model.Cookie{{Name: "last_payment", Value: "123", HTTPOnly: true}}, resp.Cookies)
+
+	// The rule template must remain untouched.
+	assert.Equal(t, "/payments/{payment_id}", rule.Responses[0].Headers["Location"])
+}
+
+func TestMockService_GoTemplateResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		headers map[string]string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Should access path, query, headers and variables",
+			body: `{"id":"{{.Request.PathParams.id}}","country":"{{.Request.Query.country}}",` +
+				`"trace":"{{index .Request.Headers "X-Trace"}}","payer":"{{.Vars.payer}}"}`,
+			want: `{"id":"123","country":"AR","trace":"abc","payer":"999"}`,
+		},
+		{
+			name: "Should loop over the parsed body with conditionals and math",
+			body: `[{{range $i, $item := .Request.JSON.items}}{{if $i}},{{end}}` +
+				`{"sku":"{{$item.sku}}","total":{{mul $item.price $item.quantity}}}{{end}}]`,
+			want: `[{"sku":"A1","total":20},{"sku":"B2","total":7.5}]`,
+		},
+		{
+			name: "Should escape JSON and apply helpers",
+			body: `{"note":"{{jsonEscape .Request.JSON.note}}","items":{{json .Request.JSON.items}},` +
+				`"date":"{{formatDate "2006-01-02" (dateAdd "24h" "2024-02-28T10:00:00Z")}}","upper":"{{upper "ok"}}",` +
+				`"missing":"{{default "none" .Request.JSON.missing}}","count":{{len .Request.JSON.items}}}`,
+			want: `{"note":"say \"hi\"\n","items":[{"price":10,"quantity":2,"sku":"A1"},` +
+				`{"price":2.5,"quantity":3,"sku":"B2"}],"date":"2024-02-29","upper":"OK","missing":"none","count":2}`,
+		},
+		{
+			name: "Should print large numbers without an exponent",
+			body: `{{add 999999 1}} {{mul 123456789 1000}} {{sub "5000000" 1}} {{div 3000000 2}} {{div 7 2}} ` +
+				`{{mul 1000000 1.5}} {{add (mul 9000000000000000000 10) 1}} {{toInt (div 5000000 2)}}`,
+			want: `1000000 123456789000 4999999 1500000 3.5 1500000 90000000000000000000 2500000`,
+		},
+		{
+			name:    "Should render headers",
+			body:    `{}`,
+			headers: map[string]string{"Location": "/payments/{{.Request.PathParams.id}}"},
+			want:    `{}`,
+		},
+		{
+			name:    "Should fail when template execution fails",
+			body:    `{{div 1 0}}`,
+			wantErr: true,
+		},
+	}
+
+	requestBody := `{"note":"say \"hi\"\n","items":[{"sku":"A1","price":10,"quantity":2},` +
+		`{"sku":"B2","price":2.5,"quantity":3}]}`
+
+	for _, tt := range tests { //nolint:paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			ruleServiceMock := mocks.NewMockRuleService(mockCtrl)
+
+			rule := model.Rule{
+				Key:      "template",
+				Path:     "/payments/{id}",
+				Strategy: model.RuleStrategyNormal,
+				Method:   "POST",
+				Status:   "enabled",
+				Variables: []*model.Variable{
+					{Type: model.VariableTypeHeader, Name: "payer", Key: "X-Payer"},
+				},
+				Responses: []model.Response{
+					{
+						Body:        tt.body,
+						ContentType: "application/json",
+						HTTPStatus:  http.StatusOK,
+						Template:    model.ResponseTemplateGo,
+						Headers:     tt.headers,
+					},
+				},
+			}
+
+			ruleServiceMock.EXPECT().SearchByMethodAndPath(gomock.Any(), "POST", "/payments/123", gomock.Any()).
+				Return(rule, nil)
+
+			srv, err := service.NewMockService(ruleServiceMock, newWebhookService(t), newScenarioService(t),
+				newResourceService(t), newChaosService(t))
+			assert.Nil(t, err)
+
+			req := getMockRequest(http.MethodPost, "url", requestBody,
+				map[string][]string{"X-Trace": {"abc"}, "X-Payer": {"999"}}, map[string]string{"country": "AR"})
+
+			resp, _, err := srv.SearchResponseForRequest(context.Background(), req, "/payments/123", requestBody, "")
+			if tt.wantErr {
+				assert.NotNil(t, err)
+
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, resp.Body)
+
+			if tt.headers != nil {
+				assert.Equal(t, "/payments/123", resp.Headers["Location"])
+			}
+		})
+	}
+}
+
+func TestMockService_GoTemplateWebhook(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	ruleServiceMock := mocks.NewMockRuleService(mockCtrl)
+	webhookServiceMock := mocks.NewMockWebhookService(mockCtrl)
+
+	rule := model.Rule{
+		Key:      "template",
+		Path:     "/payments/{id}",
+		Strategy: model.RuleStrategyNormal,
+		Method:   "POST",
+		Status:   "enabled",
+		Responses: []model.Response{
+			{
+				Body:        `{}`,
+				ContentType: "application/json",
+				HTTPStatus:  http.StatusOK,
+				Template:    model.ResponseTemplateGo,
+				Webhook: &model.WebhookConfig{
+					URL:     "http://hooks.local/{{.Request.PathParams.id}}",
+					Method:  http.MethodPost,
+					Headers: map[string]string{"X-Id": "{{.Request.PathParams.id}}"},
+					Body:    `{"id":"{{.Request.PathParams.id}}"}`,
+					Enabled: true,
+				},
+				Webhooks: []model.WebhookConfig{
+					{URL: "http://hooks.local/disabled", Method: http.MethodPost},
+					{URL: "http://hooks.local/{{.Request.PathParams.id}}/approved", Method: http.MethodPost, Enabled: true},
+				},
+				WebhookMode: model.WebhookModeParallel,
+			},
+		},
+	}
+
+	ruleServiceMock.EXPECT().SearchByMethodAndPath(gomock.Any(), "POST", "/payments/123", gomock.Any()).
+		Return(rule, nil)
+	webhookServiceMock.EXPECT().Fire(gomock.Any(), "01", []model.WebhookConfig{
+		{
+			URL:     "http://hooks.local/123",
+			Method:  http.MethodPost,
+			Headers: map[string]string{"X-Id": "123"},
+			Body:    `{"id":"123"}`,
+			Enabled: true,
+		},
+		{URL: "http://hooks.local/123/approved", Method: http.MethodPost, Enabled: true},
+	}, model.WebhookModeParallel, gomock.Nil())
+
+	srv, err := service.NewMockService(ruleServiceMock, webhookServiceMock, newScenarioService(t),
+		newResourceService(t), newChaosService(t))
+	assert.Nil(t, err)
+
+	req := getMockRequest(http.MethodPost, "url", "{}", nil, nil)
+
+	_, _, err = srv.SearchResponseForRequest(context.Background(), req, "/payments/123", "{}", "01")
+	assert.Nil(t, err)
+}
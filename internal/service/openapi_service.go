@@ -0,0 +1,595 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	mockscontext "github.com/nicopozo/mockserver/internal/context"
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
+	"github.com/nicopozo/mockserver/internal/model"
+	yamlutils "github.com/nicopozo/mockserver/internal/utils/yaml"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// openAPISceneHeader selects, on rules with more than one documented status code, the response to return.
+	openAPISceneHeader = "X-Mock-Scene"
+
+	// maxSchemaDepth bounds $ref inlining and example generation for recursive schemas.
+	maxSchemaDepth = 8
+
+	defaultImportGroup = "openapi"
+)
+
+//nolint:gochecknoglobals
+var openAPIMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+//go:generate mockgen -destination=../utils/test/mocks/openapi_service_mock.go -package=mocks -source=./openapi_service.go
+
+// OpenAPIService turns OpenAPI 3 and Swagger 2 specifications into rules.
+type OpenAPIService interface {
+	Import(ctx context.Context, spec []byte, group string) (model.ImportReport, error)
+}
+
+type openAPIService struct {
+	RuleService RuleService
+}
+
+func NewOpenAPIService(ruleService RuleService) (OpenAPIService, error) {
+	if ruleService == nil {
+		return nil, fmt.Errorf("rule service cannot be nil") //nolint:err113
+	}
+
+	return &openAPIService{
+		RuleService: ruleService,
+	}, nil
+}
+
+// openAPIDocument is a parsed specification, kept as generic maps so that both OpenAPI 3 and
+// Swagger 2 documents can be walked with the same helpers.
+type openAPIDocument struct {
+	root     map[string]interface{}
+	swagger2 bool
+	basePath string
+}
+
+// Import creates one rule per operation of spec. Operations that already have a rule with the same
+// group, method and path update it instead.
+func (svc *openAPIService) Import(ctx context.Context, spec []byte, group string) (model.ImportReport, error) {
+	logger := mockscontext.Logger(ctx)
+
+	logger.Debug(svc, nil, "Entering openAPIService Import()")
+
+	doc, err := parseOpenAPIDocument(spec)
+	if err != nil {
+		return model.ImportReport{}, err
+	}
+
+	if group == "" {
+		group = doc.title()
+	}
+
+	report := model.ImportReport{
+		Created: make([]model.ImportedRule, 0),
+		Updated: make([]model.ImportedRule, 0),
+		Skipped: make([]model.SkippedOperation, 0),
+	}
+
+	paths := asMap(doc.root["paths"])
+
+	for _, specPath := range sortedKeys(paths) {
+		pathItem := asMap(doc.resolve(paths[specPath], 0))
+
+		for _, method := range openAPIMethods {
+			operation, ok := pathItem[method]
+			if !ok {
+				continue
+			}
+
+			path := doc.basePath + specPath
+			upperMethod := strings.ToUpper(method)
+
+			rule, err := doc.buildRule(group, upperMethod, path, asMap(doc.resolve(operation, 0)))
+			if err == nil {
+				err = svc.saveRule(ctx, &rule, &report)
+			}
+
+			if err != nil {
+				logger.Error(svc, nil, err, "Skipping operation %s %s", upperMethod, path)
+
+				report.Skipped = append(report.Skipped, model.SkippedOperation{
+					Method: upperMethod,
+					Path:   path,
+					Reason: err.Error(),
+				})
+			}
+		}
+	}
+
+	return report, nil
+}
+
+func (svc *openAPIService) saveRule(ctx context.Context, rule *model.Rule, report *model.ImportReport) error {
+	existing, err := svc.RuleService.Search(ctx, map[string]interface{}{
+		"group":  rule.Group,
+		"method": rule.Method,
+		"path":   rule.Path,
+	}, model.Paging{Limit: 100}) //nolint:mnd
+	if err != nil {
+		return err
+	}
+
+	for _, candidate := range existing.Results {
+		if candidate.Group != rule.Group || candidate.Method != rule.Method || candidate.Path != rule.Path {
+			continue
+		}
+
+		updated, err := svc.RuleService.Update(ctx, candidate.Key, *rule)
+		if err != nil {
+			return err
+		}
+
+		report.Updated = append(report.Updated, importedRule(updated))
+
+		return nil
+	}
+
+	created, err := svc.RuleService.Save(ctx, *rule)
+	if err != nil {
+		return err
+	}
+
+	report.Created = append(report.Created, importedRule(created))
+
+	return nil
+}
+
+func importedRule(rule model.Rule) model.ImportedRule {
+	return model.ImportedRule{Key: rule.Key, Name: rule.Name, Method: rule.Method, Path: rule.Path}
+}
+
+func parseOpenAPIDocument(spec []byte) (*openAPIDocument, error) {
+	var raw interface{}
+	if err := yaml.Unmarshal(spec, &raw); err != nil {
+		return nil, mockserrors.InvalidRulesError{Message: fmt.Sprintf("specification is not valid YAML or JSON: %s", err)}
+	}
+
+	doc := &openAPIDocument{root: asMap(yamlutils.Normalize(raw))}
+
+	version := fmt.Sprint(doc.root["openapi"])
+
+	switch {
+	case strings.HasPrefix(version, "3."):
+		doc.basePath = serverBasePath(doc.root)
+	case fmt.Sprint(doc.root["swagger"]) == "2.0":
+		doc.swagger2 = true
+		doc.basePath = strings.TrimSuffix(asString(doc.root["basePath"]), "/")
+	default:
+		return nil, mockserrors.InvalidRulesError{
+			Message: "unsupported specification, only OpenAPI 3 and Swagger 2.0 are supported",
+		}
+	}
+
+	if len(asMap(doc.root["paths"])) == 0 {
+		return nil, mockserrors.InvalidRulesError{Message: "specification does not have any paths"}
+	}
+
+	return doc, nil
+}
+
+func serverBasePath(root map[string]interface{}) string {
+	servers, _ := root["servers"].([]interface{})
+	if len(servers) == 0 {
+		return ""
+	}
+
+	serverURL, err := url.Parse(asString(asMap(servers[0])["url"]))
+	if err != nil || strings.Contains(serverURL.Path, "{") {
+		return ""
+	}
+
+	return strings.TrimSuffix(serverURL.Path, "/")
+}
+
+func (doc *openAPIDocument) title() string {
+	if title := asString(asMap(doc.root["info"])["title"]); title != "" {
+		return title
+	}
+
+	return defaultImportGroup
+}
+
+func (doc *openAPIDocument) buildRule(group, method, path string, operation map[string]interface{}) (model.Rule, error) {
+	name := asString(operation["operationId"])
+	if name == "" {
+		name = asString(operation["summary"])
+	}
+
+	if name == "" {
+		name = method + " " + path
+	}
+
+	responses, err := doc.buildResponses(operation)
+	if err != nil {
+		return model.Rule{}, err
+	}
+
+	rule := model.Rule{
+		Group:     group,
+		Name:      name,
+		Path:      path,
+		Strategy:  model.RuleStrategyNormal,
+		Method:    method,
+		Status:    model.RuleStatusEnabled,
+		Responses: responses,
+		Variables: make([]*model.Variable, 0),
+	}
+
+	if len(responses) > 1 {
+		rule.Strategy = model.RuleStrategyScene
+		rule.Variables = append(rule.Variables, &model.Variable{
+			Type: model.VariableTypeHeader,
+			Name: model.RuleStrategyScene,
+			Key:  openAPISceneHeader,
+		})
+	}
+
+	if schema := doc.requestSchema(operation); schema != nil {
+		rule.Variables = append(rule.Variables, &model.Variable{
+			Type: model.VariableTypeBody,
+			Name: "request_body",
+			Key:  "$",
+			Assertions: []*model.Assertion{
+				{Type: model.AssertionTypeJSONSchema, Value: mustMarshal(schema), FailOnError: true},
+			},
+		})
+	}
+
+	return rule, nil
+}
+
+// buildResponses returns one response per documented numeric status code. The lowest 2xx code comes
+// first and is the "default" scene; every other response uses its status code as scene.
+func (doc *openAPIDocument) buildResponses(operation map[string]interface{}) ([]model.Response, error) {
+	documented := asMap(operation["responses"])
+	codes := make([]int, 0, len(documented))
+
+	for code := range documented {
+		status, err := strconv.Atoi(code)
+		if err == nil && status >= http.StatusOK && status <= 599 {
+			codes = append(codes, status)
+		}
+	}
+
+	if len(codes) == 0 {
+		return nil, mockserrors.InvalidRulesError{Message: "no documented status codes"}
+	}
+
+	sort.SliceStable(codes, func(i, j int) bool {
+		iSuccess, jSuccess := codes[i] < 300, codes[j] < 300
+		if iSuccess != jSuccess {
+			return iSuccess
+		}
+
+		return codes[i] < codes[j]
+	})
+
+	responses := make([]model.Response, 0, len(codes))
+
+	for index, code := range codes {
+		response := doc.buildResponse(code, asMap(doc.resolve(documented[strconv.Itoa(code)], 0)), operation)
+
+		if len(codes) > 1 {
+			response.Scene = strconv.Itoa(code)
+			if index == 0 {
+				response.Scene = "default"
+			}
+		}
+
+		responses = append(responses, response)
+	}
+
+	return responses, nil
+}
+
+func (doc *openAPIDocument) buildResponse(code int, documented, operation map[string]interface{}) model.Response {
+	response := model.Response{
+		HTTPStatus:  code,
+		ContentType: "application/json",
+		Description: asString(documented["description"]),
+	}
+
+	var (
+		example interface{}
+		found   bool
+	)
+
+	if doc.swagger2 {
+		response.ContentType = doc.swagger2ContentType(operation)
+
+		if examples := asMap(documented["examples"]); len(examples) > 0 {
+			contentType := preferredContentType(examples)
+			response.ContentType = contentType
+			example, found = examples[contentType], true
+		} else if schema, ok := documented["schema"]; ok {
+			example, found = doc.exampleFromSchema(schema, 0), true
+		}
+	} else if content := asMap(documented["content"]); len(content) > 0 {
+		response.ContentType = preferredContentType(content)
+		example, found = doc.exampleFromMedia(asMap(content[response.ContentType]))
+	}
+
+	if found {
+		response.Body = formatExample(example, response.ContentType)
+	}
+
+	return response
+}
+
+func (doc *openAPIDocument) exampleFromMedia(media map[string]interface{}) (interface{}, bool) {
+	if example, ok := media["example"]; ok {
+		return example, true
+	}
+
+	if examples := asMap(media["examples"]); len(examples) > 0 {
+		first := asMap(doc.resolve(examples[sortedKeys(examples)[0]], 0))
+		if value, ok := first["value"]; ok {
+			return value, true
+		}
+	}
+
+	if schema, ok := media["schema"]; ok {
+		return doc.exampleFromSchema(schema, 0), true
+	}
+
+	return nil, false
+}
+
+func (doc *openAPIDocument) swagger2ContentType(operation map[string]interface{}) string {
+	produces, _ := operation["produces"].([]interface{})
+	if len(produces) == 0 {
+		produces, _ = doc.root["produces"].([]interface{})
+	}
+
+	for _, contentType := range produces {
+		if strings.Contains(asString(contentType), "json") {
+			return asString(contentType)
+		}
+	}
+
+	if len(produces) > 0 {
+		return asString(produces[0])
+	}
+
+	return "application/json"
+}
+
+// requestSchema returns the fully inlined JSON request body schema of operation, if any.
+func (doc *openAPIDocument) requestSchema(operation map[string]interface{}) interface{} {
+	var schema interface{}
+
+	if doc.swagger2 {
+		parameters, _ := operation["parameters"].([]interface{})
+		for _, parameter := range parameters {
+			resolved := asMap(doc.resolve(parameter, 0))
+			if asString(resolved["in"]) == "body" {
+				schema = resolved["schema"]
+			}
+		}
+	} else {
+		content := asMap(asMap(doc.resolve(operation["requestBody"], 0))["content"])
+		for _, contentType := range sortedKeys(content) {
+			if strings.Contains(contentType, "json") {
+				schema = asMap(content[contentType])["schema"]
+
+				break
+			}
+		}
+	}
+
+	if schema == nil {
+		return nil
+	}
+
+	return doc.inline(schema, 0)
+}
+
+// resolve follows local "$ref" pointers until a value without reference is found.
+func (doc *openAPIDocument) resolve(value interface{}, depth int) interface{} {
+	ref := asString(asMap(value)["$ref"])
+	if ref == "" || depth > maxSchemaDepth || !strings.HasPrefix(ref, "#/") {
+		return value
+	}
+
+	var current interface{} = doc.root
+
+	for _, token := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		current = asMap(current)[token]
+	}
+
+	return doc.resolve(current, depth+1)
+}
+
+// inline returns a copy of schema with every local reference replaced by its target, so that it can
+// be used on its own as a json_schema assertion.
+func (doc *openAPIDocument) inline(schema interface{}, depth int) interface{} {
+	if depth > maxSchemaDepth {
+		return map[string]interface{}{}
+	}
+
+	switch typed := doc.resolve(schema, 0).(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(typed))
+
+		for key, value := range typed {
+			switch key {
+			case "example", "examples", "xml", "discriminator", "externalDocs", "nullable", "readOnly", "writeOnly":
+				continue
+			}
+
+			result[key] = doc.inline(value, depth+1)
+		}
+
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(typed))
+		for index, value := range typed {
+			result[index] = doc.inline(value, depth+1)
+		}
+
+		return result
+	default:
+		return typed
+	}
+}
+
+// exampleFromSchema builds sample data from a schema, preferring the examples it documents.
+//
+//nolint:cyclop
+func (doc *openAPIDocument) exampleFromSchema(value interface{}, depth int) interface{} {
+	schema := asMap(doc.resolve(value, 0))
+
+	if example, ok := schema["example"]; ok {
+		return example
+	}
+
+	if defaultValue, ok := schema["default"]; ok {
+		return defaultValue
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok && len(enum) > 0 {
+		return enum[0]
+	}
+
+	if depth > maxSchemaDepth {
+		return nil
+	}
+
+	for _, composition := range []string{"oneOf", "anyOf"} {
+		if options, ok := schema[composition].([]interface{}); ok && len(options) > 0 {
+			return doc.exampleFromSchema(options[0], depth+1)
+		}
+	}
+
+	if parts, ok := schema["allOf"].([]interface{}); ok {
+		merged := make(map[string]interface{})
+
+		for _, part := range parts {
+			for key, item := range asMap(doc.exampleFromSchema(part, depth+1)) {
+				merged[key] = item
+			}
+		}
+
+		return merged
+	}
+
+	switch asString(schema["type"]) {
+	case "array":
+		return []interface{}{doc.exampleFromSchema(schema["items"], depth+1)}
+	case "string":
+		return stringExample(asString(schema["format"]))
+	case "integer":
+		if minimum, ok := schema["minimum"]; ok {
+			return minimum
+		}
+
+		return 0
+	case "number":
+		if minimum, ok := schema["minimum"]; ok {
+			return minimum
+		}
+
+		return 0.0
+	case "boolean":
+		return true
+	}
+
+	properties := asMap(schema["properties"])
+	if len(properties) == 0 && asString(schema["type"]) != "object" {
+		return nil
+	}
+
+	result := make(map[string]interface{}, len(properties))
+	for name, property := range properties {
+		result[name] = doc.exampleFromSchema(property, depth+1)
+	}
+
+	return result
+}
+
+func stringExample(format string) string {
+	switch format {
+	case "date-time":
+		return "2024-01-01T00:00:00Z"
+	case "date":
+		return "2024-01-01"
+	case "uuid":
+		return "3fa85f64-5717-4562-b3fc-2c963f66afa6"
+	case "email":
+		return "user@example.com"
+	case "uri", "url":
+		return "https://example.com"
+	}
+
+	return "string"
+}
+
+// preferredContentType picks the JSON content type when there is one, or the first one otherwise.
+func preferredContentType(content map[string]interface{}) string {
+	keys := sortedKeys(content)
+
+	for _, key := range keys {
+		if strings.Contains(key, "json") {
+			return key
+		}
+	}
+
+	return keys[0]
+}
+
+func formatExample(example interface{}, contentType string) string {
+	if text, ok := example.(string); ok && !strings.Contains(contentType, "json") {
+		return text
+	}
+
+	return mustMarshal(example)
+}
+
+func mustMarshal(value interface{}) string {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+
+	return string(content)
+}
+
+func asMap(value interface{}) map[string]interface{} {
+	result, _ := value.(map[string]interface{})
+
+	return result
+}
+
+func asString(value interface{}) string {
+	result, _ := value.(string)
+
+	return result
+}
+
+func sortedKeys(values map[string]interface{}) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
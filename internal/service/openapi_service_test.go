@@ -0,0 +1,252 @@
+package service_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/nicopozo/mockserver/internal/configs"
+	mockscontext "github.com/nicopozo/mockserver/internal/context"
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
+	"github.com/nicopozo/mockserver/internal/model"
+	"github.com/nicopozo/mockserver/internal/repository"
+	"github.com/nicopozo/mockserver/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+const openAPI3Spec = `
+openapi: 3.0.3
+info:
+  title: payments
+  version: 1.0.0
+servers:
+  - url: https://api.example.com/v1
+paths:
+  /payments/{payment_id}:
+    get:
+      operationId: getPayment
+      responses:
+        200:
+          description: the payment
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Payment'
+        404:
+          description: not found
+          content:
+            application/json:
+              example:
+                message: payment not found
+  /payments:
+    post:
+      summary: create payment
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/NewPayment'
+      responses:
+        '201':
+          description: created
+          content:
+            application/json:
+              examples:
+                card:
+                  value: {id: 10, status: approved}
+    delete:
+      responses:
+        default:
+          description: unexpected error
+components:
+  schemas:
+    Payment:
+      type: object
+      properties:
+        id:
+          type: integer
+          minimum: 1
+        created:
+          type: string
+          format: date-time
+        status:
+          type: string
+          enum: [approved, rejected]
+        items:
+          type: array
+          items:
+            type: string
+    NewPayment:
+      type: object
+      required: [amount]
+      properties:
+        amount:
+          type: number
+`
+
+const swagger2Spec = `{
+  "swagger": "2.0",
+  "info": {"title": "users", "version": "1"},
+  "basePath": "/api",
+  "produces": ["application/json"],
+  "paths": {
+    "/users/{id}": {
+      "put": {
+        "operationId": "updateUser",
+        "parameters": [
+          {"in": "path", "name": "id", "type": "string", "required": true},
+          {"in": "body", "name": "body", "schema": {"$ref": "#/definitions/User"}}
+        ],
+        "responses": {
+          "200": {"description": "ok", "examples": {"application/json": {"name": "john"}}},
+          "400": {"description": "invalid", "schema": {"$ref": "#/definitions/Error"}}
+        }
+      }
+    }
+  },
+  "definitions": {
+    "User": {"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}}},
+    "Error": {"type": "object", "properties": {"message": {"type": "string", "example": "invalid user"}}}
+  }
+}`
+
+func newFileRuleService(t *testing.T) service.RuleService {
+	t.Helper()
+
+	dir := t.TempDir()
+	cfg := &configs.Config{
+		MocksFile:     filepath.Join(dir, "mocks.json"),
+		RevisionsFile: filepath.Join(dir, "revisions.json"),
+	}
+
+	ruleRepository, err := repository.NewRuleFileRepository(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	revisionRepository, err := repository.NewRuleRevisionFileRepository(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ruleService, err := service.NewRuleService(ruleRepository, revisionRepository)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return ruleService
+}
+
+func TestOpenAPIService_ImportOpenAPI3(t *testing.T) {
+	ruleService := newFileRuleService(t)
+
+	srv, err := service.NewOpenAPIService(ruleService)
+	assert.Nil(t, err)
+
+	report, err := srv.Import(mockscontext.Background(), []byte(openAPI3Spec), "")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	assert.Len(t, report.Created, 2)
+	assert.Empty(t, report.Updated)
+	assert.Equal(t, []model.SkippedOperation{
+		{Method: "DELETE", Path: "/v1/payments", Reason: "no documented status codes"},
+	}, report.Skipped)
+
+	getRule, err := ruleService.Get(mockscontext.Background(), report.Created[1].Key)
+	assert.Nil(t, err)
+	assert.Equal(t, "payments", getRule.Group)
+	assert.Equal(t, "getPayment", getRule.Name)
+	assert.Equal(t, "/v1/payments/{payment_id}", getRule.Path)
+	assert.Equal(t, model.RuleStrategyScene, getRule.Strategy)
+
+	if assert.Len(t, getRule.Responses, 2) {
+		assert.Equal(t, "default", getRule.Responses[0].Scene)
+		assert.Equal(t, 200, getRule.Responses[0].HTTPStatus)
+		assert.JSONEq(t, `{"id":1,"created":"2024-01-01T00:00:00Z","status":"approved","items":["string"]}`,
+			getRule.Responses[0].Body)
+		assert.Equal(t, "404", getRule.Responses[1].Scene)
+		assert.JSONEq(t, `{"message":"payment not found"}`, getRule.Responses[1].Body)
+	}
+
+	postRule, err := ruleService.Get(mockscontext.Background(), report.Created[0].Key)
+	assert.Nil(t, err)
+	assert.Equal(t, "create payment", postRule.Name)
+	assert.Equal(t, model.RuleStrategyNormal, postRule.Strategy)
+	assert.JSONEq(t, `{"id":10,"status":"approved"}`, postRule.Responses[0].Body)
+
+	if assert.Len(t, postRule.Variables, 1) && assert.Len(t, postRule.Variables[0].Assertions, 1) {
+		assert.Equal(t, "$", postRule.Variables[0].Key)
+		assert.Equal(t, model.AssertionTypeJSONSchema, postRule.Variables[0].Assertions[0].Type)
+		assert.JSONEq(t, `{"type":"object","required":["amount"],"properties":{"amount":{"type":"number"}}}`,
+			postRule.Variables[0].Assertions[0].Value)
+	}
+
+	// Importing the same specification again updates the rules instead of duplicating them.
+	report, err = srv.Import(mockscontext.Background(), []byte(openAPI3Spec), "")
+	assert.Nil(t, err)
+	assert.Empty(t, report.Created)
+	assert.Len(t, report.Updated, 2)
+}
+
+func TestOpenAPIService_ImportSwagger2(t *testing.T) {
+	ruleService := newFileRuleService(t)
+
+	srv, err := service.NewOpenAPIService(ruleService)
+	assert.Nil(t, err)
+
+	report, err := srv.Import(mockscontext.Background(), []byte(swagger2Spec), "legacy")
+	if !assert.Nil(t, err) || !assert.Len(t, report.Created, 1) {
+		return
+	}
+
+	assert.Equal(t, model.ImportedRule{
+		Key: report.Created[0].Key, Name: "updateUser", Method: "PUT", Path: "/api/users/{id}",
+	}, report.Created[0])
+
+	rule, err := ruleService.Get(mockscontext.Background(), report.Created[0].Key)
+	assert.Nil(t, err)
+	assert.Equal(t, "legacy", rule.Group)
+
+	if assert.Len(t, rule.Responses, 2) {
+		assert.JSONEq(t, `{"name":"john"}`, rule.Responses[0].Body)
+		assert.JSONEq(t, `{"message":"invalid user"}`, rule.Responses[1].Body)
+	}
+
+	if assert.Len(t, rule.Variables, 2) {
+		assert.Equal(t, model.VariableTypeHeader, rule.Variables[0].Type)
+		assert.JSONEq(t, `{"type":"object","required":["name"],"properties":{"name":{"type":"string"}}}`,
+			rule.Variables[1].Assertions[0].Value)
+	}
+}
+
+func TestOpenAPIService_ImportInvalidSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		wantErr string
+	}{
+		{name: "Should fail with invalid YAML", spec: "openapi: [", wantErr: "specification is not valid YAML or JSON"},
+		{
+			name:    "Should fail with unsupported version",
+			spec:    `{"swagger": "1.2", "paths": {}}`,
+			wantErr: "unsupported specification, only OpenAPI 3 and Swagger 2.0 are supported",
+		},
+		{
+			name:    "Should fail without paths",
+			spec:    "openapi: 3.1.0\ninfo: {title: empty}",
+			wantErr: "specification does not have any paths",
+		},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			srv, err := service.NewOpenAPIService(newFileRuleService(t))
+			assert.Nil(t, err)
+
+			_, err = srv.Import(mockscontext.Background(), []byte(tt.spec), "")
+			assert.ErrorAs(t, err, &mockserrors.InvalidRulesError{})
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nicopozo/mockserver/internal/configs"
+	mockscontext "github.com/nicopozo/mockserver/internal/context"
+	"github.com/nicopozo/mockserver/internal/model"
+	httputils "github.com/nicopozo/mockserver/internal/utils/http"
+)
+
+// defaultRecordGroup is the group of rules recorded from the global proxy target.
+const defaultRecordGroup = "recorded"
+
+//go:generate mockgen -destination=../utils/test/mocks/proxy_service_mock.go -package=mocks -source=./proxy_service.go
+
+// ProxyService forwards requests that no rule matched to a real upstream and optionally records the
+// upstream responses as new rules.
+type ProxyService interface {
+	// Forward proxies the request to the upstream configured for path and writes the upstream response
+	// to writer. It returns false, without writing anything, when there is no upstream for path.
+	Forward(ctx context.Context, writer http.ResponseWriter, request *http.Request, path, body string) (ProxyResult, bool)
+}
+
+// ProxyResult describes the response returned to the client by a proxied request.
+type ProxyResult struct {
+	Target       string
+	StatusCode   int
+	Body         string
+	RecordedRule string
+}
+
+type proxyTarget struct {
+	group  string
+	prefix string
+	url    *url.URL
+}
+
+type proxyService struct {
+	targets     []proxyTarget
+	record      bool
+	ruleService RuleService
+}
+
+//nolint:gochecknoglobals
+var (
+	pathParamSegment = regexp.MustCompile(
+		`^(\d+|[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}|[0-9A-HJKMNP-TV-Z]{26}|[0-9a-fA-F]{24,})$`)
+
+	// recordedHeaderExclusions lists upstream headers that are not copied into recorded rules.
+	recordedHeaderExclusions = map[string]bool{
+		"Connection": true, "Content-Encoding": true, "Content-Length": true, "Content-Type": true, "Date": true,
+		"Keep-Alive": true, "Proxy-Authenticate": true, "Set-Cookie": true, "Trailer": true, "Transfer-Encoding": true,
+		"Upgrade": true,
+	}
+)
+
+// NewProxyService builds the proxy targets from the configuration. Group targets are matched by the
+// longest path prefix and take precedence over the global target.
+func NewProxyService(cfg *configs.Config, ruleService RuleService) (ProxyService, error) {
+	if ruleService == nil {
+		return nil, fmt.Errorf("rule service cannot be nil") //nolint:err113
+	}
+
+	svc := &proxyService{
+		record:      cfg.Proxy.Record,
+		ruleService: ruleService,
+	}
+
+	for _, entry := range strings.Split(cfg.Proxy.Groups, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		group, rest, okGroup := strings.Cut(entry, ":")
+		prefix, target, okTarget := strings.Cut(rest, "=")
+
+		if !okGroup || !okTarget || group == "" || !strings.HasPrefix(prefix, "/") {
+			return nil, fmt.Errorf("invalid proxy group '%s', expected group:/path/prefix=http://upstream", entry) //nolint:err113
+		}
+
+		if err := svc.addTarget(group, strings.TrimSuffix(prefix, "/"), target); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.Proxy.Target != "" {
+		if err := svc.addTarget(defaultRecordGroup, "", cfg.Proxy.Target); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.SliceStable(svc.targets, func(i, j int) bool {
+		return len(svc.targets[i].prefix) > len(svc.targets[j].prefix)
+	})
+
+	return svc, nil
+}
+
+func (svc *proxyService) addTarget(group, prefix, target string) error {
+	targetURL, err := url.Parse(target)
+	if err != nil || targetURL.Scheme == "" || targetURL.Host == "" {
+		return fmt.Errorf("invalid proxy target '%s'", target) //nolint:err113
+	}
+
+	svc.targets = append(svc.targets, proxyTarget{group: group, prefix: prefix, url: targetURL})
+
+	return nil
+}
+
+func (svc *proxyService) Forward(ctx context.Context, writer http.ResponseWriter, request *http.Request,
+	path, body string,
+) (ProxyResult, bool) {
+	logger := mockscontext.Logger(ctx)
+
+	target, ok := svc.findTarget(path)
+	if !ok {
+		return ProxyResult{}, false
+	}
+
+	logger.Debug(svc, map[string]string{"target": target.url.String(), "path": path}, "Proxying unmatched request")
+
+	result := ProxyResult{Target: target.url.String()}
+
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(proxyRequest *httputil.ProxyRequest) {
+			proxyRequest.SetURL(target.url)
+			proxyRequest.SetXForwarded()
+
+			proxyRequest.Out.URL.Path = strings.TrimSuffix(target.url.Path, "/") + path
+			proxyRequest.Out.URL.RawPath = ""
+			proxyRequest.Out.Body = io.NopCloser(strings.NewReader(body))
+			proxyRequest.Out.ContentLength = int64(len(body))
+
+			if svc.record {
+				// Ask for an uncompressed body so that it can be stored in the recorded rule.
+				proxyRequest.Out.Header.Del("Accept-Encoding")
+			}
+		},
+		ModifyResponse: func(response *http.Response) error {
+			responseBody, err := io.ReadAll(response.Body)
+			_ = response.Body.Close()
+
+			if err != nil {
+				return fmt.Errorf("error reading upstream response, %w", err)
+			}
+
+			response.Body = io.NopCloser(bytes.NewReader(responseBody))
+			result.StatusCode = response.StatusCode
+			result.Body = string(responseBody)
+
+			if svc.record {
+				result.RecordedRule = svc.recordRule(ctx, target, request.Method, path, response, result.Body)
+			}
+
+			return nil
+		},
+		ErrorHandler: func(writer http.ResponseWriter, _ *http.Request, err error) {
+			logger.Error(svc, nil, err, "error proxying request to %s", target.url.String())
+
+			errorResult := model.NewError(model.ServiceUnavailableError, "Error proxying request to %s. %s",
+				target.url.String(), err.Error())
+
+			httputils.WriteJSON(writer, errorResult.Status, errorResult)
+
+			result.StatusCode = errorResult.Status
+			result.Body = errorResult.Message
+		},
+	}
+
+	proxy.ServeHTTP(writer, request)
+
+	return result, true
+}
+
+func (svc *proxyService) findTarget(path string) (proxyTarget, bool) {
+	for _, target := range svc.targets {
+		if target.prefix == "" || path == target.prefix || strings.HasPrefix(path, target.prefix+"/") {
+			return target, true
+		}
+	}
+
+	return proxyTarget{}, false
+}
+
+// recordRule saves the upstream response as a new rule and returns its key, or an empty string when
+// it could not be saved.
+func (svc *proxyService) recordRule(ctx context.Context, target proxyTarget, method, path string,
+	response *http.Response, body string,
+) string {
+	logger := mockscontext.Logger(ctx)
+
+	headers := make(map[string]string)
+
+	for name, values := range response.Header {
+		if !recordedHeaderExclusions[name] && len(values) > 0 {
+			headers[name] = values[0]
+		}
+	}
+
+	rule := model.Rule{
+		Group:    target.group,
+		Name:     fmt.Sprintf("%s %s", strings.ToUpper(method), path),
+		Path:     templatePath(path),
+		Strategy: model.RuleStrategyNormal,
+		Method:   method,
+		Status:   model.RuleStatusEnabled,
+		Responses: []model.Response{
+			{
+				Body:        body,
+				ContentType: response.Header.Get("Content-Type"),
+				HTTPStatus:  response.StatusCode,
+				Description: "recorded from " + target.url.String(),
+			},
+		},
+	}
+
+	if len(headers) > 0 {
+		rule.Responses[0].Headers = headers
+	}
+
+	saved, err := svc.ruleService.Save(ctx, rule)
+	if err != nil {
+		logger.Error(svc, nil, err, "error recording proxied response for %s %s", method, path)
+
+		return ""
+	}
+
+	return saved.Key
+}
+
+// templatePath replaces path segments that look like identifiers (numbers, UUIDs, ULIDs or long hex
+// strings) with placeholders, so that the recorded rule matches any value for them.
+func templatePath(path string) string {
+	segments := strings.Split(path, "/")
+	params := 0
+
+	for index, segment := range segments {
+		if pathParamSegment.MatchString(segment) {
+			params++
+			segments[index] = fmt.Sprintf("{param%d}", params)
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
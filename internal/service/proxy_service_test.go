@@ -0,0 +1,187 @@
+package service_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nicopozo/mockserver/internal/configs"
+	mockscontext "github.com/nicopozo/mockserver/internal/context"
+	"github.com/nicopozo/mockserver/internal/model"
+	"github.com/nicopozo/mockserver/internal/service"
+	"github.com/nicopozo/mockserver/internal/utils/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func newUpstream(t *testing.T, name string) *httptest.Server {
+	t.Helper()
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, _ := io.ReadAll(request.Body)
+
+		writer.Header().Set("Content-Type", "application/json")
+		writer.Header().Set("X-Upstream", name)
+		writer.WriteHeader(http.StatusCreated)
+		_, _ = writer.Write([]byte(`{"upstream":"` + name + `","path":"` + request.URL.Path + `","query":"` +
+			request.URL.RawQuery + `","body":"` + string(body) + `"}`))
+	}))
+
+	t.Cleanup(upstream.Close)
+
+	return upstream
+}
+
+func TestProxyService_Forward(t *testing.T) {
+	global := newUpstream(t, "global")
+	payments := newUpstream(t, "payments")
+
+	tests := []struct {
+		name          string
+		cfg           configs.ProxyConfig
+		path          string
+		wantForwarded bool
+		wantStatus    int
+		wantBody      string
+	}{
+		{
+			name:          "Should not forward when there is no target",
+			path:          "/v1/users",
+			wantForwarded: false,
+		},
+		{
+			name:          "Should forward to the global target",
+			cfg:           configs.ProxyConfig{Target: global.URL + "/api"},
+			path:          "/v1/users",
+			wantForwarded: true,
+			wantStatus:    http.StatusCreated,
+			wantBody:      `{"upstream":"global","path":"/api/v1/users","query":"page=2","body":"{}"}`,
+		},
+		{
+			name: "Should forward to the group target matching the path prefix",
+			cfg: configs.ProxyConfig{
+				Target: global.URL,
+				Groups: "payments:/v1/payments=" + payments.URL,
+			},
+			path:          "/v1/payments/10",
+			wantForwarded: true,
+			wantStatus:    http.StatusCreated,
+			wantBody:      `{"upstream":"payments","path":"/v1/payments/10","query":"page=2","body":"{}"}`,
+		},
+		{
+			name: "Should not forward when only a group target exists and it does not match",
+			cfg: configs.ProxyConfig{
+				Groups: "payments:/v1/payments=" + payments.URL,
+			},
+			path:          "/v1/payments-legacy",
+			wantForwarded: false,
+		},
+		{
+			name:          "Should return bad gateway when the upstream is down",
+			cfg:           configs.ProxyConfig{Target: "http://127.0.0.1:1"},
+			path:          "/v1/users",
+			wantForwarded: true,
+			wantStatus:    http.StatusBadGateway,
+		},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			srv, err := service.NewProxyService(&configs.Config{Proxy: tt.cfg}, mocks.NewMockRuleService(mockCtrl))
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			writer := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, "/mock-service/mock"+tt.path+"?page=2", strings.NewReader("{}"))
+
+			result, forwarded := srv.Forward(mockscontext.Background(), writer, request, tt.path, "{}")
+			assert.Equal(t, tt.wantForwarded, forwarded)
+
+			if !tt.wantForwarded {
+				return
+			}
+
+			assert.Equal(t, tt.wantStatus, writer.Code)
+			assert.Equal(t, tt.wantStatus, result.StatusCode)
+
+			if tt.wantBody != "" {
+				assert.Equal(t, tt.wantBody, writer.Body.String())
+				assert.Equal(t, tt.wantBody, result.Body)
+			}
+		})
+	}
+}
+
+func TestProxyService_ForwardRecordsRule(t *testing.T) {
+	upstream := newUpstream(t, "staging")
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	ruleServiceMock := mocks.NewMockRuleService(mockCtrl)
+
+	ruleServiceMock.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ interface{}, rule model.Rule) (model.Rule, error) {
+			assert.Equal(t, "orders", rule.Group)
+			assert.Equal(t, "GET /v1/orders/42/items/01ARZ3NDEKTSV4RRFFQ69G5FAV", rule.Name)
+			assert.Equal(t, "/v1/orders/{param1}/items/{param2}", rule.Path)
+			assert.Equal(t, http.MethodGet, rule.Method)
+			assert.Equal(t, model.RuleStatusEnabled, rule.Status)
+
+			if assert.Len(t, rule.Responses, 1) {
+				assert.Equal(t, http.StatusCreated, rule.Responses[0].HTTPStatus)
+				assert.Equal(t, "application/json", rule.Responses[0].ContentType)
+				assert.Equal(t, map[string]string{"X-Upstream": "staging"}, rule.Responses[0].Headers)
+				assert.Contains(t, rule.Responses[0].Body, `"upstream":"staging"`)
+			}
+
+			rule.Key = "recorded_key"
+
+			return rule, nil
+		})
+
+	srv, err := service.NewProxyService(&configs.Config{Proxy: configs.ProxyConfig{
+		Groups: "orders:/v1/orders=" + upstream.URL,
+		Record: true,
+	}}, ruleServiceMock)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	path := "/v1/orders/42/items/01ARZ3NDEKTSV4RRFFQ69G5FAV"
+	writer := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/mock-service/mock"+path, nil)
+	request.Header.Set("Accept-Encoding", "gzip")
+
+	result, forwarded := srv.Forward(mockscontext.Background(), writer, request, path, "")
+	assert.True(t, forwarded)
+	assert.Equal(t, http.StatusCreated, writer.Code)
+	assert.Equal(t, "recorded_key", result.RecordedRule)
+}
+
+func TestNewProxyService_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  configs.ProxyConfig
+	}{
+		{name: "Should fail with a relative target", cfg: configs.ProxyConfig{Target: "staging.local"}},
+		{name: "Should fail with a group without prefix", cfg: configs.ProxyConfig{Groups: "payments=http://p.local"}},
+		{name: "Should fail with a group prefix without slash", cfg: configs.ProxyConfig{Groups: "payments:v1=http://p.local"}},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			_, err := service.NewProxyService(&configs.Config{Proxy: tt.cfg}, mocks.NewMockRuleService(mockCtrl))
+			assert.NotNil(t, err)
+		})
+	}
+}
@@ -0,0 +1,371 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	mockscontext "github.com/nicopozo/mockserver/internal/context"
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
+	"github.com/nicopozo/mockserver/internal/model"
+	"github.com/nicopozo/mockserver/internal/repository"
+	jsonutils "github.com/nicopozo/mockserver/internal/utils/json"
+	"github.com/oklog/ulid/v2"
+)
+
+const totalCountHeader = "X-Total-Count"
+
+//go:generate mockgen -destination=../utils/test/mocks/resource_service_mock.go -package=mocks -source=./resource_service.go
+
+// ResourceService serves the REST collections of the resource rules. Each rule has its own collection,
+// which is seeded with the seed entities of the rule on its first request.
+type ResourceService interface {
+	Handle(ctx context.Context, rule model.Rule, request *http.Request, path, body string) (model.Response, error)
+	Reset(ctx context.Context, key string) error
+}
+
+type resourceService struct {
+	ruleService RuleService
+	repository  repository.ResourceRepository
+}
+
+type resourceList struct {
+	Paging  model.Paging             `json:"paging"`
+	Results []map[string]interface{} `json:"results"`
+}
+
+func NewResourceService(ruleService RuleService, resourceRepository repository.ResourceRepository,
+) (ResourceService, error) {
+	if ruleService == nil {
+		return nil, fmt.Errorf("rule service cannot be nil") //nolint:err113
+	}
+
+	if resourceRepository == nil {
+		return nil, fmt.Errorf("resource repository cannot be nil") //nolint:err113
+	}
+
+	return &resourceService{
+		ruleService: ruleService,
+		repository:  resourceRepository,
+	}, nil
+}
+
+// Handle lists and creates entities on the rule path, and reads, replaces, patches and deletes them on
+// its item path. Client errors are returned as responses; only storage errors are returned as errors.
+func (svc *resourceService) Handle(ctx context.Context, rule model.Rule, request *http.Request, path,
+	body string,
+) (model.Response, error) {
+	logger := mockscontext.Logger(ctx)
+
+	logger.Debug(svc, nil, "Entering resourceService Handle()")
+
+	if err := svc.initialize(ctx, rule); err != nil {
+		return model.Response{}, err
+	}
+
+	id, isItem := resourceID(rule.Path, path)
+	method := strings.ToUpper(request.Method)
+
+	switch {
+	case !isItem && method == http.MethodGet:
+		return svc.list(ctx, rule, request.URL.Query())
+	case !isItem && method == http.MethodPost:
+		return svc.create(ctx, rule, path, body)
+	case isItem && method == http.MethodGet:
+		return svc.get(ctx, rule, id)
+	case isItem && (method == http.MethodPut || method == http.MethodPatch):
+		return svc.update(ctx, rule, id, body, method == http.MethodPatch)
+	case isItem && method == http.MethodDelete:
+		return svc.delete(ctx, rule, id)
+	}
+
+	return model.Response{}, mockserrors.InvalidRulesError{
+		Message: fmt.Sprintf("resource rule %s does not answer to %s %s", rule.Key, method, path),
+	}
+}
+
+// Reset replaces the entities of the collection of the rule with its seed entities.
+func (svc *resourceService) Reset(ctx context.Context, key string) error {
+	rule, err := svc.ruleService.Get(ctx, key)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	if !rule.IsResource() {
+		return mockserrors.InvalidRulesError{Message: fmt.Sprintf("rule %s is not a resource", key)}
+	}
+
+	return svc.seed(ctx, rule)
+}
+
+func (svc *resourceService) initialize(ctx context.Context, rule model.Rule) error {
+	initialized, err := svc.repository.Initialized(ctx, rule.Key)
+	if err != nil {
+		return fmt.Errorf("error reading resource collection, %w", err)
+	}
+
+	if initialized {
+		return nil
+	}
+
+	return svc.seed(ctx, rule)
+}
+
+func (svc *resourceService) seed(ctx context.Context, rule model.Rule) error {
+	config := rule.ResourceConfig()
+	now := time.Now().UTC()
+	entities := make([]model.ResourceEntity, 0, len(config.Seed))
+
+	for index, seed := range config.Seed {
+		data := make(map[string]interface{}, len(seed)+1)
+		for field, value := range seed {
+			data[field] = value
+		}
+
+		id, ok := model.EntityID(data[config.IDField])
+		if !ok {
+			id = ulid.Make().String()
+			data[config.IDField] = id
+		}
+
+		// Seed entities are spaced by a microsecond so that they are listed in the order they were declared.
+		createdAt := now.Add(time.Duration(index) * time.Microsecond)
+
+		entities = append(entities, model.ResourceEntity{ID: id, Data: data, CreatedAt: createdAt, UpdatedAt: createdAt})
+	}
+
+	if err := svc.repository.Reset(ctx, rule.Key, entities); err != nil {
+		return fmt.Errorf("error seeding resource collection, %w", err)
+	}
+
+	return nil
+}
+
+func (svc *resourceService) list(ctx context.Context, rule model.Rule, query url.Values) (model.Response, error) {
+	config := rule.ResourceConfig()
+
+	paging, err := resourcePaging(query, config.DefaultLimit)
+	if err != nil {
+		return resourceError(model.ValidationError, "%s", err.Error()), nil
+	}
+
+	entities, err := svc.repository.List(ctx, rule.Key, paging)
+	if err != nil {
+		return model.Response{}, fmt.Errorf("error listing resource entities, %w", err)
+	}
+
+	list := resourceList{Paging: entities.Paging, Results: make([]map[string]interface{}, 0, len(entities.Results))}
+	for _, entity := range entities.Results {
+		list.Results = append(list.Results, entity.Data)
+	}
+
+	response := resourceJSON(http.StatusOK, list)
+	response.Headers = map[string]string{totalCountHeader: strconv.FormatInt(entities.Paging.Total, 10)}
+
+	return response, nil
+}
+
+func (svc *resourceService) create(ctx context.Context, rule model.Rule, path, body string) (model.Response,
+	error,
+) {
+	config := rule.ResourceConfig()
+
+	data, err := decodeEntity(body)
+	if err != nil {
+		return resourceError(model.ValidationError, "%s", err.Error()), nil
+	}
+
+	id, ok := model.EntityID(data[config.IDField])
+
+	switch {
+	case data[config.IDField] == nil:
+		id = ulid.Make().String()
+		data[config.IDField] = id
+	case !ok:
+		return resourceError(model.ValidationError, "%s must be a string or a number", config.IDField), nil
+	}
+
+	if errs := config.SchemaErrors(data); len(errs) > 0 {
+		return resourceError(model.ValidationError, "entity does not match the schema: %s",
+			strings.Join(errs, "; ")), nil
+	}
+
+	now := time.Now().UTC()
+
+	err = svc.repository.Create(ctx, rule.Key, model.ResourceEntity{ID: id, Data: data, CreatedAt: now, UpdatedAt: now})
+	if errors.As(err, &mockserrors.ResourceEntityConflictError{}) {
+		return resourceError(model.Conflict, "%s", err.Error()), nil
+	}
+
+	if err != nil {
+		return model.Response{}, fmt.Errorf("error creating resource entity, %w", err)
+	}
+
+	response := resourceJSON(http.StatusCreated, data)
+	response.Headers = map[string]string{"Location": strings.TrimSuffix(path, "/") + "/" + url.PathEscape(id)}
+
+	return response, nil
+}
+
+func (svc *resourceService) get(ctx context.Context, rule model.Rule, id string) (model.Response, error) {
+	entity, err := svc.repository.Get(ctx, rule.Key, id)
+	if errors.As(err, &mockserrors.ResourceEntityNotFoundError{}) {
+		return resourceError(model.ResourceNotFoundError, "%s", err.Error()), nil
+	}
+
+	if err != nil {
+		return model.Response{}, fmt.Errorf("error getting resource entity, %w", err)
+	}
+
+	return resourceJSON(http.StatusOK, entity.Data), nil
+}
+
+// update replaces the entity with body, or applies body to it as a JSON merge patch (RFC 7386).
+func (svc *resourceService) update(ctx context.Context, rule model.Rule, id, body string, patch bool,
+) (model.Response, error) {
+	config := rule.ResourceConfig()
+
+	existing, err := svc.repository.Get(ctx, rule.Key, id)
+	if errors.As(err, &mockserrors.ResourceEntityNotFoundError{}) {
+		return resourceError(model.ResourceNotFoundError, "%s", err.Error()), nil
+	}
+
+	if err != nil {
+		return model.Response{}, fmt.Errorf("error getting resource entity, %w", err)
+	}
+
+	data, err := decodeEntity(body)
+	if err != nil {
+		return resourceError(model.ValidationError, "%s", err.Error()), nil
+	}
+
+	if patch {
+		data = mergePatch(existing.Data, data)
+	}
+
+	if value, found := data[config.IDField]; found {
+		if bodyID, _ := model.EntityID(value); bodyID != id {
+			return resourceError(model.ValidationError, "%s cannot be changed", config.IDField), nil
+		}
+	}
+
+	data[config.IDField] = existing.Data[config.IDField]
+
+	if errs := config.SchemaErrors(data); len(errs) > 0 {
+		return resourceError(model.ValidationError, "entity does not match the schema: %s",
+			strings.Join(errs, "; ")), nil
+	}
+
+	existing.Data = data
+	existing.UpdatedAt = time.Now().UTC()
+
+	err = svc.repository.Update(ctx, rule.Key, *existing)
+	if errors.As(err, &mockserrors.ResourceEntityNotFoundError{}) {
+		return resourceError(model.ResourceNotFoundError, "%s", err.Error()), nil
+	}
+
+	if err != nil {
+		return model.Response{}, fmt.Errorf("error updating resource entity, %w", err)
+	}
+
+	return resourceJSON(http.StatusOK, data), nil
+}
+
+func (svc *resourceService) delete(ctx context.Context, rule model.Rule, id string) (model.Response, error) {
+	err := svc.repository.Delete(ctx, rule.Key, id)
+	if errors.As(err, &mockserrors.ResourceEntityNotFoundError{}) {
+		return resourceError(model.ResourceNotFoundError, "%s", err.Error()), nil
+	}
+
+	if err != nil {
+		return model.Response{}, fmt.Errorf("error deleting resource entity, %w", err)
+	}
+
+	return model.Response{HTTPStatus: http.StatusNoContent, ContentType: "application/json"}, nil
+}
+
+// resourceID returns the id in path when it addresses an item of the collection at basePath.
+func resourceID(basePath, path string) (string, bool) {
+	baseSegments := strings.Split(strings.TrimSuffix(basePath, "/"), "/")
+	segments := strings.Split(strings.TrimSuffix(path, "/"), "/")
+
+	if len(segments) != len(baseSegments)+1 {
+		return "", false
+	}
+
+	return segments[len(segments)-1], true
+}
+
+func resourcePaging(query url.Values, defaultLimit int32) (model.Paging, error) {
+	paging := model.Paging{Limit: defaultLimit}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || limit < 1 || limit > model.ResourceMaxLimit {
+			return model.Paging{}, fmt.Errorf("limit must be a number between 1 and %d", //nolint:err113
+				model.ResourceMaxLimit)
+		}
+
+		paging.Limit = int32(limit)
+	}
+
+	if value := query.Get("offset"); value != "" {
+		offset, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || offset < 0 {
+			return model.Paging{}, fmt.Errorf("offset must be a positive number") //nolint:err113
+		}
+
+		paging.Offset = int32(offset)
+	}
+
+	return paging, nil
+}
+
+func decodeEntity(body string) (map[string]interface{}, error) {
+	var data map[string]interface{}
+
+	if err := json.Unmarshal([]byte(body), &data); err != nil || data == nil {
+		return nil, fmt.Errorf("request body must be a JSON object") //nolint:err113
+	}
+
+	return data, nil
+}
+
+// mergePatch applies patch to target as described in RFC 7386: null removes a field, objects are merged
+// recursively and any other value replaces the field.
+func mergePatch(target, patch map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(target)+len(patch))
+	for field, value := range target {
+		result[field] = value
+	}
+
+	for field, value := range patch {
+		switch patchValue := value.(type) {
+		case nil:
+			delete(result, field)
+		case map[string]interface{}:
+			targetValue, _ := result[field].(map[string]interface{})
+			result[field] = mergePatch(targetValue, patchValue)
+		default:
+			result[field] = value
+		}
+	}
+
+	return result
+}
+
+func resourceJSON(status int, body interface{}) model.Response {
+	return model.Response{HTTPStatus: status, ContentType: "application/json", Body: jsonutils.Marshal(body)}
+}
+
+func resourceError(code int64, format string, args ...interface{}) model.Response {
+	errResult := model.NewError(code, format, args...)
+
+	return resourceJSON(errResult.Status, errResult)
+}
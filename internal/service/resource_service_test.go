@@ -0,0 +1,269 @@
+package service_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nicopozo/mockserver/internal/configs"
+	mockscontext "github.com/nicopozo/mockserver/internal/context"
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
+	"github.com/nicopozo/mockserver/internal/model"
+	"github.com/nicopozo/mockserver/internal/repository"
+	"github.com/nicopozo/mockserver/internal/service"
+	jsonutils "github.com/nicopozo/mockserver/internal/utils/json"
+	"github.com/stretchr/testify/assert"
+)
+
+func newResourceServices(t *testing.T, cfg *configs.Config) (service.RuleService, service.ResourceService,
+	service.MockService,
+) {
+	t.Helper()
+
+	ruleService, scenarioService := newScenarioServices(t, cfg)
+
+	resourceRepo, err := repository.NewResourceFileRepository(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resourceService, err := service.NewResourceService(ruleService, resourceRepo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mockService, err := service.NewMockService(ruleService, newWebhookService(t), scenarioService,
+		resourceService, newChaosService(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return ruleService, resourceService, mockService
+}
+
+func newResourceService(t *testing.T) service.ResourceService {
+	t.Helper()
+
+	_, resourceService, _ := newResourceServices(t, newScenarioConfig(t))
+
+	return resourceService
+}
+
+func resourceRule() model.Rule {
+	return model.Rule{
+		Group:    "users",
+		Name:     "users",
+		Path:     "/users",
+		Strategy: model.RuleStrategyResource,
+		Status:   model.RuleStatusEnabled,
+		Resource: &model.ResourceConfig{
+			Schema: map[string]interface{}{
+				"type":     "object",
+				"required": []interface{}{"name"},
+				"properties": map[string]interface{}{
+					"name": map[string]interface{}{"type": "string"},
+				},
+			},
+			Seed: []map[string]interface{}{
+				{"id": float64(1), "name": "Ada"},
+				{"id": float64(2), "name": "Grace"},
+				{"id": float64(3), "name": "Linus"},
+			},
+		},
+	}
+}
+
+type resourceCall func(method, path, body string) (model.Response, map[string]interface{})
+
+func newResourceCall(t *testing.T, mockService service.MockService) resourceCall {
+	t.Helper()
+
+	return func(method, path, body string) (model.Response, map[string]interface{}) {
+		request := httptest.NewRequest(method, path, strings.NewReader(body))
+
+		response, _, err := mockService.SearchResponseForRequest(mockscontext.Background(), request,
+			request.URL.Path, body, "")
+		assert.NoError(t, err)
+
+		var decoded map[string]interface{}
+		if response.Body != "" {
+			_ = jsonutils.Unmarshal(strings.NewReader(response.Body), &decoded)
+		}
+
+		return response, decoded
+	}
+}
+
+func TestResourceService_CRUD(t *testing.T) {
+	ctx := mockscontext.Background()
+	ruleService, _, mockService := newResourceServices(t, newScenarioConfig(t))
+
+	rule, err := ruleService.Save(ctx, resourceRule())
+	assert.NoError(t, err)
+	assert.Equal(t, model.RuleMethodAny, rule.Method)
+
+	call := newResourceCall(t, mockService)
+
+	response, body := call(http.MethodGet, "/users?limit=2&offset=1", "")
+	assert.Equal(t, http.StatusOK, response.HTTPStatus)
+	assert.Equal(t, "3", response.Headers["X-Total-Count"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"id": float64(2), "name": "Grace"},
+		map[string]interface{}{"id": float64(3), "name": "Linus"},
+	}, body["results"])
+
+	response, body = call(http.MethodPost, "/users", `{"id":"ken","name":"Ken"}`)
+	assert.Equal(t, http.StatusCreated, response.HTTPStatus)
+	assert.Equal(t, "/users/ken", response.Headers["Location"])
+	assert.Equal(t, "Ken", body["name"])
+
+	response, _ = call(http.MethodPost, "/users", `{"id":"ken","name":"Ken"}`)
+	assert.Equal(t, http.StatusConflict, response.HTTPStatus)
+
+	response, _ = call(http.MethodPost, "/users", `{"name":1}`)
+	assert.Equal(t, http.StatusBadRequest, response.HTTPStatus)
+
+	response, body = call(http.MethodPost, "/users", `{"name":"Barbara"}`)
+	assert.Equal(t, http.StatusCreated, response.HTTPStatus)
+	assert.NotEmpty(t, body["id"])
+
+	response, body = call(http.MethodGet, "/users/1", "")
+	assert.Equal(t, http.StatusOK, response.HTTPStatus)
+	assert.Equal(t, "Ada", body["name"])
+
+	response, body = call(http.MethodPatch, "/users/1", `{"email":"ada@example.com","name":null}`)
+	assert.Equal(t, http.StatusBadRequest, response.HTTPStatus, "name is required by the schema")
+	assert.Contains(t, body["message"], "schema")
+
+	response, body = call(http.MethodPatch, "/users/1", `{"email":"ada@example.com"}`)
+	assert.Equal(t, http.StatusOK, response.HTTPStatus)
+	assert.Equal(t, map[string]interface{}{"id": float64(1), "name": "Ada", "email": "ada@example.com"}, body)
+
+	response, body = call(http.MethodPut, "/users/1", `{"name":"Ada Lovelace"}`)
+	assert.Equal(t, http.StatusOK, response.HTTPStatus)
+	assert.Equal(t, map[string]interface{}{"id": float64(1), "name": "Ada Lovelace"}, body)
+
+	response, _ = call(http.MethodPut, "/users/1", `{"id":2,"name":"Ada"}`)
+	assert.Equal(t, http.StatusBadRequest, response.HTTPStatus)
+
+	response, _ = call(http.MethodDelete, "/users/2", "")
+	assert.Equal(t, http.StatusNoContent, response.HTTPStatus)
+
+	response, _ = call(http.MethodGet, "/users/2", "")
+	assert.Equal(t, http.StatusNotFound, response.HTTPStatus)
+
+	response, _ = call(http.MethodDelete, "/users/2", "")
+	assert.Equal(t, http.StatusNotFound, response.HTTPStatus)
+
+	response, _ = call(http.MethodGet, "/users", "")
+	assert.Equal(t, "4", response.Headers["X-Total-Count"])
+}
+
+func TestResourceService_Reset(t *testing.T) {
+	ctx := mockscontext.Background()
+	cfg := newScenarioConfig(t)
+	ruleService, resourceService, mockService := newResourceServices(t, cfg)
+
+	rule, err := ruleService.Save(ctx, resourceRule())
+	assert.NoError(t, err)
+
+	call := newResourceCall(t, mockService)
+
+	response, _ := call(http.MethodDelete, "/users/1", "")
+	assert.Equal(t, http.StatusNoContent, response.HTTPStatus)
+
+	// The collection is kept in the resources file, so it survives a restart.
+	_, restarted, restartedMock := newResourceServices(t, cfg)
+
+	response, _ = newResourceCall(t, restartedMock)(http.MethodGet, "/users", "")
+	assert.Equal(t, "2", response.Headers["X-Total-Count"])
+
+	assert.NoError(t, restarted.Reset(ctx, rule.Key))
+
+	response, _ = newResourceCall(t, restartedMock)(http.MethodGet, "/users/1", "")
+	assert.Equal(t, http.StatusOK, response.HTTPStatus)
+
+	assert.ErrorAs(t, restarted.Reset(ctx, "unknown"), &mockserrors.RuleNotFoundError{})
+
+	normal, err := ruleService.Save(ctx, scenarioRule("order", "", "paid", "body"))
+	assert.NoError(t, err)
+	assert.ErrorAs(t, resourceService.Reset(ctx, normal.Key), &mockserrors.InvalidRulesError{})
+}
+
+func TestResourceService_NormalRulesTakePrecedence(t *testing.T) {
+	ctx := mockscontext.Background()
+	ruleService, _, mockService := newResourceServices(t, newScenarioConfig(t))
+
+	_, err := ruleService.Save(ctx, resourceRule())
+	assert.NoError(t, err)
+
+	_, err = ruleService.Save(ctx, model.Rule{
+		Group:     "users",
+		Name:      "current user",
+		Path:      "/users/me",
+		Method:    http.MethodGet,
+		Strategy:  model.RuleStrategyNormal,
+		Status:    model.RuleStatusEnabled,
+		Responses: []model.Response{{Body: "me", ContentType: "text/plain", HTTPStatus: http.StatusOK}},
+	})
+	assert.NoError(t, err)
+
+	call := newResourceCall(t, mockService)
+
+	response, _ := call(http.MethodGet, "/users/me", "")
+	assert.Equal(t, "me", response.Body)
+
+	response, _ = call(http.MethodGet, "/users/3", "")
+	assert.Equal(t, http.StatusOK, response.HTTPStatus)
+}
+
+func TestRuleService_SaveInvalidResource(t *testing.T) {
+	ruleService, _ := newScenarioServices(t, newScenarioConfig(t))
+
+	tests := []struct {
+		name    string
+		rule    func(rule *model.Rule)
+		wantErr string
+	}{
+		{
+			name:    "Should fail with a trailing slash",
+			rule:    func(rule *model.Rule) { rule.Path = "/users/" },
+			wantErr: "resource path cannot end with '/'",
+		},
+		{
+			name: "Should fail with a duplicated seed id",
+			rule: func(rule *model.Rule) {
+				rule.Resource.Seed = append(rule.Resource.Seed, map[string]interface{}{"id": float64(1), "name": "Bis"})
+			},
+			wantErr: "seed entity 3: duplicated id '1'",
+		},
+		{
+			name: "Should fail with a seed that does not match the schema",
+			rule: func(rule *model.Rule) {
+				rule.Resource.Seed = []map[string]interface{}{{"id": float64(1)}}
+			},
+			wantErr: "seed entity 0 does not match the schema: (root): name is required",
+		},
+		{
+			name: "Should fail with a resource on a normal rule",
+			rule: func(rule *model.Rule) {
+				rule.Strategy = model.RuleStrategyNormal
+				rule.Method = http.MethodGet
+				rule.Responses = []model.Response{{Body: "body", ContentType: "text/plain", HTTPStatus: http.StatusOK}}
+			},
+			wantErr: "resource is only allowed with the 'resource' strategy",
+		},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			rule := resourceRule()
+			tt.rule(&rule)
+
+			_, err := ruleService.Save(mockscontext.Background(), rule)
+			assert.ErrorAs(t, err, &mockserrors.InvalidRulesError{})
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
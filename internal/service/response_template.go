@@ -0,0 +1,506 @@
+package service
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
+	"github.com/nicopozo/mockserver/internal/model"
+)
+
+// templateData is the value exposed as "." to responses rendered with the go template engine.
+type templateData struct {
+	Request templateRequest
+	Vars    map[string]string
+}
+
+type templateRequest struct {
+	Method     string
+	Path       string
+	PathParams map[string]string
+	Query      map[string]string
+	Headers    map[string]string
+	Cookies    map[string]string
+	Body       string
+	JSON       interface{}
+}
+
+//nolint:gochecknoglobals
+var templateFuncs = template.FuncMap{
+	"json":       templateJSON,
+	"jsonEscape": templateJSONEscape,
+	"add":        templateOperation(func(x, y float64) float64 { return x + y }, templateAddInt),
+	"sub":        templateOperation(func(x, y float64) float64 { return x - y }, templateSubInt),
+	"mul":        templateOperation(func(x, y float64) float64 { return x * y }, templateMulInt),
+	"div":        templateDiv,
+	"mod":        templateMod,
+	"toInt":      templateToInt,
+	"now":        time.Now,
+	"formatDate": templateFormatDate,
+	"dateAdd":    templateDateAdd,
+	"uuid":       templateUUID,
+	"randomInt":  templateRandomInt,
+	"upper":      strings.ToUpper,
+	"lower":      strings.ToLower,
+	"default":    templateDefault,
+}
+
+func newTemplateData(request *http.Request, path string, pathParams map[string]string, body string,
+	variables []*model.Variable,
+) templateData {
+	data := templateData{
+		Request: templateRequest{
+			Method:     request.Method,
+			Path:       path,
+			PathParams: pathParams,
+			Query:      make(map[string]string),
+			Headers:    make(map[string]string, len(request.Header)),
+			Cookies:    make(map[string]string),
+			Body:       body,
+		},
+		Vars: make(map[string]string, len(variables)),
+	}
+
+	if data.Request.PathParams == nil {
+		data.Request.PathParams = make(map[string]string)
+	}
+
+	for key, values := range request.URL.Query() {
+		data.Request.Query[key] = values[0]
+	}
+
+	for key, values := range request.Header {
+		data.Request.Headers[key] = values[0]
+	}
+
+	for _, cookie := range request.Cookies() {
+		data.Request.Cookies[cookie.Name] = cookie.Value
+	}
+
+	var document interface{}
+	if err := json.Unmarshal([]byte(body), &document); err == nil {
+		data.Request.JSON = document
+	}
+
+	for _, variable := range variables {
+		data.Vars[variable.Name] = variable.Value
+	}
+
+	return data
+}
+
+func parseTemplate(name, text string) (*template.Template, error) {
+	//nolint:wrapcheck
+	return template.New(name).Funcs(templateFuncs).Parse(text)
+}
+
+func renderTemplate(name, text string, data templateData) (string, error) {
+	tmpl, err := parseTemplate(name, text)
+	if err != nil {
+		return "", fmt.Errorf("error parsing %s template, %w", name, err)
+	}
+
+	var buffer bytes.Buffer
+	if err := tmpl.Execute(&buffer, data); err != nil {
+		return "", fmt.Errorf("error rendering %s template, %w", name, err)
+	}
+
+	return buffer.String(), nil
+}
+
+// renderResponseTemplate renders the body, headers, cookie values and webhook of response.
+func renderResponseTemplate(response model.Response, data templateData) (model.Response, error) {
+	var err error
+
+	if response.Body, err = renderTemplate("body", response.Body, data); err != nil {
+		return model.Response{}, err
+	}
+
+	if response.Headers, err = renderTemplateMap("header", response.Headers, data); err != nil {
+		return model.Response{}, err
+	}
+
+	if response.Cookies != nil {
+		cookies := make([]model.Cookie, len(response.Cookies))
+
+		for index, cookie := range response.Cookies {
+			if cookie.Value, err = renderTemplate("cookie "+cookie.Name, cookie.Value, data); err != nil {
+				return model.Response{}, err
+			}
+
+			cookies[index] = cookie
+		}
+
+		response.Cookies = cookies
+	}
+
+	if response.Webhook != nil {
+		webhook, err := renderWebhookTemplate("webhook", *response.Webhook, data)
+		if err != nil {
+			return model.Response{}, err
+		}
+
+		response.Webhook = &webhook
+	}
+
+	if response.Webhooks != nil {
+		webhooks := make([]model.WebhookConfig, len(response.Webhooks))
+
+		for index, webhook := range response.Webhooks {
+			if webhooks[index], err = renderWebhookTemplate(webhookTemplateName(index), webhook, data); err != nil {
+				return model.Response{}, err
+			}
+		}
+
+		response.Webhooks = webhooks
+	}
+
+	return response, nil
+}
+
+// renderWebhookTemplate renders the URL, body and headers of webhook.
+func renderWebhookTemplate(name string, webhook model.WebhookConfig, data templateData) (model.WebhookConfig, error) {
+	var err error
+
+	if webhook.URL, err = renderTemplate(name+" url", webhook.URL, data); err != nil {
+		return model.WebhookConfig{}, err
+	}
+
+	if webhook.Body, err = renderTemplate(name+" body", webhook.Body, data); err != nil {
+		return model.WebhookConfig{}, err
+	}
+
+	if webhook.Headers, err = renderTemplateMap(name+" header", webhook.Headers, data); err != nil {
+		return model.WebhookConfig{}, err
+	}
+
+	return webhook, nil
+}
+
+func webhookTemplateName(index int) string {
+	return fmt.Sprintf("webhooks[%d]", index)
+}
+
+func renderTemplateMap(kind string, values map[string]string, data templateData) (map[string]string, error) {
+	if values == nil {
+		return nil, nil
+	}
+
+	result := make(map[string]string, len(values))
+
+	for name, value := range values {
+		rendered, err := renderTemplate(kind+" "+name, value, data)
+		if err != nil {
+			return nil, err
+		}
+
+		result[name] = rendered
+	}
+
+	return result, nil
+}
+
+// validateResponseTemplate checks that every templated field of a go template response parses.
+func validateResponseTemplate(response model.Response) error {
+	switch response.Template {
+	case "":
+		return nil
+	case model.ResponseTemplateGo:
+	default:
+		return mockserrors.InvalidRulesError{
+			Message: fmt.Sprintf("invalid response template - only '%s' is a valid value", model.ResponseTemplateGo),
+		}
+	}
+
+	sources := map[string]string{"body": response.Body}
+
+	for name, value := range response.Headers {
+		sources["header "+name] = value
+	}
+
+	for _, cookie := range response.Cookies {
+		sources["cookie "+cookie.Name] = cookie.Value
+	}
+
+	if response.Webhook != nil {
+		addWebhookTemplateSources(sources, "webhook", *response.Webhook)
+	}
+
+	for index, webhook := range response.Webhooks {
+		addWebhookTemplateSources(sources, webhookTemplateName(index), webhook)
+	}
+
+	for name, text := range sources {
+		if _, err := parseTemplate(name, text); err != nil {
+			return mockserrors.InvalidRulesError{
+				Message: fmt.Sprintf("invalid %s template: %s", name, err.Error()),
+			}
+		}
+	}
+
+	return nil
+}
+
+func addWebhookTemplateSources(sources map[string]string, name string, webhook model.WebhookConfig) {
+	sources[name+" url"] = webhook.URL
+	sources[name+" body"] = webhook.Body
+
+	for header, value := range webhook.Headers {
+		sources[name+" header "+header] = value
+	}
+}
+
+func templateJSON(value interface{}) (string, error) {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("error marshaling value, %w", err)
+	}
+
+	return string(content), nil
+}
+
+// templateJSONEscape escapes value so that it can be placed between double quotes in a JSON document.
+func templateJSONEscape(value interface{}) (string, error) {
+	text, err := templateJSON(fmt.Sprint(value))
+	if err != nil {
+		return "", err
+	}
+
+	return text[1 : len(text)-1], nil
+}
+
+// templateDecimal is the result of the arithmetic functions when an operand is not a whole number. It is
+// printed without an exponent, like the numbers of a JSON document.
+type templateDecimal float64
+
+func (number templateDecimal) String() string {
+	return strconv.FormatFloat(float64(number), 'f', -1, 64)
+}
+
+func templateFloat(value interface{}) (float64, error) {
+	switch number := value.(type) {
+	case int:
+		return float64(number), nil
+	case int64:
+		return float64(number), nil
+	case float64:
+		return number, nil
+	case templateDecimal:
+		return float64(number), nil
+	case json.Number:
+		return number.Float64() //nolint:wrapcheck
+	case string:
+		parsed, err := strconv.ParseFloat(number, 64)
+		if err != nil {
+			return 0, fmt.Errorf("'%s' is not a number", number) //nolint:err113
+		}
+
+		return parsed, nil
+	}
+
+	return 0, fmt.Errorf("'%v' is not a number", value) //nolint:err113
+}
+
+// maxExactFloatInteger is the largest whole number that every float64 below it can hold exactly.
+const maxExactFloatInteger = 1 << 53
+
+// templateInteger returns a number that templateFloat accepts as an int64 when it is a whole number. The
+// numbers of the request JSON are decoded as floats, so floats without a fraction are whole numbers too.
+func templateInteger(value interface{}) (int64, bool) {
+	switch number := value.(type) {
+	case int:
+		return int64(number), true
+	case int64:
+		return number, true
+	case json.Number:
+		parsed, err := number.Int64()
+
+		return parsed, err == nil
+	case string:
+		parsed, err := strconv.ParseInt(number, 10, 64)
+
+		return parsed, err == nil
+	}
+
+	number, err := templateFloat(value)
+	if err != nil || number != math.Trunc(number) || math.Abs(number) > maxExactFloatInteger {
+		return 0, false
+	}
+
+	return int64(number), true
+}
+
+// templateMath applies the operation to two numbers. Whole numbers go through intOperation, so that large
+// integers are neither rounded nor printed with an exponent, unless it reports that the result does not
+// fit in an int64.
+func templateMath(a, b interface{}, operation func(x, y float64) float64,
+	intOperation func(x, y int64) (int64, bool),
+) (interface{}, error) {
+	x, err := templateFloat(a)
+	if err != nil {
+		return nil, err
+	}
+
+	y, err := templateFloat(b)
+	if err != nil {
+		return nil, err
+	}
+
+	intX, isInteger := templateInteger(a)
+	intY, bothIntegers := templateInteger(b)
+
+	if isInteger && bothIntegers {
+		if result, ok := intOperation(intX, intY); ok {
+			return result, nil
+		}
+	}
+
+	return templateDecimal(operation(x, y)), nil
+}
+
+func templateOperation(operation func(x, y float64) float64,
+	intOperation func(x, y int64) (int64, bool),
+) func(a, b interface{}) (interface{}, error) {
+	return func(a, b interface{}) (interface{}, error) {
+		return templateMath(a, b, operation, intOperation)
+	}
+}
+
+func templateDiv(a, b interface{}) (interface{}, error) {
+	if divisor, err := templateFloat(b); err == nil && divisor == 0 {
+		return nil, fmt.Errorf("division by zero") //nolint:err113
+	}
+
+	return templateMath(a, b, func(x, y float64) float64 { return x / y }, templateDivInt)
+}
+
+func templateMod(a, b interface{}) (interface{}, error) {
+	if divisor, err := templateFloat(b); err == nil && divisor == 0 {
+		return nil, fmt.Errorf("division by zero") //nolint:err113
+	}
+
+	return templateMath(a, b, math.Mod, func(x, y int64) (int64, bool) { return x % y, true })
+}
+
+func templateAddInt(x, y int64) (int64, bool) {
+	result := x + y
+
+	return result, (result > x) == (y > 0)
+}
+
+func templateSubInt(x, y int64) (int64, bool) {
+	result := x - y
+
+	return result, (result < x) == (y > 0)
+}
+
+func templateMulInt(x, y int64) (int64, bool) {
+	if x == 0 || y == 0 {
+		return 0, true
+	}
+
+	result := x * y
+
+	return result, result/y == x && !(x == -1 && y == math.MinInt64) && !(y == -1 && x == math.MinInt64)
+}
+
+// templateDivInt only divides whole numbers when the quotient is a whole number, 7 / 2 is still 3.5.
+func templateDivInt(x, y int64) (int64, bool) {
+	if x%y != 0 || (x == math.MinInt64 && y == -1) {
+		return 0, false
+	}
+
+	return x / y, true
+}
+
+func templateToInt(value interface{}) (int64, error) {
+	number, err := templateFloat(value)
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(number), nil
+}
+
+// templateFormatDate formats value, a time.Time or an RFC 3339 string, with a Go time layout.
+func templateFormatDate(layout string, value interface{}) (string, error) {
+	date, err := templateTime(value)
+	if err != nil {
+		return "", err
+	}
+
+	return date.Format(layout), nil
+}
+
+// templateDateAdd adds a Go duration such as "24h" or "-90m" to value.
+func templateDateAdd(duration string, value interface{}) (time.Time, error) {
+	date, err := templateTime(value)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	parsed, err := time.ParseDuration(duration)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid duration '%s', %w", duration, err)
+	}
+
+	return date.Add(parsed), nil
+}
+
+func templateTime(value interface{}) (time.Time, error) {
+	switch date := value.(type) {
+	case time.Time:
+		return date, nil
+	case string:
+		parsed, err := time.Parse(time.RFC3339, date)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("'%s' is not an RFC 3339 date, %w", date, err)
+		}
+
+		return parsed, nil
+	}
+
+	return time.Time{}, fmt.Errorf("'%v' is not a date", value) //nolint:err113
+}
+
+func templateUUID() (string, error) {
+	id := make([]byte, 16) //nolint:mnd
+	if _, err := rand.Read(id); err != nil {
+		return "", fmt.Errorf("error generating uuid, %w", err)
+	}
+
+	id[6] = (id[6] & 0x0f) | 0x40 //nolint:mnd
+	id[8] = (id[8] & 0x3f) | 0x80 //nolint:mnd
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", id[0:4], id[4:6], id[6:8], id[8:10], id[10:]), nil
+}
+
+func templateRandomInt(minValue, maxValue int) (int64, error) {
+	if maxValue < minValue {
+		return 0, fmt.Errorf("randomInt max %d is lower than min %d", maxValue, minValue) //nolint:err113
+	}
+
+	number, err := rand.Int(rand.Reader, big.NewInt(int64(maxValue-minValue)+1))
+	if err != nil {
+		return 0, fmt.Errorf("error generating random number, %w", err)
+	}
+
+	return number.Int64() + int64(minValue), nil
+}
+
+func templateDefault(fallback, value interface{}) interface{} {
+	if value == nil || value == "" {
+		return fallback
+	}
+
+	return value
+}
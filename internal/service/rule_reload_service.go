@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nicopozo/mockserver/internal/configs"
+	mockscontext "github.com/nicopozo/mockserver/internal/context"
+	"github.com/nicopozo/mockserver/internal/model"
+	"github.com/nicopozo/mockserver/internal/repository"
+)
+
+//go:generate mockgen -destination=../utils/test/mocks/rule_reload_service_mock.go -package=mocks -source=./rule_reload_service.go
+
+// RuleReloadService picks up the rules that are edited outside the API, like the mocks file of the file
+// datasource when it is kept in git and edited by hand.
+type RuleReloadService interface {
+	// Reload reads the rules again when their source changed and reports whether they were replaced. The
+	// new rules are validated like the ones saved through the API; if any of them is not valid, the
+	// previous rules are kept.
+	Reload(ctx context.Context) (bool, error)
+	// Start checks the source of the rules for changes every cfg.RulesReloadInterval until ctx is done.
+	Start(ctx context.Context)
+}
+
+type ruleReloadService struct {
+	repository repository.ReloadableRuleRepository
+	interval   time.Duration
+}
+
+// NewRuleReloadService creates a RuleReloadService. Only the file datasource can be reloaded, for the
+// others Reload does nothing.
+func NewRuleReloadService(cfg *configs.Config, ruleRepository repository.RuleRepository) RuleReloadService {
+	service := &ruleReloadService{interval: cfg.RulesReloadInterval}
+
+	if reloadable, ok := ruleRepository.(repository.ReloadableRuleRepository); ok && cfg.IsFile() {
+		service.repository = reloadable
+	}
+
+	return service
+}
+
+func (s *ruleReloadService) Reload(ctx context.Context) (bool, error) {
+	if s.repository == nil {
+		return false, nil
+	}
+
+	reloaded, err := s.repository.Reload(ctx, checkRule)
+	if err != nil {
+		return false, fmt.Errorf("error reloading rules, %w", err)
+	}
+
+	return reloaded, nil
+}
+
+func (s *ruleReloadService) Start(ctx context.Context) {
+	if s.repository == nil || s.interval <= 0 {
+		return
+	}
+
+	logger := mockscontext.Logger(ctx)
+
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			reloaded, err := s.Reload(ctx)
+			if err != nil {
+				logger.Error(s, nil, err, "rules were not reloaded, the previous rules are kept")
+
+				continue
+			}
+
+			if reloaded {
+				logger.Info(s, nil, "rules reloaded")
+			}
+		}
+	}()
+}
+
+// checkRule validates a rule that was edited by hand and fills its defaults, the same way Save does.
+func checkRule(rule model.Rule) (model.Rule, error) {
+	if err := validateRule(rule); err != nil {
+		return model.Rule{}, err
+	}
+
+	return formatRule(rule), nil
+}
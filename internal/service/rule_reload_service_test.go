@@ -0,0 +1,96 @@
+package service_test
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	mockscontext "github.com/nicopozo/mockserver/internal/context"
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
+	"github.com/nicopozo/mockserver/internal/model"
+	"github.com/nicopozo/mockserver/internal/repository"
+	"github.com/nicopozo/mockserver/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuleReloadService(t *testing.T) {
+	ctx := mockscontext.Background()
+	cfg := newScenarioConfig(t)
+	cfg.RulesReloadInterval = 10 * time.Millisecond
+
+	fileRepo, err := repository.NewRuleFileRepository(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ruleRepo := repository.NewIndexedRuleRepository(fileRepo)
+
+	ruleService, err := service.NewRuleService(ruleRepo, newRevisionRepository(t, cfg))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reloadService := service.NewRuleReloadService(cfg, ruleRepo)
+
+	_, err = ruleService.Save(ctx, model.Rule{
+		Name: "saved", Path: "/saved", Method: http.MethodGet,
+		Responses: []model.Response{{Body: "saved", HTTPStatus: http.StatusOK}},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// Loads the routing index, so the reload must drop it.
+	_, err = ruleService.SearchByMethodAndPath(ctx, http.MethodGet, "/saved", &model.MatchRequest{})
+	assert.NoError(t, err)
+
+	// A rule that would be rejected by the API is rejected on reload too.
+	editMocksFile(t, cfg.MocksFile, `[{"key":"k1","name":"edited","path":"edited","method":"GET",
+		"responses":[{"body":"edited","http_status":200}]}]`)
+
+	reloaded, err := reloadService.Reload(ctx)
+	assert.ErrorAs(t, err, &mockserrors.InvalidRulesError{})
+	assert.False(t, reloaded)
+
+	_, err = ruleService.SearchByMethodAndPath(ctx, http.MethodGet, "/saved", &model.MatchRequest{})
+	assert.NoError(t, err, "the previous rules are kept")
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	reloadService.Start(ctx)
+
+	editMocksFile(t, cfg.MocksFile, `[{"key":"k1","name":"edited","path":"/edited","method":"get",
+		"responses":[{"body":"edited","http_status":200}]}]`)
+
+	assert.Eventually(t, func() bool {
+		rule, err := ruleService.SearchByMethodAndPath(ctx, http.MethodGet, "/edited", &model.MatchRequest{})
+
+		return err == nil && rule.Key == "k1"
+	}, time.Second, 10*time.Millisecond)
+
+	rule, err := ruleService.Get(ctx, "k1")
+	if assert.NoError(t, err) {
+		assert.Equal(t, model.RuleStatusEnabled, rule.Status, "the defaults are filled like on save")
+		assert.Equal(t, http.MethodGet, rule.Method)
+	}
+
+	_, err = ruleService.SearchByMethodAndPath(ctx, http.MethodGet, "/saved", &model.MatchRequest{})
+	assert.ErrorAs(t, err, &mockserrors.RuleNotFoundError{})
+}
+
+// editMocksFile writes the mocks file by hand, with a modification time that is always new.
+func editMocksFile(t *testing.T, name, content string) {
+	t.Helper()
+
+	if err := os.WriteFile(name, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	modTime := time.Now().Add(time.Duration(len(content)) * time.Second)
+	if err := os.Chtimes(name, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
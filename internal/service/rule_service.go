@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	mockscontext "github.com/nicopozo/mockserver/internal/context"
 	mockserrors "github.com/nicopozo/mockserver/internal/errors"
@@ -22,25 +23,53 @@ type RuleService interface {
 	UpdateStatus(ctx context.Context, key string, rule model.RuleStatus) (model.Rule, error)
 	Get(ctx context.Context, key string) (model.Rule, error)
 	Search(ctx context.Context, params map[string]interface{}, paging model.Paging) (model.RuleList, error)
-	SearchByMethodAndPath(ctx context.Context, method, path string) (model.Rule, error)
+	SearchByMethodAndPath(ctx context.Context, method, path string, request *model.MatchRequest) (model.Rule, error)
+	SearchCandidates(ctx context.Context, method, path string) (model.RuleList, error)
 	Delete(ctx context.Context, key string) error
+	// NextResponse returns the response that a sequential rule serves and moves the rule on to the next
+	// one, atomically so that concurrent requests are served in turn. It is not a change of the rule, so
+	// no revision is appended.
+	NextResponse(ctx context.Context, rule model.Rule) (model.Response, error)
+	// Revisions lists the revision history of a rule, oldest first. Every change made through Save,
+	// Update, UpdateStatus, Delete and RestoreRevision appends a revision.
+	Revisions(ctx context.Context, key string) ([]model.RuleRevision, error)
+	Revision(ctx context.Context, key string, revision int) (model.RuleRevision, error)
+	// DiffRevisions lists the changes between the rules of two revisions of a rule.
+	DiffRevisions(ctx context.Context, key string, from, to int) (model.RuleDiff, error)
+	// RestoreRevision saves the rule of a revision again, which also brings back a deleted rule. The
+	// restore is recorded as a new revision, the history is never rewritten.
+	RestoreRevision(ctx context.Context, key string, revision int) (model.Rule, error)
 }
 
 type ruleService struct {
-	RuleRepository repository.RuleRepository
+	RuleRepository     repository.RuleRepository
+	RevisionRepository repository.RuleRevisionRepository
 }
 
-func NewRuleService(ruleRepository repository.RuleRepository) (RuleService, error) {
+func NewRuleService(ruleRepository repository.RuleRepository,
+	revisionRepository repository.RuleRevisionRepository,
+) (RuleService, error) {
 	if ruleRepository == nil {
 		return nil, fmt.Errorf("rule repository cannot be nil") //nolint:err113
 	}
 
+	if revisionRepository == nil {
+		return nil, fmt.Errorf("rule revision repository cannot be nil") //nolint:err113
+	}
+
 	return &ruleService{
-		RuleRepository: ruleRepository,
+		RuleRepository:     ruleRepository,
+		RevisionRepository: revisionRepository,
 	}, nil
 }
 
 func (ruleService *ruleService) Save(ctx context.Context, rule model.Rule) (model.Rule, error) {
+	return ruleService.save(ctx, rule, "")
+}
+
+// save creates or updates the rule and records the revision. The revision action is the one of the
+// repository call, unless revisionAction is given.
+func (ruleService *ruleService) save(ctx context.Context, rule model.Rule, revisionAction string) (model.Rule, error) {
 	logger := mockscontext.Logger(ctx)
 
 	logger.Debug(ruleService, nil, "Entering ruleService Save()")
@@ -62,16 +91,16 @@ func (ruleService *ruleService) Save(ctx context.Context, rule model.Rule) (mode
 		err      error
 	)
 
-	action := "creating"
+	action, repositoryAction := "creating", model.RuleRevisionCreate
 
 	if rule.Key == "" {
 		repoRule, err = ruleService.RuleRepository.Create(ctx, &rule)
 	} else {
-		action = "updating"
+		action, repositoryAction = "updating", model.RuleRevisionUpdate
 		repoRule, err = ruleService.RuleRepository.Update(ctx, &rule)
 
 		if err != nil && errors.As(err, &mockserrors.RuleNotFoundError{}) {
-			action = "creating"
+			action, repositoryAction = "creating", model.RuleRevisionCreate
 			repoRule, err = ruleService.RuleRepository.Create(ctx, &rule)
 		}
 	}
@@ -80,6 +109,12 @@ func (ruleService *ruleService) Save(ctx context.Context, rule model.Rule) (mode
 		return model.Rule{}, fmt.Errorf("error %s rule - %w", action, err)
 	}
 
+	if revisionAction == "" {
+		revisionAction = repositoryAction
+	}
+
+	ruleService.appendRevision(ctx, revisionAction, *repoRule)
+
 	return *repoRule, nil
 }
 
@@ -110,6 +145,25 @@ func (ruleService *ruleService) UpdateStatus(ctx context.Context, key string,
 	return ruleService.Save(ctx, rule)
 }
 
+func (ruleService *ruleService) NextResponse(ctx context.Context, rule model.Rule) (model.Response, error) {
+	logger := mockscontext.Logger(ctx)
+
+	logger.Debug(ruleService, nil, "Entering ruleService NextResponse()")
+
+	if len(rule.Responses) == 0 {
+		return model.Response{}, fmt.Errorf("rule %s has no responses", rule.Key) //nolint:err113
+	}
+
+	index, err := ruleService.RuleRepository.AdvanceResponse(ctx, rule.Key, len(rule.Responses))
+	if err != nil {
+		logger.Error(ruleService, nil, err, "error advancing rule response")
+
+		return model.Response{}, fmt.Errorf("error advancing rule response, %w", err)
+	}
+
+	return rule.Responses[index], nil
+}
+
 func (ruleService *ruleService) Get(ctx context.Context, key string) (model.Rule, error) {
 	logger := mockscontext.Logger(ctx)
 
@@ -140,19 +194,62 @@ func (ruleService *ruleService) Search(ctx context.Context, params map[string]in
 	return *ReposRules, nil
 }
 
-func (ruleService *ruleService) SearchByMethodAndPath(ctx context.Context, method, path string) (model.Rule, error) {
+// SearchByMethodAndPath returns the first enabled rule for method and path whose matchers all pass
+// for the given request.
+func (ruleService *ruleService) SearchByMethodAndPath(ctx context.Context, method, path string,
+	request *model.MatchRequest,
+) (model.Rule, error) {
 	logger := mockscontext.Logger(ctx)
 
 	logger.Debug(ruleService, nil, "Entering ruleService Search()")
 
-	result, err := ruleService.RuleRepository.SearchByMethodAndPath(ctx, method, path)
+	candidates, err := ruleService.RuleRepository.SearchByMethodAndPath(ctx, method, path)
 	if err != nil {
 		logger.Error(ruleService, nil, err, "error searching rules")
 
 		return model.Rule{}, fmt.Errorf("error searching rule, %w", err)
 	}
 
-	return *result, nil
+	for _, candidate := range candidates {
+		if candidate.MatchesRequest(request) {
+			return *candidate, nil
+		}
+	}
+
+	err = mockserrors.RuleNotFoundError{
+		Message: fmt.Sprintf("no rule found for path: %s and method %s", path, method),
+	}
+
+	logger.Error(ruleService, nil, err, "error searching rules")
+
+	return model.Rule{}, fmt.Errorf("error searching rule, %w", err)
+}
+
+// SearchCandidates lists every enabled rule for method and path in the order they are resolved.
+func (ruleService *ruleService) SearchCandidates(ctx context.Context, method, path string) (model.RuleList, error) {
+	logger := mockscontext.Logger(ctx)
+
+	logger.Debug(ruleService, nil, "Entering ruleService SearchCandidates()")
+
+	if err := validateHTTPMethod(method); err != nil {
+		return model.RuleList{}, err
+	}
+
+	if !strings.HasPrefix(path, "/") {
+		return model.RuleList{}, mockserrors.InvalidRulesError{
+			Message: "path must start with '/'",
+		}
+	}
+
+	candidates, err := ruleService.RuleRepository.SearchByMethodAndPath(ctx, strings.ToUpper(method), path)
+	if err != nil {
+		return model.RuleList{}, fmt.Errorf("error searching candidates - %w", err)
+	}
+
+	return model.RuleList{
+		Paging:  model.Paging{Total: int64(len(candidates)), Limit: int32(len(candidates))}, //nolint:gosec
+		Results: candidates,
+	}, nil
 }
 
 func (ruleService *ruleService) Delete(ctx context.Context, key string) error {
@@ -160,14 +257,114 @@ func (ruleService *ruleService) Delete(ctx context.Context, key string) error {
 
 	logger.Debug(ruleService, nil, "Entering TaskService Get()")
 
-	err := ruleService.RuleRepository.Delete(ctx, key)
+	// The revision of a delete keeps the deleted rule, so that it can be restored.
+	repoRule, err := ruleService.RuleRepository.Get(ctx, key)
+	if err != nil {
+		return fmt.Errorf("error deleting rule - %w", err)
+	}
+
+	err = ruleService.RuleRepository.Delete(ctx, key)
 	if err != nil {
 		return fmt.Errorf("error deleting rule - %w", err)
 	}
 
+	ruleService.appendRevision(ctx, model.RuleRevisionDelete, *repoRule)
+
 	return nil
 }
 
+func (ruleService *ruleService) Revisions(ctx context.Context, key string) ([]model.RuleRevision, error) {
+	logger := mockscontext.Logger(ctx)
+
+	logger.Debug(ruleService, nil, "Entering ruleService Revisions()")
+
+	revisions, err := ruleService.RevisionRepository.List(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("error listing revisions - %w", err)
+	}
+
+	// The rules saved before the history was kept have no revisions, only an unknown key is an error.
+	if len(revisions) == 0 {
+		if _, err := ruleService.RuleRepository.Get(ctx, key); err != nil {
+			return nil, fmt.Errorf("error getting rule, %w", err)
+		}
+	}
+
+	return revisions, nil
+}
+
+func (ruleService *ruleService) Revision(ctx context.Context, key string, revision int) (model.RuleRevision, error) {
+	logger := mockscontext.Logger(ctx)
+
+	logger.Debug(ruleService, nil, "Entering ruleService Revision()")
+
+	repoRevision, err := ruleService.RevisionRepository.Get(ctx, key, revision)
+	if err != nil {
+		return model.RuleRevision{}, fmt.Errorf("error getting revision - %w", err)
+	}
+
+	return *repoRevision, nil
+}
+
+func (ruleService *ruleService) DiffRevisions(ctx context.Context, key string, from, to int) (model.RuleDiff, error) {
+	logger := mockscontext.Logger(ctx)
+
+	logger.Debug(ruleService, nil, "Entering ruleService DiffRevisions()")
+
+	fromRevision, err := ruleService.Revision(ctx, key, from)
+	if err != nil {
+		return model.RuleDiff{}, err
+	}
+
+	toRevision, err := ruleService.Revision(ctx, key, to)
+	if err != nil {
+		return model.RuleDiff{}, err
+	}
+
+	changes, err := model.DiffRules(fromRevision.Rule, toRevision.Rule)
+	if err != nil {
+		return model.RuleDiff{}, fmt.Errorf("error comparing revisions - %w", err)
+	}
+
+	return model.RuleDiff{RuleKey: key, From: from, To: to, Changes: changes}, nil
+}
+
+func (ruleService *ruleService) RestoreRevision(ctx context.Context, key string, revision int) (model.Rule, error) {
+	logger := mockscontext.Logger(ctx)
+
+	logger.Debug(ruleService, nil, "Entering ruleService RestoreRevision()")
+
+	repoRevision, err := ruleService.Revision(ctx, key, revision)
+	if err != nil {
+		return model.Rule{}, err
+	}
+
+	rule := repoRevision.Rule
+	rule.Key = key
+
+	return ruleService.save(ctx, rule, model.RuleRevisionRestore)
+}
+
+// appendRevision records a change of a rule. The author is the authenticated caller, or the tracking id
+// of the request when auth is disabled. The change is already saved, so a failure is only logged.
+func (ruleService *ruleService) appendRevision(ctx context.Context, action string, rule model.Rule) {
+	author := mockscontext.Identity(ctx)
+	if author == "" {
+		author = mockscontext.TrackingID(ctx)
+	}
+
+	_, err := ruleService.RevisionRepository.Append(ctx, model.RuleRevision{
+		RuleKey:   rule.Key,
+		Action:    action,
+		Author:    author,
+		CreatedAt: time.Now().UTC(),
+		Rule:      rule,
+	})
+	if err != nil {
+		mockscontext.Logger(ctx).Error(ruleService, nil, err, "error saving revision of rule %s", rule.Key)
+	}
+}
+
 //nolint:cyclop
 func validateRule(rule model.Rule) error {
 	if rule.Name == "" {
@@ -194,16 +391,19 @@ func validateRule(rule model.Rule) error {
 		}
 	}
 
-	if err := validateHTTPMethod(rule.Method); err != nil {
-		return err
+	if rule.Strategy != model.RuleStrategyResource {
+		if err := validateHTTPMethod(rule.Method); err != nil {
+			return err
+		}
 	}
 
 	if rule.Strategy != "" && rule.Strategy != model.RuleStrategyNormal && rule.Strategy != model.RuleStrategyRandom &&
-		rule.Strategy != model.RuleStrategySequential && rule.Strategy != model.RuleStrategyScene {
+		rule.Strategy != model.RuleStrategySequential && rule.Strategy != model.RuleStrategyScene &&
+		rule.Strategy != model.RuleStrategyResource {
 		return mockserrors.InvalidRulesError{
-			Message: fmt.Sprintf("invalid rule strategy - only '%s', '%s', '%s' or '%s' are valid values",
+			Message: fmt.Sprintf("invalid rule strategy - only '%s', '%s', '%s', '%s' or '%s' are valid values",
 				model.RuleStrategyNormal, model.RuleStrategyRandom, model.RuleStrategySequential,
-				model.RuleStrategyScene),
+				model.RuleStrategyScene, model.RuleStrategyResource),
 		}
 	}
 
@@ -211,9 +411,68 @@ func validateRule(rule model.Rule) error {
 		return err
 	}
 
+	if err := validateMatchers(rule.Matchers); err != nil {
+		return err
+	}
+
+	if rule.Scenario != nil {
+		if err := rule.Scenario.Validate(); err != nil {
+			return err //nolint:wrapcheck
+		}
+	}
+
+	if rule.Chaos != nil {
+		if err := rule.Chaos.Validate(); err != nil {
+			return err //nolint:wrapcheck
+		}
+	}
+
+	if rule.Strategy == model.RuleStrategyResource {
+		return validateResource(rule)
+	}
+
+	if rule.Resource != nil {
+		return mockserrors.InvalidRulesError{
+			Message: fmt.Sprintf("resource is only allowed with the '%s' strategy", model.RuleStrategyResource),
+		}
+	}
+
 	return validateResponses(rule.Responses)
 }
 
+// validateResource validates a rule that serves a REST collection. Its responses are not used, and it
+// answers to every CRUD method, so the method can only be left empty or set to ANY.
+func validateResource(rule model.Rule) error {
+	if rule.Method != "" && !strings.EqualFold(rule.Method, model.RuleMethodAny) {
+		return mockserrors.InvalidRulesError{
+			Message: fmt.Sprintf("resource rules answer to every CRUD method, method must be empty or '%s'",
+				model.RuleMethodAny),
+		}
+	}
+
+	if rule.Path != "/" && strings.HasSuffix(rule.Path, "/") {
+		return mockserrors.InvalidRulesError{
+			Message: "resource path cannot end with '/'",
+		}
+	}
+
+	if rule.Resource == nil {
+		return nil
+	}
+
+	return rule.Resource.Validate() //nolint:wrapcheck
+}
+
+func validateMatchers(matchers []model.Matcher) error {
+	for _, matcher := range matchers {
+		if err := matcher.Validate(); err != nil {
+			return err //nolint:wrapcheck
+		}
+	}
+
+	return nil
+}
+
 func validateResponses(responses []model.Response) error {
 	if len(responses) == 0 {
 		return mockserrors.InvalidRulesError{
@@ -227,6 +486,28 @@ func validateResponses(responses []model.Response) error {
 				Message: fmt.Sprintf("%v is not a valid HTTP Status", response.HTTPStatus),
 			}
 		}
+
+		if err := response.ValidateHeaders(); err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		if err := response.ValidateFault(); err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		if err := response.ValidateWebhooks(); err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		if err := validateResponseTemplate(response); err != nil {
+			return err
+		}
+
+		for _, cookie := range response.Cookies {
+			if err := cookie.Validate(); err != nil {
+				return err //nolint:wrapcheck
+			}
+		}
 	}
 
 	return nil
@@ -267,6 +548,14 @@ func validateHTTPMethod(method string) error {
 func formatRule(rule model.Rule) model.Rule {
 	rule.Method = strings.ToUpper(rule.Method)
 
+	if rule.Strategy == model.RuleStrategyResource {
+		rule.Method = model.RuleMethodAny
+
+		if rule.Responses == nil {
+			rule.Responses = make([]model.Response, 0)
+		}
+	}
+
 	if rule.Status == "" {
 		rule.Status = model.RuleStatusEnabled
 	}
@@ -2,14 +2,18 @@ package service_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
 
 	mockscontext "github.com/nicopozo/mockserver/internal/context"
 	mockserrors "github.com/nicopozo/mockserver/internal/errors"
 	"github.com/nicopozo/mockserver/internal/model"
+	"github.com/nicopozo/mockserver/internal/repository"
 	"github.com/nicopozo/mockserver/internal/service"
 	"github.com/nicopozo/mockserver/internal/utils/test/mocks"
 	"github.com/stretchr/testify/assert"
@@ -357,7 +361,7 @@ func TestRuleService_Save(t *testing.T) {
 				},
 			},
 			wantedErr: mockserrors.InvalidRulesError{
-				Message: "invalid rule strategy - only 'normal', 'random', 'sequential' or 'scene' are valid values",
+				Message: "invalid rule strategy - only 'normal', 'random', 'sequential', 'scene' or 'resource' are valid values",
 			},
 			repositoryErr:    nil,
 			serviceCallTimes: 0,
@@ -409,6 +413,174 @@ func TestRuleService_Save(t *testing.T) {
 			repositoryErr:    nil,
 			serviceCallTimes: 0,
 		},
+		{
+			name: "Should return InvalidRuleError rule when response has invalid template engine",
+			args: args{
+				ctx: mockscontext.Background(),
+				rule: model.Rule{
+					Group:    "myapp",
+					Name:     "test_mock",
+					Path:     "/test",
+					Strategy: "normal",
+					Method:   "put",
+					Status:   "enabled",
+					Responses: []model.Response{
+						{
+							Body:        "{\"balance\":5000}",
+							ContentType: "application/json",
+							HTTPStatus:  http.StatusOK,
+							Template:    "handlebars",
+						},
+					},
+				},
+			},
+			wantedErr: mockserrors.InvalidRulesError{
+				Message: "invalid response template - only 'go' is a valid value",
+			},
+			repositoryErr:    nil,
+			serviceCallTimes: 0,
+		},
+		{
+			name: "Should return InvalidRuleError rule when response body template does not parse",
+			args: args{
+				ctx: mockscontext.Background(),
+				rule: model.Rule{
+					Group:    "myapp",
+					Name:     "test_mock",
+					Path:     "/test",
+					Strategy: "normal",
+					Method:   "put",
+					Status:   "enabled",
+					Responses: []model.Response{
+						{
+							Body:        "{\"balance\":{{.Request.JSON.balance}",
+							ContentType: "application/json",
+							HTTPStatus:  http.StatusOK,
+							Template:    model.ResponseTemplateGo,
+						},
+					},
+				},
+			},
+			wantedErr: mockserrors.InvalidRulesError{
+				Message: "invalid body template: template: body:1: bad character U+007D '}'",
+			},
+			repositoryErr:    nil,
+			serviceCallTimes: 0,
+		},
+		{
+			name: "Should return InvalidRuleError rule when response has invalid header name",
+			args: args{
+				ctx: mockscontext.Background(),
+				rule: model.Rule{
+					Group:    "myapp",
+					Name:     "test_mock",
+					Path:     "/test",
+					Strategy: "normal",
+					Method:   "put",
+					Status:   "enabled",
+					Responses: []model.Response{
+						{
+							Body:        "{\"balance\":5000}",
+							ContentType: "application/json",
+							HTTPStatus:  http.StatusOK,
+							Headers:     map[string]string{"Invalid Header": "value"},
+						},
+					},
+				},
+			},
+			wantedErr: mockserrors.InvalidRulesError{
+				Message: "'Invalid Header' is not a valid header name",
+			},
+			repositoryErr:    nil,
+			serviceCallTimes: 0,
+		},
+		{
+			name: "Should return InvalidRuleError rule when response has invalid cookie",
+			args: args{
+				ctx: mockscontext.Background(),
+				rule: model.Rule{
+					Group:    "myapp",
+					Name:     "test_mock",
+					Path:     "/test",
+					Strategy: "normal",
+					Method:   "put",
+					Status:   "enabled",
+					Responses: []model.Response{
+						{
+							Body:        "{\"balance\":5000}",
+							ContentType: "application/json",
+							HTTPStatus:  http.StatusOK,
+							Cookies:     []model.Cookie{{Name: "session", Value: "abc", SameSite: "sometimes"}},
+						},
+					},
+				},
+			},
+			wantedErr: mockserrors.InvalidRulesError{
+				Message: "cookie same_site must be 'lax', 'strict' or 'none'",
+			},
+			repositoryErr:    nil,
+			serviceCallTimes: 0,
+		},
+		{
+			name: "Should return InvalidRuleError rule when response has invalid fault",
+			args: args{
+				ctx: mockscontext.Background(),
+				rule: model.Rule{
+					Group:    "myapp",
+					Name:     "test_mock",
+					Path:     "/test",
+					Strategy: "normal",
+					Method:   "put",
+					Status:   "enabled",
+					Responses: []model.Response{
+						{
+							Body:        "{\"balance\":5000}",
+							ContentType: "application/json",
+							HTTPStatus:  http.StatusOK,
+							Fault:       "timeout",
+						},
+					},
+				},
+			},
+			wantedErr: mockserrors.InvalidRulesError{
+				Message: "'timeout' is not a valid fault, valid values are: empty_reply, connection_reset, " +
+					"random_data, truncated_body, malformed_chunk, stall_after_headers",
+			},
+			repositoryErr:    nil,
+			serviceCallTimes: 0,
+		},
+		{
+			name: "Should return InvalidRuleError rule when webhook retry has no attempts",
+			args: args{
+				ctx: mockscontext.Background(),
+				rule: model.Rule{
+					Group:    "myapp",
+					Name:     "test_mock",
+					Path:     "/test",
+					Strategy: "normal",
+					Method:   "put",
+					Status:   "enabled",
+					Responses: []model.Response{
+						{
+							Body:        "{\"balance\":5000}",
+							ContentType: "application/json",
+							HTTPStatus:  http.StatusOK,
+							Webhook: &model.WebhookConfig{
+								URL:     "http://localhost/hook",
+								Method:  http.MethodPost,
+								Enabled: true,
+								Retry:   &model.WebhookRetry{MaxAttempts: 0},
+							},
+						},
+					},
+				},
+			},
+			wantedErr: mockserrors.InvalidRulesError{
+				Message: "webhook max_attempts must be between 1 and 10",
+			},
+			repositoryErr:    nil,
+			serviceCallTimes: 0,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -428,7 +600,8 @@ func TestRuleService_Save(t *testing.T) {
 					return rule, nil
 				}).Times(tt.serviceCallTimes)
 
-			ruleService, err := service.NewRuleService(ruleRepositoryMock)
+			ruleService, err := service.NewRuleService(ruleRepositoryMock,
+				newRevisionRepositoryMock(t, mockCtrl, model.RuleRevisionCreate, appendedRevisions(tt.repositoryErr, tt.serviceCallTimes)))
 			assert.Nil(t, err)
 
 			got, err := ruleService.Save(tt.args.ctx, tt.args.rule)
@@ -526,7 +699,8 @@ func TestRuleService_Get(t *testing.T) {
 			ruleRepositoryMock.EXPECT().Get(gomock.Any(), tt.args.key).
 				Return(tt.repositoryRule, tt.repositoryErr).Times(tt.serviceCallTimes)
 
-			ruleService, err := service.NewRuleService(ruleRepositoryMock)
+			ruleService, err := service.NewRuleService(ruleRepositoryMock,
+				mocks.NewMockRuleRevisionRepository(mockCtrl))
 			assert.Nil(t, err)
 
 			got, err := ruleService.Get(tt.args.ctx, tt.args.key)
@@ -603,9 +777,12 @@ func TestRuleService_Delete(t *testing.T) {
 			ruleRepositoryMock := mocks.NewMockRuleRepository(mockCtrl)
 			defer mockCtrl.Finish()
 
+			ruleRepositoryMock.EXPECT().Get(gomock.Any(), tt.args.key).Return(&model.Rule{Key: tt.args.key}, nil).
+				Times(tt.serviceCallTimes)
 			ruleRepositoryMock.EXPECT().Delete(gomock.Any(), tt.args.key).Return(tt.repositoryErr).Times(tt.serviceCallTimes)
 
-			ruleService, err := service.NewRuleService(ruleRepositoryMock)
+			ruleService, err := service.NewRuleService(ruleRepositoryMock,
+				newRevisionRepositoryMock(t, mockCtrl, model.RuleRevisionDelete, appendedRevisions(tt.repositoryErr, tt.serviceCallTimes)))
 			assert.Nil(t, err)
 
 			err = ruleService.Delete(tt.args.ctx, tt.args.key)
@@ -721,7 +898,8 @@ func TestRuleService_Update(t *testing.T) {
 					return rule, nil
 				}).Times(tt.serviceCallTimes)
 
-			ruleService, err := service.NewRuleService(ruleRepositoryMock)
+			ruleService, err := service.NewRuleService(ruleRepositoryMock,
+				newRevisionRepositoryMock(t, mockCtrl, model.RuleRevisionUpdate, appendedRevisions(tt.repositoryErr, tt.serviceCallTimes)))
 			assert.Nil(t, err)
 
 			got, err := ruleService.Update(tt.args.ctx, tt.args.key, tt.args.rule)
@@ -736,3 +914,194 @@ func TestRuleService_Update(t *testing.T) {
 		})
 	}
 }
+
+func TestRuleService_SearchByMethodAndPath(t *testing.T) {
+	refundRule := &model.Rule{
+		Key:      "refund",
+		Path:     "/v1/payments",
+		Method:   "POST",
+		Status:   "enabled",
+		Matchers: []model.Matcher{{Type: "query", Key: "type", Operator: "equals", Value: "refund"}},
+	}
+	argentinaRule := &model.Rule{
+		Key:      "argentina",
+		Path:     "/v1/payments",
+		Method:   "POST",
+		Status:   "enabled",
+		Matchers: []model.Matcher{{Type: "header", Key: "X-Country", Operator: "equals", Value: "AR"}},
+	}
+	defaultRule := &model.Rule{
+		Key:    "default",
+		Path:   "/v1/payments",
+		Method: "POST",
+		Status: "enabled",
+	}
+
+	tests := []struct {
+		name       string
+		candidates []*model.Rule
+		request    *model.MatchRequest
+		wantKey    string
+		wantedErr  error
+	}{
+		{
+			name:       "Should return the rule whose query matcher passes",
+			candidates: []*model.Rule{refundRule, argentinaRule, defaultRule},
+			request:    &model.MatchRequest{Query: url.Values{"type": {"refund"}}},
+			wantKey:    "refund",
+		},
+		{
+			name:       "Should return the rule whose header matcher passes",
+			candidates: []*model.Rule{refundRule, argentinaRule, defaultRule},
+			request:    &model.MatchRequest{Headers: http.Header{"X-Country": {"AR"}}},
+			wantKey:    "argentina",
+		},
+		{
+			name:       "Should fall back to the rule without matchers",
+			candidates: []*model.Rule{refundRule, argentinaRule, defaultRule},
+			request:    &model.MatchRequest{Headers: http.Header{"X-Country": {"BR"}}},
+			wantKey:    "default",
+		},
+		{
+			name:       "Should return RuleNotFoundError when no matchers pass",
+			candidates: []*model.Rule{refundRule, argentinaRule},
+			request:    &model.MatchRequest{},
+			wantedErr: mockserrors.RuleNotFoundError{
+				Message: "no rule found for path: /v1/payments and method POST",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+
+			ruleRepositoryMock := mocks.NewMockRuleRepository(mockCtrl)
+			defer mockCtrl.Finish()
+
+			ruleRepositoryMock.EXPECT().SearchByMethodAndPath(gomock.Any(), "POST", "/v1/payments").
+				Return(tt.candidates, nil)
+
+			ruleService, err := service.NewRuleService(ruleRepositoryMock,
+				mocks.NewMockRuleRevisionRepository(mockCtrl))
+			assert.Nil(t, err)
+
+			got, err := ruleService.SearchByMethodAndPath(mockscontext.Background(), "POST", "/v1/payments", tt.request)
+			if tt.wantedErr != nil {
+				assert.ErrorIs(t, err, tt.wantedErr)
+
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.wantKey, got.Key)
+		})
+	}
+}
+
+func TestRuleService_Revisions(t *testing.T) {
+	cfg := newScenarioConfig(t)
+
+	ruleRepo, err := repository.NewRuleFileRepository(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ruleService, err := service.NewRuleService(ruleRepo, newRevisionRepository(t, cfg))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	request := httptest.NewRequest(http.MethodPut, "/mock-service/rules/k1", nil)
+	request.Header.Set("x-tracking-id", "tracking-1")
+
+	trackedCtx := mockscontext.New(request)
+	authCtx := mockscontext.WithIdentity(trackedCtx, "ci-pipeline")
+
+	rule, err := ruleService.Save(trackedCtx, model.Rule{
+		Name: "get payment", Path: "/v1/payments", Method: http.MethodGet,
+		Responses: []model.Response{{Body: `{"id":1}`, HTTPStatus: http.StatusOK}},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	broken := rule
+	broken.Responses = []model.Response{{Body: `{"id":1}`, HTTPStatus: http.StatusInternalServerError}}
+
+	_, err = ruleService.Update(authCtx, rule.Key, broken)
+	assert.NoError(t, err)
+	assert.NoError(t, ruleService.Delete(authCtx, rule.Key))
+
+	revisions, err := ruleService.Revisions(trackedCtx, rule.Key)
+	if assert.NoError(t, err) && assert.Len(t, revisions, 3) {
+		assert.Equal(t, model.RuleRevisionCreate, revisions[0].Action)
+		assert.Equal(t, "tracking-1", revisions[0].Author, "the tracking id is the author without auth")
+		assert.Equal(t, model.RuleRevisionUpdate, revisions[1].Action)
+		assert.Equal(t, "ci-pipeline", revisions[1].Author, "the authenticated caller is the author")
+		assert.Equal(t, model.RuleRevisionDelete, revisions[2].Action)
+		assert.Equal(t, http.StatusInternalServerError, revisions[2].Rule.Responses[0].HTTPStatus,
+			"the revision of a delete keeps the deleted rule")
+		assert.False(t, revisions[2].CreatedAt.IsZero())
+	}
+
+	diff, err := ruleService.DiffRevisions(trackedCtx, rule.Key, 1, 2)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []model.RuleChange{{
+			Op: model.RuleChangeReplace, Path: "/responses/0/http_status",
+			OldValue: json.Number("200"), Value: json.Number("500"),
+		}}, diff.Changes)
+	}
+
+	// Restoring brings the deleted rule back with its key, and is recorded as a new revision.
+	restored, err := ruleService.RestoreRevision(authCtx, rule.Key, 1)
+	if assert.NoError(t, err) {
+		assert.Equal(t, rule, restored)
+	}
+
+	got, err := ruleService.Get(trackedCtx, rule.Key)
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusOK, got.Responses[0].HTTPStatus)
+	}
+
+	revision, err := ruleService.Revision(trackedCtx, rule.Key, 4)
+	if assert.NoError(t, err) {
+		assert.Equal(t, model.RuleRevisionRestore, revision.Action)
+	}
+
+	_, err = ruleService.RestoreRevision(authCtx, rule.Key, 9)
+	assert.ErrorAs(t, err, &mockserrors.RuleRevisionNotFoundError{})
+
+	_, err = ruleService.Revisions(trackedCtx, "unknown")
+	assert.ErrorAs(t, err, &mockserrors.RuleNotFoundError{})
+}
+
+// newRevisionRepositoryMock expects a revision with the given action to be appended times times.
+func newRevisionRepositoryMock(t *testing.T, mockCtrl *gomock.Controller, action string,
+	times int,
+) *mocks.MockRuleRevisionRepository {
+	t.Helper()
+
+	revisionRepositoryMock := mocks.NewMockRuleRevisionRepository(mockCtrl)
+
+	revisionRepositoryMock.EXPECT().Append(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, revision model.RuleRevision) (model.RuleRevision, error) {
+			assert.Equal(t, action, revision.Action)
+
+			revision.Revision = 1
+
+			return revision, nil
+		}).Times(times)
+
+	return revisionRepositoryMock
+}
+
+// appendedRevisions is the number of revisions appended by calls to the repository that fail with
+// repositoryErr.
+func appendedRevisions(repositoryErr error, serviceCallTimes int) int {
+	if repositoryErr != nil {
+		return 0
+	}
+
+	return serviceCallTimes
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
+	"github.com/nicopozo/mockserver/internal/model"
+	"github.com/nicopozo/mockserver/internal/repository"
+)
+
+const scenarioRulesPageSize = 1000
+
+//go:generate mockgen -destination=../utils/test/mocks/scenario_service_mock.go -package=mocks -source=./scenario_service.go
+
+// ScenarioService keeps track of the state of the scenarios that rules take part in.
+type ScenarioService interface {
+	List(ctx context.Context) ([]model.Scenario, error)
+	Get(ctx context.Context, name string) (model.Scenario, error)
+	State(ctx context.Context, name string) (string, error)
+	SetState(ctx context.Context, name, state string) (model.Scenario, error)
+	// Transition moves the scenario to state to only while it is still in state from, and reports whether
+	// it moved.
+	Transition(ctx context.Context, name, from, to string) (bool, error)
+	Reset(ctx context.Context, name string) error
+	ResetAll(ctx context.Context) error
+}
+
+type scenarioService struct {
+	ruleService RuleService
+	repository  repository.ScenarioRepository
+}
+
+func NewScenarioService(ruleService RuleService, scenarioRepository repository.ScenarioRepository,
+) (ScenarioService, error) {
+	if ruleService == nil {
+		return nil, fmt.Errorf("rule service cannot be nil") //nolint:err113
+	}
+
+	if scenarioRepository == nil {
+		return nil, fmt.Errorf("scenario repository cannot be nil") //nolint:err113
+	}
+
+	return &scenarioService{
+		ruleService: ruleService,
+		repository:  scenarioRepository,
+	}, nil
+}
+
+// List returns every scenario referenced by a rule or with a stored state, sorted by name.
+func (svc *scenarioService) List(ctx context.Context) ([]model.Scenario, error) {
+	scenarios, err := svc.collect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]model.Scenario, 0, len(scenarios))
+	for _, scenario := range scenarios {
+		result = append(result, *scenario)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+
+	return result, nil
+}
+
+// Get returns the scenario with its current state, or a ScenarioNotFoundError when no rule references it
+// and it has no stored state.
+func (svc *scenarioService) Get(ctx context.Context, name string) (model.Scenario, error) {
+	scenarios, err := svc.collect(ctx)
+	if err != nil {
+		return model.Scenario{}, err
+	}
+
+	scenario, found := scenarios[name]
+	if !found {
+		return model.Scenario{}, mockserrors.ScenarioNotFoundError{Message: "no scenario found with name: " + name}
+	}
+
+	return *scenario, nil
+}
+
+// State returns the current state of the scenario, which is Started unless a rule or a call to SetState
+// moved it.
+func (svc *scenarioService) State(ctx context.Context, name string) (string, error) {
+	scenario, err := svc.repository.Get(ctx, name)
+	if errors.As(err, &mockserrors.ScenarioNotFoundError{}) {
+		return model.ScenarioStateStarted, nil
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("error reading scenario state, %w", err)
+	}
+
+	return scenario.State, nil
+}
+
+func (svc *scenarioService) SetState(ctx context.Context, name, state string) (model.Scenario, error) {
+	if err := (model.ScenarioState{State: state}).Validate(); err != nil {
+		return model.Scenario{}, err //nolint:wrapcheck
+	}
+
+	scenario := model.Scenario{Name: name, State: state, UpdatedAt: time.Now().UTC()}
+
+	if err := svc.repository.Save(ctx, scenario); err != nil {
+		return model.Scenario{}, fmt.Errorf("error saving scenario state, %w", err)
+	}
+
+	return scenario, nil
+}
+
+func (svc *scenarioService) Transition(ctx context.Context, name, from, to string) (bool, error) {
+	scenario := model.Scenario{Name: name, State: to, UpdatedAt: time.Now().UTC()}
+
+	moved, err := svc.repository.CompareAndSet(ctx, scenario, from)
+	if err != nil {
+		return false, fmt.Errorf("error saving scenario state, %w", err)
+	}
+
+	return moved, nil
+}
+
+// Reset moves the scenario back to Started.
+func (svc *scenarioService) Reset(ctx context.Context, name string) error {
+	if err := svc.repository.Delete(ctx, name); err != nil {
+		return fmt.Errorf("error resetting scenario, %w", err)
+	}
+
+	return nil
+}
+
+// ResetAll moves every scenario back to Started.
+func (svc *scenarioService) ResetAll(ctx context.Context) error {
+	if err := svc.repository.DeleteAll(ctx); err != nil {
+		return fmt.Errorf("error resetting scenarios, %w", err)
+	}
+
+	return nil
+}
+
+// collect merges the scenarios referenced by the rules with the stored states.
+func (svc *scenarioService) collect(ctx context.Context) (map[string]*model.Scenario, error) {
+	scenarios := make(map[string]*model.Scenario)
+	states := make(map[string]map[string]bool)
+
+	scenarioFor := func(name string) *model.Scenario {
+		scenario, found := scenarios[name]
+		if !found {
+			scenario = &model.Scenario{Name: name, State: model.ScenarioStateStarted}
+			scenarios[name] = scenario
+			states[name] = map[string]bool{model.ScenarioStateStarted: true}
+		}
+
+		return scenario
+	}
+
+	paging := model.Paging{Limit: scenarioRulesPageSize}
+
+	for {
+		rules, err := svc.ruleService.Search(ctx, nil, paging)
+		if err != nil {
+			return nil, fmt.Errorf("error searching rules, %w", err)
+		}
+
+		for _, rule := range rules.Results {
+			if rule.Scenario == nil {
+				continue
+			}
+
+			scenario := scenarioFor(rule.Scenario.Name)
+			scenario.Rules = append(scenario.Rules, rule.Key)
+
+			for _, state := range []string{rule.Scenario.RequiredState, rule.Scenario.NewState} {
+				if state != "" {
+					states[scenario.Name][state] = true
+				}
+			}
+		}
+
+		if rules.Paging.LastID == "" {
+			break
+		}
+
+		paging.LastID = rules.Paging.LastID
+	}
+
+	stored, err := svc.repository.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing scenario states, %w", err)
+	}
+
+	for _, storedScenario := range stored {
+		scenario := scenarioFor(storedScenario.Name)
+		scenario.State = storedScenario.State
+		scenario.UpdatedAt = storedScenario.UpdatedAt
+		states[scenario.Name][storedScenario.State] = true
+	}
+
+	for name, scenario := range scenarios {
+		for state := range states[name] {
+			scenario.States = append(scenario.States, state)
+		}
+
+		sort.Strings(scenario.States)
+		sort.Strings(scenario.Rules)
+	}
+
+	return scenarios, nil
+}
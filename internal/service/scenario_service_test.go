@@ -0,0 +1,358 @@
+package service_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nicopozo/mockserver/internal/configs"
+	mockscontext "github.com/nicopozo/mockserver/internal/context"
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
+	"github.com/nicopozo/mockserver/internal/model"
+	"github.com/nicopozo/mockserver/internal/repository"
+	"github.com/nicopozo/mockserver/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func newScenarioConfig(t *testing.T) *configs.Config {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	return &configs.Config{
+		MocksFile:       filepath.Join(dir, "mocks.json"),
+		ScenariosFile:   filepath.Join(dir, "scenarios.json"),
+		ResourcesFile:   filepath.Join(dir, "resources.json"),
+		ChaosFile:       filepath.Join(dir, "chaos.json"),
+		WebhookJobsFile: filepath.Join(dir, "webhook-jobs.json"),
+		BinsFile:        filepath.Join(dir, "bins.json"),
+		RevisionsFile:   filepath.Join(dir, "revisions.json"),
+	}
+}
+
+func newRevisionRepository(t *testing.T, cfg *configs.Config) repository.RuleRevisionRepository {
+	t.Helper()
+
+	revisionRepo, err := repository.NewRuleRevisionFileRepository(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return revisionRepo
+}
+
+func newScenarioServices(t *testing.T, cfg *configs.Config) (service.RuleService, service.ScenarioService) {
+	t.Helper()
+
+	ruleRepo, err := repository.NewRuleFileRepository(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ruleService, err := service.NewRuleService(ruleRepo, newRevisionRepository(t, cfg))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarioRepo, err := repository.NewScenarioFileRepository(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarioService, err := service.NewScenarioService(ruleService, scenarioRepo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return ruleService, scenarioService
+}
+
+func newScenarioService(t *testing.T) service.ScenarioService {
+	t.Helper()
+
+	_, scenarioService := newScenarioServices(t, newScenarioConfig(t))
+
+	return scenarioService
+}
+
+func scenarioRule(name, requiredState, newState, body string) model.Rule {
+	return model.Rule{
+		Group:    "orders",
+		Name:     name,
+		Path:     "/orders/{id}",
+		Method:   http.MethodGet,
+		Strategy: model.RuleStrategyNormal,
+		Status:   model.RuleStatusEnabled,
+		Scenario: &model.RuleScenario{Name: "checkout", RequiredState: requiredState, NewState: newState},
+		Responses: []model.Response{
+			{Body: body, ContentType: "text/plain", HTTPStatus: http.StatusOK},
+		},
+	}
+}
+
+func TestScenarioService_State(t *testing.T) {
+	ctx := mockscontext.Background()
+	cfg := newScenarioConfig(t)
+	_, scenarioService := newScenarioServices(t, cfg)
+
+	state, err := scenarioService.State(ctx, "checkout")
+	assert.NoError(t, err)
+	assert.Equal(t, model.ScenarioStateStarted, state)
+
+	_, err = scenarioService.SetState(ctx, "checkout", "paid")
+	assert.NoError(t, err)
+
+	// The state is kept in the scenarios file, so it survives a restart.
+	_, restarted := newScenarioServices(t, cfg)
+
+	state, err = restarted.State(ctx, "checkout")
+	assert.NoError(t, err)
+	assert.Equal(t, "paid", state)
+
+	assert.NoError(t, restarted.Reset(ctx, "checkout"))
+
+	state, err = restarted.State(ctx, "checkout")
+	assert.NoError(t, err)
+	assert.Equal(t, model.ScenarioStateStarted, state)
+
+	_, err = restarted.SetState(ctx, "checkout", " ")
+	assert.ErrorAs(t, err, &mockserrors.InvalidRulesError{})
+}
+
+func TestScenarioService_ListAndGet(t *testing.T) {
+	ctx := mockscontext.Background()
+	ruleService, scenarioService := newScenarioServices(t, newScenarioConfig(t))
+
+	pending, err := ruleService.Save(ctx, scenarioRule("pending order", model.ScenarioStateStarted, "paid", "pending"))
+	assert.NoError(t, err)
+
+	paid, err := ruleService.Save(ctx, scenarioRule("paid order", "paid", "", "paid"))
+	assert.NoError(t, err)
+
+	_, err = scenarioService.SetState(ctx, "onboarding", "verified")
+	assert.NoError(t, err)
+
+	scenarios, err := scenarioService.List(ctx)
+	assert.NoError(t, err)
+
+	if assert.Len(t, scenarios, 2) {
+		assert.Equal(t, "checkout", scenarios[0].Name)
+		assert.Equal(t, model.ScenarioStateStarted, scenarios[0].State)
+		assert.Equal(t, []string{model.ScenarioStateStarted, "paid"}, scenarios[0].States)
+		assert.ElementsMatch(t, []string{pending.Key, paid.Key}, scenarios[0].Rules)
+
+		assert.Equal(t, "onboarding", scenarios[1].Name)
+		assert.Equal(t, "verified", scenarios[1].State)
+		assert.Empty(t, scenarios[1].Rules)
+	}
+
+	_, err = scenarioService.Get(ctx, "unknown")
+	assert.ErrorAs(t, err, &mockserrors.ScenarioNotFoundError{})
+
+	assert.NoError(t, scenarioService.ResetAll(ctx))
+
+	scenario, err := scenarioService.Get(ctx, "checkout")
+	assert.NoError(t, err)
+	assert.Equal(t, model.ScenarioStateStarted, scenario.State)
+
+	_, err = scenarioService.Get(ctx, "onboarding")
+	assert.ErrorAs(t, err, &mockserrors.ScenarioNotFoundError{})
+}
+
+func TestRuleService_SaveInvalidScenario(t *testing.T) {
+	ruleService, _ := newScenarioServices(t, newScenarioConfig(t))
+
+	tests := []struct {
+		name     string
+		scenario *model.RuleScenario
+		wantErr  string
+	}{
+		{name: "Should fail without name", scenario: &model.RuleScenario{NewState: "paid"}, wantErr: "scenario name cannot be empty"},
+		{
+			name:     "Should fail without states",
+			scenario: &model.RuleScenario{Name: "checkout"},
+			wantErr:  "scenario 'checkout' must set required_state, new_state or both",
+		},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			rule := scenarioRule("order", "", "", "body")
+			rule.Scenario = tt.scenario
+
+			_, err := ruleService.Save(mockscontext.Background(), rule)
+			assert.ErrorAs(t, err, &mockserrors.InvalidRulesError{})
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestScenarioService_Transition(t *testing.T) {
+	const workers = 20
+
+	ctx := mockscontext.Background()
+	_, scenarioService := newScenarioServices(t, newScenarioConfig(t))
+
+	var (
+		wg    sync.WaitGroup
+		moved atomic.Int32
+	)
+
+	for range workers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			ok, err := scenarioService.Transition(ctx, "checkout", model.ScenarioStateStarted, "paid")
+			if assert.NoError(t, err) && ok {
+				moved.Add(1)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, int32(1), moved.Load(), "only one worker moves the scenario from Started")
+
+	ok, err := scenarioService.Transition(ctx, "checkout", model.ScenarioStateStarted, "shipped")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = scenarioService.Transition(ctx, "checkout", "paid", "shipped")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	state, err := scenarioService.State(ctx, "checkout")
+	assert.NoError(t, err)
+	assert.Equal(t, "shipped", state)
+}
+
+func TestMockService_ScenarioTransitions(t *testing.T) {
+	ctx := mockscontext.Background()
+	ruleService, scenarioService := newScenarioServices(t, newScenarioConfig(t))
+
+	rules := []model.Rule{
+		scenarioRule("pending order", model.ScenarioStateStarted, "paid", "pending"),
+		scenarioRule("paid order", "paid", "shipped", "paid"),
+		scenarioRule("shipped order", "shipped", "", "shipped"),
+	}
+
+	for _, rule := range rules {
+		_, err := ruleService.Save(ctx, rule)
+		assert.NoError(t, err)
+	}
+
+	mockService, err := service.NewMockService(ruleService, newWebhookService(t), scenarioService,
+		newResourceService(t), newChaosService(t))
+	assert.NoError(t, err)
+
+	execute := func() string {
+		request := httptest.NewRequest(http.MethodGet, "/orders/1", nil)
+
+		response, _, err := mockService.SearchResponseForRequest(ctx, request, "/orders/1", "", "")
+		assert.NoError(t, err)
+
+		return response.Body
+	}
+
+	assert.Equal(t, "pending", execute())
+	assert.Equal(t, "paid", execute())
+	assert.Equal(t, "shipped", execute())
+	assert.Equal(t, "shipped", execute())
+
+	state, err := scenarioService.State(ctx, "checkout")
+	assert.NoError(t, err)
+	assert.Equal(t, "shipped", state)
+
+	assert.NoError(t, scenarioService.Reset(ctx, "checkout"))
+	assert.Equal(t, "pending", execute())
+}
+
+func TestMockService_ScenarioConcurrentRequests(t *testing.T) {
+	const requests = 20
+
+	ctx := mockscontext.Background()
+	cfg := newScenarioConfig(t)
+	ruleService, _ := newScenarioServices(t, cfg)
+
+	scenarioRepo, err := repository.NewScenarioFileRepository(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every request reads the state of the scenario before any of them moves it.
+	scenarioService, err := service.NewScenarioService(ruleService, slowScenarioRepository{scenarioRepo})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rules := []model.Rule{
+		scenarioRule("pending order", model.ScenarioStateStarted, "paid", "pending"),
+		scenarioRule("paid order", "paid", "shipped", "paid"),
+		scenarioRule("shipped order", "shipped", "", "shipped"),
+	}
+
+	for _, rule := range rules {
+		_, err := ruleService.Save(ctx, rule)
+		assert.NoError(t, err)
+	}
+
+	mockService, err := service.NewMockService(ruleService, newWebhookService(t), scenarioService,
+		newResourceService(t), newChaosService(t))
+	assert.NoError(t, err)
+
+	var (
+		wg    sync.WaitGroup
+		mutex sync.Mutex
+	)
+
+	served := make(map[string]int)
+	start := make(chan struct{})
+
+	for range requests {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			<-start
+
+			request := httptest.NewRequest(http.MethodGet, "/orders/1", nil)
+
+			response, _, err := mockService.SearchResponseForRequest(ctx, request, "/orders/1", "", "")
+			if assert.NoError(t, err) {
+				mutex.Lock()
+				served[response.Body]++
+				mutex.Unlock()
+			}
+		}()
+	}
+
+	close(start)
+	wg.Wait()
+
+	// Each transition is made by a single request, the others are matched again in the new state.
+	assert.Equal(t, map[string]int{"pending": 1, "paid": 1, "shipped": requests - 2}, served)
+
+	state, err := scenarioService.State(ctx, "checkout")
+	assert.NoError(t, err)
+	assert.Equal(t, "shipped", state)
+}
+
+type slowScenarioRepository struct {
+	repository.ScenarioRepository
+}
+
+func (repo slowScenarioRepository) Get(ctx context.Context, name string) (*model.Scenario, error) {
+	scenario, err := repo.ScenarioRepository.Get(ctx, name)
+
+	time.Sleep(10 * time.Millisecond)
+
+	return scenario, err //nolint:wrapcheck
+}
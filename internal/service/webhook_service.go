@@ -3,105 +3,521 @@ package service
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"maps"
+	"math/rand"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/nicopozo/mockserver/internal/configs"
 	mockscontext "github.com/nicopozo/mockserver/internal/context"
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
 	"github.com/nicopozo/mockserver/internal/model"
+	"github.com/nicopozo/mockserver/internal/repository"
+	"github.com/oklog/ulid/v2"
 )
 
-const defaultWebhookTimeout = 5 * time.Second
+const (
+	defaultWebhookTimeout = 5 * time.Second
+	// webhookPollInterval is how often the queue is checked for due jobs. Jobs queued by this instance
+	// also wake the workers up when they are due, so the interval matters for the jobs left by a previous
+	// run or by other instances.
+	webhookPollInterval = time.Second
+	// webhookJobLease is how long a job stays locked after its timeout, before another worker can pick it.
+	webhookJobLease = 30 * time.Second
+)
 
 //go:generate mockgen -destination=../utils/test/mocks/mock_service_webhook.go -package=mocks -source=./webhook_service.go
 
-// WebhookService defines the interface for firing webhooks.
+// WebhookService queues the webhooks fired by the responses and sends them with a pool of workers. The
+// queue is kept in the configured datasource, so pending deliveries survive a restart.
 type WebhookService interface {
-	Fire(
-		ctx context.Context,
-		webhook model.WebhookConfig,
-		variables []*model.Variable,
-		onResult func(model.WebhookResult),
-	)
+	Fire(ctx context.Context, logID string, webhooks []model.WebhookConfig, mode string, variables []*model.Variable)
+	Redeliver(ctx context.Context, ruleKey string, result model.WebhookResult) model.WebhookResult
+	ListJobs(ctx context.Context, status string) ([]model.WebhookJob, error)
+	CancelJob(ctx context.Context, id string) error
+	Start(ctx context.Context)
 }
 
-type webhookService struct{}
+type webhookService struct {
+	repo        repository.WebhookJobRepository
+	logService  LogService
+	ruleService RuleService
+	workers     int
+	wake        chan struct{}
+}
 
-// NewWebhookService creates a new WebhookService instance.
-func NewWebhookService() WebhookService {
-	return &webhookService{}
+// NewWebhookService creates a new WebhookService instance. Webhooks are queued right away, but they are
+// not sent until Start is called.
+func NewWebhookService(cfg *configs.Config, repo repository.WebhookJobRepository,
+	logService LogService, ruleService RuleService,
+) WebhookService {
+	return &webhookService{
+		repo:        repo,
+		logService:  logService,
+		ruleService: ruleService,
+		workers:     max(cfg.WebhookWorkers, 1),
+		wake:        make(chan struct{}, 1),
+	}
 }
 
+// Fire queues the webhooks, to be sent one after the other or all at once depending on mode. In
+// sequential mode the webhooks share a single job, so the variables taken from the response of a webhook
+// are available to the next ones. The results are recorded in the log entry logID.
 func (s *webhookService) Fire(
 	ctx context.Context,
-	webhook model.WebhookConfig,
+	logID string,
+	webhooks []model.WebhookConfig,
+	mode string,
 	variables []*model.Variable,
-	onResult func(model.WebhookResult),
 ) {
-	//nolint:gosec
-	go s.fireAsync(ctx, webhook, variables, onResult)
+	now := time.Now().UTC()
+	values := make([]model.WebhookJobVariable, 0, len(variables))
+
+	for _, variable := range variables {
+		values = append(values, model.WebhookJobVariable{Name: variable.Name, Value: variable.Value})
+	}
+
+	jobs := []model.WebhookJob{newWebhookJob(logID, webhooks, 0, values, now)}
+
+	if mode == model.WebhookModeParallel {
+		jobs = make([]model.WebhookJob, 0, len(webhooks))
+
+		for index, webhook := range webhooks {
+			jobs = append(jobs, newWebhookJob(logID, []model.WebhookConfig{webhook}, index, values, now))
+		}
+	}
+
+	for _, job := range jobs {
+		if err := s.repo.Save(ctx, job); err != nil {
+			mockscontext.Logger(ctx).Error(s, map[string]string{"log_id": logID}, err, "error queueing webhook")
+
+			continue
+		}
+
+		s.wakeAt(job.DueAt)
+	}
 }
 
-func (s *webhookService) fireAsync(
-	ctx context.Context,
-	webhook model.WebhookConfig,
-	variables []*model.Variable,
-	onResult func(model.WebhookResult),
-) {
+func newWebhookJob(logID string, webhooks []model.WebhookConfig, index int,
+	variables []model.WebhookJobVariable, now time.Time,
+) model.WebhookJob {
+	return model.WebhookJob{
+		ID:        ulid.Make().String(),
+		LogID:     logID,
+		Webhooks:  webhooks,
+		Index:     index,
+		Variables: variables,
+		Status:    model.WebhookJobPending,
+		DueAt:     now.Add(time.Duration(webhooks[0].Delay) * time.Millisecond),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Redeliver sends again the request recorded in result for the rule ruleKey, once, and returns the
+// outcome. It is sent like a retry of the webhook of the rule: with its timeout and, when it is signed,
+// signed again with the current time, since the recorded signature may be too old for the receiver.
+func (s *webhookService) Redeliver(ctx context.Context, ruleKey string,
+	result model.WebhookResult,
+) model.WebhookResult {
 	logger := mockscontext.Logger(ctx)
-	start := time.Now()
+	webhook := s.webhookOf(ctx, ruleKey, result)
+	headers := result.RequestHeaders
 
-	// Apply delay before sending the webhook, if configured.
-	if webhook.Delay > 0 {
-		logger.Debug(s, map[string]string{"delay_ms": fmt.Sprintf("%d", webhook.Delay)}, "delaying webhook execution")
-		time.Sleep(time.Duration(webhook.Delay) * time.Millisecond)
+	if webhook.Signature != nil {
+		headers = maps.Clone(headers)
+		maps.DeleteFunc(headers, func(name, _ string) bool { return strings.EqualFold(name, webhook.Signature.Header) })
 	}
 
-	// Use background context to prevent cancellation when parent request finishes.
-	webhookCtx := context.Background()
-	timeout := s.resolveTimeout(webhook.Timeout)
+	//nolint:contextcheck
+	redelivery, err := s.attempt(webhook, result.URL, headers, result.RequestBody)
+	if err != nil {
+		logger.Error(s, nil, err, "error creating webhook request")
+	}
 
-	webhookCtx, cancel := context.WithTimeout(webhookCtx, timeout)
-	defer cancel()
+	return redelivery
+}
+
+// webhookOf returns the webhook of the rule that result was sent for: the webhook at the same position
+// among the webhooks of a response, with the same method and, when it is signed, a signature header that
+// was sent. When the rule no longer has it, the webhook is made of the method of result alone.
+func (s *webhookService) webhookOf(ctx context.Context, ruleKey string,
+	result model.WebhookResult,
+) model.WebhookConfig {
+	recorded := model.WebhookConfig{Method: result.Method}
+
+	if ruleKey == "" {
+		return recorded
+	}
+
+	rule, err := s.ruleService.Get(ctx, ruleKey)
+	if err != nil {
+		mockscontext.Logger(ctx).Error(s, map[string]string{"rule_key": ruleKey}, err,
+			"error reading the rule of the webhook")
+
+		return recorded
+	}
+
+	for _, response := range rule.Responses {
+		webhooks := response.EnabledWebhooks()
+		if result.Webhook >= len(webhooks) || !strings.EqualFold(webhooks[result.Webhook].Method, result.Method) {
+			continue
+		}
+
+		webhook := webhooks[result.Webhook]
+		if webhook.Signature == nil {
+			return webhook
+		}
+
+		for name := range result.RequestHeaders {
+			if strings.EqualFold(name, webhook.Signature.Header) {
+				return webhook
+			}
+		}
+	}
+
+	return recorded
+}
+
+// ListJobs returns the queued webhooks with the given status, or all of them when status is empty.
+func (s *webhookService) ListJobs(ctx context.Context, status string) ([]model.WebhookJob, error) {
+	if err := model.ValidateWebhookJobStatus(status); err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	jobs, err := s.repo.List(ctx, status)
+	if err != nil {
+		return nil, fmt.Errorf("error reading webhook jobs, %w", err)
+	}
+
+	if jobs == nil {
+		jobs = []model.WebhookJob{}
+	}
+
+	return jobs, nil
+}
+
+// CancelJob removes a job from the queue, so that its pending webhooks are not sent. Failed jobs are
+// cancelled the same way once they are no longer needed.
+func (s *webhookService) CancelJob(ctx context.Context, id string) error {
+	if _, err := s.repo.Get(ctx, id); err != nil {
+		return fmt.Errorf("error reading webhook job, %w", err)
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("error cancelling webhook job, %w", err)
+	}
+
+	return nil
+}
+
+// Start runs the workers until ctx is done. The jobs that are already due, like the ones left by a
+// previous run, are picked up right away.
+func (s *webhookService) Start(ctx context.Context) {
+	jobs := make(chan model.WebhookJob)
+
+	for range s.workers {
+		go func() {
+			for job := range jobs {
+				s.process(ctx, job)
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+
+		ticker := time.NewTicker(webhookPollInterval)
+		defer ticker.Stop()
+
+		for {
+			s.dispatch(ctx, jobs)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-s.wake:
+			}
+		}
+	}()
+}
+
+// dispatch claims the due jobs and hands them to the workers, until there are no more due jobs.
+func (s *webhookService) dispatch(ctx context.Context, jobs chan<- model.WebhookJob) {
+	logger := mockscontext.Logger(ctx)
+
+	for {
+		now := time.Now().UTC()
+
+		due, err := s.repo.Due(ctx, now, s.workers)
+		if err != nil {
+			logger.Error(s, nil, err, "error reading due webhook jobs")
+
+			return
+		}
+
+		claimed := 0
+
+		for _, job := range due {
+			until := now.Add(s.resolveTimeout(job.Webhook().Timeout) + webhookJobLease)
+
+			ok, err := s.repo.Claim(ctx, job.ID, now, until)
+			if err != nil {
+				logger.Error(s, map[string]string{"job_id": job.ID}, err, "error claiming webhook job")
+			}
+
+			if !ok {
+				continue
+			}
+
+			claimed++
+
+			select {
+			case jobs <- job:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		if len(due) < s.workers || claimed == 0 {
+			return
+		}
+	}
+}
+
+// process makes an attempt to send the current webhook of job and records its result. The job is then
+// scheduled for a retry, moved to the next webhook, marked as failed or, when every webhook has been
+// delivered, removed.
+func (s *webhookService) process(ctx context.Context, job model.WebhookJob) {
+	logger := mockscontext.Logger(ctx)
+	webhook := job.Webhook()
+	variables := job.Variables
 
 	url := s.applyVariables(webhook.URL, variables)
 	body := s.applyVariables(webhook.Body, variables)
 	headers := s.resolveHeaders(webhook.Headers, variables)
 
+	job.Attempt++
+
 	//nolint:contextcheck
-	req, err := s.buildRequest(webhookCtx, webhook.Method, url, body)
+	result, err := s.attempt(webhook, url, headers, body)
 	if err != nil {
 		logger.Error(s, nil, err, "error creating webhook request")
-		notifyResult(onResult, url, webhook.Method, 0, time.Since(start), err.Error(), "")
+	}
+
+	result.Webhook = job.Index + job.Step
+	result.Attempt = job.Attempt
+	// A request that cannot be built will not get better on the next attempt.
+	result.Superseded = err == nil && job.Attempt < webhook.Retry.Attempts() && webhook.Retry.Retryable(result)
+
+	tags := map[string]string{
+		"job_id":      job.ID,
+		"webhook_url": url,
+		"status":      strconv.Itoa(result.StatusCode),
+		"attempt":     strconv.Itoa(job.Attempt),
+	}
+
+	// The job is left claimed when the result cannot be recorded, so the attempt is made again once
+	// its lease expires instead of the result being lost.
+	if err := s.record(job.LogID, result); err != nil {
+		logger.Error(s, tags, err, "error recording webhook result")
+
+		return
+	}
+
+	now := time.Now().UTC()
+	job.LockedUntil = time.Time{}
+	job.UpdatedAt = now
+	job.LastError = ""
+
+	switch {
+	case result.Superseded:
+		delay := webhook.Retry.Delay(job.Attempt, rand.Float64()) //nolint:gosec
+
+		logger.Info(s, tags, "webhook delivery failed, retrying in %s", delay)
+
+		job.DueAt = now.Add(delay)
+		job.LastError = webhookError(result)
+	case result.Failed():
+		logger.Info(s, tags, "webhook delivery failed")
+
+		job.Status = model.WebhookJobFailed
+		job.LastError = webhookError(result)
+	case job.Step+1 < len(job.Webhooks):
+		logger.Info(s, tags, "webhook sent")
+
+		job.Variables = append(job.Variables, s.extractVariables(ctx, webhook.Variables, result)...)
+		job.Step++
+		job.Attempt = 0
+		job.DueAt = now.Add(time.Duration(job.Webhook().Delay) * time.Millisecond)
+	default:
+		logger.Info(s, tags, "webhook sent")
+
+		if err := s.repo.Delete(ctx, job.ID); err != nil {
+			logger.Error(s, tags, err, "error removing delivered webhook job")
+		}
+
+		return
+	}
+
+	s.reschedule(ctx, job)
+}
+
+// reschedule stores the new state of job, unless it was cancelled while its webhook was being sent.
+func (s *webhookService) reschedule(ctx context.Context, job model.WebhookJob) {
+	logger := mockscontext.Logger(ctx)
+
+	if _, err := s.repo.Get(ctx, job.ID); err != nil {
+		if !errors.As(err, &mockserrors.WebhookJobNotFoundError{}) {
+			logger.Error(s, map[string]string{"job_id": job.ID}, err, "error reading webhook job")
+		}
+
+		return
+	}
+
+	if err := s.repo.Save(ctx, job); err != nil {
+		logger.Error(s, map[string]string{"job_id": job.ID}, err, "error saving webhook job")
 
 		return
 	}
 
+	if job.Status == model.WebhookJobPending {
+		s.wakeAt(job.DueAt)
+	}
+}
+
+// record attaches result to the log entry the webhook was fired for.
+func (s *webhookService) record(logID string, result model.WebhookResult) error {
+	if logID == "" {
+		return nil
+	}
+
+	//nolint:wrapcheck
+	return s.logService.Update(logID, func(entry *model.LogEntry) {
+		entry.WebhookResults = append(entry.WebhookResults, result)
+	})
+}
+
+// wakeAt wakes the workers up when a job is due at dueAt. The wake ups are coalesced, since a single
+// one makes the workers take every due job.
+func (s *webhookService) wakeAt(dueAt time.Time) {
+	time.AfterFunc(time.Until(dueAt), func() {
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	})
+}
+
+// extractVariables takes the values of the webhook variables from the response of the webhook. A variable
+// that cannot be taken is left out, so its placeholder stays in the next webhooks.
+func (s *webhookService) extractVariables(
+	ctx context.Context,
+	variables []*model.Variable,
+	result model.WebhookResult,
+) []model.WebhookJobVariable {
+	extracted := make([]model.WebhookJobVariable, 0, len(variables))
+
+	for _, variable := range variables {
+		value, err := jsonPathValue(variable.Key, result.ResponseBody)
+		if err != nil {
+			mockscontext.Logger(ctx).Error(s, map[string]string{"variable": variable.Name}, err,
+				"error taking variable from the webhook response")
+
+			continue
+		}
+
+		extracted = append(extracted, model.WebhookJobVariable{Name: variable.Name, Value: value})
+	}
+
+	return extracted
+}
+
+func webhookError(result model.WebhookResult) string {
+	if result.Error != "" {
+		return result.Error
+	}
+
+	return fmt.Sprintf("webhook answered with status %d", result.StatusCode)
+}
+
+// attempt sends webhook once to url, with its timeout and its signature. Both the attempts made from the
+// queue and the redeliveries go through it.
+func (s *webhookService) attempt(webhook model.WebhookConfig, url string, headers map[string]string,
+	body string,
+) (model.WebhookResult, error) {
+	return s.send(s.resolveTimeout(webhook.Timeout), webhook.Method, url, headers, body, webhook.Signature)
+}
+
+// send delivers the webhook once, signed with signature when it is not nil. The returned error is only
+// set when the request cannot be built; a failed delivery is reported in the result.
+func (s *webhookService) send(
+	timeout time.Duration,
+	method, url string,
+	headers map[string]string,
+	body string,
+	signature *model.WebhookSignature,
+) (model.WebhookResult, error) {
+	sentAt := time.Now().UTC()
+
+	// Every attempt is signed again, since schemes like Stripe's reject old timestamps.
+	if signature != nil {
+		headers = maps.Clone(headers)
+		if headers == nil {
+			headers = make(map[string]string, 1)
+		}
+
+		headers[signature.Header] = signature.Sign(body, sentAt)
+	}
+
+	result := model.WebhookResult{
+		URL:            url,
+		Method:         method,
+		Timestamp:      sentAt,
+		RequestHeaders: headers,
+		RequestBody:    body,
+	}
+
+	// Use background context to prevent cancellation when parent request finishes.
+	webhookCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	req, err := s.buildRequest(webhookCtx, method, url, body)
+	if err != nil {
+		result.Error = err.Error()
+
+		return result, err
+	}
+
 	for k, v := range headers {
 		req.Header.Set(k, v)
 	}
 
+	start := time.Now()
+
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		logger.Error(s, nil, err, "error sending webhook")
-		notifyResult(onResult, url, webhook.Method, 0, time.Since(start), err.Error(), "")
+		result.Error = err.Error()
+		result.DurationMs = time.Since(start).Milliseconds()
 
-		return
+		return result, nil
 	}
 
 	defer resp.Body.Close()
 
 	respBodyBytes, _ := io.ReadAll(resp.Body)
-	respBody := string(respBodyBytes)
 
-	notifyResult(onResult, url, webhook.Method, resp.StatusCode, time.Since(start), "", respBody)
+	result.StatusCode = resp.StatusCode
+	result.ResponseBody = string(respBodyBytes)
+	result.DurationMs = time.Since(start).Milliseconds()
 
-	logger.Info(s, map[string]string{
-		"webhook_url": url,
-		"status":      fmt.Sprintf("%d", resp.StatusCode),
-	}, "webhook sent successfully")
+	return result, nil
 }
 
 func (s *webhookService) resolveTimeout(timeout *int) time.Duration {
@@ -115,7 +531,7 @@ func (s *webhookService) resolveTimeout(timeout *int) time.Duration {
 
 func (s *webhookService) resolveHeaders(
 	headers map[string]string,
-	variables []*model.Variable,
+	variables []model.WebhookJobVariable,
 ) map[string]string {
 	result := make(map[string]string, len(headers))
 
@@ -126,33 +542,6 @@ func (s *webhookService) resolveHeaders(
 	return result
 }
 
-func notifyResult(
-	onResult func(model.WebhookResult),
-	url, method string,
-	statusCode int,
-	duration time.Duration,
-	errMsg string,
-	responseBody string,
-) {
-	if onResult == nil {
-		return
-	}
-
-	result := model.WebhookResult{
-		URL:          url,
-		Method:       method,
-		StatusCode:   statusCode,
-		DurationMs:   duration.Milliseconds(),
-		ResponseBody: responseBody,
-	}
-
-	if errMsg != "" {
-		result.Error = errMsg
-	}
-
-	onResult(result)
-}
-
 func (s *webhookService) buildRequest(
 	ctx context.Context,
 	method, url, body string,
@@ -166,7 +555,7 @@ func (s *webhookService) buildRequest(
 	return http.NewRequestWithContext(ctx, method, url, nil)
 }
 
-func (s *webhookService) applyVariables(input string, variables []*model.Variable) string {
+func (s *webhookService) applyVariables(input string, variables []model.WebhookJobVariable) string {
 	for _, variable := range variables {
 		input = strings.ReplaceAll(input, fmt.Sprintf("{%s}", variable.Name), variable.Value)
 	}
@@ -0,0 +1,482 @@
+package service_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nicopozo/mockserver/internal/configs"
+	mockscontext "github.com/nicopozo/mockserver/internal/context"
+	mockserrors "github.com/nicopozo/mockserver/internal/errors"
+	"github.com/nicopozo/mockserver/internal/model"
+	"github.com/nicopozo/mockserver/internal/repository"
+	"github.com/nicopozo/mockserver/internal/service"
+	"github.com/stretchr/testify/assert"
+)
+
+// newFlakyServer answers the first failures requests with status and the next ones with 200.
+func newFlakyServer(t *testing.T, failures int32, status int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, _ := io.ReadAll(request.Body)
+
+		if calls.Add(1) <= failures {
+			writer.WriteHeader(status)
+
+			return
+		}
+
+		_, _ = writer.Write(body)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &calls
+}
+
+// newWebhookQueue creates a WebhookService that queues the webhooks in a temporary file and records their
+// results with the returned LogService. Its workers are not started.
+func newWebhookQueue(t *testing.T) (service.WebhookService, service.LogService, *configs.Config) {
+	t.Helper()
+
+	cfg := newScenarioConfig(t)
+	cfg.WebhookWorkers = 4
+	logService := service.NewLogService(repository.NewLogMemoryRepository())
+
+	return newWebhookServiceFor(t, cfg, logService), logService, cfg
+}
+
+func newWebhookServiceFor(t *testing.T, cfg *configs.Config, logService service.LogService) service.WebhookService {
+	t.Helper()
+
+	repo, err := repository.NewWebhookJobFileRepository(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ruleService, _ := newScenarioServices(t, cfg)
+
+	return service.NewWebhookService(cfg, repo, logService, ruleService)
+}
+
+func newWebhookService(t *testing.T) service.WebhookService {
+	t.Helper()
+
+	webhookService, _, _ := newWebhookQueue(t)
+
+	return webhookService
+}
+
+func collectWebhookResults(t *testing.T, webhook model.WebhookConfig, want int) []model.WebhookResult {
+	t.Helper()
+
+	return fireWebhooks(t, []model.WebhookConfig{webhook}, "", want)
+}
+
+// fireWebhooks fires webhooks with an id variable set to 42, and returns their results once there are
+// no pending jobs left.
+func fireWebhooks(t *testing.T, webhooks []model.WebhookConfig, mode string, want int) []model.WebhookResult {
+	t.Helper()
+
+	webhookService, logService, _ := newWebhookQueue(t)
+	logService.Add(model.LogEntry{ID: "01"})
+
+	webhookService.Start(t.Context())
+	webhookService.Fire(mockscontext.Background(), "01", webhooks, mode, []*model.Variable{{Name: "id", Value: "42"}})
+
+	return waitWebhookResults(t, webhookService, logService, want)
+}
+
+// waitWebhookResults waits until there are no pending jobs left and checks that want results were recorded.
+func waitWebhookResults(t *testing.T, webhookService service.WebhookService, logService service.LogService,
+	want int,
+) []model.WebhookResult {
+	t.Helper()
+
+	assert.Eventually(t, func() bool {
+		jobs, err := webhookService.ListJobs(mockscontext.Background(), model.WebhookJobPending)
+
+		return err == nil && len(jobs) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	entry, err := logService.Get("01")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	if !assert.Len(t, entry.WebhookResults, want) {
+		t.FailNow()
+	}
+
+	return entry.WebhookResults
+}
+
+func TestWebhookService_FireRetries(t *testing.T) {
+	server, calls := newFlakyServer(t, 2, http.StatusServiceUnavailable)
+
+	results := collectWebhookResults(t, model.WebhookConfig{
+		URL:     server.URL + "/hooks/{id}",
+		Method:  http.MethodPost,
+		Headers: map[string]string{"X-Payment": "{id}"},
+		Body:    `{"id":"{id}"}`,
+		Enabled: true,
+		Retry:   &model.WebhookRetry{MaxAttempts: 5, Backoff: 1, MaxBackoff: 2},
+	}, 3)
+
+	assert.Equal(t, int32(3), calls.Load())
+
+	for i, result := range results {
+		assert.Equal(t, i+1, result.Attempt)
+		assert.Equal(t, server.URL+"/hooks/42", result.URL)
+		assert.Equal(t, `{"id":"42"}`, result.RequestBody)
+		assert.Equal(t, map[string]string{"X-Payment": "42"}, result.RequestHeaders)
+		assert.False(t, result.Timestamp.IsZero())
+	}
+
+	assert.Equal(t, http.StatusServiceUnavailable, results[0].StatusCode)
+	assert.True(t, results[0].Superseded)
+	assert.True(t, results[1].Superseded)
+	assert.Equal(t, http.StatusOK, results[2].StatusCode)
+	assert.Equal(t, `{"id":"42"}`, results[2].ResponseBody)
+	assert.False(t, results[2].Superseded)
+
+	entry := model.LogEntry{WebhookResults: results}
+	assert.False(t, entry.HasWebhookFailures())
+}
+
+func TestWebhookService_FireStopsRetrying(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		retry       *model.WebhookRetry
+		wantResults int
+	}{
+		{
+			name:        "Should not retry a status that is not retryable",
+			status:      http.StatusBadRequest,
+			retry:       &model.WebhookRetry{MaxAttempts: 3, Backoff: 1},
+			wantResults: 1,
+		},
+		{
+			name:        "Should retry the configured statuses only",
+			status:      http.StatusConflict,
+			retry:       &model.WebhookRetry{MaxAttempts: 3, Backoff: 1, RetryOn: []int{http.StatusConflict}},
+			wantResults: 3,
+		},
+		{
+			name:        "Should send once without retry",
+			status:      http.StatusServiceUnavailable,
+			wantResults: 1,
+		},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := newFlakyServer(t, 10, tt.status)
+
+			results := collectWebhookResults(t, model.WebhookConfig{
+				URL: server.URL, Method: http.MethodPost, Enabled: true, Retry: tt.retry,
+			}, tt.wantResults)
+
+			last := results[len(results)-1]
+			assert.Equal(t, tt.status, last.StatusCode)
+			assert.False(t, last.Superseded)
+			assert.True(t, model.LogEntry{WebhookResults: results}.HasWebhookFailures())
+		})
+	}
+}
+
+func TestWebhookService_Redeliver(t *testing.T) {
+	server, calls := newFlakyServer(t, 0, 0)
+
+	result := newWebhookService(t).Redeliver(mockscontext.Background(), "", model.WebhookResult{
+		URL:            server.URL,
+		Method:         http.MethodPut,
+		Attempt:        3,
+		StatusCode:     http.StatusServiceUnavailable,
+		RequestHeaders: map[string]string{"Content-Type": "application/json"},
+		RequestBody:    `{"event":"payment.created"}`,
+	})
+
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Equal(t, http.StatusOK, result.StatusCode)
+	assert.Equal(t, `{"event":"payment.created"}`, result.ResponseBody)
+	assert.Equal(t, http.MethodPut, result.Method)
+	assert.Empty(t, result.Error)
+}
+
+func TestWebhookService_RedeliverSigned(t *testing.T) {
+	signature := model.WebhookSignature{
+		Algorithm: model.WebhookSignatureHMACSHA256, Secret: "whsec_test_secret", Header: "Stripe-Signature",
+		Format: "t={timestamp},v1={sig}", Payload: "{timestamp}.{body}",
+	}
+
+	received := make(chan string, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, request *http.Request) {
+		received <- request.Header.Get("Stripe-Signature")
+	}))
+	defer server.Close()
+
+	cfg := newScenarioConfig(t)
+	ruleService, _ := newScenarioServices(t, cfg)
+
+	rule, err := ruleService.Save(mockscontext.Background(), model.Rule{
+		Group: "payments", Name: "create payment", Path: "/v1/payments", Method: http.MethodPost,
+		Strategy: model.RuleStrategyNormal, Status: model.RuleStatusEnabled,
+		Responses: []model.Response{{
+			Body: "{}", ContentType: "application/json", HTTPStatus: http.StatusCreated,
+			Webhook: &model.WebhookConfig{URL: server.URL, Method: http.MethodPost, Enabled: true, Signature: &signature},
+		}},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	body := `{"id":"42"}`
+	sentAt := time.Now().Add(-time.Hour)
+	webhookService := newWebhookServiceFor(t, cfg, service.NewLogService(repository.NewLogMemoryRepository()))
+
+	result := webhookService.Redeliver(mockscontext.Background(), rule.Key, model.WebhookResult{
+		URL:            server.URL,
+		Method:         http.MethodPost,
+		Timestamp:      sentAt,
+		RequestHeaders: map[string]string{"Stripe-Signature": signature.Sign(body, sentAt)},
+		RequestBody:    body,
+	})
+
+	// The redelivery is signed again, with the time it is sent at.
+	header := <-received
+	assert.Equal(t, signature.Sign(body, result.Timestamp), header)
+	assert.NotEqual(t, signature.Sign(body, sentAt), header)
+	assert.Equal(t, header, result.RequestHeaders["Stripe-Signature"])
+}
+
+func TestWebhookService_RedeliverWithTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	cfg := newScenarioConfig(t)
+	ruleService, _ := newScenarioServices(t, cfg)
+	timeout := 50
+
+	rule, err := ruleService.Save(mockscontext.Background(), model.Rule{
+		Group: "payments", Name: "create payment", Path: "/v1/payments", Method: http.MethodPost,
+		Strategy: model.RuleStrategyNormal, Status: model.RuleStatusEnabled,
+		Responses: []model.Response{{
+			Body: "{}", ContentType: "application/json", HTTPStatus: http.StatusCreated,
+			Webhook: &model.WebhookConfig{URL: server.URL, Method: http.MethodPost, Enabled: true, Timeout: &timeout},
+		}},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	webhookService := newWebhookServiceFor(t, cfg, service.NewLogService(repository.NewLogMemoryRepository()))
+
+	// The redelivery gives up after the timeout of the webhook, as its attempts do.
+	result := webhookService.Redeliver(mockscontext.Background(), rule.Key, model.WebhookResult{
+		URL: server.URL, Method: http.MethodPost, RequestBody: "{}",
+	})
+
+	assert.NotEmpty(t, result.Error)
+	assert.Less(t, result.DurationMs, int64(200))
+}
+
+func TestWebhookService_FireSigned(t *testing.T) {
+	signature := model.WebhookSignature{
+		Algorithm: model.WebhookSignatureHMACSHA256, Secret: "whsec_test_secret", Header: "Stripe-Signature",
+		Format: "t={timestamp},v1={sig}", Payload: "{timestamp}.{body}",
+	}
+
+	received := make(chan string, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, request *http.Request) {
+		received <- request.Header.Get("Stripe-Signature")
+	}))
+	defer server.Close()
+
+	results := collectWebhookResults(t, model.WebhookConfig{
+		URL:       server.URL,
+		Method:    http.MethodPost,
+		Body:      `{"id":"{id}"}`,
+		Enabled:   true,
+		Signature: &signature,
+	}, 1)
+
+	header := <-received
+	assert.Equal(t, signature.Sign(`{"id":"42"}`, results[0].Timestamp), header)
+	assert.Equal(t, header, results[0].RequestHeaders["Stripe-Signature"])
+}
+
+func TestWebhookService_FireChained(t *testing.T) {
+	requests := make(chan string, 2)
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, _ := io.ReadAll(request.Body)
+		requests <- request.URL.Path + " " + string(body)
+
+		_, _ = writer.Write([]byte(`{"approval":{"id":"apr_7"}}`))
+	}))
+	defer server.Close()
+
+	results := fireWebhooks(t, []model.WebhookConfig{
+		{
+			URL: server.URL + "/created", Method: http.MethodPost, Body: `{"payment":"{id}"}`, Enabled: true,
+			Variables: []*model.Variable{
+				{Type: model.VariableTypeWebhook, Name: "approval_id", Key: "$.approval.id"},
+				{Type: model.VariableTypeWebhook, Name: "missing", Key: "$.["},
+			},
+		},
+		{
+			URL: server.URL + "/approved", Method: http.MethodPost, Delay: 20, Enabled: true,
+			Body: `{"payment":"{id}","approval":"{approval_id}","other":"{missing}"}`,
+		},
+	}, model.WebhookModeSequential, 2)
+
+	assert.Equal(t, `/created {"payment":"42"}`, <-requests)
+	assert.Equal(t, `/approved {"payment":"42","approval":"apr_7","other":"{missing}"}`, <-requests)
+	assert.Equal(t, 0, results[0].Webhook)
+	assert.Equal(t, 1, results[1].Webhook)
+	assert.False(t, results[1].Timestamp.Before(results[0].Timestamp.Add(20*time.Millisecond)))
+}
+
+func TestWebhookService_FireParallel(t *testing.T) {
+	release := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, request *http.Request) {
+		// The first webhook is held until the second one has arrived.
+		if request.URL.Path == "/first" {
+			<-release
+		} else {
+			close(release)
+		}
+	}))
+	defer server.Close()
+
+	results := fireWebhooks(t, []model.WebhookConfig{
+		{URL: server.URL + "/first", Method: http.MethodPost, Enabled: true},
+		{URL: server.URL + "/second", Method: http.MethodPost, Enabled: true},
+	}, model.WebhookModeParallel, 2)
+
+	// Sent one after the other, the first webhook would never be answered.
+	assert.ElementsMatch(t, []int{0, 1}, []int{results[0].Webhook, results[1].Webhook})
+}
+
+func TestWebhookService_FireFailed(t *testing.T) {
+	server, calls := newFlakyServer(t, 10, http.StatusInternalServerError)
+
+	webhookService, logService, _ := newWebhookQueue(t)
+	logService.Add(model.LogEntry{ID: "01"})
+
+	webhookService.Start(t.Context())
+	webhookService.Fire(mockscontext.Background(), "01", []model.WebhookConfig{
+		{URL: server.URL + "/created", Method: http.MethodPost, Enabled: true},
+		{URL: server.URL + "/approved", Method: http.MethodPost, Enabled: true},
+	}, model.WebhookModeSequential, nil)
+
+	results := waitWebhookResults(t, webhookService, logService, 1)
+	assert.Equal(t, server.URL+"/created", results[0].URL)
+	assert.Equal(t, int32(1), calls.Load(), "the chain stops at the failed webhook")
+
+	jobs, err := webhookService.ListJobs(mockscontext.Background(), model.WebhookJobFailed)
+	assert.NoError(t, err)
+
+	if assert.Len(t, jobs, 1) {
+		assert.Equal(t, "01", jobs[0].LogID)
+		assert.Equal(t, 0, jobs[0].Step)
+		assert.Equal(t, "webhook answered with status 500", jobs[0].LastError)
+	}
+}
+
+func TestWebhookService_PicksUpQueuedJobs(t *testing.T) {
+	server, calls := newFlakyServer(t, 0, 0)
+
+	// The first instance queues the webhook and stops before sending it.
+	stopped, logService, cfg := newWebhookQueue(t)
+	logService.Add(model.LogEntry{ID: "01"})
+	stopped.Fire(mockscontext.Background(), "01", []model.WebhookConfig{
+		{URL: server.URL, Method: http.MethodPost, Enabled: true},
+	}, "", nil)
+
+	jobs, err := stopped.ListJobs(mockscontext.Background(), "")
+	assert.NoError(t, err)
+	assert.Len(t, jobs, 1)
+
+	restarted := newWebhookServiceFor(t, cfg, logService)
+	restarted.Start(t.Context())
+
+	results := waitWebhookResults(t, restarted, logService, 1)
+	assert.Equal(t, http.StatusOK, results[0].StatusCode)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+// unwritableLogService fails every update of the log entries.
+type unwritableLogService struct {
+	service.LogService
+	updates atomic.Int32
+}
+
+func (logService *unwritableLogService) Update(string, func(entry *model.LogEntry)) error {
+	logService.updates.Add(1)
+
+	return errors.New("database is locked") //nolint:err113
+}
+
+func TestWebhookService_KeepsJobWhenResultIsNotRecorded(t *testing.T) {
+	server, calls := newFlakyServer(t, 0, 0)
+
+	cfg := newScenarioConfig(t)
+	logService := &unwritableLogService{LogService: service.NewLogService(repository.NewLogMemoryRepository())}
+	webhookService := newWebhookServiceFor(t, cfg, logService)
+
+	webhookService.Start(t.Context())
+	webhookService.Fire(mockscontext.Background(), "01", []model.WebhookConfig{
+		{URL: server.URL, Method: http.MethodPost, Enabled: true},
+	}, "", nil)
+
+	assert.Eventually(t, func() bool { return logService.updates.Load() == 1 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(1), calls.Load())
+
+	// The job stays claimed, to be sent again once its lease expires.
+	assert.Never(t, func() bool {
+		jobs, err := webhookService.ListJobs(mockscontext.Background(), model.WebhookJobPending)
+
+		return err != nil || len(jobs) != 1 || jobs[0].Attempt != 0
+	}, 200*time.Millisecond, 10*time.Millisecond)
+}
+
+func TestWebhookService_CancelJob(t *testing.T) {
+	webhookService := newWebhookService(t)
+	ctx := mockscontext.Background()
+
+	webhookService.Fire(ctx, "01", []model.WebhookConfig{
+		{URL: "http://hooks.local", Method: http.MethodPost, Delay: 3600000, Enabled: true},
+	}, "", nil)
+
+	jobs, err := webhookService.ListJobs(ctx, model.WebhookJobPending)
+	assert.NoError(t, err)
+
+	if !assert.Len(t, jobs, 1) {
+		return
+	}
+
+	assert.NoError(t, webhookService.CancelJob(ctx, jobs[0].ID))
+
+	jobs, err = webhookService.ListJobs(ctx, "")
+	assert.NoError(t, err)
+	assert.Empty(t, jobs)
+
+	assert.ErrorAs(t, webhookService.CancelJob(ctx, "missing"), &mockserrors.WebhookJobNotFoundError{})
+
+	_, err = webhookService.ListJobs(ctx, "done")
+	assert.EqualError(t, err, "status must be 'pending' or 'failed'")
+}
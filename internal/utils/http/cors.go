@@ -9,7 +9,7 @@ func CORS(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
 		writer.Header().Set("Access-Control-Allow-Origin", "*")
 		writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS, PATCH")
-		writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, x-tracking-id")
+		writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key, x-tracking-id")
 
 		if request.Method == http.MethodOptions {
 			writer.WriteHeader(http.StatusNoContent)
@@ -3,11 +3,17 @@ package httputils
 import (
 	"encoding/json"
 	"fmt"
+	"mime"
 	"net/http"
+	"strings"
 
 	"github.com/nicopozo/mockserver/internal/model"
+	yamlutils "github.com/nicopozo/mockserver/internal/utils/yaml"
 )
 
+// ContentTypeYAML is the media type of the YAML responses.
+const ContentTypeYAML = "application/yaml"
+
 // WriteJSON writes a JSON response with the given status code and data.
 func WriteJSON(writer http.ResponseWriter, status int, data any) {
 	writer.Header().Set("Content-Type", "application/json")
@@ -18,6 +24,41 @@ func WriteJSON(writer http.ResponseWriter, status int, data any) {
 	}
 }
 
+// WriteYAML writes a YAML response with the given status code and data, encoded with its json tags.
+func WriteYAML(writer http.ResponseWriter, status int, data any) {
+	content, err := yamlutils.Marshal(data)
+	if err != nil {
+		WriteError(writer, model.InternalError, "Error encoding YAML. %s", err.Error())
+
+		return
+	}
+
+	writer.Header().Set("Content-Type", ContentTypeYAML)
+	writer.WriteHeader(status)
+
+	if _, err := writer.Write([]byte(content)); err != nil {
+		fmt.Printf("Error writing YAML: %v\n", err) //nolint:forbidigo
+	}
+}
+
+// IsYAML reports whether a Content-Type or Accept header holds a YAML media type, such as
+// application/yaml, application/x-yaml or text/yaml.
+func IsYAML(header string) bool {
+	for _, value := range strings.Split(header, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(value))
+		if err != nil {
+			continue
+		}
+
+		switch mediaType {
+		case ContentTypeYAML, "application/x-yaml", "text/yaml", "text/x-yaml":
+			return true
+		}
+	}
+
+	return false
+}
+
 // WriteError writes a consistent error response using the model.Error structure.
 func WriteError(writer http.ResponseWriter, causeCode int64, message string, args ...interface{}) {
 	errResult := model.NewError(causeCode, message, args...)
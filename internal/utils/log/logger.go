@@ -21,10 +21,12 @@ type ILogger interface {
 	Debug(source interface{}, tags map[string]string, message string, args ...interface{})
 	GetTrackingID() string
 	GetMessage(message string, args ...interface{}) string
+	WithIdentity(identity string) ILogger
 }
 
 type log struct {
 	trackingID string
+	identity   string
 	logrus     *logrus.Logger
 }
 
@@ -83,6 +85,19 @@ func (logger *log) GetTrackingID() string {
 	return logger.trackingID
 }
 
+// WithIdentity returns a logger that tags every message with the authenticated caller.
+func (logger *log) WithIdentity(identity string) ILogger {
+	if identity == "" {
+		return logger
+	}
+
+	return &log{
+		trackingID: logger.trackingID,
+		identity:   identity,
+		logrus:     logger.logrus,
+	}
+}
+
 func (logger *log) GetMessage(message string, args ...interface{}) string {
 	if len(args) > 0 {
 		return fmt.Sprintf(message, args...)
@@ -121,5 +136,9 @@ func (logger *log) getTags(source interface{}, tags map[string]string) []string
 	res[index] = fmt.Sprintf("TRACKING_ID:%v", logger.trackingID)
 	res[index+1] = fmt.Sprintf("Class:%v", getClass(source))
 
+	if logger.identity != "" {
+		res = append(res, fmt.Sprintf("IDENTITY:%v", logger.identity))
+	}
+
 	return res
 }
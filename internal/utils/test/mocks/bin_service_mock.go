@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./bin_service.go
+//
+// Generated by this command:
+//
+//	mockgen -destination=../utils/test/mocks/bin_service_mock.go -package=mocks -source=./bin_service.go
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/nicopozo/mockserver/internal/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBinService is a mock of BinService interface.
+type MockBinService struct {
+	ctrl     *gomock.Controller
+	recorder *MockBinServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockBinServiceMockRecorder is the mock recorder for MockBinService.
+type MockBinServiceMockRecorder struct {
+	mock *MockBinService
+}
+
+// NewMockBinService creates a new mock instance.
+func NewMockBinService(ctrl *gomock.Controller) *MockBinService {
+	mock := &MockBinService{ctrl: ctrl}
+	mock.recorder = &MockBinServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBinService) EXPECT() *MockBinServiceMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockBinService) Delete(ctx context.Context, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockBinServiceMockRecorder) Delete(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBinService)(nil).Delete), ctx, name)
+}
+
+// Get mocks base method.
+func (m *MockBinService) Get(ctx context.Context, name string) (*model.Bin, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, name)
+	ret0, _ := ret[0].(*model.Bin)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockBinServiceMockRecorder) Get(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockBinService)(nil).Get), ctx, name)
+}
+
+// List mocks base method.
+func (m *MockBinService) List(ctx context.Context) ([]model.Bin, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]model.Bin)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockBinServiceMockRecorder) List(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockBinService)(nil).List), ctx)
+}
+
+// Save mocks base method.
+func (m *MockBinService) Save(ctx context.Context, bin model.Bin) (*model.Bin, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, bin)
+	ret0, _ := ret[0].(*model.Bin)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockBinServiceMockRecorder) Save(ctx, bin any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockBinService)(nil).Save), ctx, bin)
+}
@@ -0,0 +1,102 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./chaos_service.go
+//
+// Generated by this command:
+//
+//	mockgen -destination=../utils/test/mocks/chaos_service_mock.go -package=mocks -source=./chaos_service.go
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/nicopozo/mockserver/internal/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockChaosService is a mock of ChaosService interface.
+type MockChaosService struct {
+	ctrl     *gomock.Controller
+	recorder *MockChaosServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockChaosServiceMockRecorder is the mock recorder for MockChaosService.
+type MockChaosServiceMockRecorder struct {
+	mock *MockChaosService
+}
+
+// NewMockChaosService creates a new mock instance.
+func NewMockChaosService(ctrl *gomock.Controller) *MockChaosService {
+	mock := &MockChaosService{ctrl: ctrl}
+	mock.recorder = &MockChaosServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChaosService) EXPECT() *MockChaosServiceMockRecorder {
+	return m.recorder
+}
+
+// Decide mocks base method.
+func (m *MockChaosService) Decide(ctx context.Context, rule model.Rule) (*model.ChaosResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Decide", ctx, rule)
+	ret0, _ := ret[0].(*model.ChaosResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Decide indicates an expected call of Decide.
+func (mr *MockChaosServiceMockRecorder) Decide(ctx, rule any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decide", reflect.TypeOf((*MockChaosService)(nil).Decide), ctx, rule)
+}
+
+// Get mocks base method.
+func (m *MockChaosService) Get(ctx context.Context) (*model.ChaosSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx)
+	ret0, _ := ret[0].(*model.ChaosSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockChaosServiceMockRecorder) Get(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockChaosService)(nil).Get), ctx)
+}
+
+// Save mocks base method.
+func (m *MockChaosService) Save(ctx context.Context, settings model.ChaosSettings) (*model.ChaosSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, settings)
+	ret0, _ := ret[0].(*model.ChaosSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockChaosServiceMockRecorder) Save(ctx, settings any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockChaosService)(nil).Save), ctx, settings)
+}
+
+// SetStatus mocks base method.
+func (m *MockChaosService) SetStatus(ctx context.Context, status string) (*model.ChaosSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetStatus", ctx, status)
+	ret0, _ := ret[0].(*model.ChaosSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetStatus indicates an expected call of SetStatus.
+func (mr *MockChaosServiceMockRecorder) SetStatus(ctx, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStatus", reflect.TypeOf((*MockChaosService)(nil).SetStatus), ctx, status)
+}
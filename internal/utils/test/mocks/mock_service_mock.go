@@ -43,17 +43,17 @@ func (m *MockMockService) EXPECT() *MockMockServiceMockRecorder {
 }
 
 // SearchResponseForRequest mocks base method.
-func (m *MockMockService) SearchResponseForRequest(ctx context.Context, request *http.Request, path, body string, onWebhookResult func(model.WebhookResult)) (model.Response, model.AssertionResult, error) {
+func (m *MockMockService) SearchResponseForRequest(ctx context.Context, request *http.Request, path, body, logID string) (model.Response, model.ExecutionResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SearchResponseForRequest", ctx, request, path, body, onWebhookResult)
+	ret := m.ctrl.Call(m, "SearchResponseForRequest", ctx, request, path, body, logID)
 	ret0, _ := ret[0].(model.Response)
-	ret1, _ := ret[1].(model.AssertionResult)
+	ret1, _ := ret[1].(model.ExecutionResult)
 	ret2, _ := ret[2].(error)
 	return ret0, ret1, ret2
 }
 
 // SearchResponseForRequest indicates an expected call of SearchResponseForRequest.
-func (mr *MockMockServiceMockRecorder) SearchResponseForRequest(ctx, request, path, body, onWebhookResult any) *gomock.Call {
+func (mr *MockMockServiceMockRecorder) SearchResponseForRequest(ctx, request, path, body, logID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchResponseForRequest", reflect.TypeOf((*MockMockService)(nil).SearchResponseForRequest), ctx, request, path, body, onWebhookResult)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchResponseForRequest", reflect.TypeOf((*MockMockService)(nil).SearchResponseForRequest), ctx, request, path, body, logID)
 }
@@ -0,0 +1,109 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./webhook_service.go
+//
+// Generated by this command:
+//
+//	mockgen -destination=../utils/test/mocks/mock_service_webhook.go -package=mocks -source=./webhook_service.go
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/nicopozo/mockserver/internal/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockWebhookService is a mock of WebhookService interface.
+type MockWebhookService struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockWebhookServiceMockRecorder is the mock recorder for MockWebhookService.
+type MockWebhookServiceMockRecorder struct {
+	mock *MockWebhookService
+}
+
+// NewMockWebhookService creates a new mock instance.
+func NewMockWebhookService(ctrl *gomock.Controller) *MockWebhookService {
+	mock := &MockWebhookService{ctrl: ctrl}
+	mock.recorder = &MockWebhookServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookService) EXPECT() *MockWebhookServiceMockRecorder {
+	return m.recorder
+}
+
+// CancelJob mocks base method.
+func (m *MockWebhookService) CancelJob(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelJob", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelJob indicates an expected call of CancelJob.
+func (mr *MockWebhookServiceMockRecorder) CancelJob(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelJob", reflect.TypeOf((*MockWebhookService)(nil).CancelJob), ctx, id)
+}
+
+// Fire mocks base method.
+func (m *MockWebhookService) Fire(ctx context.Context, logID string, webhooks []model.WebhookConfig, mode string, variables []*model.Variable) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Fire", ctx, logID, webhooks, mode, variables)
+}
+
+// Fire indicates an expected call of Fire.
+func (mr *MockWebhookServiceMockRecorder) Fire(ctx, logID, webhooks, mode, variables any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fire", reflect.TypeOf((*MockWebhookService)(nil).Fire), ctx, logID, webhooks, mode, variables)
+}
+
+// ListJobs mocks base method.
+func (m *MockWebhookService) ListJobs(ctx context.Context, status string) ([]model.WebhookJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListJobs", ctx, status)
+	ret0, _ := ret[0].([]model.WebhookJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListJobs indicates an expected call of ListJobs.
+func (mr *MockWebhookServiceMockRecorder) ListJobs(ctx, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListJobs", reflect.TypeOf((*MockWebhookService)(nil).ListJobs), ctx, status)
+}
+
+// Redeliver mocks base method.
+func (m *MockWebhookService) Redeliver(ctx context.Context, ruleKey string, result model.WebhookResult) model.WebhookResult {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Redeliver", ctx, ruleKey, result)
+	ret0, _ := ret[0].(model.WebhookResult)
+	return ret0
+}
+
+// Redeliver indicates an expected call of Redeliver.
+func (mr *MockWebhookServiceMockRecorder) Redeliver(ctx, ruleKey, result any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Redeliver", reflect.TypeOf((*MockWebhookService)(nil).Redeliver), ctx, ruleKey, result)
+}
+
+// Start mocks base method.
+func (m *MockWebhookService) Start(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Start", ctx)
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockWebhookServiceMockRecorder) Start(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockWebhookService)(nil).Start), ctx)
+}
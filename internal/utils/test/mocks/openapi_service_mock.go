@@ -0,0 +1,57 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./openapi_service.go
+//
+// Generated by this command:
+//
+//	mockgen -destination=../utils/test/mocks/openapi_service_mock.go -package=mocks -source=./openapi_service.go
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/nicopozo/mockserver/internal/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockOpenAPIService is a mock of OpenAPIService interface.
+type MockOpenAPIService struct {
+	ctrl     *gomock.Controller
+	recorder *MockOpenAPIServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockOpenAPIServiceMockRecorder is the mock recorder for MockOpenAPIService.
+type MockOpenAPIServiceMockRecorder struct {
+	mock *MockOpenAPIService
+}
+
+// NewMockOpenAPIService creates a new mock instance.
+func NewMockOpenAPIService(ctrl *gomock.Controller) *MockOpenAPIService {
+	mock := &MockOpenAPIService{ctrl: ctrl}
+	mock.recorder = &MockOpenAPIServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOpenAPIService) EXPECT() *MockOpenAPIServiceMockRecorder {
+	return m.recorder
+}
+
+// Import mocks base method.
+func (m *MockOpenAPIService) Import(ctx context.Context, spec []byte, group string) (model.ImportReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, spec, group)
+	ret0, _ := ret[0].(model.ImportReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockOpenAPIServiceMockRecorder) Import(ctx, spec, group any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockOpenAPIService)(nil).Import), ctx, spec, group)
+}
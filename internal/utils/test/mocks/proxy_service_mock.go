@@ -0,0 +1,58 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./proxy_service.go
+//
+// Generated by this command:
+//
+//	mockgen -destination=../utils/test/mocks/proxy_service_mock.go -package=mocks -source=./proxy_service.go
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	http "net/http"
+	reflect "reflect"
+
+	service "github.com/nicopozo/mockserver/internal/service"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockProxyService is a mock of ProxyService interface.
+type MockProxyService struct {
+	ctrl     *gomock.Controller
+	recorder *MockProxyServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockProxyServiceMockRecorder is the mock recorder for MockProxyService.
+type MockProxyServiceMockRecorder struct {
+	mock *MockProxyService
+}
+
+// NewMockProxyService creates a new mock instance.
+func NewMockProxyService(ctrl *gomock.Controller) *MockProxyService {
+	mock := &MockProxyService{ctrl: ctrl}
+	mock.recorder = &MockProxyServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProxyService) EXPECT() *MockProxyServiceMockRecorder {
+	return m.recorder
+}
+
+// Forward mocks base method.
+func (m *MockProxyService) Forward(ctx context.Context, writer http.ResponseWriter, request *http.Request, path, body string) (service.ProxyResult, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Forward", ctx, writer, request, path, body)
+	ret0, _ := ret[0].(service.ProxyResult)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Forward indicates an expected call of Forward.
+func (mr *MockProxyServiceMockRecorder) Forward(ctx, writer, request, path, body any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Forward", reflect.TypeOf((*MockProxyService)(nil).Forward), ctx, writer, request, path, body)
+}
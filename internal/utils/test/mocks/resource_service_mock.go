@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./resource_service.go
+//
+// Generated by this command:
+//
+//	mockgen -destination=../utils/test/mocks/resource_service_mock.go -package=mocks -source=./resource_service.go
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	http "net/http"
+	reflect "reflect"
+
+	model "github.com/nicopozo/mockserver/internal/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockResourceService is a mock of ResourceService interface.
+type MockResourceService struct {
+	ctrl     *gomock.Controller
+	recorder *MockResourceServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockResourceServiceMockRecorder is the mock recorder for MockResourceService.
+type MockResourceServiceMockRecorder struct {
+	mock *MockResourceService
+}
+
+// NewMockResourceService creates a new mock instance.
+func NewMockResourceService(ctrl *gomock.Controller) *MockResourceService {
+	mock := &MockResourceService{ctrl: ctrl}
+	mock.recorder = &MockResourceServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockResourceService) EXPECT() *MockResourceServiceMockRecorder {
+	return m.recorder
+}
+
+// Handle mocks base method.
+func (m *MockResourceService) Handle(ctx context.Context, rule model.Rule, request *http.Request, path, body string) (model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Handle", ctx, rule, request, path, body)
+	ret0, _ := ret[0].(model.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Handle indicates an expected call of Handle.
+func (mr *MockResourceServiceMockRecorder) Handle(ctx, rule, request, path, body any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Handle", reflect.TypeOf((*MockResourceService)(nil).Handle), ctx, rule, request, path, body)
+}
+
+// Reset mocks base method.
+func (m *MockResourceService) Reset(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reset", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reset indicates an expected call of Reset.
+func (mr *MockResourceServiceMockRecorder) Reset(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockResourceService)(nil).Reset), ctx, key)
+}
@@ -0,0 +1,68 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./rule_reload_service.go
+//
+// Generated by this command:
+//
+//	mockgen -destination=../utils/test/mocks/rule_reload_service_mock.go -package=mocks -source=./rule_reload_service.go
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRuleReloadService is a mock of RuleReloadService interface.
+type MockRuleReloadService struct {
+	ctrl     *gomock.Controller
+	recorder *MockRuleReloadServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockRuleReloadServiceMockRecorder is the mock recorder for MockRuleReloadService.
+type MockRuleReloadServiceMockRecorder struct {
+	mock *MockRuleReloadService
+}
+
+// NewMockRuleReloadService creates a new mock instance.
+func NewMockRuleReloadService(ctrl *gomock.Controller) *MockRuleReloadService {
+	mock := &MockRuleReloadService{ctrl: ctrl}
+	mock.recorder = &MockRuleReloadServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRuleReloadService) EXPECT() *MockRuleReloadServiceMockRecorder {
+	return m.recorder
+}
+
+// Reload mocks base method.
+func (m *MockRuleReloadService) Reload(ctx context.Context) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reload", ctx)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reload indicates an expected call of Reload.
+func (mr *MockRuleReloadServiceMockRecorder) Reload(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reload", reflect.TypeOf((*MockRuleReloadService)(nil).Reload), ctx)
+}
+
+// Start mocks base method.
+func (m *MockRuleReloadService) Start(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Start", ctx)
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockRuleReloadServiceMockRecorder) Start(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockRuleReloadService)(nil).Start), ctx)
+}
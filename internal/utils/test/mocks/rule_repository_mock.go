@@ -41,6 +41,21 @@ func (m *MockRuleRepository) EXPECT() *MockRuleRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AdvanceResponse mocks base method.
+func (m *MockRuleRepository) AdvanceResponse(ctx context.Context, key string, responses int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdvanceResponse", ctx, key, responses)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdvanceResponse indicates an expected call of AdvanceResponse.
+func (mr *MockRuleRepositoryMockRecorder) AdvanceResponse(ctx, key, responses any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdvanceResponse", reflect.TypeOf((*MockRuleRepository)(nil).AdvanceResponse), ctx, key, responses)
+}
+
 // Create mocks base method.
 func (m *MockRuleRepository) Create(ctx context.Context, rule *model.Rule) (*model.Rule, error) {
 	m.ctrl.T.Helper()
@@ -101,10 +116,10 @@ func (mr *MockRuleRepositoryMockRecorder) Search(ctx, params, paging any) *gomoc
 }
 
 // SearchByMethodAndPath mocks base method.
-func (m *MockRuleRepository) SearchByMethodAndPath(ctx context.Context, method, path string) (*model.Rule, error) {
+func (m *MockRuleRepository) SearchByMethodAndPath(ctx context.Context, method, path string) ([]*model.Rule, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "SearchByMethodAndPath", ctx, method, path)
-	ret0, _ := ret[0].(*model.Rule)
+	ret0, _ := ret[0].([]*model.Rule)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -129,3 +144,146 @@ func (mr *MockRuleRepositoryMockRecorder) Update(ctx, rule any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRuleRepository)(nil).Update), ctx, rule)
 }
+
+// MockReloadableRuleRepository is a mock of ReloadableRuleRepository interface.
+type MockReloadableRuleRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockReloadableRuleRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockReloadableRuleRepositoryMockRecorder is the mock recorder for MockReloadableRuleRepository.
+type MockReloadableRuleRepositoryMockRecorder struct {
+	mock *MockReloadableRuleRepository
+}
+
+// NewMockReloadableRuleRepository creates a new mock instance.
+func NewMockReloadableRuleRepository(ctrl *gomock.Controller) *MockReloadableRuleRepository {
+	mock := &MockReloadableRuleRepository{ctrl: ctrl}
+	mock.recorder = &MockReloadableRuleRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReloadableRuleRepository) EXPECT() *MockReloadableRuleRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AdvanceResponse mocks base method.
+func (m *MockReloadableRuleRepository) AdvanceResponse(ctx context.Context, key string, responses int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdvanceResponse", ctx, key, responses)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdvanceResponse indicates an expected call of AdvanceResponse.
+func (mr *MockReloadableRuleRepositoryMockRecorder) AdvanceResponse(ctx, key, responses any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdvanceResponse", reflect.TypeOf((*MockReloadableRuleRepository)(nil).AdvanceResponse), ctx, key, responses)
+}
+
+// Create mocks base method.
+func (m *MockReloadableRuleRepository) Create(ctx context.Context, rule *model.Rule) (*model.Rule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, rule)
+	ret0, _ := ret[0].(*model.Rule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockReloadableRuleRepositoryMockRecorder) Create(ctx, rule any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockReloadableRuleRepository)(nil).Create), ctx, rule)
+}
+
+// Delete mocks base method.
+func (m *MockReloadableRuleRepository) Delete(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockReloadableRuleRepositoryMockRecorder) Delete(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockReloadableRuleRepository)(nil).Delete), ctx, key)
+}
+
+// Get mocks base method.
+func (m *MockReloadableRuleRepository) Get(ctx context.Context, key string) (*model.Rule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, key)
+	ret0, _ := ret[0].(*model.Rule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockReloadableRuleRepositoryMockRecorder) Get(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockReloadableRuleRepository)(nil).Get), ctx, key)
+}
+
+// Reload mocks base method.
+func (m *MockReloadableRuleRepository) Reload(ctx context.Context, check func(model.Rule) (model.Rule, error)) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reload", ctx, check)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reload indicates an expected call of Reload.
+func (mr *MockReloadableRuleRepositoryMockRecorder) Reload(ctx, check any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reload", reflect.TypeOf((*MockReloadableRuleRepository)(nil).Reload), ctx, check)
+}
+
+// Search mocks base method.
+func (m *MockReloadableRuleRepository) Search(ctx context.Context, params map[string]any, paging model.Paging) (*model.RuleList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, params, paging)
+	ret0, _ := ret[0].(*model.RuleList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockReloadableRuleRepositoryMockRecorder) Search(ctx, params, paging any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockReloadableRuleRepository)(nil).Search), ctx, params, paging)
+}
+
+// SearchByMethodAndPath mocks base method.
+func (m *MockReloadableRuleRepository) SearchByMethodAndPath(ctx context.Context, method, path string) ([]*model.Rule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchByMethodAndPath", ctx, method, path)
+	ret0, _ := ret[0].([]*model.Rule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchByMethodAndPath indicates an expected call of SearchByMethodAndPath.
+func (mr *MockReloadableRuleRepositoryMockRecorder) SearchByMethodAndPath(ctx, method, path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchByMethodAndPath", reflect.TypeOf((*MockReloadableRuleRepository)(nil).SearchByMethodAndPath), ctx, method, path)
+}
+
+// Update mocks base method.
+func (m *MockReloadableRuleRepository) Update(ctx context.Context, rule *model.Rule) (*model.Rule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, rule)
+	ret0, _ := ret[0].(*model.Rule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockReloadableRuleRepositoryMockRecorder) Update(ctx, rule any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockReloadableRuleRepository)(nil).Update), ctx, rule)
+}
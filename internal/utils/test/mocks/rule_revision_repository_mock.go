@@ -0,0 +1,87 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./rule_revision_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -destination=../utils/test/mocks/rule_revision_repository_mock.go -package=mocks -source=./rule_revision_repository.go
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/nicopozo/mockserver/internal/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRuleRevisionRepository is a mock of RuleRevisionRepository interface.
+type MockRuleRevisionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRuleRevisionRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRuleRevisionRepositoryMockRecorder is the mock recorder for MockRuleRevisionRepository.
+type MockRuleRevisionRepositoryMockRecorder struct {
+	mock *MockRuleRevisionRepository
+}
+
+// NewMockRuleRevisionRepository creates a new mock instance.
+func NewMockRuleRevisionRepository(ctrl *gomock.Controller) *MockRuleRevisionRepository {
+	mock := &MockRuleRevisionRepository{ctrl: ctrl}
+	mock.recorder = &MockRuleRevisionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRuleRevisionRepository) EXPECT() *MockRuleRevisionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Append mocks base method.
+func (m *MockRuleRevisionRepository) Append(ctx context.Context, revision model.RuleRevision) (model.RuleRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Append", ctx, revision)
+	ret0, _ := ret[0].(model.RuleRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Append indicates an expected call of Append.
+func (mr *MockRuleRevisionRepositoryMockRecorder) Append(ctx, revision any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Append", reflect.TypeOf((*MockRuleRevisionRepository)(nil).Append), ctx, revision)
+}
+
+// Get mocks base method.
+func (m *MockRuleRevisionRepository) Get(ctx context.Context, ruleKey string, revision int) (*model.RuleRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, ruleKey, revision)
+	ret0, _ := ret[0].(*model.RuleRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockRuleRevisionRepositoryMockRecorder) Get(ctx, ruleKey, revision any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRuleRevisionRepository)(nil).Get), ctx, ruleKey, revision)
+}
+
+// List mocks base method.
+func (m *MockRuleRevisionRepository) List(ctx context.Context, ruleKey string) ([]model.RuleRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, ruleKey)
+	ret0, _ := ret[0].([]model.RuleRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockRuleRevisionRepositoryMockRecorder) List(ctx, ruleKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRuleRevisionRepository)(nil).List), ctx, ruleKey)
+}
@@ -55,6 +55,21 @@ func (mr *MockRuleServiceMockRecorder) Delete(ctx, key any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRuleService)(nil).Delete), ctx, key)
 }
 
+// DiffRevisions mocks base method.
+func (m *MockRuleService) DiffRevisions(ctx context.Context, key string, from, to int) (model.RuleDiff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiffRevisions", ctx, key, from, to)
+	ret0, _ := ret[0].(model.RuleDiff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DiffRevisions indicates an expected call of DiffRevisions.
+func (mr *MockRuleServiceMockRecorder) DiffRevisions(ctx, key, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiffRevisions", reflect.TypeOf((*MockRuleService)(nil).DiffRevisions), ctx, key, from, to)
+}
+
 // Get mocks base method.
 func (m *MockRuleService) Get(ctx context.Context, key string) (model.Rule, error) {
 	m.ctrl.T.Helper()
@@ -70,6 +85,66 @@ func (mr *MockRuleServiceMockRecorder) Get(ctx, key any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRuleService)(nil).Get), ctx, key)
 }
 
+// NextResponse mocks base method.
+func (m *MockRuleService) NextResponse(ctx context.Context, rule model.Rule) (model.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NextResponse", ctx, rule)
+	ret0, _ := ret[0].(model.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextResponse indicates an expected call of NextResponse.
+func (mr *MockRuleServiceMockRecorder) NextResponse(ctx, rule any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextResponse", reflect.TypeOf((*MockRuleService)(nil).NextResponse), ctx, rule)
+}
+
+// RestoreRevision mocks base method.
+func (m *MockRuleService) RestoreRevision(ctx context.Context, key string, revision int) (model.Rule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreRevision", ctx, key, revision)
+	ret0, _ := ret[0].(model.Rule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreRevision indicates an expected call of RestoreRevision.
+func (mr *MockRuleServiceMockRecorder) RestoreRevision(ctx, key, revision any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreRevision", reflect.TypeOf((*MockRuleService)(nil).RestoreRevision), ctx, key, revision)
+}
+
+// Revision mocks base method.
+func (m *MockRuleService) Revision(ctx context.Context, key string, revision int) (model.RuleRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revision", ctx, key, revision)
+	ret0, _ := ret[0].(model.RuleRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Revision indicates an expected call of Revision.
+func (mr *MockRuleServiceMockRecorder) Revision(ctx, key, revision any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revision", reflect.TypeOf((*MockRuleService)(nil).Revision), ctx, key, revision)
+}
+
+// Revisions mocks base method.
+func (m *MockRuleService) Revisions(ctx context.Context, key string) ([]model.RuleRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revisions", ctx, key)
+	ret0, _ := ret[0].([]model.RuleRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Revisions indicates an expected call of Revisions.
+func (mr *MockRuleServiceMockRecorder) Revisions(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revisions", reflect.TypeOf((*MockRuleService)(nil).Revisions), ctx, key)
+}
+
 // Save mocks base method.
 func (m *MockRuleService) Save(ctx context.Context, rule model.Rule) (model.Rule, error) {
 	m.ctrl.T.Helper()
@@ -101,18 +176,33 @@ func (mr *MockRuleServiceMockRecorder) Search(ctx, params, paging any) *gomock.C
 }
 
 // SearchByMethodAndPath mocks base method.
-func (m *MockRuleService) SearchByMethodAndPath(ctx context.Context, method, path string) (model.Rule, error) {
+func (m *MockRuleService) SearchByMethodAndPath(ctx context.Context, method, path string, request *model.MatchRequest) (model.Rule, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SearchByMethodAndPath", ctx, method, path)
+	ret := m.ctrl.Call(m, "SearchByMethodAndPath", ctx, method, path, request)
 	ret0, _ := ret[0].(model.Rule)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // SearchByMethodAndPath indicates an expected call of SearchByMethodAndPath.
-func (mr *MockRuleServiceMockRecorder) SearchByMethodAndPath(ctx, method, path any) *gomock.Call {
+func (mr *MockRuleServiceMockRecorder) SearchByMethodAndPath(ctx, method, path, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchByMethodAndPath", reflect.TypeOf((*MockRuleService)(nil).SearchByMethodAndPath), ctx, method, path, request)
+}
+
+// SearchCandidates mocks base method.
+func (m *MockRuleService) SearchCandidates(ctx context.Context, method, path string) (model.RuleList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchCandidates", ctx, method, path)
+	ret0, _ := ret[0].(model.RuleList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchCandidates indicates an expected call of SearchCandidates.
+func (mr *MockRuleServiceMockRecorder) SearchCandidates(ctx, method, path any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchByMethodAndPath", reflect.TypeOf((*MockRuleService)(nil).SearchByMethodAndPath), ctx, method, path)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchCandidates", reflect.TypeOf((*MockRuleService)(nil).SearchCandidates), ctx, method, path)
 }
 
 // Update mocks base method.
@@ -0,0 +1,145 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./scenario_service.go
+//
+// Generated by this command:
+//
+//	mockgen -destination=../utils/test/mocks/scenario_service_mock.go -package=mocks -source=./scenario_service.go
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/nicopozo/mockserver/internal/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockScenarioService is a mock of ScenarioService interface.
+type MockScenarioService struct {
+	ctrl     *gomock.Controller
+	recorder *MockScenarioServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockScenarioServiceMockRecorder is the mock recorder for MockScenarioService.
+type MockScenarioServiceMockRecorder struct {
+	mock *MockScenarioService
+}
+
+// NewMockScenarioService creates a new mock instance.
+func NewMockScenarioService(ctrl *gomock.Controller) *MockScenarioService {
+	mock := &MockScenarioService{ctrl: ctrl}
+	mock.recorder = &MockScenarioServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScenarioService) EXPECT() *MockScenarioServiceMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockScenarioService) Get(ctx context.Context, name string) (model.Scenario, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, name)
+	ret0, _ := ret[0].(model.Scenario)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockScenarioServiceMockRecorder) Get(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockScenarioService)(nil).Get), ctx, name)
+}
+
+// List mocks base method.
+func (m *MockScenarioService) List(ctx context.Context) ([]model.Scenario, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]model.Scenario)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockScenarioServiceMockRecorder) List(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockScenarioService)(nil).List), ctx)
+}
+
+// Reset mocks base method.
+func (m *MockScenarioService) Reset(ctx context.Context, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reset", ctx, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reset indicates an expected call of Reset.
+func (mr *MockScenarioServiceMockRecorder) Reset(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockScenarioService)(nil).Reset), ctx, name)
+}
+
+// ResetAll mocks base method.
+func (m *MockScenarioService) ResetAll(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetAll", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetAll indicates an expected call of ResetAll.
+func (mr *MockScenarioServiceMockRecorder) ResetAll(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetAll", reflect.TypeOf((*MockScenarioService)(nil).ResetAll), ctx)
+}
+
+// SetState mocks base method.
+func (m *MockScenarioService) SetState(ctx context.Context, name, state string) (model.Scenario, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetState", ctx, name, state)
+	ret0, _ := ret[0].(model.Scenario)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetState indicates an expected call of SetState.
+func (mr *MockScenarioServiceMockRecorder) SetState(ctx, name, state any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetState", reflect.TypeOf((*MockScenarioService)(nil).SetState), ctx, name, state)
+}
+
+// State mocks base method.
+func (m *MockScenarioService) State(ctx context.Context, name string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "State", ctx, name)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// State indicates an expected call of State.
+func (mr *MockScenarioServiceMockRecorder) State(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "State", reflect.TypeOf((*MockScenarioService)(nil).State), ctx, name)
+}
+
+// Transition mocks base method.
+func (m *MockScenarioService) Transition(ctx context.Context, name, from, to string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transition", ctx, name, from, to)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Transition indicates an expected call of Transition.
+func (mr *MockScenarioServiceMockRecorder) Transition(ctx, name, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transition", reflect.TypeOf((*MockScenarioService)(nil).Transition), ctx, name, from, to)
+}
@@ -0,0 +1,203 @@
+package yamlutils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Unmarshal decodes a YAML document into model. The document is converted to JSON first, so model is
+// decoded with its json tags, the same way as a JSON body. Since JSON is valid YAML, body may also be JSON.
+func Unmarshal(body io.Reader, model any) error {
+	content, err := io.ReadAll(body)
+	if err != nil {
+		return fmt.Errorf("error reading body, %w", err)
+	}
+
+	jsonContent, err := ToJSON(content)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(jsonContent, model); err != nil {
+		return fmt.Errorf("error unmarshalling yaml, %w", err)
+	}
+
+	return nil
+}
+
+// ToJSON converts a YAML document to JSON. Mappings keep the order of their keys, so that objects
+// written in YAML, like response bodies, are converted the way they were written.
+func ToJSON(content []byte) ([]byte, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(content, &node); err != nil {
+		return nil, fmt.Errorf("error unmarshalling yaml, %w", err)
+	}
+
+	var buffer bytes.Buffer
+
+	if err := writeJSON(&buffer, &node); err != nil {
+		return nil, fmt.Errorf("error converting yaml to json, %w", err)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func writeJSON(buffer *bytes.Buffer, node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		return writeJSON(buffer, node.Content[0])
+	case yaml.AliasNode:
+		return writeJSON(buffer, node.Alias)
+	case yaml.SequenceNode:
+		buffer.WriteByte('[')
+
+		for index, item := range node.Content {
+			if index > 0 {
+				buffer.WriteByte(',')
+			}
+
+			if err := writeJSON(buffer, item); err != nil {
+				return err
+			}
+		}
+
+		buffer.WriteByte(']')
+
+		return nil
+	case yaml.MappingNode:
+		if !hasMergeKey(node) {
+			return writeMappingJSON(buffer, node)
+		}
+	case yaml.ScalarNode:
+	default:
+		// An empty document.
+		buffer.WriteString("null")
+
+		return nil
+	}
+
+	// Scalars, and the mappings that merge other mappings, are resolved by the decoder.
+	var value any
+	if err := node.Decode(&value); err != nil {
+		return fmt.Errorf("error decoding yaml value, %w", err)
+	}
+
+	content, err := json.Marshal(Normalize(value))
+	if err != nil {
+		return fmt.Errorf("error converting yaml value, %w", err)
+	}
+
+	buffer.Write(content)
+
+	return nil
+}
+
+func writeMappingJSON(buffer *bytes.Buffer, node *yaml.Node) error {
+	buffer.WriteByte('{')
+
+	for index := 0; index+1 < len(node.Content); index += 2 {
+		if index > 0 {
+			buffer.WriteByte(',')
+		}
+
+		key, err := json.Marshal(node.Content[index].Value)
+		if err != nil {
+			return fmt.Errorf("error converting yaml key, %w", err)
+		}
+
+		buffer.Write(key)
+		buffer.WriteByte(':')
+
+		if err := writeJSON(buffer, node.Content[index+1]); err != nil {
+			return err
+		}
+	}
+
+	buffer.WriteByte('}')
+
+	return nil
+}
+
+func hasMergeKey(node *yaml.Node) bool {
+	for index := 0; index < len(node.Content); index += 2 {
+		if node.Content[index].Tag == "!!merge" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Marshal encodes model as YAML using its json tags. Fields keep the order of the JSON encoding and
+// multiline strings, like response bodies, are written as literal blocks.
+func Marshal(model any) (string, error) {
+	jsonContent, err := json.Marshal(model)
+	if err != nil {
+		return "", fmt.Errorf("error marshalling model, %w", err)
+	}
+
+	// JSON is a subset of YAML, decoding it into a node keeps the order of the fields.
+	var node yaml.Node
+	if err := yaml.Unmarshal(jsonContent, &node); err != nil {
+		return "", fmt.Errorf("error converting json to yaml, %w", err)
+	}
+
+	blockStyle(&node)
+
+	var buffer bytes.Buffer
+
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(2) //nolint:mnd
+
+	if err := encoder.Encode(&node); err != nil {
+		return "", fmt.Errorf("error marshalling yaml, %w", err)
+	}
+
+	if err := encoder.Close(); err != nil {
+		return "", fmt.Errorf("error marshalling yaml, %w", err)
+	}
+
+	return buffer.String(), nil
+}
+
+// blockStyle drops the flow and quoted styles of the nodes decoded from JSON, so that the encoder picks
+// the plain YAML style. Strings that would be read back as another type are still quoted by the encoder.
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
+
+// Normalize converts the map[interface{}]interface{} values produced for YAML mappings with non string
+// keys, such as unquoted status codes, into map[string]interface{}.
+func Normalize(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, item := range typed {
+			typed[key] = Normalize(item)
+		}
+
+		return typed
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(typed))
+		for key, item := range typed {
+			result[fmt.Sprint(key)] = Normalize(item)
+		}
+
+		return result
+	case []interface{}:
+		for index, item := range typed {
+			typed[index] = Normalize(item)
+		}
+
+		return typed
+	}
+
+	return value
+}
@@ -0,0 +1,94 @@
+package yamlutils_test
+
+import (
+	"strings"
+	"testing"
+
+	yamlutils "github.com/nicopozo/mockserver/internal/utils/yaml"
+	"github.com/stretchr/testify/assert"
+)
+
+type dto struct {
+	ID      int64             `json:"id"`
+	Name    string            `json:"name"`
+	Code    string            `json:"code"`
+	Body    string            `json:"body,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Tags    []string          `json:"tags,omitempty"`
+}
+
+func TestMarshal(t *testing.T) {
+	value := dto{
+		ID:      12,
+		Name:    "the_name",
+		Code:    "200",
+		Body:    "{\n  \"field\": \"value\"\n}",
+		Headers: map[string]string{"X-Test": "true"},
+		Tags:    []string{"a", "b"},
+	}
+
+	got, err := yamlutils.Marshal(value)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, `id: 12
+name: the_name
+code: "200"
+body: |-
+  {
+    "field": "value"
+  }
+headers:
+  X-Test: "true"
+tags:
+  - a
+  - b
+`, got)
+
+	result := dto{}
+
+	assert.NoError(t, yamlutils.Unmarshal(strings.NewReader(got), &result))
+	assert.Equal(t, value, result)
+}
+
+func TestUnmarshal(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wanted  dto
+		wantErr bool
+	}{
+		{
+			name:   "Unmarshal YAML successfully",
+			body:   "id: 12\nname: the_name\ncode: \"404\"\n",
+			wanted: dto{ID: 12, Name: "the_name", Code: "404"},
+		},
+		{
+			name:   "Unmarshal JSON successfully",
+			body:   `{"id": 12, "name": "the_name", "tags": ["a"]}`,
+			wanted: dto{ID: 12, Name: "the_name", Tags: []string{"a"}},
+		},
+		{
+			name:    "Fail with invalid YAML",
+			body:    "id: [12",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := dto{}
+
+			err := yamlutils.Unmarshal(strings.NewReader(tt.body), &result)
+			if tt.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wanted, result)
+		})
+	}
+}
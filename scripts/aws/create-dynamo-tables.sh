@@ -4,6 +4,13 @@
 REGION="us-east-1"
 RULES_TABLE="mockserver_rules"
 LOGS_TABLE="mockserver_logs"
+API_KEYS_TABLE="mockserver_api_keys"
+SCENARIOS_TABLE="mockserver_scenarios"
+RESOURCE_COLLECTIONS_TABLE="mockserver_resource_collections"
+RESOURCE_ENTITIES_TABLE="mockserver_resource_entities"
+SETTINGS_TABLE="mockserver_settings"
+WEBHOOK_JOBS_TABLE="mockserver_webhook_jobs"
+RULE_REVISIONS_TABLE="mockserver_rule_revisions"
 
 # Exit on error
 set -e
@@ -69,6 +76,119 @@ else
     aws dynamodb wait table-exists --table-name "$LOGS_TABLE" --region "$REGION"
 fi
 
+# 3. Create API Keys Table
+if table_exists "$API_KEYS_TABLE"; then
+    echo "✅ Table '$API_KEYS_TABLE' already exists."
+else
+    echo "✨ Creating table '$API_KEYS_TABLE'..."
+    aws dynamodb create-table \
+        --table-name "$API_KEYS_TABLE" \
+        --attribute-definitions AttributeName=secret_hash,AttributeType=S \
+        --key-schema AttributeName=secret_hash,KeyType=HASH \
+        --billing-mode PAY_PER_REQUEST \
+        --region "$REGION"
+    echo "⏳ Waiting for table '$API_KEYS_TABLE' to be created..."
+    aws dynamodb wait table-exists --table-name "$API_KEYS_TABLE" --region "$REGION"
+fi
+
+# 4. Create Scenarios Table
+if table_exists "$SCENARIOS_TABLE"; then
+    echo "✅ Table '$SCENARIOS_TABLE' already exists."
+else
+    echo "✨ Creating table '$SCENARIOS_TABLE'..."
+    aws dynamodb create-table \
+        --table-name "$SCENARIOS_TABLE" \
+        --attribute-definitions AttributeName=name,AttributeType=S \
+        --key-schema AttributeName=name,KeyType=HASH \
+        --billing-mode PAY_PER_REQUEST \
+        --region "$REGION"
+    echo "⏳ Waiting for table '$SCENARIOS_TABLE' to be created..."
+    aws dynamodb wait table-exists --table-name "$SCENARIOS_TABLE" --region "$REGION"
+fi
+
+# 5. Create Resource Collections Table
+if table_exists "$RESOURCE_COLLECTIONS_TABLE"; then
+    echo "✅ Table '$RESOURCE_COLLECTIONS_TABLE' already exists."
+else
+    echo "✨ Creating table '$RESOURCE_COLLECTIONS_TABLE'..."
+    aws dynamodb create-table \
+        --table-name "$RESOURCE_COLLECTIONS_TABLE" \
+        --attribute-definitions AttributeName=name,AttributeType=S \
+        --key-schema AttributeName=name,KeyType=HASH \
+        --billing-mode PAY_PER_REQUEST \
+        --region "$REGION"
+    echo "⏳ Waiting for table '$RESOURCE_COLLECTIONS_TABLE' to be created..."
+    aws dynamodb wait table-exists --table-name "$RESOURCE_COLLECTIONS_TABLE" --region "$REGION"
+fi
+
+# 6. Create Resource Entities Table
+if table_exists "$RESOURCE_ENTITIES_TABLE"; then
+    echo "✅ Table '$RESOURCE_ENTITIES_TABLE' already exists."
+else
+    echo "✨ Creating table '$RESOURCE_ENTITIES_TABLE'..."
+    aws dynamodb create-table \
+        --table-name "$RESOURCE_ENTITIES_TABLE" \
+        --attribute-definitions \
+            AttributeName=collection,AttributeType=S \
+            AttributeName=id,AttributeType=S \
+        --key-schema \
+            AttributeName=collection,KeyType=HASH \
+            AttributeName=id,KeyType=RANGE \
+        --billing-mode PAY_PER_REQUEST \
+        --region "$REGION"
+    echo "⏳ Waiting for table '$RESOURCE_ENTITIES_TABLE' to be created..."
+    aws dynamodb wait table-exists --table-name "$RESOURCE_ENTITIES_TABLE" --region "$REGION"
+fi
+
+# 7. Create Settings Table
+if table_exists "$SETTINGS_TABLE"; then
+    echo "✅ Table '$SETTINGS_TABLE' already exists."
+else
+    echo "✨ Creating table '$SETTINGS_TABLE'..."
+    aws dynamodb create-table \
+        --table-name "$SETTINGS_TABLE" \
+        --attribute-definitions AttributeName=name,AttributeType=S \
+        --key-schema AttributeName=name,KeyType=HASH \
+        --billing-mode PAY_PER_REQUEST \
+        --region "$REGION"
+    echo "⏳ Waiting for table '$SETTINGS_TABLE' to be created..."
+    aws dynamodb wait table-exists --table-name "$SETTINGS_TABLE" --region "$REGION"
+fi
+
+# 8. Create Webhook Jobs Table
+if table_exists "$WEBHOOK_JOBS_TABLE"; then
+    echo "✅ Table '$WEBHOOK_JOBS_TABLE' already exists."
+else
+    echo "✨ Creating table '$WEBHOOK_JOBS_TABLE'..."
+    aws dynamodb create-table \
+        --table-name "$WEBHOOK_JOBS_TABLE" \
+        --attribute-definitions AttributeName=id,AttributeType=S \
+        --key-schema AttributeName=id,KeyType=HASH \
+        --billing-mode PAY_PER_REQUEST \
+        --region "$REGION"
+    echo "⏳ Waiting for table '$WEBHOOK_JOBS_TABLE' to be created..."
+    aws dynamodb wait table-exists --table-name "$WEBHOOK_JOBS_TABLE" --region "$REGION"
+fi
+
+# 9. Create Rule Revisions Table
+if table_exists "$RULE_REVISIONS_TABLE"; then
+    echo "✅ Table '$RULE_REVISIONS_TABLE' already exists."
+else
+    echo "✨ Creating table '$RULE_REVISIONS_TABLE'..."
+    aws dynamodb create-table \
+        --table-name "$RULE_REVISIONS_TABLE" \
+        --attribute-definitions \
+            AttributeName=rule_key,AttributeType=S \
+            AttributeName=revision,AttributeType=N \
+        --key-schema \
+            AttributeName=rule_key,KeyType=HASH \
+            AttributeName=revision,KeyType=RANGE \
+        --billing-mode PAY_PER_REQUEST \
+        --region "$REGION"
+    echo "⏳ Waiting for table '$RULE_REVISIONS_TABLE' to be created..."
+    aws dynamodb wait table-exists --table-name "$RULE_REVISIONS_TABLE" --region "$REGION"
+fi
+
 echo "---------------------------------------------------------"
 echo "✅ DynamoDB provisioning finished successfully!"
 echo "---------------------------------------------------------"
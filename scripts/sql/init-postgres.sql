@@ -11,6 +11,11 @@ CREATE TABLE IF NOT EXISTS mockserver.rules
     status                varchar(255) NOT NULL,
     pattern               varchar(255) NOT NULL,
     next_response_index   int          NOT NULL DEFAULT 0,
+    priority              int          NOT NULL DEFAULT 0,
+    matchers              text         DEFAULT NULL,
+    scenario              text         DEFAULT NULL,
+    resource              text         DEFAULT NULL,
+    chaos                 text         DEFAULT NULL,
     PRIMARY KEY ("key"),
     UNIQUE ("key")
 );
@@ -25,7 +30,12 @@ CREATE TABLE IF NOT EXISTS mockserver.responses
     scene        varchar(255) DEFAULT NULL,
     rule_key     varchar(255) NOT NULL,
     description  varchar(255) DEFAULT NULL,
+    headers      text         DEFAULT NULL,
+    cookies      text         DEFAULT NULL,
     webhook      text         DEFAULT NULL,
+    webhooks     text         DEFAULT NULL,
+    webhook_mode varchar(20)  DEFAULT NULL,
+    fault        varchar(50)  DEFAULT NULL,
     PRIMARY KEY (id),
     CONSTRAINT fk_rules FOREIGN KEY (rule_key) REFERENCES mockserver.rules ("key")
 );
@@ -52,6 +62,9 @@ CREATE TABLE IF NOT EXISTS mockserver.request_logs
 (
     id               varchar(27) NOT NULL,
     timestamp        timestamp   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    rule_key         varchar(100),
+    rule_name        varchar(255),
+    scene            varchar(255),
     method           varchar(10) NOT NULL,
     url              text        NOT NULL,
     request_body     text,
@@ -59,7 +72,112 @@ CREATE TABLE IF NOT EXISTS mockserver.request_logs
     query_params     jsonb,
     response_status  int,
     response_body    text,
+    duration_ms      bigint      NOT NULL DEFAULT 0,
+    fault            varchar(50),
+    chaos            jsonb,
     assertion_errors jsonb,
     webhook_results jsonb,
+    has_assertion_errors boolean NOT NULL DEFAULT FALSE,
+    has_webhook_failures boolean NOT NULL DEFAULT FALSE,
+    webhook_version  int         NOT NULL DEFAULT 0,
     PRIMARY KEY (id)
 );
+
+CREATE INDEX IF NOT EXISTS idx_request_logs_timestamp ON mockserver.request_logs (timestamp);
+CREATE INDEX IF NOT EXISTS idx_request_logs_rule_key ON mockserver.request_logs (rule_key);
+
+CREATE TABLE IF NOT EXISTS mockserver.bin_requests
+(
+    id               varchar(27) NOT NULL,
+    bin              varchar(64) NOT NULL,
+    timestamp        timestamp   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    rule_key         varchar(100),
+    rule_name        varchar(255),
+    scene            varchar(255),
+    method           varchar(10) NOT NULL,
+    url              text        NOT NULL,
+    request_body     text,
+    request_headers  jsonb,
+    query_params     jsonb,
+    response_status  int,
+    response_body    text,
+    duration_ms      bigint      NOT NULL DEFAULT 0,
+    fault            varchar(50),
+    chaos            jsonb,
+    assertion_errors jsonb,
+    webhook_results jsonb,
+    has_assertion_errors boolean NOT NULL DEFAULT FALSE,
+    has_webhook_failures boolean NOT NULL DEFAULT FALSE,
+    webhook_version  int         NOT NULL DEFAULT 0,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_bin_requests_bin ON mockserver.bin_requests (bin, id);
+
+CREATE TABLE IF NOT EXISTS mockserver.api_keys
+(
+    id          varchar(27)  NOT NULL,
+    name        varchar(100) NOT NULL,
+    role        varchar(20)  NOT NULL,
+    secret_hash char(64)     NOT NULL UNIQUE,
+    created_at  timestamp    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE IF NOT EXISTS mockserver.scenarios
+(
+    name       varchar(255) NOT NULL,
+    state      varchar(255) NOT NULL,
+    updated_at timestamp    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (name)
+);
+
+CREATE TABLE IF NOT EXISTS mockserver.settings
+(
+    name       varchar(100) NOT NULL,
+    value      text         NOT NULL,
+    updated_at timestamp    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (name)
+);
+
+CREATE TABLE IF NOT EXISTS mockserver.resource_collections
+(
+    name           varchar(255) NOT NULL,
+    initialized_at timestamp    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (name)
+);
+
+CREATE TABLE IF NOT EXISTS mockserver.resource_entities
+(
+    collection varchar(255) NOT NULL,
+    id         varchar(255) NOT NULL,
+    data       text         NOT NULL,
+    created_at timestamp    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at timestamp    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (collection, id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_resource_entities_created_at ON mockserver.resource_entities (collection, created_at);
+
+CREATE TABLE IF NOT EXISTS mockserver.webhook_jobs
+(
+    id           varchar(27) NOT NULL,
+    status       varchar(20) NOT NULL,
+    due_at       timestamp   NOT NULL,
+    locked_until timestamp   NULL,
+    data         text        NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_jobs_due_at ON mockserver.webhook_jobs (status, due_at);
+
+CREATE TABLE IF NOT EXISTS mockserver.rule_revisions
+(
+    rule_key   varchar(255) NOT NULL,
+    revision   int          NOT NULL,
+    action     varchar(20)  NOT NULL,
+    author     varchar(255) NOT NULL,
+    created_at timestamp    NOT NULL,
+    data       text         NOT NULL,
+    PRIMARY KEY (rule_key, revision)
+);
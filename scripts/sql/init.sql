@@ -11,6 +11,11 @@ CREATE TABLE `mockserver`.`rules`
     `status`              varchar(255) NOT NULL,
     `pattern`             varchar(255) NOT NULL,
     `next_response_index` int NOT NULL DEFAULT '0' ,
+    `priority`            int NOT NULL DEFAULT '0',
+    `matchers`            longtext     DEFAULT NULL,
+    `scenario`            text         DEFAULT NULL,
+    `resource`            text         DEFAULT NULL,
+    `chaos`               text         DEFAULT NULL,
         PRIMARY KEY (`key`),
     UNIQUE KEY `key_UNIQUE` (`key`)
 ) ENGINE = InnoDB
@@ -26,7 +31,12 @@ CREATE TABLE `mockserver`.`responses`
     `scene`        varchar(255) DEFAULT NULL,
     `rule_key`     varchar(255) NOT NULL,
     `description`  varchar(255) DEFAULT NULL,
+    `headers`      longtext     DEFAULT NULL,
+    `cookies`      longtext     DEFAULT NULL,
     `webhook`      longtext     DEFAULT NULL,
+    `webhooks`     longtext     DEFAULT NULL,
+    `webhook_mode` varchar(20)  DEFAULT NULL,
+    `fault`        varchar(50)  DEFAULT NULL,
     PRIMARY KEY (`id`),
     KEY `rules_idx` (`rule_key`),
     CONSTRAINT `rules` FOREIGN KEY (`rule_key`) REFERENCES `rules` (`key`)
@@ -53,6 +63,9 @@ CREATE TABLE `mockserver`.`request_logs`
 (
     `id`               varchar(27)  NOT NULL,
     `timestamp`        timestamp    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `rule_key`         varchar(100) DEFAULT NULL,
+    `rule_name`        varchar(255) DEFAULT NULL,
+    `scene`            varchar(255) DEFAULT NULL,
     `method`           varchar(10)  NOT NULL,
     `url`              text         NOT NULL,
     `request_body`     longtext,
@@ -60,8 +73,118 @@ CREATE TABLE `mockserver`.`request_logs`
     `query_params`     longtext,
     `response_status`  int,
     `response_body`    longtext,
+    `duration_ms`      bigint       NOT NULL DEFAULT 0,
+    `fault`            varchar(50)  DEFAULT NULL,
+    `chaos`            text         DEFAULT NULL,
     `assertion_errors` longtext,
     `webhook_results` longtext,
-    PRIMARY KEY (`id`)
+    `has_assertion_errors` boolean  NOT NULL DEFAULT FALSE,
+    `has_webhook_failures` boolean  NOT NULL DEFAULT FALSE,
+    `webhook_version`  int          NOT NULL DEFAULT 0,
+    PRIMARY KEY (`id`),
+    INDEX `idx_request_logs_timestamp` (`timestamp`),
+    INDEX `idx_request_logs_rule_key` (`rule_key`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = latin1;
+
+CREATE TABLE `mockserver`.`bin_requests`
+(
+    `id`               varchar(27)  NOT NULL,
+    `bin`              varchar(64)  NOT NULL,
+    `timestamp`        timestamp    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `rule_key`         varchar(100) DEFAULT NULL,
+    `rule_name`        varchar(255) DEFAULT NULL,
+    `scene`            varchar(255) DEFAULT NULL,
+    `method`           varchar(10)  NOT NULL,
+    `url`              text         NOT NULL,
+    `request_body`     longtext,
+    `request_headers`  longtext,
+    `query_params`     longtext,
+    `response_status`  int,
+    `response_body`    longtext,
+    `duration_ms`      bigint       NOT NULL DEFAULT 0,
+    `fault`            varchar(50)  DEFAULT NULL,
+    `chaos`            text         DEFAULT NULL,
+    `assertion_errors` longtext,
+    `webhook_results` longtext,
+    `has_assertion_errors` boolean  NOT NULL DEFAULT FALSE,
+    `has_webhook_failures` boolean  NOT NULL DEFAULT FALSE,
+    `webhook_version`  int          NOT NULL DEFAULT 0,
+    PRIMARY KEY (`id`),
+    INDEX `idx_bin_requests_bin` (`bin`, `id`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = latin1;
+
+CREATE TABLE `mockserver`.`api_keys`
+(
+    `id`          varchar(27)  NOT NULL,
+    `name`        varchar(100) NOT NULL,
+    `role`        varchar(20)  NOT NULL,
+    `secret_hash` char(64)     NOT NULL,
+    `created_at`  timestamp    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_api_keys_secret_hash` (`secret_hash`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = latin1;
+
+CREATE TABLE `mockserver`.`scenarios`
+(
+    `name`       varchar(255) NOT NULL,
+    `state`      varchar(255) NOT NULL,
+    `updated_at` timestamp    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`name`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = latin1;
+
+CREATE TABLE `mockserver`.`settings`
+(
+    `name`       varchar(100) NOT NULL,
+    `value`      longtext     NOT NULL,
+    `updated_at` timestamp    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`name`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = latin1;
+
+CREATE TABLE `mockserver`.`resource_collections`
+(
+    `name`           varchar(255) NOT NULL,
+    `initialized_at` timestamp    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`name`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = latin1;
+
+CREATE TABLE `mockserver`.`resource_entities`
+(
+    `collection` varchar(255) NOT NULL,
+    `id`         varchar(255) NOT NULL,
+    `data`       longtext     NOT NULL,
+    `created_at` timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    `updated_at` timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    PRIMARY KEY (`collection`, `id`),
+    INDEX `idx_resource_entities_created_at` (`collection`, `created_at`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = latin1;
+
+CREATE TABLE `mockserver`.`webhook_jobs`
+(
+    `id`           varchar(27) NOT NULL,
+    `status`       varchar(20) NOT NULL,
+    `due_at`       datetime(6) NOT NULL,
+    `locked_until` datetime(6) NULL,
+    `data`         longtext    NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_webhook_jobs_due_at` (`status`, `due_at`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = latin1;
+
+CREATE TABLE `mockserver`.`rule_revisions`
+(
+    `rule_key`   varchar(255) NOT NULL,
+    `revision`   int          NOT NULL,
+    `action`     varchar(20)  NOT NULL,
+    `author`     varchar(255) NOT NULL,
+    `created_at` datetime(6)  NOT NULL,
+    `data`       longtext     NOT NULL,
+    PRIMARY KEY (`rule_key`, `revision`)
 ) ENGINE = InnoDB
   DEFAULT CHARSET = latin1;
@@ -1,9 +1,19 @@
 import { createApp } from 'vue'
+import axios from 'axios'
 import App from './App.vue'
 import router from './router'
 import vuetify from './plugins/vuetify'
 import './assets/main.css'
 
+// Send the API key configured in the settings dialog, needed when the server requires authentication
+axios.interceptors.request.use((config) => {
+  const apiKey = localStorage.getItem('mockserver-api-key')
+  if (apiKey) {
+    config.headers.set('X-API-Key', apiKey)
+  }
+  return config
+})
+
 const app = createApp(App)
 
 app.use(router)
@@ -17,6 +17,22 @@ export interface Variable {
   assertions: Assertion[];
 }
 
+export interface WebhookRetry {
+  max_attempts: number;
+  backoff?: number;
+  max_backoff?: number;
+  retry_on?: number[];
+}
+
+export interface WebhookSignature {
+  algorithm: 'hmac-sha1' | 'hmac-sha256' | 'hmac-sha512';
+  secret: string;
+  header: string;
+  format?: string;
+  payload?: string;
+  encoding?: 'hex' | 'base64';
+}
+
 export interface WebhookConfig {
   url: string;
   method: string;
@@ -25,6 +41,20 @@ export interface WebhookConfig {
   enabled: boolean;
   delay: number;
   timeout?: number;
+  retry?: WebhookRetry;
+  signature?: WebhookSignature;
+  variables?: Variable[];
+}
+
+export interface Cookie {
+  name: string;
+  value: string;
+  path?: string;
+  domain?: string;
+  max_age?: number;
+  secure?: boolean;
+  http_only?: boolean;
+  same_site?: 'lax' | 'strict' | 'none';
 }
 
 export interface Response {
@@ -34,7 +64,60 @@ export interface Response {
   http_status: number;
   delay: number;
   scene?: string;
+  template?: 'go';
+  headers?: Record<string, string>;
+  cookies?: Cookie[];
   webhook?: WebhookConfig;
+  webhooks?: WebhookConfig[];
+  webhook_mode?: 'sequential' | 'parallel';
+  fault?: 'empty_reply' | 'connection_reset' | 'random_data' | 'truncated_body' | 'malformed_chunk' | 'stall_after_headers';
+}
+
+export interface Matcher {
+  type: 'header' | 'query' | 'cookie' | 'jsonpath' | 'xpath';
+  key: string;
+  operator: 'equals' | 'regex' | 'present' | 'absent';
+  value?: string;
+}
+
+export interface RuleScenario {
+  name: string;
+  required_state?: string;
+  new_state?: string;
+}
+
+export interface ResourceConfig {
+  id_field?: string;
+  default_limit?: number;
+  schema?: Record<string, unknown>;
+  seed?: Record<string, unknown>[];
+}
+
+export interface ChaosLatency {
+  distribution: 'fixed' | 'uniform' | 'normal' | 'lognormal';
+  value?: number;
+  min?: number;
+  max?: number;
+  mean?: number;
+  std_dev?: number;
+  p50?: number;
+  p99?: number;
+}
+
+export interface ChaosPolicy {
+  probability: number;
+  error_status?: number;
+  error_body?: string;
+  latency?: ChaosLatency;
+  fault?: string;
+}
+
+export interface ChaosResult {
+  source: 'rule' | 'group';
+  group?: string;
+  latency_ms?: number;
+  error_status?: number;
+  fault?: string;
 }
 
 export interface Mock {
@@ -45,6 +128,11 @@ export interface Mock {
   strategy: string;
   method: string;
   status: 'enabled' | 'disabled';
+  priority?: number;
+  matchers?: Matcher[];
+  scenario?: RuleScenario;
+  resource?: ResourceConfig;
+  chaos?: ChaosPolicy;
   responses: Response[];
   variables: Variable[];
 }
@@ -64,15 +152,52 @@ export interface PaginatedMocks {
 export interface WebhookResult {
   url: string;
   method: string;
+  webhook: number;
+  attempt?: number;
+  timestamp?: string;
   status_code: number;
   duration_ms: number;
   error?: string;
   response_body?: string;
+  request_headers?: Record<string, string>;
+  request_body?: string;
+  redelivery?: boolean;
+  superseded?: boolean;
+}
+
+export interface WebhookJob {
+  id: string;
+  log_id?: string;
+  webhooks: WebhookConfig[];
+  index: number;
+  step: number;
+  attempt: number;
+  variables?: { name: string; value: string }[];
+  status: 'pending' | 'failed';
+  due_at: string;
+  locked_until?: string;
+  last_error?: string;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface Bin {
+  name: string;
+  status: number;
+  content_type?: string;
+  headers?: Record<string, string>;
+  body?: string;
+  delay?: number;
+  updated_at?: string;
 }
 
 export interface LogEntry {
   id: string;
+  bin?: string;
   timestamp: string;
+  rule_key?: string;
+  rule_name?: string;
+  scene?: string;
   method: string;
   url: string;
   request_body: string;
@@ -80,6 +205,9 @@ export interface LogEntry {
   query_params: Record<string, string>;
   response_status: number;
   response_body: string;
+  duration_ms: number;
+  fault?: string;
+  chaos?: ChaosResult;
   assertion_errors?: string[];
   webhook_results?: WebhookResult[];
 }